@@ -0,0 +1,100 @@
+package scrapfly
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// AdaptiveConcurrency lowers the concurrency of a run when the API throttles
+// it and ramps it back up as scrapes succeed (AIMD: the limit is halved on
+// throttling and grows by one every limit successes), instead of failing
+// the configs scraped while over the limit. Throttled configs are queued
+// again and the dispatch waits for the Retry-After of the response. The
+// limit starts at, and never exceeds, ConcurrentScrapeOptions.Concurrency.
+type AdaptiveConcurrency struct {
+	// Min is the concurrency the limit never goes under. Defaults to 1.
+	Min int
+	// MaxRequeues is how many times a throttled config is queued again
+	// before its error is reported. Defaults to 5.
+	MaxRequeues int
+}
+
+func (a *AdaptiveConcurrency) min() int {
+	if a.Min <= 0 {
+		return 1
+	}
+	return a.Min
+}
+
+func (a *AdaptiveConcurrency) maxRequeues() int {
+	if a.MaxRequeues <= 0 {
+		return 5
+	}
+	return a.MaxRequeues
+}
+
+// enable caps the scrapes in flight with the adaptive limit policy,
+// starting at limit.
+func (s *hostScheduler) enable(policy *AdaptiveConcurrency, limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.adaptive = policy
+	s.maxLimit = float64(max(limit, policy.min()))
+	s.limit = s.maxLimit
+	s.started = make([]time.Time, len(s.priorities))
+	s.throttles = make([]int, len(s.priorities))
+}
+
+// isThrottled reports whether err is the API throttling the account: a
+// 429, an ERR::THROTTLE error or ErrTooManyRequests. It returns the
+// Retry-After of the response.
+func isThrottled(err error) (bool, time.Duration) {
+	if errors.Is(err, ErrTooManyRequests) {
+		return true, 0
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.HTTPStatusCode == 429 || strings.HasPrefix(apiErr.Code, "ERR::THROTTLE")) {
+		return true, time.Duration(apiErr.RetryAfterMs) * time.Millisecond
+	}
+	return false, 0
+}
+
+// defaultAutoConcurrencyInterval is the AutoConcurrencyInterval default.
+const defaultAutoConcurrencyInterval = 30 * time.Second
+
+// setCap caps the scrapes in flight to n, at least one.
+func (s *hostScheduler) setCap(n int) {
+	s.mu.Lock()
+	s.cap = max(n, 1)
+	s.mu.Unlock()
+	s.signalFreed()
+}
+
+// refreshConcurrency caps the scrapes of sched to the concurrency the
+// account has available, every interval until done is closed. The
+// account's remaining slots exclude the scrapes of this run, so they are
+// added back. Failed lookups keep the previous cap.
+func (c *Client) refreshConcurrency(sched *hostScheduler, interval time.Duration, done <-chan struct{}) {
+	if interval <= 0 {
+		interval = defaultAutoConcurrencyInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+		account, err := c.Account()
+		if err != nil {
+			DefaultLogger.Warn("failed to refresh account concurrency:", err)
+			continue
+		}
+		sched.mu.Lock()
+		running := sched.running
+		sched.mu.Unlock()
+		sched.setCap(running + account.Subscription.Usage.Scrape.ConcurrentRemaining)
+	}
+}
@@ -0,0 +1,150 @@
+package scrapfly
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_ConcurrentScrapeWithOptions_Adaptive(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, throttled := 0, 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		over := inFlight > 2
+		if over {
+			throttled++
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		if over {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"code":"ERR::THROTTLE::MAX_CONCURRENT_REQUEST_EXCEEDED","message":"too many concurrent requests"}`)
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE","status_code":200}}`)
+	})
+	configs := make([]*ScrapeConfig, 20)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	results := 0
+	for item := range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{Concurrency: 8, Adaptive: &AdaptiveConcurrency{}}) {
+		if item.Err != nil {
+			t.Errorf("config %d failed: %v", item.Index, item.Err)
+		}
+		results++
+	}
+	if results != len(configs) {
+		t.Errorf("got %d results, want %d", results, len(configs))
+	}
+	if throttled == 0 || throttled >= len(configs) {
+		t.Errorf("%d throttled scrapes, the limit should have been lowered after the first ones", throttled)
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_AdaptiveMaxRequeues(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"code":"ERR::THROTTLE::MAX_REQUEST_RATE_EXCEEDED","message":"too many requests"}`)
+	})
+	configs := []*ScrapeConfig{{URL: "https://example.com"}}
+	for item := range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{Concurrency: 1, Adaptive: &AdaptiveConcurrency{MaxRequeues: 2}}) {
+		if throttled, _ := isThrottled(item.Err); !throttled {
+			t.Errorf("err = %v, want the throttling error", item.Err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("%d calls, want the first one and 2 requeues", calls)
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_AdaptiveThrottledAfterDeadline(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"code":"ERR::THROTTLE::MAX_CONCURRENT_REQUEST_EXCEEDED","message":"too many concurrent requests"}`)
+	})
+	configs := make([]*ScrapeConfig, 5)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	var summary ConcurrentScrapeSummary
+	results := 0
+	for item := range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{
+		Concurrency:    2,
+		Adaptive:       &AdaptiveConcurrency{},
+		MaxRunDuration: 50 * time.Millisecond,
+		OnComplete:     func(s ConcurrentScrapeSummary) { summary = s },
+	}) {
+		if throttled, _ := isThrottled(item.Err); !throttled {
+			t.Errorf("config %d: err = %v, want the throttling error", item.Index, item.Err)
+		}
+		results++
+	}
+	if !summary.DeadlineReached || results+len(summary.PendingIndexes) != len(configs) {
+		t.Errorf("%d results and pending %v, want every config reported once", results, summary.PendingIndexes)
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_AutoConcurrency(t *testing.T) {
+	var mu sync.Mutex
+	accountCalls, inFlight, maxInFlight := 0, 0, 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/account" {
+			mu.Lock()
+			accountCalls++
+			// other jobs release slots after the first lookup
+			remaining := 2
+			if accountCalls > 1 {
+				remaining = 4 - inFlight
+			}
+			mu.Unlock()
+			fmt.Fprintf(w, `{"subscription":{"usage":{"scrape":{"concurrent_limit":10,"concurrent_remaining":%d}}}}`, remaining)
+			return
+		}
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE","status_code":200}}`)
+	})
+	configs := make([]*ScrapeConfig, 16)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	var firstMax int
+	for item := range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{AutoConcurrency: true, AutoConcurrencyInterval: 50 * time.Millisecond}) {
+		if item.Err != nil {
+			t.Fatal(item.Err)
+		}
+		mu.Lock()
+		if accountCalls == 1 {
+			firstMax = maxInFlight
+		}
+		mu.Unlock()
+	}
+	if firstMax > 2 {
+		t.Errorf("%d scrapes in flight before the refresh, want the 2 remaining slots at most", firstMax)
+	}
+	if accountCalls < 2 || maxInFlight < 3 || maxInFlight > 4 {
+		t.Errorf("%d account lookups, up to %d scrapes in flight; want the cap raised to 4 by a refresh", accountCalls, maxInFlight)
+	}
+}
@@ -0,0 +1,122 @@
+package scrapfly
+
+import (
+	"strings"
+	"time"
+)
+
+// Article is the main article of a page, as found by ScrapeResult.Article.
+type Article struct {
+	// Title is the article headline.
+	Title string
+	// Author is the byline, authors joined with ", ".
+	Author string
+	// Published is the publication date; zero when the page has none.
+	Published time.Time
+	// SiteName is the name of the publication.
+	SiteName string
+	// Excerpt is the summary of the article, from its description or first
+	// paragraph.
+	Excerpt string
+	// Text is the cleaned main text, paragraphs separated by blank lines.
+	Text string
+	// Markdown is the main content as Markdown, see ScrapeResult.Markdown.
+	Markdown string
+}
+
+// Article extracts the main article of an HTML page with a readability
+// algorithm: the content block is the element whose paragraphs score
+// highest on text length and punctuation, penalized by link density and by
+// class names such as "comment" or "sidebar". The title, author and dates
+// are taken from the page metadata (JSON-LD, OpenGraph and meta tags),
+// falling back to the markup.
+//
+// It costs no API call, unlike the Extraction API, and returns an error for
+// non-HTML content or in builds with the scrapfly_nogoquery tag.
+//
+// Example:
+//
+//	article, err := result.Article()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(article.Title, "by", article.Author, article.Published.Format(time.DateOnly))
+//	fmt.Println(article.Text)
+func (r *ScrapeResult) Article() (*Article, error) {
+	return htmlArticle(r)
+}
+
+// articleMetadata fills the article fields available in the page metadata.
+func (a *Article) articleMetadata(data *StructuredData) {
+	for _, typ := range []string{"NewsArticle", "Article", "BlogPosting", "Report", "TechArticle"} {
+		objects := data.JSONLDOfType(typ)
+		if len(objects) == 0 {
+			continue
+		}
+		obj := objects[0]
+		a.Title = firstNonEmpty(a.Title, stringifyValue(obj["headline"]), stringifyValue(obj["name"]))
+		a.Author = firstNonEmpty(a.Author, jsonLDNames(obj["author"]))
+		a.Excerpt = firstNonEmpty(a.Excerpt, stringifyValue(obj["description"]))
+		if publisher, ok := obj["publisher"].(map[string]interface{}); ok {
+			a.SiteName = firstNonEmpty(a.SiteName, stringifyValue(publisher["name"]))
+		}
+		if a.Published.IsZero() {
+			a.Published = parseArticleTime(stringifyValue(obj["datePublished"]))
+		}
+		break
+	}
+	a.Title = firstNonEmpty(a.Title, data.OpenGraph["title"], data.Meta["twitter:title"])
+	a.Author = firstNonEmpty(a.Author, data.Meta["author"], data.Meta["article:author"])
+	a.SiteName = firstNonEmpty(a.SiteName, data.OpenGraph["site_name"])
+	a.Excerpt = firstNonEmpty(a.Excerpt, data.OpenGraph["description"], data.Meta["description"])
+	if a.Published.IsZero() {
+		for _, key := range []string{"article:published_time", "datepublished", "date", "pubdate", "publish-date"} {
+			if t := parseArticleTime(data.Meta[key]); !t.IsZero() {
+				a.Published = t
+				break
+			}
+		}
+	}
+}
+
+// jsonLDNames returns the names of a JSON-LD person or organization value:
+// a string, an object with a name or a list of those.
+func jsonLDNames(v interface{}) string {
+	switch value := v.(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		return stringifyValue(value["name"])
+	case []interface{}:
+		var names []string
+		for _, item := range value {
+			if name := jsonLDNames(item); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+// articleTimeLayouts are the date formats of article metadata.
+var articleTimeLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", time.DateOnly, time.RFC1123, time.RFC1123Z}
+
+func parseArticleTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range articleTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
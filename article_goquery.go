@@ -0,0 +1,175 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	// articlePositive and articleNegative are the class and id hints of
+	// content and boilerplate blocks.
+	articlePositive = regexp.MustCompile(`(?i)article|body|content|entry|hentry|main|page|post|text|blog|story`)
+	articleNegative = regexp.MustCompile(`(?i)comment|meta|footer|footnote|sidebar|sponsor|shoutbox|related|share|social|promo|banner|advert|\bads?\b|widget|nav|menu|breadcrumb|pagination|popup|subscribe|newsletter|cookie|masthead|outbrain|taboola`)
+)
+
+// articleNoise are the elements removed before scoring.
+const articleNoise = "script, style, noscript, template, iframe, svg, form, nav, aside, footer, header, button, select, textarea"
+
+// articleBlocks are the elements the main text is read from.
+const articleBlocks = "p, pre, h2, h3, h4, h5, h6, li, blockquote"
+
+func htmlArticle(r *ScrapeResult) (*Article, error) {
+	doc, err := r.Selector()
+	if err != nil {
+		return nil, err
+	}
+	data, err := r.StructuredData()
+	if err != nil {
+		return nil, err
+	}
+	article := &Article{}
+	article.articleMetadata(data)
+
+	// work on a copy, the document is cached on the result
+	root := doc.Selection.Clone()
+	if article.Title == "" {
+		article.Title = firstNonEmpty(root.Find("h1").First().Text(), root.Find("title").First().Text())
+	}
+	if article.Author == "" {
+		article.Author = firstNonEmpty(root.Find(`[rel="author"], [itemprop="author"], .author, .byline`).First().Text())
+	}
+	if article.Published.IsZero() {
+		article.Published = parseArticleTime(root.Find("time[datetime]").First().AttrOr("datetime", ""))
+	}
+
+	root.Find(articleNoise).Remove()
+	root.Find("*").Each(func(_ int, s *goquery.Selection) {
+		// hidden blocks and boilerplate containers that are not content
+		if style := strings.ReplaceAll(strings.ToLower(s.AttrOr("style", "")), " ", ""); strings.Contains(style, "display:none") {
+			s.Remove()
+			return
+		}
+		hint := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+		if goquery.NodeName(s) != "body" && goquery.NodeName(s) != "article" && articleNegative.MatchString(hint) && !articlePositive.MatchString(hint) {
+			s.Remove()
+		}
+	})
+
+	top := topArticleCandidate(root)
+	if top == nil {
+		top = root.Find("body").First()
+	}
+
+	var paragraphs []string
+	top.Find(articleBlocks).Each(func(_ int, s *goquery.Selection) {
+		// nested blocks are read with their parent
+		if s.ParentsUntilSelection(top).Filter(articleBlocks).Length() > 0 {
+			return
+		}
+		text := strings.Join(strings.Fields(s.Text()), " ")
+		if text == "" || linkDensity(s) > 0.5 {
+			return
+		}
+		paragraphs = append(paragraphs, text)
+	})
+	article.Text = strings.Join(paragraphs, "\n\n")
+	if len(paragraphs) > 0 {
+		article.Excerpt = firstNonEmpty(article.Excerpt, paragraphs[0])
+	}
+
+	c := &markdownConverter{}
+	if r.Result.URL != "" {
+		c.base, _ = url.Parse(r.Result.URL)
+	}
+	article.Markdown = c.block(top)
+	return article, nil
+}
+
+// topArticleCandidate scores the parents of the paragraphs of root and
+// returns the best one, or nil when the page has no paragraphs.
+func topArticleCandidate(root *goquery.Selection) *goquery.Selection {
+	type candidate struct {
+		s     *goquery.Selection
+		score float64
+	}
+	var candidates []*candidate
+	byNode := map[interface{}]*candidate{}
+	lookup := func(s *goquery.Selection) *candidate {
+		if s.Length() == 0 {
+			return nil
+		}
+		c, ok := byNode[s.Nodes[0]]
+		if !ok {
+			c = &candidate{s: s, score: initialArticleScore(s)}
+			byNode[s.Nodes[0]] = c
+			candidates = append(candidates, c)
+		}
+		return c
+	}
+
+	root.Find("p, pre, td").Each(func(_ int, p *goquery.Selection) {
+		text := strings.Join(strings.Fields(p.Text()), " ")
+		if len(text) < 25 {
+			return
+		}
+		score := 1 + float64(strings.Count(text, ",")) + min(float64(len(text))/100, 3)
+		if parent := lookup(p.Parent()); parent != nil {
+			parent.score += score
+			if grandparent := lookup(parent.s.Parent()); grandparent != nil {
+				grandparent.score += score / 2
+			}
+		}
+	})
+
+	var top *goquery.Selection
+	best := 0.0
+	for _, c := range candidates {
+		if score := c.score * (1 - linkDensity(c.s)); top == nil || score > best {
+			top, best = c.s, score
+		}
+	}
+	return top
+}
+
+// initialArticleScore weighs a candidate by its tag and class hints.
+func initialArticleScore(s *goquery.Selection) float64 {
+	score := 0.0
+	switch goquery.NodeName(s) {
+	case "article":
+		score += 10
+	case "div", "main", "section":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "form", "ol", "ul", "dl", "dd", "dt", "li", "address":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+	hint := s.AttrOr("class", "") + " " + s.AttrOr("id", "")
+	if articlePositive.MatchString(hint) {
+		score += 25
+	}
+	if articleNegative.MatchString(hint) {
+		score -= 25
+	}
+	return score
+}
+
+// linkDensity is the share of the text of s inside links.
+func linkDensity(s *goquery.Selection) float64 {
+	text := len(strings.Join(strings.Fields(s.Text()), " "))
+	if text == 0 {
+		return 0
+	}
+	links := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		links += len(strings.Join(strings.Fields(a.Text()), " "))
+	})
+	return float64(links) / float64(text)
+}
@@ -0,0 +1,74 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScrapeResult_Article(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{
+		URL:         "https://news.example.com/2024/rates",
+		ContentType: "text/html",
+		Content: `<html><head><title>Rates rise | Example News</title>
+<meta property="og:site_name" content="Example News">
+<script type="application/ld+json">{"@type": "NewsArticle", "headline": "Central bank raises rates",
+  "author": [{"@type": "Person", "name": "Jane Doe"}, {"@type": "Person", "name": "John Roe"}],
+  "datePublished": "2024-03-01T09:30:00Z"}</script>
+</head><body>
+<nav><ul><li><a href="/">Home</a></li><li><a href="/world">World</a></li></ul></nav>
+<div class="sidebar"><p>Subscribe to our newsletter for the latest updates, offers, and news.</p></div>
+<article><div class="article-body">
+<p>The central bank raised its key rate on Friday, citing persistent inflation, strong wages, and a tight labour market.</p>
+<p>Analysts had expected the move, which brings the rate to its highest level in a decade, and markets barely reacted.</p>
+<h2>What it means</h2>
+<p>Borrowing costs for households and companies are set to rise further, economists said, as banks pass on the increase.</p>
+</div></article>
+<div class="comments"><p>Great article, thanks for sharing this with all of us here today!</p></div>
+<footer><p>Copyright Example News, all rights reserved, since the beginning of times.</p></footer>
+</body></html>`,
+	}}
+	article, err := result.Article()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Title != "Central bank raises rates" || article.Author != "Jane Doe, John Roe" || article.SiteName != "Example News" {
+		t.Errorf("unexpected metadata %+v", article)
+	}
+	if !article.Published.Equal(time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("Published = %v", article.Published)
+	}
+	paragraphs := strings.Split(article.Text, "\n\n")
+	if len(paragraphs) != 4 || !strings.HasPrefix(paragraphs[0], "The central bank") || paragraphs[2] != "What it means" {
+		t.Errorf("unexpected text %q", article.Text)
+	}
+	for _, noise := range []string{"newsletter", "Great article", "Copyright", "Home"} {
+		if strings.Contains(article.Text, noise) {
+			t.Errorf("text should not contain %q", noise)
+		}
+	}
+	if !strings.HasPrefix(article.Excerpt, "The central bank") || !strings.Contains(article.Markdown, "## What it means") {
+		t.Errorf("unexpected excerpt %q or markdown %q", article.Excerpt, article.Markdown)
+	}
+	// the cached document is left untouched
+	if doc, _ := result.Selector(); doc.Find("footer").Length() != 1 {
+		t.Error("Article() should not modify the result document")
+	}
+}
+
+func TestScrapeResult_Article_MetaFallbacks(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{
+		ContentType: "text/html",
+		Content: `<html><head><title>Fallback title</title><meta name="author" content="A. Writer"></head>
+<body><time datetime="2023-05-06">May 6</time><div><p>Only paragraph of the page, long enough to be scored.</p></div></body></html>`,
+	}}
+	article, err := result.Article()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if article.Title != "Fallback title" || article.Author != "A. Writer" || article.Published.Format(time.DateOnly) != "2023-05-06" {
+		t.Errorf("unexpected article %+v", article)
+	}
+}
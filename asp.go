@@ -0,0 +1,110 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultASPStages is the escalation used when ASPEscalation.Stages is empty.
+var DefaultASPStages = []ASPStage{ASPStageASP, ASPStageBrowser, ASPStageResidential}
+
+// ASPEscalation controls how Client.ScrapeEscalating escalates anti-scraping
+// protection bypasses and how much they may spend.
+type ASPEscalation struct {
+	// Stages are tried in order; leave a stage out to disable it.
+	// Defaults to DefaultASPStages.
+	Stages []ASPStage
+	// StageBudget caps the API credits of a single stage, sent as
+	// cost_budget. Zero leaves the config CostBudget.
+	StageBudget int
+	// MaxCost caps the API credits spent across all stages. Zero means no cap.
+	MaxCost int
+}
+
+// ScrapeEscalating scrapes config with the first stage of policy and moves
+// to the next stage when the scrape is blocked (ErrASPBypassFailed or an
+// upstream 4xx), stopping at the first success. It fails with
+// ErrCostBudgetExceeded as soon as a stage hits its StageBudget or the
+// credits spent reach MaxCost; the credits of failed stages are taken from
+// the API error response.
+//
+// Example:
+//
+//	result, err := client.ScrapeEscalating(ctx, config, &scrapfly.ASPEscalation{
+//	    Stages:  []scrapfly.ASPStage{scrapfly.ASPStageNone, scrapfly.ASPStageASP},
+//	    MaxCost: 50,
+//	})
+//	if errors.Is(err, scrapfly.ErrCostBudgetExceeded) {
+//	    log.Println("bypass too expensive, skipping", config.URL)
+//	}
+func (c *Client) ScrapeEscalating(ctx context.Context, config *ScrapeConfig, policy *ASPEscalation) (*ScrapeResult, error) {
+	if policy == nil {
+		policy = &ASPEscalation{}
+	}
+	stages := policy.Stages
+	if len(stages) == 0 {
+		stages = DefaultASPStages
+	}
+	for _, stage := range stages {
+		if !stage.IsValid() {
+			return nil, fmt.Errorf("%w: invalid ASP stage %q", ErrScrapeConfig, string(stage))
+		}
+	}
+
+	// the stages are attempts of one scrape, they share its correlation ID
+	ctx, config = c.correlate(ctx, config)
+	spent := 0
+	var lastErr error
+	for _, stage := range stages {
+		budget := policy.StageBudget
+		if policy.MaxCost > 0 {
+			// the previous stages left credits, see below
+			remaining := policy.MaxCost - spent
+			if budget == 0 || remaining < budget {
+				budget = remaining
+			}
+		}
+
+		attempt := stage.apply(config)
+		if budget > 0 {
+			attempt.CostBudget = budget
+		}
+		result, err := c.ScrapeContext(ctx, attempt)
+		if err == nil {
+			return result, nil
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.APIResponse != nil {
+			spent += apiErr.APIResponse.Context.Cost.Total
+		}
+		if !errors.Is(err, ErrASPBypassFailed) && !errors.Is(err, ErrUpstreamClient) {
+			return nil, err
+		}
+		if policy.MaxCost > 0 && spent >= policy.MaxCost {
+			return nil, fmt.Errorf("%w: spent %d of %d credits by stage %s: %w", ErrCostBudgetExceeded, spent, policy.MaxCost, stage, err)
+		}
+		DefaultLogger.Debug("ASP stage", stage, "blocked, escalating", "url", config.URL, "spent", spent)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// apply returns a copy of config set up for the stage.
+func (s ASPStage) apply(config *ScrapeConfig) *ScrapeConfig {
+	out := config.Clone()
+	switch s {
+	case ASPStageNone:
+		out.ASP = false
+	case ASPStageASP:
+		out.ASP = true
+	case ASPStageBrowser:
+		out.ASP = true
+		out.RenderJS = true
+	case ASPStageResidential:
+		out.ASP = true
+		out.RenderJS = true
+		out.ProxyPool = PublicResidentialPool
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const aspBlockedResult = `{"context":{"cost":{"total":10}},"result":{"success":false,"status":"ERR::ASP::SHIELD_PROTECTION_FAILED","status_code":200}}`
+
+func TestClient_ScrapeEscalatingStopsAtFirstSuccess(t *testing.T) {
+	var stages []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		stages = append(stages, q.Get("asp")+"/"+q.Get("render_js")+"/"+q.Get("cost_budget"))
+		w.Header().Set("Content-Type", "application/json")
+		if q.Get("render_js") != "true" {
+			w.Write([]byte(aspBlockedResult))
+			return
+		}
+		w.Write([]byte(`{"result":{"success":true,"status":"DONE","status_code":200}}`))
+	})
+
+	config := &ScrapeConfig{URL: "https://example.com"}
+	policy := &ASPEscalation{Stages: []ASPStage{ASPStageNone, ASPStageASP, ASPStageBrowser, ASPStageResidential}, StageBudget: 25, MaxCost: 40}
+	if _, err := client.ScrapeEscalating(context.Background(), config, policy); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"//25", "true//25", "true/true/20"}
+	if len(stages) != len(want) {
+		t.Fatalf("stages %v, want %v", stages, want)
+	}
+	for i := range want {
+		if stages[i] != want[i] {
+			t.Errorf("stage %d sent %q, want %q", i, stages[i], want[i])
+		}
+	}
+	if config.ASP || config.RenderJS || config.CostBudget != 0 {
+		t.Error("the caller's config must not be mutated")
+	}
+}
+
+func TestClient_ScrapeEscalatingMaxCost(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(aspBlockedResult))
+	})
+
+	_, err := client.ScrapeEscalating(context.Background(), &ScrapeConfig{URL: "https://example.com"}, &ASPEscalation{MaxCost: 20})
+	if !errors.Is(err, ErrCostBudgetExceeded) || !errors.Is(err, ErrASPBypassFailed) {
+		t.Fatalf("got %v, want ErrCostBudgetExceeded wrapping the last ASP error", err)
+	}
+	if calls != 2 {
+		t.Errorf("got %d calls, want 2", calls)
+	}
+}
+
+func TestClient_ScrapeEscalatingMaxCostAtLastStage(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(aspBlockedResult))
+	})
+
+	policy := &ASPEscalation{Stages: []ASPStage{ASPStageASP, ASPStageBrowser}, MaxCost: 20}
+	if _, err := client.ScrapeEscalating(context.Background(), &ScrapeConfig{URL: "https://example.com"}, policy); !errors.Is(err, ErrCostBudgetExceeded) || !errors.Is(err, ErrASPBypassFailed) {
+		t.Errorf("got %v, want ErrCostBudgetExceeded once the last stage spent MaxCost", err)
+	}
+	policy.MaxCost = 30
+	if _, err := client.ScrapeEscalating(context.Background(), &ScrapeConfig{URL: "https://example.com"}, policy); errors.Is(err, ErrCostBudgetExceeded) || !errors.Is(err, ErrASPBypassFailed) {
+		t.Errorf("got %v, want the last ASP error under MaxCost", err)
+	}
+}
+
+func TestClient_ScrapeBudgetErrorIsTyped(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result":{"success":false,"status":"ERR::SCRAPE::COST_BUDGET_LIMIT","status_code":200,"error":{"code":"ERR::SCRAPE::COST_BUDGET_LIMIT","message":"budget reached"}}}`))
+	})
+	_, err := client.ScrapeEscalating(context.Background(), &ScrapeConfig{URL: "https://example.com"}, nil)
+	if !errors.Is(err, ErrCostBudgetExceeded) {
+		t.Errorf("got %v, want ErrCostBudgetExceeded", err)
+	}
+}
@@ -0,0 +1,250 @@
+package scrapfly
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Asset is a resource referenced by a page, resolved to an absolute URL.
+type Asset struct {
+	// URL is the absolute URL of the asset, without fragment.
+	URL string
+	// Kind is the class of the asset.
+	Kind AssetKind
+}
+
+// pageExtensions are the path extensions of web pages, which <a href>
+// links to are not AssetFile.
+var pageExtensions = []string{"", ".html", ".htm", ".xhtml", ".php", ".asp", ".aspx", ".jsp", ".cfm", ".cgi"}
+
+// Assets returns the assets of an HTML result of the given kinds (all when
+// none are given) whose URL matches pattern (all when nil), resolved
+// against the final URL and deduplicated. Non-HTML results have none.
+//
+// Example:
+//
+//	pdfs := result.Assets(regexp.MustCompile(`\.pdf$`), scrapfly.AssetFile)
+//	downloads := client.DownloadAssets(ctx, pdfs, scrapfly.DirStorage("./pdfs"), scrapfly.AssetDownloadOptions{})
+func (r *ScrapeResult) Assets(pattern *regexp.Regexp, kinds ...AssetKind) []Asset {
+	pageURL := r.Result.URL
+	if pageURL == "" {
+		pageURL = r.Config.URL
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var assets []Asset
+	for _, asset := range htmlAssets(r) {
+		if len(kinds) > 0 && !slices.Contains(kinds, asset.Kind) {
+			continue
+		}
+		ref, err := base.Parse(strings.TrimSpace(asset.URL))
+		if err != nil || (ref.Scheme != "http" && ref.Scheme != "https") {
+			continue
+		}
+		if asset.Kind == AssetFile && slices.Contains(pageExtensions, strings.ToLower(path.Ext(ref.Path))) {
+			continue
+		}
+		ref.Fragment = ""
+		ref.RawFragment = ""
+		asset.URL = ref.String()
+		if seen[asset.URL] || (pattern != nil && !pattern.MatchString(asset.URL)) {
+			continue
+		}
+		seen[asset.URL] = true
+		assets = append(assets, asset)
+	}
+	return assets
+}
+
+// isStylesheetRel reports whether a <link> rel attribute is a stylesheet.
+func isStylesheetRel(rel string) bool {
+	for _, value := range strings.Fields(strings.ToLower(rel)) {
+		if value == "stylesheet" {
+			return true
+		}
+	}
+	return false
+}
+
+// AssetStorage is the destination of DownloadAssets. Store is called
+// concurrently, once per asset, with the downloaded body.
+type AssetStorage interface {
+	Store(ctx context.Context, asset Asset, body io.Reader) error
+}
+
+// DirStorage is an AssetStorage writing assets under a directory, mirroring
+// their URL: <dir>/<host>/<path>. Paths ending with "/" are stored as
+// "index" and URLs with a query get a hash of it in the file name, so
+// distinct URLs don't overwrite each other.
+type DirStorage string
+
+// Store writes body to the file of the asset, creating directories.
+func (d DirStorage) Store(_ context.Context, asset Asset, body io.Reader) error {
+	name, err := d.Path(asset)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Path returns the file an asset is stored to.
+func (d DirStorage) Path(asset Asset) (string, error) {
+	u, err := url.Parse(asset.URL)
+	if err != nil {
+		return "", err
+	}
+	// cleaning a rooted path drops any ".." so files stay under the directory
+	p := path.Clean("/" + u.Path)
+	if strings.HasSuffix(u.Path, "/") || p == "/" {
+		p = path.Join(p, "index")
+	}
+	if u.RawQuery != "" {
+		sum := sha1.Sum([]byte(u.RawQuery))
+		ext := path.Ext(p)
+		p = strings.TrimSuffix(p, ext) + "_" + hex.EncodeToString(sum[:4]) + ext
+	}
+	return filepath.Join(string(d), u.Hostname(), filepath.FromSlash(p)), nil
+}
+
+// AssetDownloadOptions configures DownloadAssets.
+type AssetDownloadOptions struct {
+	// Concurrency is the maximum number of downloads in flight. Defaults
+	// to 4.
+	Concurrency int
+	// Proxified downloads the assets through proxified scrapes (see
+	// ScrapeProxified), billed as such, instead of direct requests. Use it
+	// for assets behind anti-bot protection or geo-blocking.
+	Proxified bool
+	// Config is the base config of proxified downloads (ASP, Country...);
+	// its URL is replaced by each asset URL.
+	Config *ScrapeConfig
+}
+
+// AssetDownload is the outcome of downloading one asset.
+type AssetDownload struct {
+	Asset Asset
+	// Size is the number of bytes stored.
+	Size int64
+	// Err is the download or storage error, nil on success.
+	Err error
+}
+
+// DownloadAssets downloads assets concurrently into storage and returns
+// the outcome of each, in the order of assets. Direct downloads use the
+// client HTTP client and fail on non-2xx statuses.
+//
+// Example:
+//
+//	images := result.Assets(regexp.MustCompile(`/products/`), scrapfly.AssetImage)
+//	for _, d := range client.DownloadAssets(ctx, images, scrapfly.DirStorage("./images"), scrapfly.AssetDownloadOptions{
+//	    Concurrency: 8,
+//	    Proxified:   true,
+//	    Config:      &scrapfly.ScrapeConfig{ASP: true},
+//	}) {
+//	    if d.Err != nil {
+//	        log.Printf("%s: %v", d.Asset.URL, d.Err)
+//	    }
+//	}
+func (c *Client) DownloadAssets(ctx context.Context, assets []Asset, storage AssetStorage, opts AssetDownloadOptions) []AssetDownload {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	downloads := make([]AssetDownload, len(assets))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(assets); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				downloads[j] = c.downloadAsset(ctx, assets[j], storage, opts)
+			}
+		}()
+	}
+	for i := range assets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return downloads
+}
+
+func (c *Client) downloadAsset(ctx context.Context, asset Asset, storage AssetStorage, opts AssetDownloadOptions) AssetDownload {
+	download := AssetDownload{Asset: asset}
+	if err := ctx.Err(); err != nil {
+		download.Err = err
+		return download
+	}
+
+	var resp *http.Response
+	var err error
+	if opts.Proxified {
+		config := opts.Config.Clone()
+		if config == nil {
+			config = &ScrapeConfig{}
+		}
+		config.URL = asset.URL
+		resp, err = c.ScrapeProxifiedContext(ctx, config)
+	} else {
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, asset.URL, nil)
+		if err == nil {
+			resp, err = c.httpClient.Do(req)
+		}
+		if err == nil && (resp.StatusCode < 200 || resp.StatusCode >= 300) {
+			resp.Body.Close()
+			err = fmt.Errorf("failed to download %s: status %d", asset.URL, resp.StatusCode)
+		}
+	}
+	if err != nil {
+		download.Err = err
+		return download
+	}
+	defer resp.Body.Close()
+
+	counter := &countingReader{r: resp.Body}
+	if err := storage.Store(ctx, asset, counter); err != nil {
+		download.Err = fmt.Errorf("failed to store %s: %w", asset.URL, err)
+	}
+	download.Size = counter.n
+	return download
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
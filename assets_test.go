@@ -0,0 +1,116 @@
+package scrapfly
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestScrapeResult_Assets(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{
+		URL:         "https://example.com/products/",
+		ContentType: "text/html",
+		Content: `<html><head>
+<link rel="stylesheet" href="/css/site.css"><link rel="icon" href="/favicon.ico">
+<script src="app.js"></script><script>inline()</script>
+</head><body>
+<img src="/img/1.jpg"><img src="/img/1.jpg#dup"><img src="data:image/png;base64,xx">
+<a href="/docs/manual.pdf">Manual</a><a href="/about.html">About</a><a href="/contact">Contact</a>
+</body></html>`,
+	}}
+	all := result.Assets(nil)
+	want := []Asset{
+		{URL: "https://example.com/css/site.css", Kind: AssetStylesheet},
+		{URL: "https://example.com/products/app.js", Kind: AssetScript},
+		{URL: "https://example.com/img/1.jpg", Kind: AssetImage},
+		{URL: "https://example.com/docs/manual.pdf", Kind: AssetFile},
+	}
+	if len(all) != len(want) {
+		t.Fatalf("Assets() = %v, want %v", all, want)
+	}
+	for i := range want {
+		if all[i] != want[i] {
+			t.Errorf("asset %d = %v, want %v", i, all[i], want[i])
+		}
+	}
+	if got := result.Assets(regexp.MustCompile(`\.pdf$`), AssetFile, AssetImage); len(got) != 1 || got[0] != want[3] {
+		t.Errorf("filtered Assets() = %v", got)
+	}
+}
+
+func TestDirStorage_Path(t *testing.T) {
+	cases := map[string]string{
+		"https://example.com/img/1.jpg":        "example.com/img/1.jpg",
+		"https://example.com/files/":           "example.com/files/index",
+		"https://example.com/../../etc/passwd": "example.com/etc/passwd",
+		"https://example.com/thumb.jpg?w=100":  "example.com/thumb_",
+	}
+	for u, want := range cases {
+		got, err := DirStorage("out").Path(Asset{URL: u})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rel, _ := filepath.Rel("out", got)
+		if filepath.ToSlash(rel)[:len(want)] != want {
+			t.Errorf("Path(%s) = %s, want prefix out/%s", u, got, want)
+		}
+	}
+}
+
+func TestClient_DownloadAssets(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.png" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("data:" + r.URL.Path))
+	}))
+	defer upstream.Close()
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("direct downloads should not call the API")
+	})
+
+	dir := t.TempDir()
+	assets := []Asset{
+		{URL: upstream.URL + "/a.png", Kind: AssetImage},
+		{URL: upstream.URL + "/missing.png", Kind: AssetImage},
+		{URL: upstream.URL + "/b.js", Kind: AssetScript},
+	}
+	downloads := client.DownloadAssets(context.Background(), assets, DirStorage(dir), AssetDownloadOptions{Concurrency: 2})
+	if len(downloads) != 3 || downloads[0].Err != nil || downloads[2].Err != nil || downloads[1].Err == nil {
+		t.Fatalf("unexpected downloads %+v", downloads)
+	}
+	if downloads[0].Size != int64(len("data:/a.png")) {
+		t.Errorf("Size = %d", downloads[0].Size)
+	}
+	name, _ := DirStorage(dir).Path(assets[2])
+	if data, err := os.ReadFile(name); err != nil || string(data) != "data:/b.js" {
+		t.Errorf("stored %q, %v", data, err)
+	}
+}
+
+func TestClient_DownloadAssets_Proxified(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("proxified_response") != "true" || r.URL.Query().Get("asp") != "true" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		w.Write([]byte("via api"))
+	})
+	dir := t.TempDir()
+	asset := Asset{URL: "https://example.com/file.pdf", Kind: AssetFile}
+	downloads := client.DownloadAssets(context.Background(), []Asset{asset}, DirStorage(dir), AssetDownloadOptions{
+		Proxified: true,
+		Config:    &ScrapeConfig{ASP: true},
+	})
+	if downloads[0].Err != nil {
+		t.Fatal(downloads[0].Err)
+	}
+	name, _ := DirStorage(dir).Path(asset)
+	if data, _ := os.ReadFile(name); string(data) != "via api" {
+		t.Errorf("stored %q", data)
+	}
+}
@@ -0,0 +1,68 @@
+package scrapfly
+
+import (
+	"net/http"
+	"time"
+)
+
+// AuxiliaryPolicy is the timeout and retry policy of the auxiliary API
+// calls — account, API key verification, monitoring, schedules and alerts —
+// kept separate from scrape calls so a slow metadata request cannot stall a
+// loop that only needs quota numbers for as long as a scrape would
+// (150 seconds by default).
+type AuxiliaryPolicy struct {
+	// Timeout bounds each attempt, including reading the response body.
+	// Zero uses the HTTP client's timeout.
+	Timeout time.Duration
+	// Retries is the number of extra attempts after a network error or a
+	// 5xx response. Only idempotent (GET/HEAD) requests are retried.
+	Retries int
+	// RetryDelay is the pause between attempts.
+	RetryDelay time.Duration
+}
+
+// DefaultAuxiliaryPolicy is used until SetAuxiliaryPolicy is called.
+var DefaultAuxiliaryPolicy = AuxiliaryPolicy{
+	Timeout:    15 * time.Second,
+	Retries:    2,
+	RetryDelay: 500 * time.Millisecond,
+}
+
+// SetAuxiliaryPolicy overrides the timeout and retry policy of the
+// auxiliary (non-scrape) API calls.
+//
+// Example:
+//
+//	client.SetAuxiliaryPolicy(scrapfly.AuxiliaryPolicy{Timeout: 3 * time.Second, Retries: 1})
+func (c *Client) SetAuxiliaryPolicy(policy AuxiliaryPolicy) {
+	c.auxPolicy = &policy
+}
+
+// AuxiliaryPolicy returns the policy applied to auxiliary API calls.
+func (c *Client) AuxiliaryPolicy() AuxiliaryPolicy {
+	if c.auxPolicy == nil {
+		return DefaultAuxiliaryPolicy
+	}
+	return *c.auxPolicy
+}
+
+// doAuxiliary sends an auxiliary API request under the client's
+// AuxiliaryPolicy.
+func (c *Client) doAuxiliary(req *http.Request) (*http.Response, error) {
+	policy := c.AuxiliaryPolicy()
+	hc := c.httpClient
+	if policy.Timeout > 0 && policy.Timeout != hc.Timeout {
+		bounded := *hc
+		bounded.Timeout = policy.Timeout
+		hc = &bounded
+	}
+
+	attempts := 1
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		attempts += max(policy.Retries, 0)
+	}
+	if attempts == 1 || (req.Body != nil && req.GetBody == nil) {
+		return hc.Do(req)
+	}
+	return fetchWithRetry(hc, req, attempts, policy.RetryDelay)
+}
@@ -0,0 +1,41 @@
+package scrapfly
+
+import (
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_AuxiliaryPolicyBoundsAccountCalls(t *testing.T) {
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+	})
+	client.SetAuxiliaryPolicy(AuxiliaryPolicy{Timeout: 50 * time.Millisecond, Retries: 1, RetryDelay: time.Millisecond})
+
+	start := time.Now()
+	if _, err := client.Account(); err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("account call took %v, the auxiliary timeout was not applied", elapsed)
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("expected 1 retry (2 attempts), got %d attempts", got)
+	}
+	if client.HTTPClient().Timeout != 150*time.Second {
+		t.Error("the scrape HTTP client must keep its own timeout")
+	}
+}
+
+func TestClient_AuxiliaryPolicyDefaults(t *testing.T) {
+	client, _ := New("__API_KEY__")
+	if client.AuxiliaryPolicy() != DefaultAuxiliaryPolicy {
+		t.Errorf("unexpected default policy %+v", client.AuxiliaryPolicy())
+	}
+}
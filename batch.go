@@ -3,6 +3,7 @@ package scrapfly
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -12,8 +13,6 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-
-	"github.com/vmihailenco/msgpack/v5"
 )
 
 // BatchResult wraps a single scrape's outcome within a batch response.
@@ -67,6 +66,10 @@ func (c *Client) ScrapeBatch(configs []*ScrapeConfig) (<-chan BatchResult, error
 	return c.ScrapeBatchWithOptions(configs, BatchOptions{})
 }
 
+// errMsgpackDisabled is returned for msgpack payloads in builds with the
+// scrapfly_nomsgpack tag.
+var errMsgpackDisabled = errors.New("msgpack support is disabled by the scrapfly_nomsgpack build tag")
+
 // ScrapeBatchWithOptions is ScrapeBatch with explicit BatchOptions
 // (e.g. msgpack per-part encoding).
 func (c *Client) ScrapeBatchWithOptions(configs []*ScrapeConfig, opts BatchOptions) (<-chan BatchResult, error) {
@@ -78,6 +81,10 @@ func (c *Client) ScrapeBatchWithOptions(configs []*ScrapeConfig, opts BatchOptio
 		return nil, fmt.Errorf("ScrapeBatch: max 100 configs per batch (got %d)", len(configs))
 	}
 
+	if opts.Format == BatchFormatMsgpack && !msgpackEnabled {
+		return nil, fmt.Errorf("ScrapeBatch: %w", errMsgpackDisabled)
+	}
+
 	// Client-side correlation_id validation — fail fast.
 	seen := make(map[string]int, len(configs))
 	configByCorrelation := make(map[string]*ScrapeConfig, len(configs))
@@ -97,6 +104,7 @@ func (c *Client) ScrapeBatchWithOptions(configs []*ScrapeConfig, opts BatchOptio
 
 		// Reuse toAPIParamsWithValidation to guarantee wire parity
 		// with /scrape. Drop `key` (batch key is in the URL).
+		cfg = c.withDomainDefaults(cfg)
 		if err := cfg.processBody(); err != nil {
 			return nil, fmt.Errorf("ScrapeBatch: configs[%d]: %w", i, err)
 		}
@@ -263,15 +271,10 @@ func (c *Client) ScrapeBatchWithOptions(configs []*ScrapeConfig, opts BatchOptio
 
 			switch {
 			case strings.HasPrefix(partContentType, "application/json"):
-				decodeErr = json.Unmarshal(partBytes, &result)
+				decodeErr = result.UnmarshalJSON(partBytes)
 			case strings.HasPrefix(partContentType, "application/msgpack"),
 				strings.HasPrefix(partContentType, "application/x-msgpack"):
-				// ScrapeResult's fields use `json:` tags; tell the
-				// msgpack decoder to honor those so the same struct
-				// decodes correctly from either wire format.
-				dec := msgpack.NewDecoder(bytes.NewReader(partBytes))
-				dec.SetCustomStructTag("json")
-				decodeErr = dec.Decode(&result)
+				decodeErr = decodeMsgpack(partBytes, &result)
 			default:
 				results <- BatchResult{
 					CorrelationID: correlationID,
@@ -0,0 +1,134 @@
+package scrapfly
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchJob is a handle on a concurrent run started with StartBatchJob. It
+// lets operators halt the spending of a long run, for instance when a quota
+// alarm fires, without losing the configs left to scrape.
+type BatchJob struct {
+	results <-chan ConcurrentScrapeResult
+	control *batchControl
+	done    chan struct{}
+	summary ConcurrentScrapeSummary
+}
+
+// batchControl carries the pause and stop requests of a BatchJob to the
+// dispatcher of its run.
+type batchControl struct {
+	mu sync.Mutex
+	// resumed is non-nil while paused and closed by Resume.
+	resumed chan struct{}
+	// changed signals a pause to a dispatcher waiting to hand a config
+	// over, so it doesn't dispatch past the pause.
+	changed  chan struct{}
+	stopped  chan struct{}
+	stopOnce sync.Once
+}
+
+// pausedUntil returns a channel closed on resume, nil when not paused.
+func (b *batchControl) pausedUntil() <-chan struct{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.resumed
+}
+
+// StartBatchJob starts scraping configs as ConcurrentScrapeWithOptionsContext
+// does and returns a handle to pause, resume or stop the run. Read the
+// results from BatchJob.Results. OnComplete, when set, is still called.
+//
+// Example:
+//
+//	job := client.StartBatchJob(ctx, configs, scrapfly.ConcurrentScrapeOptions{Concurrency: 10})
+//	go func() {
+//	    for range quotaAlarms {
+//	        job.Pause()
+//	    }
+//	}()
+//	for item := range job.Results() {
+//	    ...
+//	}
+//	checkpoint(job.Summary().Pending)
+func (c *Client) StartBatchJob(ctx context.Context, configs []*ScrapeConfig, opts ConcurrentScrapeOptions) *BatchJob {
+	job := &BatchJob{
+		control: &batchControl{changed: make(chan struct{}, 1), stopped: make(chan struct{})},
+		done:    make(chan struct{}),
+	}
+	onComplete := opts.OnComplete
+	opts.OnComplete = func(summary ConcurrentScrapeSummary) {
+		job.summary = summary
+		close(job.done)
+		if onComplete != nil {
+			onComplete(summary)
+		}
+	}
+	job.results = c.concurrentScrape(ctx, configs, opts, job.control)
+	return job
+}
+
+// Results returns the results of the run, closed once it has ended.
+func (j *BatchJob) Results() <-chan ConcurrentScrapeResult {
+	return j.results
+}
+
+// Pause stops dispatching configs until Resume. Scrapes in flight are
+// finished and their results delivered; the configs not dispatched stay
+// queued. MaxRunDuration keeps running while paused.
+func (j *BatchJob) Pause() {
+	j.control.pause()
+}
+
+func (b *batchControl) pause() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.resumed != nil {
+		return
+	}
+	b.resumed = make(chan struct{})
+	select {
+	case b.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Resume resumes dispatching after Pause.
+func (j *BatchJob) Resume() {
+	b := j.control
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.resumed != nil {
+		close(b.resumed)
+		b.resumed = nil
+	}
+}
+
+// Paused reports whether the job is paused.
+func (j *BatchJob) Paused() bool {
+	return j.control.pausedUntil() != nil
+}
+
+// Stop ends the run, paused or not: no more configs are dispatched, the
+// scrapes in flight are finished, and the configs not dispatched are
+// reported in Summary().Pending, from which a new job can resume.
+func (j *BatchJob) Stop() {
+	j.control.stopOnce.Do(func() { close(j.control.stopped) })
+}
+
+// Done returns a channel closed once the run has ended, after the last
+// result has been sent.
+func (j *BatchJob) Done() <-chan struct{} {
+	return j.done
+}
+
+// Summary returns the summary of the run, once Done is closed; it is the
+// zero value before.
+func (j *BatchJob) Summary() ConcurrentScrapeSummary {
+	select {
+	case <-j.done:
+		return j.summary
+	default:
+		return ConcurrentScrapeSummary{}
+	}
+}
@@ -0,0 +1,58 @@
+package scrapfly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBatchJob_PauseResumeStop(t *testing.T) {
+	var calls atomic.Int32
+	started, release := make(chan struct{}, 10), make(chan struct{})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		started <- struct{}{}
+		<-release
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+	configs := make([]*ScrapeConfig, 5)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	job := client.StartBatchJob(context.Background(), configs, ConcurrentScrapeOptions{Concurrency: 1})
+	<-started
+	job.Pause()
+	if !job.Paused() {
+		t.Fatal("job should be paused")
+	}
+	close(release)
+	if item := <-job.Results(); item.Err != nil {
+		t.Fatal(item.Err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("%d scrapes while paused, want the in-flight one only", n)
+	}
+
+	job.Resume()
+	if item := <-job.Results(); item.Err != nil {
+		t.Fatal(item.Err)
+	}
+	job.Pause()
+	job.Stop()
+	for range job.Results() {
+	}
+	<-job.Done()
+
+	summary := job.Summary()
+	if !summary.Stopped || summary.Completed()+len(summary.Pending) != len(configs) {
+		t.Errorf("summary = %+v", summary)
+	}
+	if summary.Completed() < 2 {
+		t.Errorf("completed %d scrapes, want the 2 dispatched before the stop", summary.Completed())
+	}
+}
@@ -0,0 +1,214 @@
+package scrapfly
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sdkModulePath is the import path of this module, used to find the SDK
+// version in the binary's build information.
+const sdkModulePath = "github.com/scrapfly/go-scrapfly"
+
+// scrapeAPIParameters lists the Scrape API parameters ScrapeConfig can send.
+// Keep it in sync with toAPIParamsWithValidation (enforced by tests).
+var scrapeAPIParameters = []string{
+	"asp", "auto_scroll", "browser_brand", "cache", "cache_clear", "cache_ttl",
+	"correlation_id", "cost_budget", "country", "debug", "dns",
+	"extraction_model", "extraction_prompt", "extraction_template", "format",
+	"geolocation", "headers", "js", "js_scenario", "lang", "os",
+	"proxified_response", "proxy_pool", "render_js", "rendering_stage",
+	"rendering_wait", "retry", "screenshot_flags", "screenshots", "session",
+	"session_sticky_proxy", "ssl", "tags", "timeout", "url",
+	"wait_for_selector", "webhook_name",
+}
+
+// SDKBuildInfo describes the SDK build in use, for support triage and
+// multi-team deployments where several SDK versions coexist.
+type SDKBuildInfo struct {
+	// Version is the SDK module version ("(devel)" when built from a checkout).
+	Version string `json:"version"`
+	// GoVersion is the Go toolchain the binary was built with.
+	GoVersion string `json:"go_version"`
+	// Platform is GOOS/GOARCH.
+	Platform string `json:"platform"`
+	// BuildTags are the build tags the binary was compiled with.
+	BuildTags []string `json:"build_tags"`
+	// APIParameters are the Scrape API parameters ScrapeConfig supports.
+	APIParameters []string `json:"api_parameters"`
+	// Codecs are the response encodings the SDK decodes.
+	Codecs []string `json:"codecs"`
+	// Transport describes the HTTP transport of a client; empty from the
+	// package-level BuildInfo.
+	Transport string `json:"transport,omitempty"`
+	// Host is the API host of a client; empty from the package-level BuildInfo.
+	Host string `json:"host,omitempty"`
+}
+
+// String renders the build info on one line.
+func (b SDKBuildInfo) String() string {
+	s := fmt.Sprintf("scrapfly-go %s (%s, %s)", b.Version, b.GoVersion, b.Platform)
+	if len(b.BuildTags) > 0 {
+		s += " tags=" + strings.Join(b.BuildTags, ",")
+	}
+	if b.Transport != "" {
+		s += " transport=" + b.Transport
+	}
+	return s
+}
+
+// BuildInfo reports the SDK version, supported API parameters, enabled
+// build tags and codecs.
+//
+// Example:
+//
+//	log.Println(scrapfly.BuildInfo())
+func BuildInfo() SDKBuildInfo {
+	info := SDKBuildInfo{
+		Version:       "(unknown)",
+		GoVersion:     runtime.Version(),
+		Platform:      runtime.GOOS + "/" + runtime.GOARCH,
+		BuildTags:     []string{},
+		APIParameters: append([]string(nil), scrapeAPIParameters...),
+		Codecs:        append([]string(nil), sdkCodecs...),
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if bi.Main.Path == sdkModulePath {
+			info.Version = bi.Main.Version
+		}
+		for _, dep := range bi.Deps {
+			if dep.Path == sdkModulePath {
+				info.Version = dep.Version
+				if dep.Replace != nil {
+					info.Version += " => " + dep.Replace.Path
+				}
+			}
+		}
+		for _, setting := range bi.Settings {
+			if setting.Key == "-tags" && setting.Value != "" {
+				info.BuildTags = strings.Split(setting.Value, ",")
+			}
+		}
+	}
+	sort.Strings(info.BuildTags)
+	return info
+}
+
+// BuildInfo is the package-level BuildInfo completed with the client's
+// API host and HTTP transport.
+func (c *Client) BuildInfo() SDKBuildInfo {
+	info := BuildInfo()
+	info.Host = c.host
+	info.Transport = describeTransport(c.httpClient)
+	return info
+}
+
+func describeTransport(hc *http.Client) string {
+	rt := hc.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	desc := fmt.Sprintf("%T", rt)
+	if t, ok := rt.(*http.Transport); ok {
+		if t.ForceAttemptHTTP2 {
+			desc += " http2"
+		}
+		if t.Proxy != nil {
+			desc += " proxy-aware"
+		}
+	}
+	if hc.Timeout > 0 {
+		desc += " timeout=" + hc.Timeout.String()
+	}
+	return desc
+}
+
+// ErrorReport is a self-contained description of a failed call, safe to
+// attach to a support ticket: it carries the error details and the SDK
+// build info, never the API key.
+type ErrorReport struct {
+	Time    time.Time    `json:"time"`
+	Error   string       `json:"error"`
+	Kinds   []string     `json:"kinds,omitempty"`
+	API     *APIErrorRef `json:"api_error,omitempty"`
+	Build   SDKBuildInfo `json:"build"`
+	Context string       `json:"context,omitempty"`
+}
+
+// APIErrorRef is the APIError part of an ErrorReport.
+type APIErrorRef struct {
+	Code             string `json:"code"`
+	Message          string `json:"message"`
+	HTTPStatusCode   int    `json:"http_status_code"`
+	Retryable        bool   `json:"retryable"`
+	DocumentationURL string `json:"documentation_url,omitempty"`
+	LogURL           string `json:"log_url,omitempty"`
+}
+
+// errorKinds maps the sentinel errors reported in ErrorReport.Kinds.
+var errorKinds = []error{
+	ErrBadAPIKey, ErrScrapeConfig, ErrScreenshotConfig, ErrExtractionConfig,
+	ErrContentType, ErrTooManyRequests, ErrQuotaLimitReached,
+	ErrScreenshotAPIFailed, ErrExtractionAPIFailed, ErrUpstreamClient,
+	ErrUpstreamServer, ErrAPIClient, ErrAPIServer, ErrScrapeFailed,
+	ErrProxyFailed, ErrASPBypassFailed, ErrCostBudgetExceeded, ErrScheduleFailed, ErrWebhookFailed,
+	ErrScrapeQueued, ErrCloudBrowserConfig, ErrSessionFailed, ErrUnhandledAPIResponse,
+	ErrCrawlerConfig, ErrCrawlerFailed, ErrRobotsDisallowed,
+}
+
+// keyParamRe matches the key query parameter of an API URL.
+var keyParamRe = regexp.MustCompile(`([?&]key=)[^&\s"']+`)
+
+// redactKey replaces the API key, and any key query parameter, in s.
+func (c *Client) redactKey(s string) string {
+	if c.key != "" {
+		s = strings.ReplaceAll(s, c.key, "__API_KEY__")
+	}
+	return keyParamRe.ReplaceAllString(s, "${1}__API_KEY__")
+}
+
+// ErrorReport builds an ErrorReport for err, with context a free-form
+// description of the failing operation (URL, job name...).
+//
+// Example:
+//
+//	if _, err := client.Scrape(config); err != nil {
+//	    report, _ := json.MarshalIndent(client.ErrorReport(err, config.URL), "", "  ")
+//	    os.WriteFile("scrapfly-error.json", report, 0644)
+//	}
+func (c *Client) ErrorReport(err error, context string) *ErrorReport {
+	report := &ErrorReport{
+		Time:    time.Now().UTC(),
+		Build:   c.BuildInfo(),
+		Context: c.redactKey(context),
+	}
+	if err == nil {
+		return report
+	}
+	report.Error = c.redactKey(err.Error())
+	for _, kind := range errorKinds {
+		if errors.Is(err, kind) {
+			report.Kinds = append(report.Kinds, kind.Error())
+		}
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		report.API = &APIErrorRef{
+			Code:             apiErr.Code,
+			Message:          c.redactKey(apiErr.Message),
+			HTTPStatusCode:   apiErr.HTTPStatusCode,
+			Retryable:        apiErr.Retryable,
+			DocumentationURL: apiErr.DocumentationURL,
+		}
+		if apiErr.APIResponse != nil {
+			report.API.LogURL = c.redactKey(apiErr.APIResponse.Result.LogURL)
+		}
+	}
+	return report
+}
@@ -0,0 +1,66 @@
+package scrapfly
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	js_scenario "github.com/scrapfly/go-scrapfly/scenario"
+)
+
+func TestBuildInfo_ListsEveryEmittedAPIParameter(t *testing.T) {
+	sticky := true
+	configs := []*ScrapeConfig{
+		{
+			URL: "https://example.com", Country: "us", ProxyPool: PublicResidentialPool, RenderJS: true,
+			WaitForSelector: "#x", RenderingWait: 100, AutoScroll: true, JS: "return 1",
+			JSScenario:  []js_scenario.JSScenarioStep{{"wait": 100}},
+			Screenshots: map[string]string{"page": "fullpage"}, ScreenshotFlags: []ScreenshotFlag{DarkMode},
+			RenderingStage: RenderingStageDOMContentLoaded, ASP: true,
+			Timeout: 30000, Debug: true, SSL: true, DNS: true, CorrelationID: "c", Tags: []string{"t"},
+			Webhook: "w", Session: "s", SessionStickyProxy: &sticky, OS: OSLinux, Lang: []string{"en"},
+			BrowserBrand: "chrome", ProxifiedResponse: true, CostBudget: 10, Geolocation: "1,2",
+			Format: FormatMarkdown, ExtractionTemplate: "tpl", Headers: map[string]string{"x-a": "b"},
+		},
+		{URL: "https://example.com", Cache: true, CacheTTL: 60, CacheClear: true, ExtractionPrompt: "p"},
+		{URL: "https://example.com", ExtractionModel: ExtractionModelProduct},
+	}
+	known := make(map[string]bool)
+	for _, param := range BuildInfo().APIParameters {
+		known[param] = true
+	}
+	for _, cfg := range configs {
+		params, err := cfg.toAPIParamsWithValidation()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for key := range params {
+			name, _, _ := strings.Cut(key, "[")
+			if !known[name] {
+				t.Errorf("parameter %q is sent but missing from BuildInfo().APIParameters", name)
+			}
+		}
+	}
+}
+
+func TestClient_ErrorReportRedactsKey(t *testing.T) {
+	client, _ := New("secret-key")
+	err := &APIError{Code: "ERR::SCRAPE::BAD", Message: "bad request https://api.scrapfly.io/scrape?key=secret-key&url=x, other key: https://api.scrapfly.io/scrape?url=x&key=old-key", HTTPStatusCode: 422}
+	report := client.ErrorReport(errors.Join(ErrScrapeFailed, err), "https://example.com")
+
+	if strings.Contains(report.Error, "secret-key") {
+		t.Errorf("API key leaked in report: %s", report.Error)
+	}
+	if report.API == nil || report.API.Code != "ERR::SCRAPE::BAD" {
+		t.Fatalf("missing APIError details: %+v", report.API)
+	}
+	if msg := report.API.Message; strings.Contains(msg, "secret-key") || strings.Contains(msg, "old-key") || !strings.Contains(msg, "?key=__API_KEY__&url=x") {
+		t.Errorf("API key leaked in report message: %s", msg)
+	}
+	if len(report.Kinds) != 1 || report.Kinds[0] != ErrScrapeFailed.Error() {
+		t.Errorf("unexpected kinds %v", report.Kinds)
+	}
+	if report.Build.Transport == "" || report.Build.GoVersion == "" {
+		t.Errorf("incomplete build info %+v", report.Build)
+	}
+}
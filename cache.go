@@ -0,0 +1,90 @@
+package scrapfly
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// cacheEntryTimeLayouts are the timestamp layouts the API has used for
+// cache entry dates, tried in order.
+var cacheEntryTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02 15:04:05",
+}
+
+// IsHit reports whether the response was served from the Scrapfly cache.
+func (c *CacheContext) IsHit() bool {
+	return strings.EqualFold(c.State, "HIT")
+}
+
+// Age returns how long ago the cached entry was stored. The second return
+// value is false when the entry carries no parseable creation date (for
+// example on a cache MISS).
+func (c *CacheContext) Age() (time.Duration, bool) {
+	entry, ok := c.Entry.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	for _, key := range []string{"created_at", "stored_at", "last_updated_at"} {
+		raw, ok := entry[key].(string)
+		if !ok || raw == "" {
+			continue
+		}
+		for _, layout := range cacheEntryTimeLayouts {
+			if t, err := time.Parse(layout, raw); err == nil {
+				return time.Since(t), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// IsStale reports whether a cache HIT is older than maxAge. Hits without a
+// parseable creation date are considered fresh: their age is unknown, and
+// refreshing them would turn every hit into a billed scrape.
+func (c *CacheContext) IsStale(maxAge time.Duration) bool {
+	if !c.IsHit() {
+		return false
+	}
+	age, ok := c.Age()
+	return ok && age > maxAge
+}
+
+// revalidateTimeout bounds a background cache refresh.
+const revalidateTimeout = 3 * time.Minute
+
+// maybeRevalidate implements ScrapeConfig.CacheStaleWhileRevalidate: when the
+// result is a stale cache hit it starts a single background scrape with
+// cache_clear=true for the same URL. Concurrent stale hits on the same
+// URL share one refresh.
+func (c *Client) maybeRevalidate(config *ScrapeConfig, result *ScrapeResult) {
+	if config.CacheStaleWhileRevalidate <= 0 || !config.Cache || config.CacheClear {
+		return
+	}
+	maxAge := time.Duration(config.CacheStaleWhileRevalidate) * time.Second
+	if !result.Context.Cache.IsStale(maxAge) {
+		return
+	}
+
+	key := config.Method.String() + " " + config.URL
+	if _, running := c.revalidating.LoadOrStore(key, struct{}{}); running {
+		return
+	}
+
+	// a deep copy: the caller may modify config once Scrape returns
+	refresh := config.Clone()
+	refresh.CacheClear = true
+	refresh.CacheStaleWhileRevalidate = 0
+
+	go func() {
+		defer c.revalidating.Delete(key)
+		ctx, cancel := context.WithTimeout(context.Background(), revalidateTimeout)
+		defer cancel()
+		DefaultLogger.Debug("revalidating stale cache entry", "url", refresh.URL)
+		if _, err := c.ScrapeContext(ctx, refresh); err != nil {
+			DefaultLogger.Warn("background cache revalidation failed", "url", refresh.URL, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,64 @@
+package scrapfly
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCacheContext_IsStale(t *testing.T) {
+	fresh := &CacheContext{State: "HIT", Entry: map[string]interface{}{"created_at": time.Now().UTC().Format(time.RFC3339)}}
+	if fresh.IsStale(time.Minute) {
+		t.Error("fresh hit reported stale")
+	}
+	old := &CacheContext{State: "HIT", Entry: map[string]interface{}{"created_at": time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)}}
+	if !old.IsStale(time.Minute) {
+		t.Error("hour-old hit not reported stale")
+	}
+	undated := &CacheContext{State: "HIT"}
+	if undated.IsStale(time.Minute) {
+		t.Error("hit without creation date should be treated as fresh")
+	}
+	miss := &CacheContext{State: "MISS"}
+	if miss.IsStale(time.Minute) {
+		t.Error("miss must never be stale")
+	}
+}
+
+func TestClient_Scrape_StaleWhileRevalidateRefreshesInBackground(t *testing.T) {
+	refreshed := make(chan string, 1)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("cache_clear") == "true" {
+			refreshed <- r.URL.Query().Get("url")
+			fmt.Fprint(w, `{"result":{"success":true,"status":"DONE","content":"new"},"context":{"cache":{"state":"MISS"}}}`)
+			return
+		}
+		created := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+		fmt.Fprintf(w, `{"result":{"success":true,"status":"DONE","content":"old"},"context":{"cache":{"state":"HIT","entry":{"created_at":%q}}}}`, created)
+	})
+
+	config := &ScrapeConfig{
+		URL:                       "https://example.com",
+		Cache:                     true,
+		CacheStaleWhileRevalidate: 60,
+		Headers:                   map[string]string{"x-page": "1"},
+	}
+	result, err := client.Scrape(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the refresh runs on a copy: modifying the config must not race with it
+	config.Headers["x-page"] = "2"
+	if result.Result.Content != "old" {
+		t.Errorf("expected the stale content to be returned immediately, got %q", result.Result.Content)
+	}
+	select {
+	case u := <-refreshed:
+		if u != "https://example.com" {
+			t.Errorf("refresh scraped %q", u)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("background refresh with cache_clear was not issued")
+	}
+}
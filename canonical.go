@@ -0,0 +1,102 @@
+package scrapfly
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DefaultTrackingParams are the query parameters of the analytics and ad
+// click trackers, stripped by DefaultURLCanonicalizer.
+var DefaultTrackingParams = []string{
+	"utm_*", "gclid", "gclsrc", "dclid", "fbclid", "msclkid", "yclid", "twclid",
+	"mc_cid", "mc_eid", "_ga", "_gl", "igshid", "ref_src", "spm",
+}
+
+// DefaultURLCanonicalizer strips DefaultTrackingParams.
+var DefaultURLCanonicalizer = &URLCanonicalizer{StripParams: DefaultTrackingParams}
+
+// URLCanonicalizer rewrites URLs to a canonical form, so that URLs of the
+// same page compare equal: the scheme and host are lowercased, default
+// ports and the fragment are dropped, an empty path becomes "/", and the
+// query parameters are sorted, without the ones of StripParams.
+type URLCanonicalizer struct {
+	// StripParams are the query parameters removed, by name or by prefix
+	// when ending with "*" ("utm_*"). Names are case-insensitive.
+	StripParams []string
+	// KeepFragment keeps the #fragment, for sites that route on it.
+	KeepFragment bool
+}
+
+// Canonicalize returns the canonical form of rawURL, rawURL itself when
+// it does not parse.
+func (c *URLCanonicalizer) Canonicalize(rawURL string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	host, port := strings.ToLower(u.Hostname()), u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	u.Host = host
+	if strings.Contains(host, ":") {
+		u.Host = "[" + host + "]"
+	}
+	if port != "" {
+		u.Host += ":" + port
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	if !c.KeepFragment {
+		u.Fragment, u.RawFragment = "", ""
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for name := range query {
+			if c.stripped(name) {
+				delete(query, name)
+			}
+		}
+		for _, values := range query {
+			sort.Strings(values)
+		}
+		// Encode sorts by name
+		u.RawQuery = query.Encode()
+	}
+	u.ForceQuery = false
+	return u.String()
+}
+
+func (c *URLCanonicalizer) stripped(name string) bool {
+	name = strings.ToLower(name)
+	for _, param := range c.StripParams {
+		param = strings.ToLower(param)
+		if prefix, ok := strings.CutSuffix(param, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+		} else if name == param {
+			return true
+		}
+	}
+	return false
+}
+
+// duplicateConfigs maps the first of n configs with a given key to the
+// indexes of the later configs with the same key.
+func duplicateConfigs(n int, key func(index int) string) map[int][]int {
+	dups := make(map[int][]int)
+	first := make(map[string]int, n)
+	for i := 0; i < n; i++ {
+		k := key(i)
+		if original, ok := first[k]; ok {
+			dups[original] = append(dups[original], i)
+			continue
+		}
+		first[k] = i
+	}
+	return dups
+}
@@ -0,0 +1,104 @@
+package scrapfly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+func TestURLCanonicalizer_Canonicalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"HTTPS://Example.COM", "https://example.com/"},
+		{"https://example.com:443/a?b=2&a=1", "https://example.com/a?a=1&b=2"},
+		{"http://example.com:8080/a#section", "http://example.com:8080/a"},
+		{"https://example.com/p?utm_source=news&UTM_Medium=mail&id=3&gclid=x", "https://example.com/p?id=3"},
+		{"https://example.com/p?", "https://example.com/p"},
+		{"not a url", "not a url"},
+	}
+	for _, tt := range tests {
+		if got := DefaultURLCanonicalizer.Canonicalize(tt.in); got != tt.want {
+			t.Errorf("Canonicalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	custom := &URLCanonicalizer{StripParams: []string{"session*"}, KeepFragment: true}
+	if got := custom.Canonicalize("https://example.com/#/app?x=1"); got != "https://example.com/#/app?x=1" {
+		t.Errorf("KeepFragment: got %q", got)
+	}
+	if got := custom.Canonicalize("https://example.com/?sessionid=1&utm_source=a"); got != "https://example.com/?utm_source=a" {
+		t.Errorf("custom StripParams: got %q", got)
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_Dedup(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		fmt.Fprint(w, `{"context":{"cost":{"total":1}},"result":{"success":true,"status":"DONE","status_code":200}}`)
+	})
+	configs := []*ScrapeConfig{
+		{URL: "https://example.com/product?id=1"},
+		{URL: "https://EXAMPLE.com/product?id=1&utm_campaign=spring"},
+		{URL: "https://example.com/product?id=2"},
+		{URL: "https://example.com/product?id=1", Method: HttpMethodPost, Body: "q=1"},
+		{URL: "https://example.com/product?id=1#reviews"},
+		{URL: "https://example.com/product?id=1", Country: "de"},
+		{URL: "https://example.com/product?id=1", Format: FormatMarkdown},
+	}
+	var indexes []int
+	var summary ConcurrentScrapeSummary
+	for item := range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{
+		Concurrency: 2,
+		Ordered:     true,
+		Dedup:       DefaultURLCanonicalizer,
+		OnComplete:  func(s ConcurrentScrapeSummary) { summary = s },
+	}) {
+		if item.Err != nil || item.Result == nil {
+			t.Fatalf("config %d: %v", item.Index, item.Err)
+		}
+		want := 1
+		if item.Index == 1 || item.Index == 4 {
+			want = 0 // duplicates of config 0
+		}
+		if item.Attempts != want {
+			t.Errorf("config %d made %d attempts, want %d", item.Index, item.Attempts, want)
+		}
+		indexes = append(indexes, item.Index)
+	}
+	if n := calls.Load(); n != 5 {
+		t.Errorf("%d scrapes, want 5 for the 5 distinct scrapes", n)
+	}
+	if fmt.Sprint(indexes) != "[0 1 2 3 4 5 6]" {
+		t.Errorf("results %v, want one per config in order", indexes)
+	}
+	if summary.Succeeded != 7 || summary.Duplicates != 2 || summary.Cost != 5 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_DedupPending(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("canceled run scraped")
+	})
+	configs := []*ScrapeConfig{
+		{URL: "https://example.com/a"},
+		{URL: "https://example.com/b"},
+		{URL: "https://example.com/a?utm_source=x"},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var summary ConcurrentScrapeSummary
+	for range client.ConcurrentScrapeWithOptionsContext(ctx, configs, ConcurrentScrapeOptions{
+		Concurrency: 1,
+		Dedup:       DefaultURLCanonicalizer,
+		OnComplete:  func(s ConcurrentScrapeSummary) { summary = s },
+	}) {
+	}
+	if fmt.Sprint(summary.PendingIndexes) != "[0 1 2]" || len(summary.Pending) != 3 {
+		t.Errorf("pending %v, want the duplicate of a pending config too", summary.PendingIndexes)
+	}
+}
@@ -0,0 +1,203 @@
+package scrapfly
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// captureScreenshotName is the screenshot FullCapture adds when the config
+// does not request one.
+const captureScreenshotName = "capture"
+
+// CaptureBundle is an evidence-grade capture of a single page: the rendered
+// HTML, a screenshot, the extracted record and the request metadata, all
+// produced by the same scrape so they describe the same page state.
+type CaptureBundle struct {
+	// HTML is the rendered page content.
+	HTML string
+	// Screenshot is the encoded screenshot, ScreenshotExtension its file extension.
+	Screenshot          []byte
+	ScreenshotExtension string
+	// Extracted is the extraction result when an extraction option was set, nil otherwise.
+	Extracted interface{}
+	// Metadata describes the capture and carries the integrity digests.
+	Metadata CaptureMetadata
+}
+
+// CaptureMetadata is the provenance record of a CaptureBundle.
+type CaptureMetadata struct {
+	URL              string    `json:"url"`
+	FinalURL         string    `json:"final_url"`
+	StatusCode       int       `json:"status_code"`
+	CapturedAt       time.Time `json:"captured_at"`
+	ScrapeUUID       string    `json:"scrape_uuid"`
+	LogURL           string    `json:"log_url"`
+	Country          string    `json:"country,omitempty"`
+	HTMLSHA256       string    `json:"html_sha256"`
+	ScreenshotSHA256 string    `json:"screenshot_sha256"`
+	ExtractedSHA256  string    `json:"extracted_sha256,omitempty"`
+}
+
+// CaptureSink stores capture bundles. Implementations must store a bundle
+// as a whole or not at all.
+type CaptureSink interface {
+	StoreCapture(bundle *CaptureBundle) error
+}
+
+// FullCapture scrapes config.URL with the browser and returns the HTML,
+// a full-page screenshot, the extracted record (when the config sets an
+// extraction option) and metadata as one bundle. config is not modified;
+// the capture forces RenderJS and adds a full-page screenshot unless the
+// config already requests screenshots, in which case the first one
+// (in name order) is used.
+//
+// Example:
+//
+//	bundle, err := client.FullCapture(&scrapfly.ScrapeConfig{
+//	    URL:              "https://web-scraping.dev/product/1",
+//	    ExtractionPrompt: "extract the product name and price",
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	sink := &scrapfly.DirCaptureSink{Dir: "./evidence"}
+//	if err := sink.StoreCapture(bundle); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *Client) FullCapture(config *ScrapeConfig) (*CaptureBundle, error) {
+	capture := *config
+	capture.RenderJS = true
+	if len(capture.Screenshots) == 0 {
+		capture.Screenshots = map[string]string{captureScreenshotName: "fullpage"}
+	}
+
+	result, err := c.Scrape(&capture)
+	if err != nil {
+		return nil, err
+	}
+	return newCaptureBundle(result)
+}
+
+// newCaptureBundle assembles and fingerprints a bundle from a scrape result.
+func newCaptureBundle(result *ScrapeResult) (*CaptureBundle, error) {
+	names := make([]string, 0, len(result.Result.Screenshots))
+	for name := range result.Result.Screenshots {
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: capture returned no screenshot", ErrScrapeFailed)
+	}
+	sort.Strings(names)
+	screenshot := result.Result.Screenshots[names[0]]
+	image, err := screenshot.Image()
+	if err != nil {
+		return nil, fmt.Errorf("failed to download capture screenshot: %w", err)
+	}
+
+	bundle := &CaptureBundle{
+		HTML:                result.Result.Content,
+		Screenshot:          image,
+		ScreenshotExtension: screenshot.Extension,
+		Metadata: CaptureMetadata{
+			URL:              result.Config.URL,
+			FinalURL:         result.Result.URL,
+			StatusCode:       result.Result.StatusCode,
+			CapturedAt:       time.Now().UTC(),
+			ScrapeUUID:       result.UUID,
+			LogURL:           result.Result.LogURL,
+			Country:          result.Context.Proxy.Country,
+			HTMLSHA256:       sha256Hex([]byte(result.Result.Content)),
+			ScreenshotSHA256: sha256Hex(image),
+		},
+	}
+	if result.Result.ExtractedData != nil {
+		bundle.Extracted = result.Result.ExtractedData.Data
+		extracted, err := marshalExtracted(bundle.Extracted)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal extracted data: %w", err)
+		}
+		bundle.Metadata.ExtractedSHA256 = sha256Hex(extracted)
+	}
+	return bundle, nil
+}
+
+// marshalExtracted encodes extracted data as written to extracted.json, the
+// bytes ExtractedSHA256 is the digest of.
+func marshalExtracted(data interface{}) ([]byte, error) {
+	return json.MarshalIndent(data, "", "  ")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// DirCaptureSink stores each bundle in its own directory under Dir:
+// page.html, screenshot.<ext>, extracted.json (when present) and
+// metadata.json. Files are written to a temporary directory that is renamed
+// into place, so a bundle directory is either complete or absent.
+type DirCaptureSink struct {
+	Dir string
+}
+
+var _ CaptureSink = (*DirCaptureSink)(nil)
+
+var captureNameRegex = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// StoreCapture writes bundle to <Dir>/<host>_<timestamp>_<scrape uuid>.
+func (s *DirCaptureSink) StoreCapture(bundle *CaptureBundle) error {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return err
+	}
+	host := bundle.Metadata.URL
+	if u, err := url.Parse(bundle.Metadata.URL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	name := captureNameRegex.ReplaceAllString(fmt.Sprintf("%s_%s_%s",
+		host, bundle.Metadata.CapturedAt.Format("20060102T150405Z"), bundle.Metadata.ScrapeUUID), "_")
+
+	tmp, err := os.MkdirTemp(s.Dir, ".capture-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	extension := bundle.ScreenshotExtension
+	if extension == "" {
+		extension = "png"
+	}
+	files := map[string][]byte{
+		"page.html":               []byte(bundle.HTML),
+		"screenshot." + extension: bundle.Screenshot,
+	}
+	if bundle.Extracted != nil {
+		extracted, err := marshalExtracted(bundle.Extracted)
+		if err != nil {
+			return err
+		}
+		files["extracted.json"] = extracted
+	}
+	metadata, err := json.MarshalIndent(bundle.Metadata, "", "  ")
+	if err != nil {
+		return err
+	}
+	files["metadata.json"] = metadata
+
+	for file, data := range files {
+		if err := os.WriteFile(filepath.Join(tmp, file), data, 0644); err != nil {
+			return err
+		}
+	}
+	if err := os.Chmod(tmp, 0755); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(s.Dir, name))
+}
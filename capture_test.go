@@ -0,0 +1,62 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestClient_FullCapture_BundlesAndStoresAtomically(t *testing.T) {
+	var serverURL string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/shot" {
+			w.Write([]byte("PNGDATA"))
+			return
+		}
+		if r.URL.Query().Get("render_js") != "true" || r.URL.Query().Get("screenshots[capture]") != "fullpage" {
+			t.Errorf("capture must render and take a full-page screenshot, got %s", r.URL.RawQuery)
+		}
+		fmt.Fprintf(w, `{"uuid":"u-1","config":{"url":"https://example.com/p"},"result":{"success":true,"status":"DONE","status_code":200,
+			"url":"https://example.com/p","content":"<html></html>",
+			"screenshots":{"capture":{"url":"%s/shot","extension":"png"}},
+			"extracted_data":{"data":{"price":10}}}}`, serverURL)
+	})
+	serverURL = client.host
+
+	bundle, err := client.FullCapture(&ScrapeConfig{URL: "https://example.com/p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bundle.Screenshot) != "PNGDATA" || bundle.HTML != "<html></html>" || bundle.Extracted == nil {
+		t.Fatalf("incomplete bundle %+v", bundle)
+	}
+	if bundle.Metadata.HTMLSHA256 != sha256Hex([]byte("<html></html>")) || bundle.Metadata.ExtractedSHA256 == "" {
+		t.Errorf("missing digests %+v", bundle.Metadata)
+	}
+
+	dir := t.TempDir()
+	if err := (&DirCaptureSink{Dir: dir}).StoreCapture(bundle); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("expected a single bundle directory, got %d entries", len(entries))
+	}
+	bundleDir := filepath.Join(dir, entries[0].Name())
+	for _, file := range []string{"page.html", "screenshot.png", "extracted.json", "metadata.json"} {
+		if _, err := os.Stat(filepath.Join(bundleDir, file)); err != nil {
+			t.Errorf("missing %s: %v", file, err)
+		}
+	}
+	if extracted, _ := os.ReadFile(filepath.Join(bundleDir, "extracted.json")); sha256Hex(extracted) != bundle.Metadata.ExtractedSHA256 {
+		t.Errorf("ExtractedSHA256 %s is not the digest of extracted.json", bundle.Metadata.ExtractedSHA256)
+	}
+	var metadata CaptureMetadata
+	data, _ := os.ReadFile(filepath.Join(bundleDir, "metadata.json"))
+	if err := json.Unmarshal(data, &metadata); err != nil || metadata.ScrapeUUID != "u-1" {
+		t.Errorf("unexpected metadata %+v, %v", metadata, err)
+	}
+}
@@ -0,0 +1,73 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+// transcodeToUTF8 converts the text content of an HTML or text result
+// served in another charset (GBK, Shift_JIS, Windows-1251...) to UTF-8 and
+// records the charset. The charset is taken from the byte order mark, the
+// content type, then the <meta> tags.
+//
+// Content is transcoded when it holds the raw bytes of the page, as large
+// objects do, or when it holds them as Latin-1 characters, which is how a
+// misdecoded page arrives. Content with characters beyond Latin-1 is
+// already decoded and kept as is.
+func (r *ScrapeResult) transcodeToUTF8() {
+	if r.rawContent != nil || r.Result.Format == "binary" || r.Result.Content == "" {
+		return
+	}
+	contentType := r.Result.ContentType
+	if contentType == "" {
+		contentType = r.Header("Content-Type")
+	}
+	if ct := strings.ToLower(contentType); ct != "" && !strings.HasPrefix(ct, "text/") && !strings.Contains(ct, "xml") {
+		return
+	}
+
+	content := r.Result.Content
+	valid := utf8.ValidString(content)
+	raw, transcodable := []byte(content), !valid
+	if valid {
+		if b, ok := latin1Bytes(content); ok {
+			raw, transcodable = b, true
+		}
+	}
+	head := raw
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	enc, name, certain := charset.DetermineEncoding(head, contentType)
+	if certain || !valid {
+		r.charset = name
+	}
+	if !transcodable || name == "utf-8" || (valid && !certain) {
+		return
+	}
+	decoded, err := enc.NewDecoder().Bytes(raw)
+	if err != nil {
+		return
+	}
+	r.Result.Content = string(decoded)
+}
+
+// latin1Bytes returns the bytes s holds as Latin-1 characters, false when
+// s has characters beyond Latin-1 or is plain ASCII, which needs no
+// transcoding.
+func latin1Bytes(s string) ([]byte, bool) {
+	out := make([]byte, 0, len(s))
+	high := false
+	for _, r := range s {
+		if r > 0xff {
+			return nil, false
+		}
+		high = high || r >= 0x80
+		out = append(out, byte(r))
+	}
+	return out, high
+}
@@ -0,0 +1,56 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestScrapeResult_TranscodeToUTF8(t *testing.T) {
+	cases := []struct {
+		name        string
+		contentType string
+		content     string
+		want        string
+		charset     string
+	}{
+		{"raw gbk bytes", "text/html; charset=gbk", "<p>\xd6\xd0\xce\xc4</p>", "<p>中文</p>", "gbk"},
+		{"meta charset", "text/html", `<meta charset="windows-1251"><p>` + "\xcf\xf0\xe8\xe2\xe5\xf2", `<meta charset="windows-1251"><p>Привет`, "windows-1251"},
+		{"latin-1 characters", "text/html; charset=windows-1251", "<p>Ïðèâåò</p>", "<p>Привет</p>", "windows-1251"},
+		{"already decoded", "text/html; charset=gbk", "<p>中文</p>", "<p>中文</p>", "gbk"},
+		{"utf-8", "text/html; charset=utf-8", "<p>café</p>", "<p>café</p>", "utf-8"},
+		{"undeclared latin-1 characters", "text/html", "<p>café</p>", "<p>café</p>", ""},
+		{"json", "application/json", `{"a":"\xe9"}`, `{"a":"\xe9"}`, ""},
+	}
+	for _, tc := range cases {
+		result := &ScrapeResult{Result: ResultData{ContentType: tc.contentType, Content: tc.content}}
+		result.transcodeToUTF8()
+		if result.Result.Content != tc.want || result.Charset() != tc.charset {
+			t.Errorf("%s: got %q (%q), want %q (%q)", tc.name, result.Result.Content, result.Charset(), tc.want, tc.charset)
+		}
+	}
+}
+
+func TestClient_Scrape_TranscodesContent(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": map[string]interface{}{
+			"success": true, "status": "DONE", "status_code": 200, "format": "text",
+			"content_type": "text/html; charset=windows-1251",
+			"content":      "<html><body><h1>Ïðèâåò</h1></body></html>",
+		}})
+	})
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.ru"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := result.Selector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.Find("h1").Text(); got != "Привет" || result.Charset() != "windows-1251" {
+		t.Errorf("h1 = %q, charset %q", got, result.Charset())
+	}
+}
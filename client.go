@@ -2,6 +2,7 @@ package scrapfly
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,6 +21,10 @@ const (
 	defaultRetries = 3
 	defaultDelay   = 1 * time.Second
 	sdkUserAgent   = "Scrapfly-Go-SDK"
+
+	// apiTimeoutMargin is added on top of an API-side timeout when extending
+	// the local HTTP deadline, covering network and response transfer time.
+	apiTimeoutMargin = 10 * time.Second
 )
 
 // Client is the main client for interacting with the Scrapfly API.
@@ -28,6 +34,17 @@ type Client struct {
 	host             string
 	cloudBrowserHost string
 	httpClient       *http.Client
+
+	// revalidating tracks in-flight stale-while-revalidate refreshes, keyed by method and URL.
+	revalidating sync.Map
+	// referers holds the per-session *RefererTracker used by ScrapeConfig.AutoReferer.
+	referers sync.Map
+	// referersSwept is when, in Unix nanoseconds, idle referers were last evicted.
+	referersSwept atomic.Int64
+	// auxPolicy overrides DefaultAuxiliaryPolicy for non-scrape calls when set.
+	auxPolicy *AuxiliaryPolicy
+	// domainDefaults holds the per-domain default *ScrapeConfig set with SetDomainDefaults.
+	domainDefaults sync.Map
 }
 
 // SetCloudBrowserHost overrides the default Cloud Browser host
@@ -113,6 +130,25 @@ func NewWithHost(key, host string, verifySSL bool) (*Client, error) {
 	}, nil
 }
 
+// httpClientFor returns an *http.Client whose deadline is at least as long as
+// the API-side timeout plus apiTimeoutMargin. The configured client is
+// returned unchanged when it already allows enough time (or has no
+// deadline); otherwise a shallow copy with an extended Timeout is used for
+// this call only, sharing the same transport.
+func (c *Client) httpClientFor(apiTimeout time.Duration) *http.Client {
+	hc := c.httpClient
+	if apiTimeout <= 0 || hc.Timeout == 0 {
+		return hc
+	}
+	need := apiTimeout + apiTimeoutMargin
+	if hc.Timeout >= need {
+		return hc
+	}
+	extended := *hc
+	extended.Timeout = need
+	return &extended
+}
+
 // APIKey returns the currently configured API key.
 func (c *Client) APIKey() string {
 	return c.key
@@ -148,7 +184,7 @@ func (c *Client) VerifyAPIKey() (*VerifyAPIKeyResult, error) {
 	}
 	req.Header.Set("User-Agent", sdkUserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doAuxiliary(req)
 	if err != nil {
 		return nil, err
 	}
@@ -186,39 +222,24 @@ func (c *Client) VerifyAPIKey() (*VerifyAPIKeyResult, error) {
 //	}
 //	fmt.Println(result.Result.Content)
 func (c *Client) Scrape(config *ScrapeConfig) (*ScrapeResult, error) {
-	DefaultLogger.Debug("scraping", "url", config.URL)
-
-	if err := config.processBody(); err != nil {
-		return nil, err
-	}
-	params, err := config.toAPIParamsWithValidation()
-	if err != nil {
-		return nil, err
-	}
-	params.Set("key", c.key)
+	return c.ScrapeContext(context.Background(), config)
+}
 
-	endpointURL, _ := url.Parse(c.host + "/scrape")
-	endpointURL.RawQuery = params.Encode()
+// ScrapeContext is Scrape bound to ctx: cancelling ctx aborts the request,
+// including retries and large object downloads, and returns ctx's error.
+func (c *Client) ScrapeContext(ctx context.Context, config *ScrapeConfig) (*ScrapeResult, error) {
+	ctx, config = c.correlate(ctx, config)
+	config = c.withDomainDefaults(config)
+	DefaultLogger.Debug(logArgs(ctx, "scraping", "url", config.URL)...)
 
-	method := "GET"
-	if config.Method != "" {
-		method = strings.ToUpper(config.Method.String())
-	}
-
-	req, err := http.NewRequest(method, endpointURL.String(), strings.NewReader(config.Body))
+	req, method, err := c.newScrapeRequest(config)
 	if err != nil {
 		return nil, err
 	}
-	req.GetBody = func() (io.ReadCloser, error) {
-		return io.NopCloser(strings.NewReader(config.Body)), nil
-	}
-	for key, value := range config.Headers {
-		req.Header.Set(key, value)
-	}
-	req.Header.Set("User-Agent", sdkUserAgent)
-	req.Header.Set("Accept", "application/json")
+	req = req.WithContext(ctx)
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	httpClient := c.httpClientFor(time.Duration(config.Timeout) * time.Millisecond)
+	resp, err := fetchWithRetry(httpClient, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return nil, err
 	}
@@ -228,6 +249,10 @@ func (c *Client) Scrape(config *ScrapeConfig) (*ScrapeResult, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusAccepted {
+		job := parseScrapeJob(resp, bodyBytes, config)
+		return nil, fmt.Errorf("%w: job %s will be delivered to webhook %q, use ScrapeWebhook to get the job", ErrScrapeQueued, job.UUID, job.WebhookName)
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, c.handleAPIErrorResponse(resp, bodyBytes)
 	}
@@ -259,28 +284,34 @@ func (c *Client) Scrape(config *ScrapeConfig) (*ScrapeResult, error) {
 	}
 
 	var result ScrapeResult
-	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+	// called directly, json.Unmarshal would scan the body twice more
+	if err := result.UnmarshalJSON(bodyBytes); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal scrape result: %w", err)
 	}
 	if result.Result.Success && result.Result.Status == "DONE" {
-		DefaultLogger.Debug("scrape log url:", result.Result.LogURL)
+		DefaultLogger.Debug(logArgs(ctx, "scrape log url:", result.Result.LogURL)...)
 
 		// handle large objects (clob/blob formats)
 		contentFormat := result.Result.Format
 		if contentFormat == "clob" || contentFormat == "blob" {
-			newContent, newFormat, err := c.handleLargeObjects(result.Result.Content, contentFormat)
+			newContent, newFormat, err := c.handleLargeObjects(ctx, result.Result.Content, contentFormat)
 			if err != nil {
 				return nil, fmt.Errorf("failed to fetch large object: %w", err)
 			}
 			result.Result.Content = newContent
 			result.Result.Format = newFormat
+			if newFormat == "binary" {
+				// blob content is raw, not base64 like inline binary content
+				result.rawContent = []byte(newContent)
+			}
 		}
+		result.transcodeToUTF8()
 		/////////////////////////////////////////
 
 		// Add back apiKey to screenshots URLs
 		for name, screenshot := range result.Result.Screenshots {
 			newScreenshot := Screenshot{
-				URL:         screenshot.URL + "?key=" + c.key,
+				URL:         withAPIKey(screenshot.URL, c.key),
 				Extension:   screenshot.Extension,
 				Format:      screenshot.Format,
 				Size:        screenshot.Size,
@@ -293,7 +324,7 @@ func (c *Client) Scrape(config *ScrapeConfig) (*ScrapeResult, error) {
 		// Add back apiKey to attachments URLs
 		for i, attachment := range result.Result.BrowserData.Attachments {
 			newAttachment := Attachment{
-				Content:           attachment.Content + "?key=" + c.key,
+				Content:           withAPIKey(attachment.Content, c.key),
 				ContentType:       attachment.ContentType,
 				Filename:          attachment.Filename,
 				ID:                attachment.ID,
@@ -306,23 +337,78 @@ func (c *Client) Scrape(config *ScrapeConfig) (*ScrapeResult, error) {
 		}
 		/////////////////////////////////////////
 
+		c.maybeRevalidate(config, &result)
+		if config.AutoReferer {
+			c.RefererTracker(config.Session).ObserveResult(&result)
+		}
+
 		return &result, nil
 	}
-	return nil, c.createErrorFromResult(&result)
+	if result.NotModified() {
+		DefaultLogger.Debug(logArgs(ctx, "not modified", "url", config.URL)...)
+		return &result, nil
+	}
+	err = errorFromResult(&result)
+	if config.Debug && result.Result.LogURL != "" {
+		DefaultLogger.Warn(logArgs(ctx, "scrape failed:", err, "debug url:", result.Result.LogURL)...)
+		err = fmt.Errorf("%w (debug: %s)", err, result.Result.LogURL)
+	}
+	return nil, err
+}
+
+// newScrapeRequest builds the /scrape API request for config and returns it
+// with the HTTP method used.
+func (c *Client) newScrapeRequest(config *ScrapeConfig) (*http.Request, string, error) {
+	if err := config.processBody(); err != nil {
+		return nil, "", err
+	}
+	params, err := config.toAPIParamsWithValidation()
+	if err != nil {
+		return nil, "", err
+	}
+	params.Set("key", c.key)
+	if config.AutoReferer && !hasHeader(config.Headers, "referer") {
+		if referer := c.RefererTracker(config.Session).Referer(config.URL); referer != "" {
+			params.Set("headers[referer]", referer)
+		}
+	}
+
+	endpointURL, _ := url.Parse(c.host + "/scrape")
+	endpointURL.RawQuery = params.Encode()
+
+	method := string(config.Method.normalize())
+
+	body, encoding := compressScrapeBody(config)
+	req, err := http.NewRequest(method, endpointURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, "", err
+	}
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	for key, value := range config.Headers {
+		req.Header.Set(key, value)
+	}
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", string(encoding))
+	}
+	req.Header.Set("User-Agent", sdkUserAgent)
+	req.Header.Set("Accept", "application/json")
+	return req, method, nil
 }
 
 // handleLargeObjects fetches content for large objects (clob/blob formats) using the internal API key.
-func (c *Client) handleLargeObjects(contentURL string, format string) (string, string, error) {
+func (c *Client) handleLargeObjects(ctx context.Context, contentURL string, format string) (string, string, error) {
 	parsedURL, err := url.Parse(contentURL)
 	if err != nil {
-		DefaultLogger.Error("failed to parse content URL:", err)
+		DefaultLogger.Error(logArgs(ctx, "failed to parse content URL:", err)...)
 		return "", "", err
 	}
 	params := parsedURL.Query()
 	params.Set("key", c.APIKey())
 	parsedURL.RawQuery = params.Encode()
 
-	req, err := http.NewRequest("GET", parsedURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", parsedURL.String(), nil)
 	if err != nil {
 		return "", "", err
 	}
@@ -332,7 +418,7 @@ func (c *Client) handleLargeObjects(contentURL string, format string) (string, s
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		DefaultLogger.Error("failed to fetch large object:", err)
+		DefaultLogger.Error(logArgs(ctx, "failed to fetch large object:", err)...)
 		return "", "", err
 	}
 	defer resp.Body.Close()
@@ -360,39 +446,6 @@ func (c *Client) handleLargeObjects(contentURL string, format string) (string, s
 	}
 }
 
-// ConcurrentScrapeResult is one entry in the channel returned by ConcurrentScrape.
-// Exactly one of Result and Error is non-nil per emission.
-//
-// This was previously an anonymous struct with embedded fields, which prevented
-// callers outside package scrapfly from accessing the error (Go's universe-scope
-// `error` type produces an unexported promoted field in anonymous structs).
-// Named exported fields make the result usable from any caller.
-type ConcurrentScrapeResult struct {
-	// Result is the successful scrape, or nil when Error is set.
-	Result *ScrapeResult
-	// Error is the failure, or nil when Result is set.
-	Error error
-}
-
-// ConcurrentScrape performs multiple scraping requests concurrently with controlled concurrency.
-// This is useful for scraping multiple pages efficiently while respecting rate limits.
-//
-// Parameters:
-//   - configs: A slice of ScrapeConfig objects to scrape
-//   - concurrencyLimit: Maximum number of concurrent requests. If <= 0, uses account's concurrent limit
-//
-// Returns a channel that emits ConcurrentScrapeResult values as scrapes complete.
-// Each entry has either Result (success) or Error (failure) set.
-//
-// Example:
-//
-//	configs := []*scrapfly.ScrapeConfig{
-//	    {URL: "https://example.com/page1"},
-//	    {URL: "https://example.com/page2"},
-//	    {URL: "https://example.com/page3"},
-//	}
-//	for item := range client.ConcurrentScrape(configs, 3) {
-//
 // ScrapeProxified sends a scrape request with proxified_response=true and returns
 // the raw upstream *http.Response. The caller owns resp.Body (must Close() it).
 //
@@ -404,7 +457,21 @@ type ConcurrentScrapeResult struct {
 // Use this when you want Scrapfly to act like an HTTP proxy and your code
 // already knows how to handle raw HTTP responses.
 func (c *Client) ScrapeProxified(config *ScrapeConfig) (*http.Response, error) {
+	return c.ScrapeProxifiedContext(context.Background(), config)
+}
+
+// ScrapeProxifiedContext is ScrapeProxified with a context; the context
+// also bounds reading the returned body.
+func (c *Client) ScrapeProxifiedContext(ctx context.Context, config *ScrapeConfig) (*http.Response, error) {
+	return c.scrapeProxified(ctx, config, false)
+}
+
+// scrapeProxified runs ScrapeProxifiedContext; a streamed scrape is not
+// bounded by the HTTP client timeout once the response headers arrived,
+// see streamingDo.
+func (c *Client) scrapeProxified(ctx context.Context, config *ScrapeConfig, stream bool) (*http.Response, error) {
 	config.ProxifiedResponse = true
+	config = c.withDomainDefaults(config)
 
 	if err := config.processBody(); err != nil {
 		return nil, err
@@ -418,21 +485,28 @@ func (c *Client) ScrapeProxified(config *ScrapeConfig) (*http.Response, error) {
 	endpointURL, _ := url.Parse(c.host + "/scrape")
 	endpointURL.RawQuery = params.Encode()
 
-	method := "GET"
-	if config.Method != "" {
-		method = strings.ToUpper(config.Method.String())
-	}
+	method := string(config.Method.normalize())
 
-	req, err := http.NewRequest(method, endpointURL.String(), strings.NewReader(config.Body))
+	body, encoding := compressScrapeBody(config)
+	req, err := http.NewRequestWithContext(ctx, method, endpointURL.String(), bytes.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
 	for key, value := range config.Headers {
 		req.Header.Set(key, value)
 	}
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", string(encoding))
+	}
 	req.Header.Set("User-Agent", sdkUserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	httpClient := c.httpClientFor(time.Duration(config.Timeout) * time.Millisecond)
+	var resp *http.Response
+	if stream {
+		resp, err = streamingDo(httpClient, req)
+	} else {
+		resp, err = httpClient.Do(req)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -455,60 +529,83 @@ func (c *Client) ScrapeProxified(config *ScrapeConfig) (*http.Response, error) {
 			HTTPStatusCode: resp.StatusCode,
 			Retryable:      retryable,
 			RetryAfterMs:   retryAfterMs,
+			LogURL:         resp.Header.Get("X-Scrapfly-Log"),
 		}
 	}
 	// Caller owns the body — do NOT defer resp.Body.Close() here.
 	return resp, nil
 }
 
-//	    if item.Error != nil {
-//	        log.Printf("Error: %v", item.Error)
+// ConcurrentScrapeResult is one entry in the channel returned by ConcurrentScrape.
+// Exactly one of Result and Err is non-nil per emission.
+//
+// This was previously an anonymous struct with embedded fields, which prevented
+// callers outside package scrapfly from accessing the error (Go's universe-scope
+// `error` type produces an unexported promoted field in anonymous structs).
+// Named exported fields make the result usable from any caller.
+type ConcurrentScrapeResult struct {
+	// Result is the successful scrape, or nil when Err is set.
+	Result *ScrapeResult
+	// Config is the config that was scraped, as passed in the input slice.
+	// It is nil for run-level errors, such as a failed account lookup.
+	Config *ScrapeConfig
+	// Index is the position of Config in the input slice, -1 for run-level
+	// errors.
+	Index int
+	// Attempts is the number of scrapes made for Config, more than one
+	// when ConcurrentScrapeOptions.Retry retried it.
+	Attempts int
+	// Err is the failure, or nil when Result is set.
+	Err error
+	// Error is the same value as Err.
+	//
+	// Deprecated: use Err.
+	Error error
+}
+
+// ConcurrentScrape performs multiple scraping requests concurrently with controlled concurrency.
+// This is useful for scraping multiple pages efficiently while respecting rate limits.
+//
+// Parameters:
+//   - configs: A slice of ScrapeConfig objects to scrape
+//   - concurrencyLimit: Maximum number of concurrent requests. If <= 0, uses account's concurrent limit
+//
+// Returns a channel that emits ConcurrentScrapeResult values as scrapes complete.
+// Each entry has either Result (success) or Err (failure) set, with the
+// Config it was scraped from and its Index in configs.
+//
+// Example:
+//
+//	configs := []*scrapfly.ScrapeConfig{
+//	    {URL: "https://example.com/page1"},
+//	    {URL: "https://example.com/page2"},
+//	    {URL: "https://example.com/page3"},
+//	}
+//	for item := range client.ConcurrentScrape(configs, 3) {
+//	    if item.Err != nil {
+//	        log.Printf("page %d: %v", item.Index, item.Err)
 //	        continue
 //	    }
-//	    fmt.Println(item.Result.Result.Content)
+//	    fmt.Println(item.Index, item.Result.Result.Content)
 //	}
 func (c *Client) ConcurrentScrape(configs []*ScrapeConfig, concurrencyLimit int) <-chan ConcurrentScrapeResult {
-	resultsChan := make(chan ConcurrentScrapeResult, len(configs))
-
-	var wg sync.WaitGroup
-
-	if concurrencyLimit <= 0 {
-		account, err := c.Account()
-		if err != nil {
-			resultsChan <- ConcurrentScrapeResult{
-				Result: nil,
-				Error:  fmt.Errorf("failed to get account for concurrency limit: %w", err),
-			}
-			close(resultsChan)
-			return resultsChan
-		}
-		concurrencyLimit = account.Subscription.Usage.Scrape.ConcurrentLimit
-		DefaultLogger.Info("concurrency not provided - setting it to", concurrencyLimit, "from account info")
-	}
-
-	jobs := make(chan *ScrapeConfig, len(configs))
-	for i := 0; i < concurrencyLimit; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			for config := range jobs {
-				result, err := c.Scrape(config)
-				resultsChan <- ConcurrentScrapeResult{Result: result, Error: err}
-			}
-		}()
-	}
-
-	for _, config := range configs {
-		jobs <- config
-	}
-	close(jobs)
-
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-	}()
+	return c.ConcurrentScrapeContext(context.Background(), configs, concurrencyLimit)
+}
 
-	return resultsChan
+// ConcurrentScrapeContext is ConcurrentScrape with a context: cancelling
+// it stops dispatching configs, aborts the scrapes in flight and closes
+// the results channel once they return. See
+// ConcurrentScrapeWithOptionsContext.
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+//	defer cancel()
+//	for item := range client.ConcurrentScrapeContext(ctx, configs, 5) {
+//	    ...
+//	}
+func (c *Client) ConcurrentScrapeContext(ctx context.Context, configs []*ScrapeConfig, concurrencyLimit int) <-chan ConcurrentScrapeResult {
+	return c.ConcurrentScrapeWithOptionsContext(ctx, configs, ConcurrentScrapeOptions{Concurrency: concurrencyLimit})
 }
 
 // Screenshot captures a screenshot of a web page using the provided configuration.
@@ -534,6 +631,11 @@ func (c *Client) ConcurrentScrape(configs []*ScrapeConfig, concurrencyLimit int)
 //	}
 //	// result.Image contains the screenshot bytes
 func (c *Client) Screenshot(config *ScreenshotConfig) (*ScreenshotResult, error) {
+	return c.ScreenshotContext(context.Background(), config)
+}
+
+// ScreenshotContext is Screenshot bound to ctx.
+func (c *Client) ScreenshotContext(ctx context.Context, config *ScreenshotConfig) (*ScreenshotResult, error) {
 	params, err := config.toAPIParams()
 	if err != nil {
 		return nil, err
@@ -543,13 +645,14 @@ func (c *Client) Screenshot(config *ScreenshotConfig) (*ScreenshotResult, error)
 	endpointURL, _ := url.Parse(c.host + "/screenshot")
 	endpointURL.RawQuery = params.Encode()
 
-	req, err := http.NewRequest("GET", endpointURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpointURL.String(), nil)
 	if err != nil {
 		return nil, err
 	}
 	req.Header.Set("User-Agent", sdkUserAgent)
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	httpClient := c.httpClientFor(time.Duration(config.Timeout) * time.Millisecond)
+	resp, err := fetchWithRetry(httpClient, req, defaultRetries, defaultDelay)
 	if err != nil {
 		return nil, err
 	}
@@ -563,7 +666,17 @@ func (c *Client) Screenshot(config *ScreenshotConfig) (*ScreenshotResult, error)
 		return nil, c.handleAPIErrorResponse(resp, bodyBytes)
 	}
 
-	return newScreenshotResult(resp, bodyBytes)
+	result, err := newScreenshotResult(resp, bodyBytes)
+	if err == nil {
+		result.Metadata.Resolution = params.Get("resolution")
+	}
+	if err != nil || (config.Clip == nil && config.Quality == 0) {
+		return result, err
+	}
+	if err := result.reencode(config.Clip, config.Quality); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
 // Extract performs AI-powered structured data extraction from HTML content.
@@ -584,6 +697,11 @@ func (c *Client) Screenshot(config *ScreenshotConfig) (*ScreenshotResult, error)
 //	}
 //	fmt.Printf("Extracted data: %+v\n", result.Data)
 func (c *Client) Extract(config *ExtractionConfig) (*ExtractionResult, error) {
+	return c.ExtractContext(context.Background(), config)
+}
+
+// ExtractContext is Extract bound to ctx.
+func (c *Client) ExtractContext(ctx context.Context, config *ExtractionConfig) (*ExtractionResult, error) {
 	params, err := config.toAPIParams()
 	if err != nil {
 		return nil, err
@@ -593,39 +711,59 @@ func (c *Client) Extract(config *ExtractionConfig) (*ExtractionResult, error) {
 	endpointURL, _ := url.Parse(c.host + "/extraction")
 	endpointURL.RawQuery = params.Encode()
 
-	req, err := http.NewRequest("POST", endpointURL.String(), bytes.NewReader(config.Body))
+	body, encoding := compressDocument(config)
+	resp, bodyBytes, err := c.postExtraction(ctx, endpointURL.String(), config, body, encoding)
 	if err != nil {
 		return nil, err
 	}
+	// Fall back to the raw document if the API rejects an encoding the SDK chose.
+	if resp.StatusCode == http.StatusUnsupportedMediaType && encoding != config.DocumentCompressionFormat {
+		DefaultLogger.Warn("extraction API rejected", string(encoding), "document, retrying uncompressed")
+		resp, bodyBytes, err = c.postExtraction(ctx, endpointURL.String(), config, config.Body, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleAPIErrorResponse(resp, bodyBytes)
+	}
+
+	var result ExtractionResult
+	if err := json.Unmarshal(bodyBytes, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal extraction result: %w", err)
+	}
+	return &result, nil
+}
+
+// postExtraction uploads body to the Extraction API and returns the
+// response with its fully read body.
+func (c *Client) postExtraction(ctx context.Context, endpoint string, config *ExtractionConfig, body []byte, encoding CompressionFormat) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
 	req.GetBody = func() (io.ReadCloser, error) {
-		return io.NopCloser(bytes.NewReader(config.Body)), nil
+		return io.NopCloser(bytes.NewReader(body)), nil
 	}
 	req.Header.Set("User-Agent", sdkUserAgent)
 	req.Header.Set("Content-Type", config.ContentType)
 	req.Header.Set("Accept", "application/json")
-	if config.DocumentCompressionFormat != "" {
-		req.Header.Set("Content-Encoding", string(config.DocumentCompressionFormat))
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", string(encoding))
 	}
 
-	resp, err := fetchWithRetry(c.httpClient, req, defaultRetries, defaultDelay)
+	httpClient := c.httpClientFor(time.Duration(config.Timeout) * time.Second)
+	resp, err := fetchWithRetry(httpClient, req, defaultRetries, defaultDelay)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
 	bodyBytes, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
 	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, c.handleAPIErrorResponse(resp, bodyBytes)
-	}
-
-	var result ExtractionResult
-	if err := json.Unmarshal(bodyBytes, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal extraction result: %w", err)
-	}
-	return &result, nil
+	return resp, bodyBytes, nil
 }
 
 // Account retrieves information about the current Scrapfly account.
@@ -656,7 +794,7 @@ func (c *Client) Account() (*AccountData, error) {
 	}
 	req.Header.Set("User-Agent", sdkUserAgent)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doAuxiliary(req)
 	if err != nil {
 		return nil, err
 	}
@@ -681,7 +819,7 @@ func (c *Client) handleAPIErrorResponse(resp *http.Response, body []byte) error
 	statusCode := resp.StatusCode
 
 	var result ScrapeResult
-	if err := json.Unmarshal(body, &result); err == nil {
+	if err := result.UnmarshalJSON(body); err == nil {
 		if result.Result.Error != nil {
 			apiErr := &APIError{
 				APIResponse:    &result,
@@ -742,7 +880,9 @@ func (c *Client) handleAPIErrorResponse(resp *http.Response, body []byte) error
 	return apiErr
 }
 
-func (c *Client) createErrorFromResult(result *ScrapeResult) error {
+// errorFromResult maps a failed scrape result to an APIError wrapped in the
+// sentinel error of its failure.
+func errorFromResult(result *ScrapeResult) error {
 	apiErr := &APIError{
 		APIResponse:    result,
 		HTTPStatusCode: result.Result.StatusCode,
@@ -758,29 +898,32 @@ func (c *Client) createErrorFromResult(result *ScrapeResult) error {
 
 	if !result.Result.Success {
 		if result.Result.StatusCode >= 400 && result.Result.StatusCode < 500 {
-			return fmt.Errorf("%w: %s", ErrUpstreamClient, apiErr)
+			return fmt.Errorf("%w: %w", ErrUpstreamClient, apiErr)
 		}
 		if result.Result.StatusCode >= 500 {
-			return fmt.Errorf("%w: %s", ErrUpstreamServer, apiErr)
+			return fmt.Errorf("%w: %w", ErrUpstreamServer, apiErr)
 		}
 	}
 
+	if strings.Contains(apiErr.Code, "BUDGET") {
+		return fmt.Errorf("%w: %w", ErrCostBudgetExceeded, apiErr)
+	}
 	if parts := strings.Split(result.Result.Status, "::"); len(parts) > 1 {
 		resource := parts[1]
 		switch resource {
 		case "SCRAPE":
-			return fmt.Errorf("%w: %s", ErrScrapeFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrScrapeFailed, apiErr)
 		case "PROXY":
-			return fmt.Errorf("%w: %s", ErrProxyFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrProxyFailed, apiErr)
 		case "ASP":
-			return fmt.Errorf("%w: %s", ErrASPBypassFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrASPBypassFailed, apiErr)
 		case "SCHEDULE":
-			return fmt.Errorf("%w: %s", ErrScheduleFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrScheduleFailed, apiErr)
 		case "WEBHOOK":
-			return fmt.Errorf("%w: %s", ErrWebhookFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrWebhookFailed, apiErr)
 		case "SESSION":
-			return fmt.Errorf("%w: %s", ErrSessionFailed, apiErr)
+			return fmt.Errorf("%w: %w", ErrSessionFailed, apiErr)
 		}
 	}
-	return fmt.Errorf("%w: %s", ErrUnhandledAPIResponse, apiErr)
+	return fmt.Errorf("%w: %w", ErrUnhandledAPIResponse, apiErr)
 }
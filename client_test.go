@@ -0,0 +1,54 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_HTTPClientForExtendsDeadline(t *testing.T) {
+	client, _ := New("__API_KEY__")
+	client.SetHTTPClient(&http.Client{Timeout: 30 * time.Second})
+
+	if got := client.httpClientFor(0); got != client.httpClient {
+		t.Error("zero API timeout must reuse the configured client")
+	}
+	if got := client.httpClientFor(10 * time.Second); got != client.httpClient {
+		t.Error("short API timeout must reuse the configured client")
+	}
+
+	extended := client.httpClientFor(60 * time.Second)
+	if extended == client.httpClient {
+		t.Fatal("long API timeout must not reuse the configured client")
+	}
+	if extended.Timeout < 60*time.Second+apiTimeoutMargin {
+		t.Errorf("extended timeout %v is shorter than the API timeout plus margin", extended.Timeout)
+	}
+	if client.httpClient.Timeout != 30*time.Second {
+		t.Error("the configured client must not be mutated")
+	}
+
+	client.SetHTTPClient(&http.Client{})
+	if got := client.httpClientFor(time.Hour); got != client.httpClient {
+		t.Error("a client without deadline must be reused as-is")
+	}
+}
+
+func TestClient_ScrapeContextCancelsRetries(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.ScrapeContext(ctx, &ScrapeConfig{URL: "https://example.com"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > defaultDelay {
+		t.Errorf("retry delay not interrupted: %s", elapsed)
+	}
+}
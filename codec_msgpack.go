@@ -0,0 +1,24 @@
+//go:build !scrapfly_nomsgpack
+
+package scrapfly
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// msgpackEnabled reports whether msgpack decoding is compiled in.
+const msgpackEnabled = true
+
+// sdkCodecs lists the wire encodings the SDK can decode.
+var sdkCodecs = []string{"json", "msgpack"}
+
+func decodeMsgpack(data []byte, v interface{}) error {
+	// ScrapeResult's fields use `json:` tags; tell the msgpack decoder to
+	// honor those so the same struct decodes correctly from either wire
+	// format.
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
@@ -0,0 +1,13 @@
+//go:build scrapfly_nomsgpack
+
+package scrapfly
+
+// msgpackEnabled reports whether msgpack decoding is compiled in.
+const msgpackEnabled = false
+
+// sdkCodecs lists the wire encodings the SDK can decode.
+var sdkCodecs = []string{"json"}
+
+func decodeMsgpack(data []byte, v interface{}) error {
+	return errMsgpackDisabled
+}
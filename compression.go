@@ -0,0 +1,135 @@
+package scrapfly
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+// DefaultCompressionThreshold is the body size above which
+// ExtractionConfig.AutoCompress and ScrapeConfig.CompressBody compress the
+// body when no threshold is set.
+const DefaultCompressionThreshold = 1 << 20 // 1 MiB
+
+// Compressor encodes a document body for upload. Compressors must be safe
+// for concurrent use.
+type Compressor func(data []byte) ([]byte, error)
+
+var (
+	compressorsMu sync.RWMutex
+	compressors   = map[CompressionFormat]Compressor{
+		GZIP:    gzipCompress,
+		DEFLATE: deflateCompress,
+	}
+)
+
+// autoCompressionOrder lists the formats AutoCompress tries, best first.
+var autoCompressionOrder = []CompressionFormat{ZSTD, GZIP}
+
+// RegisterCompressor makes a compression format available to
+// ExtractionConfig.AutoCompress. gzip and deflate are built in; zstd is
+// provided by github.com/scrapfly/go-scrapfly/contrib/zstd so the core SDK
+// does not depend on a zstd implementation.
+func RegisterCompressor(format CompressionFormat, compressor Compressor) {
+	compressorsMu.Lock()
+	defer compressorsMu.Unlock()
+	if compressor == nil {
+		delete(compressors, format)
+		return
+	}
+	compressors[format] = compressor
+}
+
+func lookupCompressor(format CompressionFormat) Compressor {
+	compressorsMu.RLock()
+	defer compressorsMu.RUnlock()
+	return compressors[format]
+}
+
+func gzipCompress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	return finishCompression(&buf, w, data)
+}
+
+func deflateCompress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	return finishCompression(&buf, w, data)
+}
+
+func finishCompression(buf *bytes.Buffer, w io.WriteCloser, data []byte) ([]byte, error) {
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// compressDocument returns the body to upload for config and its
+// Content-Encoding. The body is compressed only when AutoCompress is set,
+// the document is not already compressed and it is larger than the
+// threshold; formats are tried in autoCompressionOrder and any failure, or
+// an output that is not smaller, falls back to the next format and finally
+// to the raw body.
+func compressDocument(config *ExtractionConfig) ([]byte, CompressionFormat) {
+	if config.IsDocumentCompressed || config.DocumentCompressionFormat != "" {
+		return config.Body, config.DocumentCompressionFormat
+	}
+	threshold := config.CompressionThreshold
+	if threshold <= 0 {
+		threshold = DefaultCompressionThreshold
+	}
+	if !config.AutoCompress || len(config.Body) <= threshold {
+		return config.Body, ""
+	}
+	for _, format := range autoCompressionOrder {
+		compressor := lookupCompressor(format)
+		if compressor == nil {
+			continue
+		}
+		compressed, err := compressor(config.Body)
+		if err != nil {
+			DefaultLogger.Warn(string(format), "compression failed, trying next format:", err)
+			continue
+		}
+		if len(compressed) >= len(config.Body) {
+			continue
+		}
+		DefaultLogger.Debug("compressed extraction document with", string(format), len(config.Body), "->", len(compressed), "bytes")
+		return compressed, format
+	}
+	return config.Body, ""
+}
+
+// compressScrapeBody returns the body to upload for config and its
+// Content-Encoding, gzip-compressing it when CompressBody is set and the
+// body is larger than the threshold. Compression failures and outputs that
+// are not smaller fall back to the raw body.
+func compressScrapeBody(config *ScrapeConfig) ([]byte, CompressionFormat) {
+	body := []byte(config.Body)
+	threshold := config.BodyCompressionThreshold
+	if threshold <= 0 {
+		threshold = DefaultCompressionThreshold
+	}
+	if !config.CompressBody || len(body) <= threshold || hasHeader(config.Headers, "content-encoding") {
+		return body, ""
+	}
+	compressed, err := gzipCompress(body)
+	if err != nil {
+		DefaultLogger.Warn("gzip compression failed, sending raw body:", err)
+		return body, ""
+	}
+	if len(compressed) >= len(body) {
+		return body, ""
+	}
+	DefaultLogger.Debug("compressed scrape body with gzip", len(body), "->", len(compressed), "bytes")
+	return compressed, GZIP
+}
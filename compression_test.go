@@ -0,0 +1,136 @@
+package scrapfly
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCompressDocument_Threshold(t *testing.T) {
+	small := &ExtractionConfig{Body: []byte("<html></html>"), AutoCompress: true}
+	if body, enc := compressDocument(small); enc != "" || !bytes.Equal(body, small.Body) {
+		t.Errorf("small body should be sent raw, got encoding %q", enc)
+	}
+
+	large := &ExtractionConfig{Body: []byte(strings.Repeat("<p>row</p>", 1000)), AutoCompress: true, CompressionThreshold: 1024}
+	body, enc := compressDocument(large)
+	if enc != GZIP {
+		t.Fatalf("encoding = %q, want gzip fallback without zstd registered", enc)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := io.ReadAll(zr)
+	if !bytes.Equal(raw, large.Body) {
+		t.Error("gzip round trip mismatch")
+	}
+
+	large.AutoCompress = false
+	if _, enc := compressDocument(large); enc != "" {
+		t.Errorf("AutoCompress disabled, got encoding %q", enc)
+	}
+}
+
+func TestCompressDocument_PrefersRegisteredZstd(t *testing.T) {
+	RegisterCompressor(ZSTD, func(data []byte) ([]byte, error) { return []byte("zstd"), nil })
+	defer RegisterCompressor(ZSTD, nil)
+
+	config := &ExtractionConfig{Body: bytes.Repeat([]byte("a"), 2048), AutoCompress: true, CompressionThreshold: 1024}
+	if _, enc := compressDocument(config); enc != ZSTD {
+		t.Errorf("encoding = %q, want zstd", enc)
+	}
+}
+
+func TestCompressDocument_KeepsPrecompressedBody(t *testing.T) {
+	config := &ExtractionConfig{
+		Body:                      bytes.Repeat([]byte("a"), 2048),
+		AutoCompress:              true,
+		CompressionThreshold:      1024,
+		IsDocumentCompressed:      true,
+		DocumentCompressionFormat: GZIP,
+	}
+	if body, enc := compressDocument(config); enc != GZIP || !bytes.Equal(body, config.Body) {
+		t.Errorf("precompressed body must be sent as is, got encoding %q", enc)
+	}
+}
+
+func TestClient_Extract_FallsBackToRawBodyOnUnsupportedEncoding(t *testing.T) {
+	document := strings.Repeat("<p>row</p>", 1000)
+	var calls int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.Header.Get("Content-Encoding") != "" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			w.Write([]byte(`{"code":"ERR::EXTRACTION::UNSUPPORTED_ENCODING","message":"unsupported"}`))
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != document {
+			t.Errorf("fallback body is not the raw document")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"ok":true},"content_type":"application/json"}`))
+	})
+
+	result, err := client.Extract(&ExtractionConfig{
+		Body:                 []byte(document),
+		ContentType:          "text/html",
+		ExtractionPrompt:     "extract rows",
+		AutoCompress:         true,
+		CompressionThreshold: 1024,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Data == nil {
+		t.Error("expected extracted data")
+	}
+	if got := atomic.LoadInt32(&calls); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+}
+
+func TestClient_Scrape_CompressesLargeBody(t *testing.T) {
+	payload := strings.Repeat(`{"sku":"a-1","qty":1},`, 200)
+	var encoding string
+	var received []byte
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			t.Errorf("body is not gzip: %v", err)
+			return
+		}
+		received, _ = io.ReadAll(zr)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result":{"success":true,"status":"DONE","status_code":200}}`))
+	})
+
+	config := &ScrapeConfig{URL: "https://example.com", Method: HttpMethodPost, Body: payload, CompressBody: true, BodyCompressionThreshold: 1024}
+	if _, err := client.Scrape(config); err != nil {
+		t.Fatal(err)
+	}
+	if encoding != "gzip" || string(received) != payload {
+		t.Errorf("got encoding %q and %d body bytes, want gzip and %d", encoding, len(received), len(payload))
+	}
+}
+
+func TestCompressScrapeBody_SkipsSmallAndEncodedBodies(t *testing.T) {
+	small := &ScrapeConfig{Body: "a=b", CompressBody: true}
+	if body, encoding := compressScrapeBody(small); encoding != "" || string(body) != "a=b" {
+		t.Errorf("small body compressed: %q", encoding)
+	}
+	encoded := &ScrapeConfig{Body: strings.Repeat("a", 2048), CompressBody: true, BodyCompressionThreshold: 1024, Headers: map[string]string{"Content-Encoding": "br"}}
+	if _, encoding := compressScrapeBody(encoded); encoding != "" {
+		t.Errorf("pre-encoded body compressed again with %q", encoding)
+	}
+	disabled := &ScrapeConfig{Body: strings.Repeat("a", 2048), BodyCompressionThreshold: 1024}
+	if _, encoding := compressScrapeBody(disabled); encoding != "" {
+		t.Errorf("body compressed without CompressBody: %q", encoding)
+	}
+}
@@ -0,0 +1,80 @@
+package scrapfly
+
+import "context"
+
+// ConcurrentScreenshotResult is one entry in the channel returned by
+// ConcurrentScreenshot. Exactly one of Result and Err is non-nil.
+type ConcurrentScreenshotResult struct {
+	// Result is the screenshot, or nil when Err is set.
+	Result *ScreenshotResult
+	// Config is the config that was captured, nil for run-level errors.
+	Config *ScreenshotConfig
+	// Index is the position of Config in the input slice, -1 for run-level
+	// errors.
+	Index int
+	// Attempts is the number of requests made for Config.
+	Attempts int
+	// Err is the failure, or nil when Result is set.
+	Err error
+}
+
+// ConcurrentExtractionResult is one entry in the channel returned by
+// ConcurrentExtract. Exactly one of Result and Err is non-nil.
+type ConcurrentExtractionResult struct {
+	// Result is the extraction, or nil when Err is set.
+	Result *ExtractionResult
+	// Config is the config that was extracted, nil for run-level errors.
+	Config *ExtractionConfig
+	// Index is the position of Config in the input slice, -1 for run-level
+	// errors.
+	Index int
+	// Attempts is the number of requests made for Config.
+	Attempts int
+	// Err is the failure, or nil when Result is set.
+	Err error
+}
+
+// ConcurrentScreenshot captures configs concurrently with the engine of
+// ConcurrentScrapeWithOptionsContext: the same concurrency, per-host,
+// deadline, retry, throttling and progress options apply. The API does not
+// report screenshot costs, so CostBudget is never reached, and Dedup is
+// ignored. Summary.PendingIndexes lists the configs not dispatched.
+//
+// Example:
+//
+//	for item := range client.ConcurrentScreenshot(ctx, configs, scrapfly.ConcurrentScrapeOptions{Concurrency: 5}) {
+//	    if item.Err != nil {
+//	        log.Print(item.Err)
+//	        continue
+//	    }
+//	    item.Result.Save(fmt.Sprintf("page-%d", item.Index))
+//	}
+func (c *Client) ConcurrentScreenshot(ctx context.Context, configs []*ScreenshotConfig, opts ConcurrentScrapeOptions) <-chan ConcurrentScreenshotResult {
+	return runConcurrent(c, ctx, configs, nil, opts, nil, concurrentTask[*ScreenshotConfig, *ScreenshotResult, ConcurrentScreenshotResult]{
+		url:      func(config *ScreenshotConfig) string { return config.URL },
+		priority: func(*ScreenshotConfig) int { return 0 },
+		run:      c.ScreenshotContext,
+		cost:     func(*ScreenshotResult, error) int { return 0 },
+		item: func(result *ScreenshotResult, config *ScreenshotConfig, index, attempts int, err error) ConcurrentScreenshotResult {
+			return ConcurrentScreenshotResult{Result: result, Config: config, Index: index, Attempts: attempts, Err: err}
+		},
+	})
+}
+
+// ConcurrentExtract runs the extractions of configs concurrently with the
+// engine of ConcurrentScrapeWithOptionsContext, like ConcurrentScreenshot.
+// Per-host options apply to the URL of the configs, and configs without
+// one share a single host. Robots is ignored: extractions don't fetch the
+// URL.
+func (c *Client) ConcurrentExtract(ctx context.Context, configs []*ExtractionConfig, opts ConcurrentScrapeOptions) <-chan ConcurrentExtractionResult {
+	opts.Robots = nil
+	return runConcurrent(c, ctx, configs, nil, opts, nil, concurrentTask[*ExtractionConfig, *ExtractionResult, ConcurrentExtractionResult]{
+		url:      func(config *ExtractionConfig) string { return config.URL },
+		priority: func(*ExtractionConfig) int { return 0 },
+		run:      c.ExtractContext,
+		cost:     func(*ExtractionResult, error) int { return 0 },
+		item: func(result *ExtractionResult, config *ExtractionConfig, index, attempts int, err error) ConcurrentExtractionResult {
+			return ConcurrentExtractionResult{Result: result, Config: config, Index: index, Attempts: attempts, Err: err}
+		},
+	})
+}
@@ -0,0 +1,79 @@
+package scrapfly
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_ConcurrentScreenshot(t *testing.T) {
+	var calls atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// the first capture is throttled, then queued again
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"code":"ERR::THROTTLE::MAX_REQUEST_RATE_EXCEEDED","message":"too many requests"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("PNG " + r.URL.Query().Get("url")))
+	})
+	configs := make([]*ScreenshotConfig, 4)
+	for i := range configs {
+		configs[i] = &ScreenshotConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	var summary ConcurrentScrapeSummary
+	seen := map[int]bool{}
+	for item := range client.ConcurrentScreenshot(context.Background(), configs, ConcurrentScrapeOptions{
+		Concurrency: 2,
+		Adaptive:    &AdaptiveConcurrency{},
+		OnComplete:  func(s ConcurrentScrapeSummary) { summary = s },
+	}) {
+		if item.Err != nil {
+			t.Fatalf("screenshot %d: %v", item.Index, item.Err)
+		}
+		if want := "PNG " + configs[item.Index].URL; string(item.Result.Image) != want || item.Config != configs[item.Index] {
+			t.Errorf("screenshot %d = %q, want %q", item.Index, item.Result.Image, want)
+		}
+		seen[item.Index] = true
+	}
+	if len(seen) != len(configs) || summary.Succeeded != len(configs) {
+		t.Errorf("got %v, summary %+v", seen, summary)
+	}
+}
+
+func TestClient_ConcurrentExtract_Ordered(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		// earlier documents answer last
+		var doc int
+		fmt.Sscanf(string(body), "doc %d", &doc)
+		time.Sleep(time.Duration(3-doc) * 15 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"data":{"doc":%d},"content_type":"application/json"}`, doc)
+	})
+	configs := make([]*ExtractionConfig, 3)
+	for i := range configs {
+		configs[i] = &ExtractionConfig{Body: []byte(fmt.Sprintf("doc %d", i)), ContentType: "text/plain", ExtractionPrompt: "doc number"}
+	}
+
+	var indexes []int
+	for item := range client.ConcurrentExtract(context.Background(), configs, ConcurrentScrapeOptions{Concurrency: 3, Ordered: true}) {
+		if item.Err != nil {
+			t.Fatal(item.Err)
+		}
+		data, _ := item.Result.Data.(map[string]interface{})
+		if data["doc"] != float64(item.Index) {
+			t.Errorf("extraction %d returned %v", item.Index, item.Result.Data)
+		}
+		indexes = append(indexes, item.Index)
+	}
+	if fmt.Sprint(indexes) != "[0 1 2]" {
+		t.Errorf("results %v, want input order", indexes)
+	}
+}
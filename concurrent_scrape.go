@@ -0,0 +1,868 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ConcurrentScrapeOptions configures ConcurrentScrapeWithOptions.
+// The zero value behaves like ConcurrentScrape with the account limit.
+type ConcurrentScrapeOptions struct {
+	// Concurrency is the maximum number of scrapes in flight. When <= 0 the
+	// account's concurrent limit is used.
+	Concurrency int
+	// MaxRunDuration time-boxes the run: once the deadline nears no new
+	// config is dispatched, in-flight scrapes are allowed to finish, and the
+	// configs never dispatched are reported in ConcurrentScrapeSummary.Pending.
+	// Zero means no deadline.
+	MaxRunDuration time.Duration
+	// DeadlineMargin is how long before MaxRunDuration dispatching stops,
+	// typically the expected duration of a single scrape so that in-flight
+	// work still ends within the window. Defaults to zero (dispatch until the
+	// deadline itself).
+	DeadlineMargin time.Duration
+	// MaxPerHostConcurrency caps the scrapes in flight per host (the host
+	// of the config URL), so a batch of URLs on one site doesn't hammer it
+	// or exhaust its sessions. Configs of other hosts are dispatched ahead
+	// of the ones waiting for a slot, so different hosts still run in
+	// parallel. Zero means no cap.
+	MaxPerHostConcurrency int
+	// MinDelayPerHost is the minimum time between the starts of two
+	// scrapes of the same host. Zero means no delay.
+	MinDelayPerHost time.Duration
+	// SpreadOver spreads the run over a duration instead of bursting at
+	// the concurrency limit, to pace the spending and the load on the
+	// targets: configs are started at a steady rate, one every SpreadOver
+	// divided by their number, so the run lasts about SpreadOver, longer
+	// when the concurrency limit can't keep up or the run is paused. Zero
+	// means no pacing.
+	SpreadOver time.Duration
+	// Robots, when set, fails the configs whose URL robots.txt disallows
+	// with ErrRobotsDisallowed, without scraping them, and waits at least
+	// the Crawl-delay of each host between its scrapes. The robots.txt of
+	// a host is fetched before its first config is dispatched.
+	Robots *Robots
+	// AutoConcurrency sizes the run from the account instead of a
+	// hardcoded number: the scrapes in flight are capped to the account's
+	// concurrent_remaining, the slots its other jobs leave free, refreshed
+	// every AutoConcurrencyInterval. Concurrency, or the plan's concurrent
+	// limit when <= 0, stays the upper bound.
+	AutoConcurrency bool
+	// AutoConcurrencyInterval is how often AutoConcurrency queries the
+	// account. Defaults to 30 seconds.
+	AutoConcurrencyInterval time.Duration
+	// Dedup, when set, scrapes a page once when several configs scrape it
+	// alike: configs with the same method, body and API parameters, the URL
+	// as canonicalized by Dedup (see DefaultURLCanonicalizer), are not
+	// scraped and get the result of the first one, with Attempts 0. Configs
+	// differing by anything else (Country, RenderJS, Format, Headers...)
+	// are scraped each. The duplicates of a config left pending are in
+	// Summary.Pending too.
+	Dedup *URLCanonicalizer
+	// CostBudget caps the API credits of the run, as reported by the
+	// results, retries included. Once they are spent no new config is
+	// dispatched, the scrapes in flight finish (and may spend past the
+	// budget), a run-level result (Index -1) reports ErrCostBudgetExceeded
+	// and the configs left are in Summary.Pending. Zero means no budget.
+	CostBudget int
+	// PauseAtBudget pauses a BatchJob that spent its CostBudget instead of
+	// stopping it: the operator decides whether to Resume, past the
+	// budget, or to Stop.
+	PauseAtBudget bool
+	// Adaptive, when set, lowers the concurrency while the API throttles
+	// the run (429) and ramps it back up, queuing the throttled configs
+	// again instead of failing them. See AdaptiveConcurrency.
+	Adaptive *AdaptiveConcurrency
+	// Ordered emits the results in the order of the input configs instead
+	// of completion order: a result is held until the results of the
+	// configs before it have been emitted, so a slow scrape delays the ones
+	// after it (the scrapes themselves still run concurrently).
+	Ordered bool
+	// OnProgress, when set, is called after each scrape ends with the
+	// progress of the run, to drive dashboards and progress bars. Calls are
+	// serialized and made from the worker goroutines: a slow callback slows
+	// the run.
+	OnProgress func(progress ConcurrentScrapeProgress)
+	// Retry, when set, retries the transient failures of a config (proxy
+	// and ASP failures by default) inside the run, keeping its worker, and
+	// only the last failure is reported. See RetryPolicy.
+	Retry *RetryPolicy
+	// OnComplete, when set, is called once with the run summary after the
+	// last result has been sent and before the results channel is closed.
+	// Persist Summary.Pending there to resume the run later.
+	OnComplete func(summary ConcurrentScrapeSummary)
+}
+
+// ConcurrentScrapeSummary reports how a concurrent run ended.
+type ConcurrentScrapeSummary struct {
+	// Total is the number of configs the run was given.
+	Total int
+	// Succeeded and Failed count the scrapes that were dispatched.
+	Succeeded int
+	Failed    int
+	// Pending holds the configs that were never dispatched, in their
+	// original order; it is the checkpoint to resume from. It is only set
+	// by scrape runs, see PendingIndexes.
+	Pending []*ScrapeConfig
+	// PendingIndexes are the positions of the configs never dispatched in
+	// the input, for every kind of run.
+	PendingIndexes []int
+	// DeadlineReached reports whether MaxRunDuration stopped the dispatch.
+	DeadlineReached bool
+	// Canceled reports whether the context of the run was done before
+	// every config was dispatched.
+	Canceled bool
+	// Stopped reports whether BatchJob.Stop stopped the dispatch.
+	Stopped bool
+	// BudgetExceeded reports whether the run spent its CostBudget.
+	BudgetExceeded bool
+	// Duplicates counts the configs of Succeeded and Failed that were not
+	// scraped, sharing the result of a config with the same page (Dedup).
+	Duplicates int
+	// Cost is the API credits spent by the scrapes, retries included.
+	Cost int
+	// Elapsed is the wall-clock duration of the run.
+	Elapsed time.Duration
+}
+
+// ConcurrentScrapeProgress reports the progress of a running concurrent
+// run; see ConcurrentScrapeOptions.OnProgress.
+type ConcurrentScrapeProgress struct {
+	// Total is the number of configs the run was given.
+	Total int
+	// Succeeded and Failed count the scrapes that ended.
+	Succeeded int
+	Failed    int
+	// InFlight is the number of scrapes running.
+	InFlight int
+	// Cost is the API credits spent so far, retries included.
+	Cost int
+	// Elapsed is the time since the run started.
+	Elapsed time.Duration
+}
+
+// Completed returns the number of scrapes that ended, successfully or not.
+func (p ConcurrentScrapeProgress) Completed() int { return p.Succeeded + p.Failed }
+
+// CompletionPct returns the completed share of Total, from 0 to 100.
+func (p ConcurrentScrapeProgress) CompletionPct() float64 {
+	if p.Total == 0 {
+		return 100
+	}
+	return float64(p.Completed()) / float64(p.Total) * 100
+}
+
+// Remaining estimates the time left from the average pace so far, zero
+// before the first scrape ends. It assumes the remaining configs are all
+// dispatched.
+func (p ConcurrentScrapeProgress) Remaining() time.Duration {
+	completed := p.Completed()
+	if completed == 0 || completed >= p.Total {
+		return 0
+	}
+	return time.Duration(float64(p.Elapsed) / float64(completed) * float64(p.Total-completed))
+}
+
+// Completed returns the number of scrapes that finished, successfully or not.
+func (s ConcurrentScrapeSummary) Completed() int { return s.Succeeded + s.Failed }
+
+// CompletionPct returns the completed share of Total, from 0 to 100.
+func (s ConcurrentScrapeSummary) CompletionPct() float64 {
+	if s.Total == 0 {
+		return 100
+	}
+	return float64(s.Completed()) / float64(s.Total) * 100
+}
+
+// ConcurrentScrapeWithOptions is ConcurrentScrape with explicit options,
+// notably a time-boxed mode for jobs that must fit a batch window:
+//
+//	results := client.ConcurrentScrapeWithOptions(configs, scrapfly.ConcurrentScrapeOptions{
+//	    Concurrency:    10,
+//	    MaxRunDuration: 2 * time.Hour,
+//	    DeadlineMargin: time.Minute,
+//	    OnComplete: func(s scrapfly.ConcurrentScrapeSummary) {
+//	        log.Printf("%.1f%% done, %d configs left for tomorrow", s.CompletionPct(), len(s.Pending))
+//	    },
+//	})
+//	for item := range results {
+//	    ...
+//	}
+//
+// Configs are dispatched only when a worker is free, so the deadline is
+// checked right before every scrape starts. For the Crawler API, bound the
+// run server-side with CrawlerConfig.MaxDuration instead.
+func (c *Client) ConcurrentScrapeWithOptions(configs []*ScrapeConfig, opts ConcurrentScrapeOptions) <-chan ConcurrentScrapeResult {
+	return c.ConcurrentScrapeWithOptionsContext(context.Background(), configs, opts)
+}
+
+// ConcurrentScrapeWithOptionsContext is ConcurrentScrapeWithOptions with a
+// context. When ctx is done no new config is dispatched, in-flight scrapes
+// are aborted and reported with the context error, the configs never
+// dispatched are reported in ConcurrentScrapeSummary.Pending, and the
+// results channel is closed.
+//
+// Example:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer stop()
+//	results := client.ConcurrentScrapeWithOptionsContext(ctx, configs, scrapfly.ConcurrentScrapeOptions{
+//	    Concurrency: 10,
+//	    OnComplete: func(s scrapfly.ConcurrentScrapeSummary) {
+//	        if s.Canceled {
+//	            log.Printf("interrupted, %d configs left", len(s.Pending))
+//	        }
+//	    },
+//	})
+func (c *Client) ConcurrentScrapeWithOptionsContext(ctx context.Context, configs []*ScrapeConfig, opts ConcurrentScrapeOptions) <-chan ConcurrentScrapeResult {
+	return c.concurrentScrape(ctx, configs, opts, nil)
+}
+
+// concurrentScrape runs ConcurrentScrapeWithOptionsContext; control, when
+// not nil, pauses and stops the dispatch of a BatchJob.
+func (c *Client) concurrentScrape(ctx context.Context, configs []*ScrapeConfig, opts ConcurrentScrapeOptions, control *batchControl) <-chan ConcurrentScrapeResult {
+	return runConcurrent(c, ctx, configs, nil, opts, control, scrapeTask(c))
+}
+
+// scrapeTask is the scrape operation of the concurrent runs.
+func scrapeTask(c *Client) concurrentTask[*ScrapeConfig, *ScrapeResult, ConcurrentScrapeResult] {
+	return concurrentTask[*ScrapeConfig, *ScrapeResult, ConcurrentScrapeResult]{
+		url:      func(config *ScrapeConfig) string { return config.URL },
+		priority: func(config *ScrapeConfig) int { return config.Priority },
+		run:      c.ScrapeContext,
+		prepare:  c.correlate,
+		cost:     scrapeCost,
+		dedupKey: scrapeDedupKey,
+		item: func(result *ScrapeResult, config *ScrapeConfig, index, attempts int, err error) ConcurrentScrapeResult {
+			return ConcurrentScrapeResult{Result: result, Config: config, Index: index, Attempts: attempts, Err: err, Error: err}
+		},
+		pending: func(summary *ConcurrentScrapeSummary, configs []*ScrapeConfig) {
+			if summary.PendingIndexes == nil {
+				return
+			}
+			summary.Pending = make([]*ScrapeConfig, len(summary.PendingIndexes))
+			for i, index := range summary.PendingIndexes {
+				summary.Pending[i] = configs[index]
+			}
+		},
+	}
+}
+
+// scrapeDedupKey identifies the configs scraping the same page alike: the
+// method, the body and the API parameters, with the URL canonicalized.
+// Invalid configs get a key of their own, they fail each.
+func scrapeDedupKey(config *ScrapeConfig, canonicalizer *URLCanonicalizer) string {
+	canonical := config.Clone()
+	canonical.URL = canonicalizer.Canonicalize(config.URL)
+	params, err := canonical.APIParams()
+	if err != nil {
+		return fmt.Sprintf("invalid %p", config)
+	}
+	return string(config.Method.normalize()) + " " + params.Encode() + " " + config.Body
+}
+
+// concurrentTask is the operation of a concurrent run over configs of
+// type C, with results of type R emitted as O on the results channel.
+type concurrentTask[C, R, O any] struct {
+	// url and priority place a config in the host scheduler.
+	url      func(config C) string
+	priority func(config C) int
+	// run makes one attempt.
+	run func(ctx context.Context, config C) (R, error)
+	// prepare, when set, returns the ctx and config shared by the attempts
+	// of a config.
+	prepare func(ctx context.Context, config C) (context.Context, C)
+	// cost returns the credits an attempt spent.
+	cost func(result R, err error) int
+	// dedupKey, when set, identifies the configs of the same page for
+	// Dedup; runs without it ignore Dedup.
+	dedupKey func(config C, canonicalizer *URLCanonicalizer) string
+	// item builds an entry of the results channel, index is -1 for
+	// run-level errors.
+	item func(result R, config C, index, attempts int, err error) O
+	// pending, when set, fills the summary from its PendingIndexes and
+	// the configs of the run.
+	pending func(summary *ConcurrentScrapeSummary, configs []C)
+}
+
+// runConcurrent is the engine of the concurrent runs: it dispatches
+// configs to task.run with the limits, retries and controls of opts.
+// input, when not nil, streams more configs until it is closed; they are
+// read ahead of the workers, up to chanQueueSize queued, and Dedup and
+// SpreadOver, which need every config up front, are ignored.
+func runConcurrent[C, R, O any](c *Client, ctx context.Context, configs []C, input <-chan C, opts ConcurrentScrapeOptions, control *batchControl, task concurrentTask[C, R, O]) <-chan O {
+	var noResult R
+	var noConfig C
+	// room for every config and a CostBudget error: sends don't block.
+	// A stream has no end to size for, its results wait for the reader.
+	size := len(configs) + 1
+	if input != nil {
+		size = 1
+		opts.Dedup, opts.SpreadOver = nil, 0
+	}
+	resultsChan := make(chan O, size)
+	// configsMu guards configs, the dispatcher appends the streamed ones.
+	var configsMu sync.RWMutex
+	configAt := func(index int) C {
+		configsMu.RLock()
+		defer configsMu.RUnlock()
+		return configs[index]
+	}
+	complete := func(summary ConcurrentScrapeSummary) {
+		if task.pending != nil {
+			task.pending(&summary, configs)
+		}
+		if opts.OnComplete != nil {
+			opts.OnComplete(summary)
+		}
+	}
+	started := time.Now()
+
+	concurrencyLimit := opts.Concurrency
+	// remaining is the account's free concurrency for AutoConcurrency.
+	remaining := 0
+	if concurrencyLimit <= 0 || opts.AutoConcurrency {
+		account, err := c.Account()
+		if err != nil {
+			err = fmt.Errorf("failed to get account for concurrency limit: %w", err)
+			resultsChan <- task.item(noResult, noConfig, -1, 0, err)
+			all := make([]int, len(configs))
+			for i := range all {
+				all[i] = i
+			}
+			complete(ConcurrentScrapeSummary{Total: len(configs), PendingIndexes: all, Elapsed: time.Since(started)})
+			close(resultsChan)
+			return resultsChan
+		}
+		usage := account.Subscription.Usage.Scrape
+		if concurrencyLimit <= 0 {
+			concurrencyLimit = max(usage.ConcurrentLimit, 1)
+			DefaultLogger.Info("concurrency not provided - setting it to", concurrencyLimit, "from account info")
+		}
+		remaining = usage.ConcurrentRemaining
+	}
+
+	// deadlineC fires when dispatching must stop; nil (never fires) without
+	// MaxRunDuration.
+	var deadlineC <-chan time.Time
+	var timer *time.Timer
+	if opts.MaxRunDuration > 0 {
+		timer = time.NewTimer(opts.MaxRunDuration - opts.DeadlineMargin)
+		deadlineC = timer.C
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		summary = ConcurrentScrapeSummary{Total: len(configs)}
+		// inFlight counts the scrapes running, for OnProgress.
+		inFlight int
+		// held and next are the out of order results and the index of the
+		// next result to emit in Ordered mode.
+		held  = make(map[int]O)
+		next  = 0
+		sched = newHostScheduler(len(configs), func(i int) (string, int) { return task.url(configs[i]), task.priority(configs[i]) }, opts.MaxPerHostConcurrency, opts.MinDelayPerHost, opts.Robots != nil)
+	)
+	// duplicates maps each config to the configs sharing its result.
+	var duplicates map[int][]int
+	if opts.Dedup != nil && task.dedupKey != nil {
+		duplicates = duplicateConfigs(len(configs), func(i int) string { return task.dedupKey(configs[i], opts.Dedup) })
+		for _, dups := range duplicates {
+			sched.remove(dups...)
+		}
+	}
+	if opts.Adaptive != nil {
+		sched.enable(opts.Adaptive, concurrencyLimit)
+	}
+	if opts.SpreadOver > 0 {
+		sched.spread(opts.SpreadOver)
+	}
+	// runDone ends the refresh of AutoConcurrency with the run.
+	runDone := make(chan struct{})
+	if opts.AutoConcurrency {
+		sched.setCap(remaining)
+		DefaultLogger.Info("account has", remaining, "concurrent scrapes available, capping the run to it")
+		go c.refreshConcurrency(sched, opts.AutoConcurrencyInterval, runDone)
+	}
+
+	// budgetC is closed when the scrapes spent CostBudget.
+	budgetC := make(chan struct{})
+	// spend adds the credits of a scrape to the summary and enforces
+	// CostBudget; mu must be held.
+	spend := func(cost int) {
+		summary.Cost += cost
+		if opts.CostBudget <= 0 || summary.Cost < opts.CostBudget || summary.BudgetExceeded {
+			return
+		}
+		summary.BudgetExceeded = true
+		err := fmt.Errorf("%w: run spent %d of %d credits", ErrCostBudgetExceeded, summary.Cost, opts.CostBudget)
+		resultsChan <- task.item(noResult, noConfig, -1, 0, err)
+		if control != nil && opts.PauseAtBudget {
+			DefaultLogger.Info("run cost budget reached, pausing dispatch")
+			control.pause()
+			return
+		}
+		close(budgetC)
+	}
+
+	// Unbuffered: a config is handed over only when a worker is ready, so
+	// nothing is queued past the deadline.
+	jobs := make(chan int)
+	for i := 0; i < concurrencyLimit; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				config := configAt(index)
+				mu.Lock()
+				inFlight++
+				mu.Unlock()
+				var result R
+				var attempts, cost int
+				var err error
+				if opts.Robots != nil {
+					err = opts.Robots.check(ctx, task.url(config))
+				}
+				if err == nil {
+					runCtx, runConfig := ctx, config
+					if task.prepare != nil {
+						runCtx, runConfig = task.prepare(ctx, config)
+					}
+					result, attempts, cost, err = retrying(runCtx, opts.Retry, task.url(config), func(ctx context.Context) (R, error) { return task.run(ctx, runConfig) }, task.cost)
+				}
+				if throttled, retryAfter := isThrottled(err); throttled && opts.Adaptive != nil {
+					if sched.throttled(index, time.Now(), retryAfter) {
+						mu.Lock()
+						inFlight--
+						spend(cost)
+						mu.Unlock()
+						continue
+					}
+					// not queued again, the throttled error is its result
+				} else {
+					sched.done(index, err == nil)
+				}
+				items := []O{task.item(result, config, index, attempts, err)}
+				indexes := []int{index}
+				for _, dup := range duplicates[index] {
+					items = append(items, task.item(result, configAt(dup), dup, 0, err))
+					indexes = append(indexes, dup)
+				}
+				mu.Lock()
+				inFlight--
+				spend(cost)
+				summary.Duplicates += len(items) - 1
+				if err != nil {
+					summary.Failed += len(items)
+				} else {
+					summary.Succeeded += len(items)
+				}
+				if opts.OnProgress != nil {
+					opts.OnProgress(ConcurrentScrapeProgress{
+						Total:     summary.Total,
+						Succeeded: summary.Succeeded,
+						Failed:    summary.Failed,
+						InFlight:  inFlight,
+						Cost:      summary.Cost,
+						Elapsed:   time.Since(started),
+					})
+				}
+				for i, item := range items {
+					if !opts.Ordered {
+						resultsChan <- item
+						continue
+					}
+					held[indexes[i]] = item
+					for item, ok := held[next]; ok; item, ok = held[next] {
+						resultsChan <- item
+						delete(held, next)
+						next++
+					}
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	// stoppedC is closed by BatchJob.Stop and changedC signals a pause;
+	// both are nil (never fire) without a BatchJob.
+	var stoppedC, changedC <-chan struct{}
+	if control != nil {
+		stoppedC, changedC = control.stopped, control.changed
+	}
+
+	go func() {
+		stop := func() {
+			summary.DeadlineReached = true
+			summary.PendingIndexes = sched.halt()
+			DefaultLogger.Info("run deadline reached, stopping dispatch with", len(summary.PendingIndexes), "configs pending")
+		}
+		cancel := func() {
+			summary.Canceled = true
+			summary.PendingIndexes = sched.halt()
+			DefaultLogger.Info("run canceled, stopping dispatch with", len(summary.PendingIndexes), "configs pending")
+		}
+		stopped := func() {
+			summary.Stopped = true
+			summary.PendingIndexes = sched.halt()
+			DefaultLogger.Info("run stopped, stopping dispatch with", len(summary.PendingIndexes), "configs pending")
+		}
+		overBudget := func() {
+			summary.PendingIndexes = sched.halt()
+			DefaultLogger.Info("run cost budget reached, stopping dispatch with", len(summary.PendingIndexes), "configs pending")
+		}
+		// accept queues a config streamed from input, or ends the stream.
+		accept := func(config C, open bool) {
+			if !open {
+				input = nil
+				return
+			}
+			configsMu.Lock()
+			configs = append(configs, config)
+			configsMu.Unlock()
+			sched.add(task.url(config), task.priority(config))
+			mu.Lock()
+			summary.Total++
+			mu.Unlock()
+		}
+	dispatch:
+		for {
+			// Check the deadline and ctx first: select picks randomly between ready cases.
+			select {
+			case <-ctx.Done():
+				cancel()
+				break dispatch
+			case <-deadlineC:
+				stop()
+				break dispatch
+			case <-stoppedC:
+				stopped()
+				break dispatch
+			case <-budgetC:
+				overBudget()
+				break dispatch
+			default:
+			}
+			if control != nil {
+				if resumed := control.pausedUntil(); resumed != nil {
+					select {
+					case <-resumed:
+					case <-ctx.Done():
+						cancel()
+						break dispatch
+					case <-deadlineC:
+						stop()
+						break dispatch
+					case <-stoppedC:
+						stopped()
+						break dispatch
+					case <-budgetC:
+						overBudget()
+						break dispatch
+					}
+					continue
+				}
+			}
+			// receive reads the stream while few configs are queued
+			receive := input
+			if receive != nil && sched.queued() >= chanQueueSize {
+				receive = nil
+			}
+			index, wait, ok := sched.next(time.Now())
+			if !ok && input == nil {
+				break dispatch
+			}
+			if index < 0 {
+				// every host with configs left is at its limit, or the
+				// stream has none queued
+				var delayC <-chan time.Time
+				if wait > 0 {
+					delayC = time.After(wait)
+				}
+				select {
+				case config, open := <-receive:
+					accept(config, open)
+				case <-sched.freed:
+				case <-delayC:
+				case <-changedC:
+				case <-ctx.Done():
+					cancel()
+					break dispatch
+				case <-deadlineC:
+					stop()
+					break dispatch
+				case <-stoppedC:
+					stopped()
+					break dispatch
+				case <-budgetC:
+					overBudget()
+					break dispatch
+				}
+				continue
+			}
+			if opts.Robots != nil {
+				// fetches the robots.txt of a new host, the workers check
+				// the rules from the cache
+				if delay, err := opts.Robots.CrawlDelay(ctx, task.url(configAt(index))); err == nil && sched.setHostDelay(index, delay) {
+					// the host may have to wait now
+					continue
+				}
+			}
+			select {
+			case jobs <- index:
+				sched.start(index, time.Now())
+			case config, open := <-receive:
+				// the config at index stays queued, behind a more urgent
+				// one maybe
+				accept(config, open)
+			case <-changedC:
+				// paused while waiting for a worker, the config stays queued
+			case <-ctx.Done():
+				cancel()
+				break dispatch
+			case <-deadlineC:
+				stop()
+				break dispatch
+			case <-stoppedC:
+				stopped()
+				break dispatch
+			case <-budgetC:
+				overBudget()
+				break dispatch
+			}
+		}
+		close(jobs)
+		if timer != nil {
+			timer.Stop()
+		}
+		wg.Wait()
+		close(runDone)
+		// the duplicates of the configs never dispatched were not either
+		if pending := summary.PendingIndexes; pending != nil && duplicates != nil {
+			for _, index := range pending {
+				summary.PendingIndexes = append(summary.PendingIndexes, duplicates[index]...)
+			}
+			sort.Ints(summary.PendingIndexes)
+		}
+
+		// with per-host limits configs are not dispatched in order, so
+		// results after a config left pending are still held
+		if opts.Ordered && len(held) > 0 {
+			indexes := make([]int, 0, len(held))
+			for index := range held {
+				indexes = append(indexes, index)
+			}
+			sort.Ints(indexes)
+			for _, index := range indexes {
+				resultsChan <- held[index]
+			}
+		}
+		summary.Elapsed = time.Since(started)
+		complete(summary)
+		close(resultsChan)
+	}()
+
+	return resultsChan
+}
+
+// ConcurrentScrapeChan scrapes the configs received from configs with up
+// to workers scrapes in flight (the account's concurrent limit when <= 0),
+// for producers that discover URLs on the fly, such as a crawler feeding
+// the links it finds. Index is the position of a config in the stream.
+//
+// Configs are read ahead of the workers, up to 1024, and dispatched by
+// Priority: a queued config gains one level every 30 seconds it waits.
+//
+// The results channel is closed once configs is closed and its scrapes are
+// done, or once ctx is done: then no more configs are read, queued configs
+// are dropped and in-flight scrapes are aborted. Results must be read for the run to progress;
+// results still unread when ctx is done are dropped.
+//
+// Example:
+//
+//	configs := make(chan *scrapfly.ScrapeConfig)
+//	go func() {
+//	    defer close(configs)
+//	    for url := range discovered {
+//	        configs <- &scrapfly.ScrapeConfig{URL: url}
+//	    }
+//	}()
+//	for item := range client.ConcurrentScrapeChan(ctx, configs, 5) {
+//	    ...
+//	}
+func (c *Client) ConcurrentScrapeChan(ctx context.Context, configs <-chan *ScrapeConfig, workers int) <-chan ConcurrentScrapeResult {
+	resultsChan := make(chan ConcurrentScrapeResult)
+	go func() {
+		defer close(resultsChan)
+		if workers <= 0 {
+			account, err := c.Account()
+			if err != nil {
+				err = fmt.Errorf("failed to get account for concurrency limit: %w", err)
+				select {
+				case resultsChan <- ConcurrentScrapeResult{Index: -1, Err: err, Error: err}:
+				case <-ctx.Done():
+				}
+				return
+			}
+			workers = max(account.Subscription.Usage.Scrape.ConcurrentLimit, 1)
+			DefaultLogger.Info("concurrency not provided - setting it to", workers, "from account info")
+		}
+
+		type job struct {
+			index  int
+			config *ScrapeConfig
+		}
+		jobs := make(chan job)
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := range jobs {
+					result, err := c.ScrapeContext(ctx, j.config)
+					select {
+					case resultsChan <- ConcurrentScrapeResult{Result: result, Config: j.config, Index: j.index, Attempts: 1, Err: err, Error: err}:
+					case <-ctx.Done():
+					}
+				}
+			}()
+		}
+
+		// read ahead of the workers so urgent configs can overtake
+		epoch := time.Now()
+		var queue priorityQueue
+		input := configs
+	dispatch:
+		for index := 0; input != nil || queue.Len() > 0; {
+			var next job
+			var send chan job
+			if queue.Len() > 0 {
+				top := queue[0]
+				next, send = job{index: top.index, config: top.config}, jobs
+			}
+			receive := input
+			if queue.Len() >= chanQueueSize {
+				receive = nil
+			}
+			select {
+			case config, ok := <-receive:
+				if !ok {
+					input = nil
+					continue
+				}
+				queue.push(index, config, epoch)
+				index++
+			case send <- next:
+				queue.pop()
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+		close(jobs)
+		wg.Wait()
+	}()
+	return resultsChan
+}
+
+// ConcurrentScrapeChanWithOptions is ConcurrentScrapeChan with the options
+// of ConcurrentScrapeWithOptionsContext: per-host limits, retries,
+// Adaptive, CostBudget, progress and summary apply to the streamed
+// configs. Index is the position of a config in the stream, Total in
+// progress and summary counts the configs received so far, and
+// Summary.Pending holds the configs received and never dispatched. Dedup
+// and SpreadOver, which need every config up front, are ignored.
+//
+// Configs are read ahead of the workers, up to 1024, and dispatched by
+// Priority. Once the run stops (ctx done, MaxRunDuration, CostBudget),
+// configs is no longer read: a producer should stop sending once ctx is
+// done or the results channel is closed. Results must be
+// read until the channel is closed for the run to progress.
+func (c *Client) ConcurrentScrapeChanWithOptions(ctx context.Context, configs <-chan *ScrapeConfig, opts ConcurrentScrapeOptions) <-chan ConcurrentScrapeResult {
+	return runConcurrent(c, ctx, nil, configs, opts, nil, scrapeTask(c))
+}
+
+// ScrapeAll scrapes configs concurrently, up to the account's concurrent
+// limit, and yields the results in completion order. Errors carry the URL
+// of the failed config and wrap the scrape error. Breaking out of the loop
+// stops dispatching configs and aborts the scrapes in flight; ScrapeAll
+// returns once they have exited. Use ConcurrentScrapeWithOptionsContext
+// for an explicit concurrency or ordered results.
+//
+// Example:
+//
+//	for result, err := range client.ScrapeAll(ctx, configs) {
+//	    if err != nil {
+//	        log.Print(err)
+//	        continue
+//	    }
+//	    if strings.Contains(result.Result.Content, "out of stock") {
+//	        break // cancels the remaining scrapes
+//	    }
+//	}
+func (c *Client) ScrapeAll(ctx context.Context, configs []*ScrapeConfig) iter.Seq2[*ScrapeResult, error] {
+	return func(yield func(*ScrapeResult, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		results := c.ConcurrentScrapeWithOptionsContext(ctx, configs, ConcurrentScrapeOptions{})
+		for item := range results {
+			err := item.Err
+			if err != nil && item.Config != nil {
+				err = fmt.Errorf("failed to scrape %s: %w", item.Config.URL, err)
+			}
+			if !yield(item.Result, err) {
+				cancel()
+				// wait for the aborted scrapes
+				for range results {
+				}
+				return
+			}
+		}
+	}
+}
+
+// ScrapeAllSync scrapes configs with up to concurrency scrapes in flight
+// (the account's concurrent limit when <= 0) and returns once all are
+// done. results[i] is the result of configs[i], nil when it failed; the
+// error joins the failures (see errors.Join), each carrying its URL, and is
+// nil when every scrape succeeded. Cancelling ctx aborts the run: the
+// configs not scraped fail with the context error.
+//
+// Example:
+//
+//	results, err := client.ScrapeAllSync(ctx, configs, 10)
+//	if err != nil {
+//	    log.Printf("some pages failed: %v", err)
+//	}
+//	for i, result := range results {
+//	    if result != nil {
+//	        store(configs[i].URL, result)
+//	    }
+//	}
+func (c *Client) ScrapeAllSync(ctx context.Context, configs []*ScrapeConfig, concurrency int) ([]*ScrapeResult, error) {
+	results := make([]*ScrapeResult, len(configs))
+	done := make([]bool, len(configs))
+	var errs []error
+	for item := range c.ConcurrentScrapeWithOptionsContext(ctx, configs, ConcurrentScrapeOptions{Concurrency: concurrency, Ordered: true}) {
+		if item.Index < 0 {
+			// run-level failure, nothing was scraped
+			return results, item.Err
+		}
+		done[item.Index] = true
+		if item.Err != nil {
+			errs = append(errs, fmt.Errorf("failed to scrape %s: %w", item.Config.URL, item.Err))
+			continue
+		}
+		results[item.Index] = item.Result
+	}
+	for i, config := range configs {
+		if !done[i] {
+			errs = append(errs, fmt.Errorf("failed to scrape %s: %w", config.URL, context.Cause(ctx)))
+		}
+	}
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,638 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_ConcurrentScrapeWithOptions_StopsDispatchAtDeadline(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+
+	configs := make([]*ScrapeConfig, 20)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	var summary ConcurrentScrapeSummary
+	results := client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{
+		Concurrency:    2,
+		MaxRunDuration: 120 * time.Millisecond,
+		OnComplete:     func(s ConcurrentScrapeSummary) { summary = s },
+	})
+	received := 0
+	for item := range results {
+		if item.Err != nil {
+			t.Errorf("unexpected error: %v", item.Err)
+		}
+		received++
+	}
+
+	if !summary.DeadlineReached {
+		t.Fatal("expected the deadline to stop the run")
+	}
+	if received != summary.Completed() || received == 0 || received == len(configs) {
+		t.Errorf("received %d results, summary reports %d completed", received, summary.Completed())
+	}
+	if received+len(summary.Pending) != len(configs) {
+		t.Errorf("in-flight work lost: %d completed + %d pending != %d", received, len(summary.Pending), len(configs))
+	}
+	if summary.Pending[0].URL != fmt.Sprintf("https://example.com/%d", received) {
+		t.Errorf("pending configs must keep their order, first is %s", summary.Pending[0].URL)
+	}
+	if pct := summary.CompletionPct(); pct <= 0 || pct >= 100 {
+		t.Errorf("unexpected completion %.1f%%", pct)
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_NoDeadlineRunsEverything(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+	configs := []*ScrapeConfig{{URL: "https://example.com/a"}, {URL: "https://example.com/b"}, {URL: "https://example.com/c"}}
+
+	var summary ConcurrentScrapeSummary
+	for range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{Concurrency: 2, OnComplete: func(s ConcurrentScrapeSummary) { summary = s }}) {
+	}
+	if summary.Succeeded != 3 || len(summary.Pending) != 0 || summary.DeadlineReached || summary.CompletionPct() != 100 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+}
+
+func TestClient_ConcurrentScrape_ResultFields(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("url") == "https://example.com/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message":"invalid url","code":"ERR::SCRAPE::BAD_URL","http_code":400}`)
+			return
+		}
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+	configs := []*ScrapeConfig{{URL: "https://example.com/a"}, {URL: "https://example.com/fail"}, {URL: "https://example.com/c"}}
+
+	seen := map[int]bool{}
+	for item := range client.ConcurrentScrape(configs, 2) {
+		if item.Index < 0 || item.Index >= len(configs) || item.Config != configs[item.Index] {
+			t.Fatalf("item %d has config %v", item.Index, item.Config)
+		}
+		seen[item.Index] = true
+		if failed := item.Index == 1; failed != (item.Err != nil) || item.Err != item.Error || (item.Result == nil) != failed {
+			t.Errorf("item %d: result %v, err %v", item.Index, item.Result, item.Err)
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("got results for %v", seen)
+	}
+}
+
+func TestClient_ConcurrentScrapeContext_Cancel(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	configs := make([]*ScrapeConfig, 10)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var summary ConcurrentScrapeSummary
+	results := client.ConcurrentScrapeWithOptionsContext(ctx, configs, ConcurrentScrapeOptions{
+		Concurrency: 2,
+		OnComplete:  func(s ConcurrentScrapeSummary) { summary = s },
+	})
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	done := make(chan int)
+	go func() {
+		received := 0
+		for item := range results {
+			if !errors.Is(item.Err, context.Canceled) {
+				t.Errorf("item %d: err %v, want context.Canceled", item.Index, item.Err)
+			}
+			received++
+		}
+		done <- received
+	}()
+	select {
+	case received := <-done:
+		if !summary.Canceled || received < 2 || received+len(summary.Pending) != len(configs) {
+			t.Errorf("received %d, summary %+v", received, summary)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("results channel not closed after cancel")
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_Ordered(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// earlier pages answer last
+		var page int
+		fmt.Sscanf(r.URL.Query().Get("url"), "https://example.com/%d", &page)
+		time.Sleep(time.Duration(5-page) * 20 * time.Millisecond)
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+	configs := make([]*ScrapeConfig, 5)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	var indexes []int
+	for item := range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{Concurrency: 5, Ordered: true}) {
+		if item.Err != nil {
+			t.Fatal(item.Err)
+		}
+		indexes = append(indexes, item.Index)
+	}
+	for i, index := range indexes {
+		if index != i {
+			t.Fatalf("results out of order: %v", indexes)
+		}
+	}
+	if len(indexes) != len(configs) {
+		t.Errorf("got %d results", len(indexes))
+	}
+}
+
+func TestClient_ConcurrentScrapeChan(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+	configs := make(chan *ScrapeConfig)
+	go func() {
+		defer close(configs)
+		for i := 0; i < 6; i++ {
+			configs <- &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+		}
+	}()
+
+	seen := map[int]bool{}
+	for item := range client.ConcurrentScrapeChan(context.Background(), configs, 3) {
+		if item.Err != nil {
+			t.Fatal(item.Err)
+		}
+		if item.Config.URL != fmt.Sprintf("https://example.com/%d", item.Index) {
+			t.Errorf("item %d has config %s", item.Index, item.Config.URL)
+		}
+		seen[item.Index] = true
+	}
+	if len(seen) != 6 {
+		t.Errorf("got results for %v", seen)
+	}
+}
+
+func TestClient_ConcurrentScrapeChan_Cancel(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+	// never closed: the run ends with ctx
+	configs := make(chan *ScrapeConfig)
+	ctx, cancel := context.WithCancel(context.Background())
+	results := client.ConcurrentScrapeChan(ctx, configs, 2)
+	configs <- &ScrapeConfig{URL: "https://example.com"}
+	if item := <-results; item.Err != nil {
+		t.Fatal(item.Err)
+	}
+	cancel()
+	select {
+	case _, ok := <-results:
+		if ok {
+			t.Error("unexpected result after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("results channel not closed after cancel")
+	}
+}
+
+func TestClient_ConcurrentScrapeChanWithOptions(t *testing.T) {
+	var mu sync.Mutex
+	running := map[string]int{}
+	overlapped := false
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		u, _ := url.Parse(r.URL.Query().Get("url"))
+		mu.Lock()
+		running[u.Host]++
+		overlapped = overlapped || running[u.Host] > 1
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		running[u.Host]--
+		mu.Unlock()
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+	configs := make(chan *ScrapeConfig)
+	go func() {
+		defer close(configs)
+		for i := 0; i < 6; i++ {
+			configs <- &ScrapeConfig{URL: fmt.Sprintf("https://host%d.example.com/%d", i%2, i)}
+		}
+	}()
+
+	var summary ConcurrentScrapeSummary
+	results := client.ConcurrentScrapeChanWithOptions(context.Background(), configs, ConcurrentScrapeOptions{
+		Concurrency:           4,
+		MaxPerHostConcurrency: 1,
+		OnComplete:            func(s ConcurrentScrapeSummary) { summary = s },
+	})
+	seen := map[int]bool{}
+	for item := range results {
+		if item.Err != nil {
+			t.Fatal(item.Err)
+		}
+		if item.Config.URL != fmt.Sprintf("https://host%d.example.com/%d", item.Index%2, item.Index) {
+			t.Errorf("item %d has config %s", item.Index, item.Config.URL)
+		}
+		seen[item.Index] = true
+	}
+	if len(seen) != 6 {
+		t.Errorf("got results for %v", seen)
+	}
+	if overlapped {
+		t.Error("MaxPerHostConcurrency not enforced on the stream")
+	}
+	if summary.Total != 6 || summary.Succeeded != 6 || len(summary.Pending) != 0 {
+		t.Errorf("unexpected summary %+v", summary)
+	}
+}
+
+func TestClient_ConcurrentScrape_NoAccountLimit(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/account" {
+			fmt.Fprint(w, `{"subscription":{"usage":{"scrape":{"concurrent_limit":0}}}}`)
+			return
+		}
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+	configs := []*ScrapeConfig{{URL: "https://example.com/a"}, {URL: "https://example.com/b"}}
+
+	done := make(chan int)
+	go func() {
+		n := 0
+		for item := range client.ConcurrentScrape(configs, 0) {
+			if item.Err == nil {
+				n++
+			}
+		}
+		done <- n
+	}()
+	select {
+	case n := <-done:
+		if n != len(configs) {
+			t.Errorf("%d results, want %d", n, len(configs))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run hangs with a zero account concurrent limit")
+	}
+}
+
+func TestClient_ScrapeAll(t *testing.T) {
+	var mu sync.Mutex
+	scraped := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/account" {
+			fmt.Fprint(w, `{"subscription":{"usage":{"scrape":{"concurrent_limit":2}}}}`)
+			return
+		}
+		mu.Lock()
+		scraped++
+		mu.Unlock()
+		if r.URL.Query().Get("url") == "https://example.com/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message":"invalid url","code":"ERR::SCRAPE::BAD_URL","http_code":400}`)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+
+	configs := []*ScrapeConfig{{URL: "https://example.com/a"}, {URL: "https://example.com/fail"}, {URL: "https://example.com/c"}}
+	results, failures := 0, 0
+	for result, err := range client.ScrapeAll(context.Background(), configs) {
+		if err != nil {
+			if !strings.Contains(err.Error(), "https://example.com/fail") {
+				t.Errorf("error without the URL: %v", err)
+			}
+			failures++
+			continue
+		}
+		if result == nil {
+			t.Fatal("nil result without error")
+		}
+		results++
+	}
+	if results != 2 || failures != 1 {
+		t.Errorf("%d results, %d failures", results, failures)
+	}
+
+	many := make([]*ScrapeConfig, 50)
+	for i := range many {
+		many[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+	mu.Lock()
+	scraped = 0
+	mu.Unlock()
+	for range client.ScrapeAll(context.Background(), many) {
+		break
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if scraped > 4 {
+		t.Errorf("break should stop the run, %d configs were scraped", scraped)
+	}
+}
+
+func TestClient_ScrapeAllSync(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("url")
+		if strings.HasSuffix(target, "/fail") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message":"invalid url","code":"ERR::SCRAPE::BAD_URL","http_code":400}`)
+			return
+		}
+		fmt.Fprintf(w, `{"result":{"success":true,"status":"DONE","url":%q}}`, target)
+	})
+	configs := []*ScrapeConfig{{URL: "https://example.com/a"}, {URL: "https://example.com/fail"}, {URL: "https://example.com/c"}}
+
+	results, err := client.ScrapeAllSync(context.Background(), configs, 2)
+	if err == nil || !strings.Contains(err.Error(), "https://example.com/fail") {
+		t.Errorf("err = %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("joined error should wrap the API error, got %T", err)
+	}
+	if len(results) != 3 || results[1] != nil || results[0].Result.URL != "https://example.com/a" || results[2].Result.URL != "https://example.com/c" {
+		t.Errorf("unexpected results %v", results)
+	}
+
+	ok, err := client.ScrapeAllSync(context.Background(), []*ScrapeConfig{configs[0], configs[2]}, 2)
+	if err != nil || len(ok) != 2 {
+		t.Errorf("ScrapeAllSync() = %v, %v", ok, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.ScrapeAllSync(ctx, configs, 2); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled run error = %v", err)
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_PerHost(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := map[string]int{}, map[string]int{}
+	starts := map[string][]time.Time{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		u, _ := url.Parse(r.URL.Query().Get("url"))
+		mu.Lock()
+		inFlight[u.Host]++
+		maxInFlight[u.Host] = max(maxInFlight[u.Host], inFlight[u.Host])
+		starts[u.Host] = append(starts[u.Host], time.Now())
+		mu.Unlock()
+		time.Sleep(60 * time.Millisecond)
+		mu.Lock()
+		inFlight[u.Host]--
+		mu.Unlock()
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+
+	var configs []*ScrapeConfig
+	for i := 0; i < 8; i++ {
+		configs = append(configs, &ScrapeConfig{URL: fmt.Sprintf("https://busy.example.com/%d", i)})
+	}
+	for i := 0; i < 4; i++ {
+		configs = append(configs, &ScrapeConfig{URL: fmt.Sprintf("https://other%d.example.com/", i)})
+	}
+	var indexes []int
+	for item := range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{
+		Concurrency:           6,
+		MaxPerHostConcurrency: 2,
+		MinDelayPerHost:       20 * time.Millisecond,
+		Ordered:               true,
+	}) {
+		if item.Err != nil {
+			t.Fatal(item.Err)
+		}
+		indexes = append(indexes, item.Index)
+	}
+
+	if maxInFlight["busy.example.com"] != 2 {
+		t.Errorf("busy host had up to %d scrapes in flight, want 2", maxInFlight["busy.example.com"])
+	}
+	busy := starts["busy.example.com"]
+	for i := 1; i < len(busy); i++ {
+		if gap := busy[i].Sub(busy[i-1]); gap < 15*time.Millisecond {
+			t.Errorf("scrapes %d and %d of the busy host started %v apart", i-1, i, gap)
+		}
+	}
+	// the other hosts don't wait for the busy one
+	if first := starts["other3.example.com"][0]; !first.Before(busy[4]) {
+		t.Error("other hosts should be scraped while the busy host is throttled")
+	}
+	for i, index := range indexes {
+		if index != i {
+			t.Fatalf("Ordered results out of order: %v", indexes)
+		}
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_Priority(t *testing.T) {
+	var mu sync.Mutex
+	var scraped []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		scraped = append(scraped, r.URL.Query().Get("url"))
+		mu.Unlock()
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+	configs := []*ScrapeConfig{
+		{URL: "https://example.com/backfill-1"},
+		{URL: "https://example.com/backfill-2"},
+		{URL: "https://example.com/urgent", Priority: 10},
+		{URL: "https://example.com/recheck", Priority: 1},
+	}
+	for item := range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{Concurrency: 1}) {
+		if item.Err != nil {
+			t.Fatal(item.Err)
+		}
+	}
+
+	want := []string{"https://example.com/urgent", "https://example.com/recheck", "https://example.com/backfill-1", "https://example.com/backfill-2"}
+	if strings.Join(scraped, " ") != strings.Join(want, " ") {
+		t.Errorf("scraped %v, want %v", scraped, want)
+	}
+}
+
+func TestClient_ConcurrentScrapeChan_Priority(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	var mu sync.Mutex
+	var scraped []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		u := r.URL.Query().Get("url")
+		mu.Lock()
+		scraped = append(scraped, u)
+		mu.Unlock()
+		if u == "https://example.com/first" {
+			close(started)
+			<-release
+		}
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE"}}`)
+	})
+	configs := make(chan *ScrapeConfig)
+	results := client.ConcurrentScrapeChan(context.Background(), configs, 1)
+	configs <- &ScrapeConfig{URL: "https://example.com/first"}
+	<-started
+	// queued while the only worker is busy
+	configs <- &ScrapeConfig{URL: "https://example.com/backfill-1"}
+	configs <- &ScrapeConfig{URL: "https://example.com/backfill-2"}
+	configs <- &ScrapeConfig{URL: "https://example.com/urgent", Priority: 5}
+	close(configs)
+	close(release)
+	for item := range results {
+		if item.Err != nil {
+			t.Fatal(item.Err)
+		}
+	}
+
+	want := []string{"https://example.com/first", "https://example.com/urgent", "https://example.com/backfill-1", "https://example.com/backfill-2"}
+	if strings.Join(scraped, " ") != strings.Join(want, " ") {
+		t.Errorf("scraped %v, want %v", scraped, want)
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_OnProgress(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Query().Get("url"), "/3") {
+			fmt.Fprint(w, `{"context":{"cost":{"total":5}},"result":{"success":false,"status":"ERR::SCRAPE::OPERATION_TIMEOUT","status_code":200}}`)
+			return
+		}
+		fmt.Fprint(w, `{"context":{"cost":{"total":1}},"result":{"success":true,"status":"DONE","status_code":200}}`)
+	})
+	configs := make([]*ScrapeConfig, 4)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	var events []ConcurrentScrapeProgress
+	var summary ConcurrentScrapeSummary
+	for range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{
+		Concurrency: 2,
+		OnProgress:  func(p ConcurrentScrapeProgress) { events = append(events, p) },
+		OnComplete:  func(s ConcurrentScrapeSummary) { summary = s },
+	}) {
+	}
+
+	if len(events) != len(configs) {
+		t.Fatalf("got %d progress events, want one per config", len(events))
+	}
+	for i, event := range events {
+		if event.Completed() != i+1 || event.Total != 4 || event.InFlight < 0 || event.InFlight > 2 {
+			t.Errorf("event %d = %+v", i, event)
+		}
+	}
+	last := events[len(events)-1]
+	if last.Succeeded != 3 || last.Failed != 1 || last.Cost != 8 || last.InFlight != 0 || last.Remaining() != 0 {
+		t.Errorf("last event = %+v", last)
+	}
+	if summary.Cost != 8 {
+		t.Errorf("summary cost = %d, want 8", summary.Cost)
+	}
+
+	half := ConcurrentScrapeProgress{Total: 4, Succeeded: 2, Elapsed: time.Minute}
+	if got := half.Remaining(); got != time.Minute {
+		t.Errorf("Remaining() = %v, want 1m", got)
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_CostBudget(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"context":{"cost":{"total":10}},"result":{"success":true,"status":"DONE","status_code":200}}`)
+	})
+	configs := make([]*ScrapeConfig, 10)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	var budgetErr error
+	var summary ConcurrentScrapeSummary
+	for item := range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{
+		Concurrency: 1,
+		CostBudget:  30,
+		OnComplete:  func(s ConcurrentScrapeSummary) { summary = s },
+	}) {
+		if item.Index < 0 {
+			budgetErr = item.Err
+		}
+	}
+	if !errors.Is(budgetErr, ErrCostBudgetExceeded) {
+		t.Errorf("run error = %v, want ErrCostBudgetExceeded", budgetErr)
+	}
+	if !summary.BudgetExceeded || summary.Cost < 30 || summary.Cost > 40 || summary.Completed()+len(summary.Pending) != len(configs) {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+func TestBatchJob_PauseAtBudget(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"context":{"cost":{"total":10}},"result":{"success":true,"status":"DONE","status_code":200}}`)
+	})
+	configs := make([]*ScrapeConfig, 5)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+	job := client.StartBatchJob(context.Background(), configs, ConcurrentScrapeOptions{Concurrency: 1, CostBudget: 20, PauseAtBudget: true})
+	for item := range job.Results() {
+		if item.Index >= 0 {
+			continue
+		}
+		if !job.Paused() {
+			t.Fatal("job should be paused at the budget")
+		}
+		// the operator raises the limit
+		job.Resume()
+	}
+	if summary := job.Summary(); summary.Succeeded != 5 || !summary.BudgetExceeded {
+		t.Errorf("summary = %+v, want every config scraped after Resume", summary)
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_SpreadOver(t *testing.T) {
+	var mu sync.Mutex
+	var starts []time.Time
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		starts = append(starts, time.Now())
+		mu.Unlock()
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE","status_code":200}}`)
+	})
+	configs := make([]*ScrapeConfig, 5)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+
+	for item := range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{Concurrency: 5, SpreadOver: 250 * time.Millisecond}) {
+		if item.Err != nil {
+			t.Fatal(item.Err)
+		}
+	}
+	if len(starts) != len(configs) {
+		t.Fatalf("%d scrapes", len(starts))
+	}
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i].Sub(starts[i-1]); gap < 40*time.Millisecond {
+			t.Errorf("scrapes %d and %d started %v apart, want 50ms", i-1, i, gap)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package scrapfly
+
+import (
+	"net/http"
+	"time"
+)
+
+// NotModified reports whether the upstream answered a conditional scrape
+// (IfNoneMatch, IfModifiedSince) with 304 Not Modified: the page did not
+// change and the result has no content. Scrape returns such results
+// without error.
+//
+// Example:
+//
+//	config := &scrapfly.ScrapeConfig{URL: url}
+//	config.ConditionalOn(previous)
+//	result, err := client.Scrape(config)
+//	if err == nil && result.NotModified() {
+//	    return previous // unchanged
+//	}
+func (r *ScrapeResult) NotModified() bool {
+	return r.Result.StatusCode == http.StatusNotModified
+}
+
+// ETag returns the entity tag of the upstream response, or "".
+func (r *ScrapeResult) ETag() string {
+	return r.Header("ETag")
+}
+
+// LastModified returns the Last-Modified date of the upstream response.
+func (r *ScrapeResult) LastModified() (time.Time, bool) {
+	t, err := http.ParseTime(r.Header("Last-Modified"))
+	return t, err == nil
+}
+
+// ConditionalOn makes the scrape conditional on the page having changed
+// since previous, setting IfNoneMatch and IfModifiedSince from its ETag and
+// Last-Modified headers. A nil previous is ignored.
+func (c *ScrapeConfig) ConditionalOn(previous *ScrapeResult) {
+	if previous == nil {
+		return
+	}
+	if etag := previous.ETag(); etag != "" {
+		c.IfNoneMatch = etag
+	}
+	if modified, ok := previous.LastModified(); ok {
+		c.IfModifiedSince = modified
+	}
+}
@@ -0,0 +1,65 @@
+package scrapfly
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestScrapeConfig_ConditionalHeaders(t *testing.T) {
+	modified := time.Date(2024, 5, 1, 10, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	cfg := &ScrapeConfig{URL: "https://example.com", IfNoneMatch: `"v1"`, IfModifiedSince: modified}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params.Get("headers[if-none-match]"); got != `"v1"` {
+		t.Errorf("if-none-match = %q", got)
+	}
+	if got := params.Get("headers[if-modified-since]"); got != "Wed, 01 May 2024 08:00:00 GMT" {
+		t.Errorf("if-modified-since = %q", got)
+	}
+	if clone := cfg.Clone(); !clone.IfModifiedSince.Equal(modified) {
+		t.Errorf("Clone dropped IfModifiedSince: %v", clone.IfModifiedSince)
+	}
+
+	conflict := &ScrapeConfig{URL: "https://example.com", IfNoneMatch: `"v1"`, Headers: map[string]string{"If-None-Match": `"v2"`}}
+	if err := conflict.Validate(); !errors.Is(err, ErrScrapeConfig) {
+		t.Errorf("got %v, want ErrScrapeConfig", err)
+	}
+}
+
+func TestScrapeConfig_ConditionalOn(t *testing.T) {
+	previous := &ScrapeResult{Result: ResultData{ResponseHeaders: map[string]interface{}{
+		"ETag":          `W/"abc"`,
+		"last-modified": []interface{}{"Wed, 01 May 2024 08:00:00 GMT"},
+	}}}
+	cfg := &ScrapeConfig{URL: "https://example.com"}
+	cfg.ConditionalOn(previous)
+	if cfg.IfNoneMatch != `W/"abc"` {
+		t.Errorf("IfNoneMatch = %q", cfg.IfNoneMatch)
+	}
+	if want := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC); !cfg.IfModifiedSince.Equal(want) {
+		t.Errorf("IfModifiedSince = %v, want %v", cfg.IfModifiedSince, want)
+	}
+}
+
+func TestClient_ScrapeNotModifiedIsNotAnError(t *testing.T) {
+	for _, success := range []string{"true", "false"} {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("headers[if-none-match]") != `"v1"` {
+				t.Errorf("conditional header not sent: %s", r.URL.RawQuery)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"result":{"success":` + success + `,"status":"DONE","status_code":304}}`))
+		})
+		result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", IfNoneMatch: `"v1"`})
+		if err != nil {
+			t.Fatalf("success=%s: unexpected error %v", success, err)
+		}
+		if !result.NotModified() {
+			t.Errorf("success=%s: NotModified() = false", success)
+		}
+	}
+}
@@ -0,0 +1,298 @@
+package scrapfly
+
+import (
+	"time"
+
+	js_scenario "github.com/scrapfly/go-scrapfly/scenario"
+)
+
+// ScrapeConfigBuilder builds a ScrapeConfig fluently, as a chainable
+// alternative to the struct literal:
+//
+//	config, err := scrapfly.NewScrapeConfig("https://web-scraping.dev/products").
+//	    RenderJS().
+//	    Country("us").
+//	    ASP().
+//	    Header("X-Foo", "bar").
+//	    WaitForSelector(".product").
+//	    Build()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	result, err := client.Scrape(config)
+//
+// Build validates the result with ScrapeConfig.Validate, so every problem is
+// reported at once rather than at scrape time. Options without a dedicated
+// method can be set with Apply.
+type ScrapeConfigBuilder struct {
+	config ScrapeConfig
+}
+
+// NewScrapeConfig starts a builder for a scrape of targetURL.
+func NewScrapeConfig(targetURL string) *ScrapeConfigBuilder {
+	return &ScrapeConfigBuilder{config: ScrapeConfig{URL: targetURL}}
+}
+
+// Build validates and returns the config. Each call returns a new config,
+// so a builder can serve as a template.
+func (b *ScrapeConfigBuilder) Build() (*ScrapeConfig, error) {
+	config := b.config.Clone()
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// MustBuild is Build that panics on an invalid config, for configs known
+// at compile time.
+func (b *ScrapeConfigBuilder) MustBuild() *ScrapeConfig {
+	config, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return config
+}
+
+// Apply runs fn on the config being built, for options without a
+// dedicated builder method.
+func (b *ScrapeConfigBuilder) Apply(fn func(config *ScrapeConfig)) *ScrapeConfigBuilder {
+	fn(&b.config)
+	return b
+}
+
+// Method sets the HTTP method.
+func (b *ScrapeConfigBuilder) Method(method HttpMethod) *ScrapeConfigBuilder {
+	b.config.Method = method
+	return b
+}
+
+// Body sets the raw request body.
+func (b *ScrapeConfigBuilder) Body(body string) *ScrapeConfigBuilder {
+	b.config.Body = body
+	return b
+}
+
+// CompressBody gzip-compresses bodies larger than threshold bytes before
+// upload; zero uses DefaultCompressionThreshold.
+func (b *ScrapeConfigBuilder) CompressBody(threshold int) *ScrapeConfigBuilder {
+	b.config.CompressBody = true
+	b.config.BodyCompressionThreshold = threshold
+	return b
+}
+
+// Data sets the request data, encoded according to the content-type header.
+func (b *ScrapeConfigBuilder) Data(data map[string]interface{}) *ScrapeConfigBuilder {
+	b.config.Data = data
+	return b
+}
+
+// Header sets a request header.
+func (b *ScrapeConfigBuilder) Header(name, value string) *ScrapeConfigBuilder {
+	if b.config.Headers == nil {
+		b.config.Headers = make(map[string]string)
+	}
+	b.config.Headers[name] = value
+	return b
+}
+
+// Cookie sets a request cookie.
+func (b *ScrapeConfigBuilder) Cookie(name, value string) *ScrapeConfigBuilder {
+	if b.config.Cookies == nil {
+		b.config.Cookies = make(map[string]string)
+	}
+	b.config.Cookies[name] = value
+	return b
+}
+
+// IfNoneMatch makes the scrape conditional on the entity tag changing.
+func (b *ScrapeConfigBuilder) IfNoneMatch(etag string) *ScrapeConfigBuilder {
+	b.config.IfNoneMatch = etag
+	return b
+}
+
+// IfModifiedSince makes the scrape conditional on the page changing after t.
+func (b *ScrapeConfigBuilder) IfModifiedSince(t time.Time) *ScrapeConfigBuilder {
+	b.config.IfModifiedSince = t
+	return b
+}
+
+// Country sets the proxy country code.
+func (b *ScrapeConfigBuilder) Country(country string) *ScrapeConfigBuilder {
+	b.config.Country = country
+	return b
+}
+
+// ProxyPool selects the proxy pool.
+func (b *ScrapeConfigBuilder) ProxyPool(pool ProxyPool) *ScrapeConfigBuilder {
+	b.config.ProxyPool = pool
+	return b
+}
+
+// GeolocationTarget spoofs the browser geolocation at a US state or city;
+// see GeolocationTarget.
+func (b *ScrapeConfigBuilder) GeolocationTarget(target GeolocationTarget) *ScrapeConfigBuilder {
+	b.config.GeolocationTarget = &target
+	return b
+}
+
+// RenderJS enables the headless browser.
+func (b *ScrapeConfigBuilder) RenderJS() *ScrapeConfigBuilder {
+	b.config.RenderJS = true
+	return b
+}
+
+// ASP enables Anti Scraping Protection bypass.
+func (b *ScrapeConfigBuilder) ASP() *ScrapeConfigBuilder {
+	b.config.ASP = true
+	return b
+}
+
+// Cache enables the response cache with a time-to-live in seconds (0 for
+// the API default).
+func (b *ScrapeConfigBuilder) Cache(ttl int) *ScrapeConfigBuilder {
+	b.config.Cache = true
+	b.config.CacheTTL = ttl
+	return b
+}
+
+// CacheClear forces a cache refresh.
+func (b *ScrapeConfigBuilder) CacheClear() *ScrapeConfigBuilder {
+	b.config.CacheClear = true
+	return b
+}
+
+// Timeout sets the request timeout in milliseconds.
+func (b *ScrapeConfigBuilder) Timeout(ms int) *ScrapeConfigBuilder {
+	b.config.Timeout = ms
+	return b
+}
+
+// Retry enables the API's automatic retries.
+func (b *ScrapeConfigBuilder) Retry() *ScrapeConfigBuilder {
+	b.config.Retry = true
+	return b
+}
+
+// Session sets the session name.
+func (b *ScrapeConfigBuilder) Session(name string) *ScrapeConfigBuilder {
+	b.config.Session = name
+	return b
+}
+
+// Tags adds tags to the scrape.
+func (b *ScrapeConfigBuilder) Tags(tags ...string) *ScrapeConfigBuilder {
+	b.config.Tags = append(b.config.Tags, tags...)
+	return b
+}
+
+// Webhook sets the webhook to queue the scrape to.
+func (b *ScrapeConfigBuilder) Webhook(name string) *ScrapeConfigBuilder {
+	b.config.Webhook = name
+	return b
+}
+
+// Debug enables debug mode.
+func (b *ScrapeConfigBuilder) Debug() *ScrapeConfigBuilder {
+	b.config.Debug = true
+	return b
+}
+
+// CorrelationID sets the correlation ID.
+func (b *ScrapeConfigBuilder) CorrelationID(id string) *ScrapeConfigBuilder {
+	b.config.CorrelationID = id
+	return b
+}
+
+// Format sets the response content format and its options.
+func (b *ScrapeConfigBuilder) Format(format Format, options ...FormatOption) *ScrapeConfigBuilder {
+	b.config.Format = format
+	b.config.FormatOptions = options
+	return b
+}
+
+// ExtractionTemplate uses a saved extraction template.
+func (b *ScrapeConfigBuilder) ExtractionTemplate(name string) *ScrapeConfigBuilder {
+	b.config.ExtractionTemplate = name
+	return b
+}
+
+// ExtractionPrompt extracts data with an AI prompt.
+func (b *ScrapeConfigBuilder) ExtractionPrompt(prompt string) *ScrapeConfigBuilder {
+	b.config.ExtractionPrompt = prompt
+	return b
+}
+
+// ExtractionModel extracts data with a predefined model.
+func (b *ScrapeConfigBuilder) ExtractionModel(model ExtractionModel) *ScrapeConfigBuilder {
+	b.config.ExtractionModel = model
+	return b
+}
+
+// WaitForSelector waits for selector before returning (requires RenderJS).
+func (b *ScrapeConfigBuilder) WaitForSelector(selector string) *ScrapeConfigBuilder {
+	b.config.WaitForSelector = selector
+	return b
+}
+
+// RenderingWait waits ms milliseconds after page load (requires RenderJS).
+func (b *ScrapeConfigBuilder) RenderingWait(ms int) *ScrapeConfigBuilder {
+	b.config.RenderingWait = ms
+	return b
+}
+
+// AutoScroll scrolls the page to load lazy content (requires RenderJS).
+func (b *ScrapeConfigBuilder) AutoScroll() *ScrapeConfigBuilder {
+	b.config.AutoScroll = true
+	return b
+}
+
+// Screenshot adds a screenshot of a selector or "fullpage" (requires RenderJS).
+func (b *ScrapeConfigBuilder) Screenshot(name, target string) *ScrapeConfigBuilder {
+	if b.config.Screenshots == nil {
+		b.config.Screenshots = make(map[string]string)
+	}
+	b.config.Screenshots[name] = target
+	return b
+}
+
+// JS runs a JavaScript snippet on the page (requires RenderJS).
+func (b *ScrapeConfigBuilder) JS(code string) *ScrapeConfigBuilder {
+	b.config.JS = code
+	return b
+}
+
+// JSScenario sets the browser scenario, typically from js_scenario.New()...Steps().
+func (b *ScrapeConfigBuilder) JSScenario(steps []js_scenario.JSScenarioStep) *ScrapeConfigBuilder {
+	b.config.JSScenario = steps
+	return b
+}
+
+// OS sets the operating system to emulate.
+func (b *ScrapeConfigBuilder) OS(os OperatingSystem) *ScrapeConfigBuilder {
+	b.config.OS = os
+	return b
+}
+
+// Lang sets the Accept-Language values, in preference order.
+func (b *ScrapeConfigBuilder) Lang(langs ...string) *ScrapeConfigBuilder {
+	b.config.Lang = langs
+	return b
+}
+
+// Profile applies a browser profile preset.
+func (b *ScrapeConfigBuilder) Profile(profile BrowserProfile) *ScrapeConfigBuilder {
+	b.config.Profile = &profile
+	return b
+}
+
+// Device applies a device and locale preset, e.g. DeviceIPhone().In("fr-FR").
+func (b *ScrapeConfigBuilder) Device(device *DeviceProfile) *ScrapeConfigBuilder {
+	b.config.Device = device
+	return b
+}
+
+// CostBudget caps the API credits spent on the scrape.
+func (b *ScrapeConfigBuilder) CostBudget(credits int) *ScrapeConfigBuilder {
+	b.config.CostBudget = credits
+	return b
+}
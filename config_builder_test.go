@@ -0,0 +1,44 @@
+package scrapfly
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScrapeConfigBuilder_Build(t *testing.T) {
+	config, err := NewScrapeConfig("https://example.com").
+		RenderJS().
+		Country("us").
+		ASP().
+		Header("X-Foo", "bar").
+		WaitForSelector(".item").
+		Tags("a", "b").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !config.RenderJS || !config.ASP || config.Country != "us" || config.Headers["X-Foo"] != "bar" ||
+		config.WaitForSelector != ".item" || len(config.Tags) != 2 {
+		t.Errorf("unexpected config %+v", config)
+	}
+}
+
+func TestScrapeConfigBuilder_BuildValidates(t *testing.T) {
+	_, err := NewScrapeConfig("https://example.com").WaitForSelector(".item").AutoScroll().Build()
+	if !errors.Is(err, ErrScrapeConfig) {
+		t.Fatalf("got %v, want ErrScrapeConfig", err)
+	}
+}
+
+func TestScrapeConfigBuilder_TemplateReuse(t *testing.T) {
+	base := NewScrapeConfig("https://example.com").Header("X-Base", "1")
+	first := base.MustBuild()
+	base.Header("X-Extra", "2")
+	second := base.MustBuild()
+	if _, ok := first.Headers["X-Extra"]; ok {
+		t.Error("later builder changes leaked into an earlier config")
+	}
+	if second.Headers["X-Base"] != "1" || second.Headers["X-Extra"] != "2" {
+		t.Errorf("unexpected headers %v", second.Headers)
+	}
+}
@@ -0,0 +1,138 @@
+package scrapfly
+
+import "reflect"
+
+// Clone returns a deep copy of the config: maps, slices and pointed-to
+// values are copied, so the clone can be modified, or used from another
+// goroutine, without affecting c.
+func (c *ScrapeConfig) Clone() *ScrapeConfig {
+	return cloneConfig(c)
+}
+
+// Merge returns a deep copy of c with the non-zero fields of override
+// applied: maps (Headers, Cookies, Screenshots...) are merged key by key,
+// every other non-zero field replaces the template value. Zero values never
+// override, so a template's true bool cannot be turned off by Merge. Neither
+// c nor override is modified.
+//
+// Example:
+//
+//	base := &scrapfly.ScrapeConfig{ASP: true, Country: "us", Headers: map[string]string{"accept-language": "en"}}
+//	for _, u := range urls {
+//	    config := base.Merge(&scrapfly.ScrapeConfig{URL: u, Headers: map[string]string{"x-page": u}})
+//	    ...
+//	}
+func (c *ScrapeConfig) Merge(override *ScrapeConfig) *ScrapeConfig {
+	return mergeConfig(c, override)
+}
+
+// Clone returns a deep copy of the config; see ScrapeConfig.Clone.
+func (c *ScreenshotConfig) Clone() *ScreenshotConfig {
+	return cloneConfig(c)
+}
+
+// Merge returns a deep copy of c with the non-zero fields of override
+// applied; see ScrapeConfig.Merge.
+func (c *ScreenshotConfig) Merge(override *ScreenshotConfig) *ScreenshotConfig {
+	return mergeConfig(c, override)
+}
+
+// Clone returns a deep copy of the config; Body is copied too. See
+// ScrapeConfig.Clone.
+func (c *ExtractionConfig) Clone() *ExtractionConfig {
+	return cloneConfig(c)
+}
+
+// Merge returns a deep copy of c with the non-zero fields of override
+// applied; see ScrapeConfig.Merge.
+func (c *ExtractionConfig) Merge(override *ExtractionConfig) *ExtractionConfig {
+	return mergeConfig(c, override)
+}
+
+func cloneConfig[T any](c *T) *T {
+	if c == nil {
+		return nil
+	}
+	clone := new(T)
+	reflect.ValueOf(clone).Elem().Set(deepCopy(reflect.ValueOf(c).Elem()))
+	return clone
+}
+
+func mergeConfig[T any](c, override *T) *T {
+	merged := cloneConfig(c)
+	if merged == nil {
+		return cloneConfig(override)
+	}
+	if override == nil {
+		return merged
+	}
+	dst := reflect.ValueOf(merged).Elem()
+	src := reflect.ValueOf(override).Elem()
+	for i := 0; i < src.NumField(); i++ {
+		field := src.Field(i)
+		if !dst.Field(i).CanSet() || field.IsZero() {
+			continue
+		}
+		if field.Kind() == reflect.Map && !dst.Field(i).IsNil() {
+			iter := field.MapRange()
+			for iter.Next() {
+				dst.Field(i).SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+			}
+			continue
+		}
+		dst.Field(i).Set(deepCopy(field))
+	}
+	return merged
+}
+
+// deepCopy returns a copy of v sharing no map, slice or pointer with it.
+// Unexported struct fields are copied shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return out
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(deepCopy(v.Elem()))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(deepCopy(v.Elem()))
+		return out
+	case reflect.Struct:
+		// copy first so unexported state (time.Time...) is kept
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if out.Field(i).CanSet() {
+				out.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,78 @@
+package scrapfly
+
+import (
+	"sync"
+	"testing"
+
+	js_scenario "github.com/scrapfly/go-scrapfly/scenario"
+)
+
+func TestScrapeConfig_CloneIsDeep(t *testing.T) {
+	sticky := true
+	base := &ScrapeConfig{
+		URL:                "https://example.com",
+		Headers:            map[string]string{"a": "1"},
+		Data:               map[string]interface{}{"nested": map[string]interface{}{"k": "v"}},
+		Tags:               []string{"t"},
+		SessionStickyProxy: &sticky,
+		JSScenario:         []js_scenario.JSScenarioStep{{"wait": 100}},
+	}
+	clone := base.Clone()
+	clone.Headers["a"] = "2"
+	clone.Data["nested"].(map[string]interface{})["k"] = "changed"
+	clone.Tags[0] = "changed"
+	*clone.SessionStickyProxy = false
+	clone.JSScenario[0]["wait"] = 1
+
+	if base.Headers["a"] != "1" || base.Data["nested"].(map[string]interface{})["k"] != "v" ||
+		base.Tags[0] != "t" || !*base.SessionStickyProxy || base.JSScenario[0]["wait"] != 100 {
+		t.Errorf("clone shares state with the template: %+v", base)
+	}
+}
+
+func TestScrapeConfig_Merge(t *testing.T) {
+	base := &ScrapeConfig{ASP: true, Country: "us", Headers: map[string]string{"accept-language": "en", "x-a": "1"}}
+	merged := base.Merge(&ScrapeConfig{URL: "https://example.com/1", Country: "de", Headers: map[string]string{"x-a": "2"}})
+
+	if merged.URL != "https://example.com/1" || merged.Country != "de" || !merged.ASP {
+		t.Errorf("unexpected merge %+v", merged)
+	}
+	if merged.Headers["accept-language"] != "en" || merged.Headers["x-a"] != "2" {
+		t.Errorf("headers not merged: %v", merged.Headers)
+	}
+	if base.Country != "us" || base.Headers["x-a"] != "1" || base.URL != "" {
+		t.Errorf("template modified: %+v", base)
+	}
+}
+
+func TestScreenshotAndExtractionConfig_CloneMerge(t *testing.T) {
+	shot := (&ScreenshotConfig{Format: FormatPNG, Options: []ScreenshotOption{OptionDarkMode}}).Merge(&ScreenshotConfig{URL: "https://example.com"})
+	if shot.URL != "https://example.com" || shot.Format != FormatPNG || len(shot.Options) != 1 {
+		t.Errorf("unexpected screenshot merge %+v", shot)
+	}
+
+	extraction := &ExtractionConfig{Body: []byte("<html>"), ContentType: "text/html"}
+	clone := extraction.Clone()
+	clone.Body[0] = 'X'
+	if extraction.Body[0] != '<' {
+		t.Error("extraction clone shares Body")
+	}
+}
+
+func TestScrapeConfig_MergeConcurrentUse(t *testing.T) {
+	base := &ScrapeConfig{Headers: map[string]string{"a": "1"}, Tags: []string{"base"}}
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			config := base.Merge(&ScrapeConfig{URL: "https://example.com"})
+			config.Headers["b"] = "2"
+			config.Tags = append(config.Tags, "x")
+		}()
+	}
+	wg.Wait()
+	if len(base.Headers) != 1 || len(base.Tags) != 1 {
+		t.Errorf("template modified: %+v", base)
+	}
+}
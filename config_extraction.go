@@ -60,6 +60,15 @@ type ExtractionConfig struct {
 	IsDocumentCompressed bool
 	// DocumentCompressionFormat specifies the compression format if IsDocumentCompressed is true.
 	DocumentCompressionFormat CompressionFormat
+	// AutoCompress compresses an uncompressed Body larger than
+	// CompressionThreshold before upload, with zstd when
+	// github.com/scrapfly/go-scrapfly/contrib/zstd is imported and gzip
+	// otherwise. If the API rejects the encoding the document is re-sent
+	// uncompressed. Body itself is never modified.
+	AutoCompress bool
+	// CompressionThreshold is the Body size in bytes above which AutoCompress
+	// applies. Defaults to DefaultCompressionThreshold.
+	CompressionThreshold int
 	// Webhook is the name of a webhook to call after extraction completes.
 	Webhook string
 	// Timeout is the maximum time in seconds for extraction processing.
@@ -3,10 +3,12 @@ package scrapfly
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	js_scenario "github.com/scrapfly/go-scrapfly/scenario"
 )
@@ -29,17 +31,35 @@ import (
 type ScrapeConfig struct {
 	// URL is the target URL to scrape (required).
 	URL string `required:"true"`
-	// Method is the HTTP method to use (GET, POST, PUT, PATCH). Defaults to GET.
+	// Method is the HTTP method to use (GET, POST, PUT, PATCH, OPTIONS or
+	// HEAD, case-insensitive). Defaults to GET. DELETE is not supported by the
+	// Scrape API.
 	Method HttpMethod
-	// Body is the raw request body for POST/PUT/PATCH requests.
+	// Body is the raw request body for POST/PUT/PATCH requests; other
+	// methods are rejected with a body.
 	Body string
 	// Data is a map that will be encoded as request body based on Content-Type.
-	// Cannot be used together with Body.
+	// Cannot be used together with Body, same method restrictions as Body.
 	Data map[string]interface{}
-	// Headers are custom HTTP headers to send with the request.
+	// CompressBody gzip-compresses a Body larger than BodyCompressionThreshold
+	// before upload, sent with Content-Encoding: gzip. Bodies with a
+	// content-encoding header are sent as is.
+	CompressBody bool
+	// BodyCompressionThreshold is the Body size in bytes above which
+	// CompressBody applies. Defaults to DefaultCompressionThreshold.
+	BodyCompressionThreshold int
+	// Headers are custom HTTP headers to send with the request. Names are
+	// case-insensitive; see EncodeHeaderParams for how they are encoded.
 	Headers map[string]string
-	// Cookies are cookies to include in the request.
+	// Cookies are cookies to include in the request, appended to any Cookie
+	// header; see EncodeHeaderParams.
 	Cookies map[string]string
+	// IfNoneMatch sends an If-None-Match header with the entity tag of a
+	// previous response; see ScrapeResult.NotModified and ConditionalOn.
+	IfNoneMatch string
+	// IfModifiedSince sends an If-Modified-Since header; see
+	// ScrapeResult.NotModified and ConditionalOn.
+	IfModifiedSince time.Time
 	// Country specifies the proxy country code (e.g., "us", "uk", "de").
 	// Supports ISO 3166-1 alpha-2 country codes.
 	Country string
@@ -55,28 +75,53 @@ type ScrapeConfig struct {
 	CacheTTL int
 	// CacheClear forces cache refresh for this request.
 	CacheClear bool
-	// Timeout sets the maximum time in milliseconds to wait for the request.
+	// CacheStaleWhileRevalidate enables a stale-while-revalidate policy (requires Cache).
+	// When a cache HIT is older than this many seconds, the stale result is returned
+	// immediately and a background scrape with cache_clear refreshes the entry.
+	// This is handled by the SDK and is not sent to the API.
+	CacheStaleWhileRevalidate int
+	// Timeout sets the maximum time in milliseconds the API spends on the request (timeout parameter).
+	// The SDK extends its own HTTP deadline for this call so it never gives up before the API does.
 	Timeout int
-	// Retry enables automatic retries on failure (enabled by default).
+	// Retry enables the API's automatic retries on failure (retry parameter).
+	// The zero value sends retry=false; set it to true to let the API retry.
 	Retry bool
 	// Session maintains a persistent browser session across requests.
 	Session string
 	// SessionStickyProxy keeps the same proxy for all requests in a session.
 	// nil means the server default (sticky on); set to &false to opt out.
 	SessionStickyProxy *bool
+	// AutoReferer sets a realistic Referer header from the session's navigation
+	// graph: the page the URL was discovered on, or else the last page scraped in
+	// the session (requires Session). An explicit Referer header always wins.
+	// This is handled by the SDK and is not sent to the API.
+	AutoReferer bool
+	// Priority orders the configs queued by ConcurrentScrape and its
+	// variants: higher priorities are dispatched first, equal ones in input
+	// order. Configs waiting in a ConcurrentScrapeChan queue gain one level
+	// every 30 seconds waited, so a steady stream of urgent configs
+	// cannot starve the others. This is handled by the SDK and is not sent
+	// to the API.
+	Priority int
 	// Tags are custom tags for organizing and filtering requests.
 	Tags []string
-	// Webhook is the name of a webhook to call after the request completes.
+	// Webhook is the name of a webhook the result is delivered to. The scrape
+	// then runs asynchronously: use Client.ScrapeWebhook, Scrape returns ErrScrapeQueued.
 	Webhook string
 	// Debug enables debug mode for viewing request details in the dashboard.
+	// Debug scrapes without a CorrelationID get a generated one, included in
+	// the SDK log lines of the scrape and its retries; see
+	// ScrapeResult.DebugURL and APIError.DebugURL for the dashboard link.
 	Debug bool
-	// SSL enables SSL certificate verification details capture.
+	// SSL captures the target TLS certificate chain (ssl parameter), see ScrapeResult.SSLInfo.
 	SSL bool
-	// DNS enables DNS resolution details capture.
+	// DNS captures the target DNS records (dns parameter), see ScrapeResult.DNSInfo.
 	DNS bool
 	// CorrelationID is a custom ID for tracking requests across systems.
+	// It is generated when Debug is set and CorrelationID is empty.
 	CorrelationID string
-	// Format specifies the output format for the scraped content.
+	// Format asks the API to convert the content (markdown, text, clean_html, json)
+	// before returning it; ScrapeResult.ContentFormat reports the format received.
 	Format Format `validate:"enum"`
 	// FormatOptions are additional options for the content format.
 	FormatOptions []FormatOption `validate:"enum"`
@@ -94,34 +139,65 @@ type ScrapeConfig struct {
 	ExtractionModel ExtractionModel `exclusive:"extraction" validate:"enum"`
 	// WaitForSelector waits for a CSS selector to appear before capturing (requires RenderJS).
 	WaitForSelector string
+	// WaitForSelectorState waits for the selector to be visible (default) or
+	// hidden, e.g. for a loading spinner to go away (requires WaitForSelector).
+	WaitForSelectorState js_scenario.SelectorState
+	// WaitForSelectorTimeout is the maximum wait in milliseconds for the
+	// selector (requires WaitForSelector). Must be between 0 and 25000.
+	//
+	// The bare wait_for_selector parameter has no state or timeout, so when
+	// either is set the SDK sends the wait as the first js_scenario step
+	// instead, the same step the scenario builder's WaitForSelector produces.
+	WaitForSelectorTimeout int
 	// RenderingWait is additional wait time in milliseconds after page load (requires RenderJS).
+	// Must be between 0 and 25000.
 	RenderingWait int
 	// AutoScroll automatically scrolls the page to load lazy content (requires RenderJS).
 	AutoScroll bool
-	// Screenshots is a map of screenshot names to CSS selectors (requires RenderJS).
+	// Screenshots is a map of screenshot names to CSS selectors, or "fullpage" for
+	// the whole page (requires RenderJS). See ScrapeResult.DownloadScreenshot.
 	Screenshots map[string]string
-	// ScreenshotFlags are options for screenshot capture.
+	// ScreenshotFlags are options for screenshot capture (requires Screenshots).
 	ScreenshotFlags []ScreenshotFlag `validate:"enum"`
-	// JS is custom JavaScript code to execute in the browser (requires RenderJS).
+	// JS is a raw JavaScript snippet executed on the page once loaded (requires
+	// RenderJS), a lighter alternative to JSScenario; its return value is
+	// available in Result.BrowserData.JSEvaluationResult. The SDK base64-encodes it.
 	JS string
 	// JSScenario is a sequence of browser actions to perform (requires RenderJS).
 	JSScenario []js_scenario.JSScenarioStep
 	// OS spoofs the operating system in the User-Agent.
-	OS string
-	// Lang sets the Accept-Language header values.
+	OS OperatingSystem `validate:"enum"`
+	// Lang sets the Accept-Language header values, in preference order.
+	// Each entry must be a language tag such as "en", "en-US" or "fr-FR".
 	Lang []string
 	// BrowserBrand selects the Chromium-based browser for fingerprint generation.
 	// Valid values: "chrome", "edge", "brave", "opera". Empty = default chrome.
 	// Invalid values are silently dropped by the server.
 	BrowserBrand string
+	// Profile applies a device emulation preset such as ProfileDesktopChrome()
+	// or ProfileMobileSafari(). Explicit OS, BrowserBrand and Headers take
+	// precedence over the profile values.
+	Profile *BrowserProfile
+	// Device applies a device and locale preset, e.g. DeviceIPhone().In("fr-FR"),
+	// setting the browser profile, lang and country together. Explicit OS,
+	// BrowserBrand, Headers, Lang and Country take precedence. Cannot be
+	// combined with Profile.
+	Device *DeviceProfile
 	// CostBudget limits the maximum API credit cost for ASP retries.
 	// ASP dynamically upgrades proxy/browser to bypass protection; this caps spending.
+	// When the cap is hit the scrape fails with ErrCostBudgetExceeded; see
+	// also Client.ScrapeEscalating.
 	CostBudget int
 	// Geolocation spoofs the browser's geolocation. Format: "latitude,longitude".
 	Geolocation string
+	// GeolocationTarget spoofs the browser geolocation at a US state or
+	// city; it sets country and geolocation, so it cannot be combined with
+	// Country or Geolocation. Proxies are still selected by country, see
+	// GeolocationTarget.
+	GeolocationTarget *GeolocationTarget
 	// RenderingStage controls when the browser considers the page loaded (requires RenderJS).
-	// Valid values: "complete" (default), "domcontentloaded".
-	RenderingStage string
+	// Valid values: RenderingStageComplete (default), RenderingStageDOMContentLoaded.
+	RenderingStage RenderingStage `validate:"enum"`
 	// ProxifiedResponse returns the raw upstream response (target's status,
 	// headers, body) instead of the JSON envelope. When true, callers must
 	// use ScrapeProxified() instead of Scrape(), which returns *http.Response.
@@ -132,8 +208,7 @@ type ScrapeConfig struct {
 // It converts the Data map to the appropriate body format based on Content-Type.
 // This is an internal method used during request preparation.
 func (c *ScrapeConfig) processBody() error {
-	method := strings.ToUpper(c.Method.String())
-	if method != "POST" && method != "PUT" && method != "PATCH" {
+	if !c.Method.normalize().allowsBody() {
 		return nil
 	}
 
@@ -142,7 +217,7 @@ func (c *ScrapeConfig) processBody() error {
 	}
 
 	if c.Data != nil {
-		contentType, ok := c.Headers["content-type"]
+		contentType, ok := headerValue(c.Headers, "content-type")
 		if !ok {
 			contentType = "application/x-www-form-urlencoded"
 			if c.Headers == nil {
@@ -170,7 +245,7 @@ func (c *ScrapeConfig) processBody() error {
 	}
 
 	if c.Body != "" {
-		if _, ok := c.Headers["content-type"]; !ok {
+		if !hasHeader(c.Headers, "content-type") {
 			if c.Headers == nil {
 				c.Headers = make(map[string]string)
 			}
@@ -180,21 +255,82 @@ func (c *ScrapeConfig) processBody() error {
 	return nil
 }
 
+// browserProfile returns the browser profile to apply, from Profile or
+// Device.
+func (c *ScrapeConfig) browserProfile() *BrowserProfile {
+	if c.Profile != nil {
+		return c.Profile
+	}
+	if c.Device != nil {
+		return &c.Device.Browser
+	}
+	return nil
+}
+
+// validateMethod checks Method and its combination with Body and Data.
+func (c *ScrapeConfig) validateMethod() error {
+	method := c.Method.normalize()
+	switch {
+	case method == "DELETE":
+		return fmt.Errorf("%w: method DELETE is not supported by the Scrape API", ErrScrapeConfig)
+	case !method.IsValid():
+		return fmt.Errorf("%w: invalid method %q, expected one of %v", ErrScrapeConfig, string(c.Method), method.Enum())
+	case !method.allowsBody() && (c.Body != "" || c.Data != nil):
+		return fmt.Errorf("%w: %s requests cannot have a Body or Data, use POST, PUT or PATCH", ErrScrapeConfig, method)
+	}
+	return nil
+}
+
 var countryRegex = regexp.MustCompile("^([a-zA-Z]{2}|)$")
 
+// langRegex matches a BCP 47 style language tag: a 2-3 letter primary
+// language subtag followed by optional region/script/variant subtags.
+var langRegex = regexp.MustCompile("^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$")
+
+// maxRenderingWait is the largest rendering_wait the API accepts, in milliseconds.
+const maxRenderingWait = 25000
+
+// Validate checks the config without sending it: mutually exclusive or
+// dependent options (browser-only options without RenderJS, Cache with
+// Session...), the URL and the option values. Unlike the check Scrape runs,
+// it reports every problem at once; the returned error wraps
+// ErrScrapeConfig and joins one error per problem.
+//
+// Example:
+//
+//	if err := config.Validate(); err != nil {
+//	    log.Fatalf("invalid scrape config:\n%v", err)
+//	}
+func (c *ScrapeConfig) Validate() error {
+	return c.validateConfig()
+}
+
 func (c *ScrapeConfig) validateConfig() error {
+	var errs []error
 
 	// validate exclusive fields, see struct tags
 	if err := ValidateExclusiveFields(c); err != nil {
-		return err
+		errs = append(errs, err)
 	}
 	// validate required fields, see struct tags
 	if err := ValidateRequiredFields(c); err != nil {
-		return err
+		errs = append(errs, err)
 	}
 	// validate enums, see struct tags
 	if err := ValidateEnums(c); err != nil {
-		return err
+		errs = append(errs, err)
+	}
+
+	if c.URL != "" {
+		if u, err := url.Parse(c.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("%w: invalid URL %q, expected an absolute http(s) URL", ErrScrapeConfig, c.URL))
+		}
+	}
+	if c.Cache && c.Session != "" {
+		errs = append(errs, fmt.Errorf("%w: Cache cannot be combined with Session", ErrScrapeConfig))
+	}
+	if err := c.validateMethod(); err != nil {
+		errs = append(errs, err)
 	}
 
 	// validate country code
@@ -202,21 +338,103 @@ func (c *ScrapeConfig) validateConfig() error {
 	if c.Country != "" {
 		country := strings.ToLower(c.Country)
 		if !countryRegex.MatchString(country) {
-			return fmt.Errorf("%w: invalid country code (ISO 3166-1 alpha-2): %s", ErrScrapeConfig, country)
+			errs = append(errs, fmt.Errorf("%w: invalid country code (ISO 3166-1 alpha-2): %s", ErrScrapeConfig, country))
+		}
+	}
+
+	if c.Geolocation != "" {
+		if err := validateGeolocation(c.Geolocation); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.GeolocationTarget != nil {
+		if c.Country != "" || c.Geolocation != "" {
+			errs = append(errs, fmt.Errorf("%w: GeolocationTarget cannot be combined with Country or Geolocation", ErrScrapeConfig))
+		}
+		if _, _, err := c.GeolocationTarget.resolve(); err != nil {
+			errs = append(errs, err)
+		}
+		if c.GeolocationTarget.State != "" && !c.RenderJS {
+			// the location is set as the browser geolocation
+			errs = append(errs, fmt.Errorf("%w: GeolocationTarget state and city targeting require RenderJS", ErrScrapeConfig))
+		}
+	}
+
+	if c.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("%w: timeout must be >= 0, got %d", ErrScrapeConfig, c.Timeout))
+	}
+
+	if c.CacheStaleWhileRevalidate < 0 {
+		errs = append(errs, fmt.Errorf("%w: CacheStaleWhileRevalidate must be >= 0, got %d", ErrScrapeConfig, c.CacheStaleWhileRevalidate))
+	}
+	if c.CacheStaleWhileRevalidate > 0 && !c.Cache {
+		errs = append(errs, fmt.Errorf("%w: CacheStaleWhileRevalidate requires Cache", ErrScrapeConfig))
+	}
+	if c.AutoReferer && c.Session == "" {
+		errs = append(errs, fmt.Errorf("%w: AutoReferer requires Session", ErrScrapeConfig))
+	}
+
+	for _, lang := range c.Lang {
+		if !langRegex.MatchString(lang) {
+			errs = append(errs, fmt.Errorf("%w: invalid lang tag (expected e.g. \"en\" or \"en-US\"): %q", ErrScrapeConfig, lang))
+		}
+	}
+
+	if c.RenderingWait < 0 || c.RenderingWait > maxRenderingWait {
+		errs = append(errs, fmt.Errorf("%w: rendering_wait must be between 0 and %d ms, got %d", ErrScrapeConfig, maxRenderingWait, c.RenderingWait))
+	}
+
+	// browser-only parameters are silently ignored by the API without
+	// render_js, so surface the mistake instead of dropping them.
+	if !c.RenderJS {
+		if c.RenderingWait > 0 {
+			errs = append(errs, fmt.Errorf("%w: rendering_wait requires RenderJS", ErrScrapeConfig))
+		}
+		if c.AutoScroll {
+			errs = append(errs, fmt.Errorf("%w: auto_scroll requires RenderJS", ErrScrapeConfig))
+		}
+		if c.RenderingStage != "" && c.RenderingStage != RenderingStageComplete {
+			errs = append(errs, fmt.Errorf("%w: rendering_stage requires RenderJS", ErrScrapeConfig))
+		}
+		if len(c.Screenshots) > 0 {
+			errs = append(errs, fmt.Errorf("%w: screenshots require RenderJS", ErrScrapeConfig))
+		}
+		if c.JS != "" {
+			errs = append(errs, fmt.Errorf("%w: js requires RenderJS", ErrScrapeConfig))
+		}
+		if c.WaitForSelector != "" {
+			errs = append(errs, fmt.Errorf("%w: wait_for_selector requires RenderJS", ErrScrapeConfig))
 		}
+		if len(c.JSScenario) > 0 {
+			errs = append(errs, fmt.Errorf("%w: js_scenario requires RenderJS", ErrScrapeConfig))
+		}
+	}
+	if (c.WaitForSelectorState != "" || c.WaitForSelectorTimeout != 0) && c.WaitForSelector == "" {
+		errs = append(errs, fmt.Errorf("%w: WaitForSelectorState and WaitForSelectorTimeout require WaitForSelector", ErrScrapeConfig))
+	}
+	switch c.WaitForSelectorState {
+	case "", js_scenario.SelectorStateVisible, js_scenario.SelectorStateHidden:
+	default:
+		errs = append(errs, fmt.Errorf("%w: invalid WaitForSelectorState %q, must be visible or hidden", ErrScrapeConfig, c.WaitForSelectorState))
+	}
+	if c.WaitForSelectorTimeout < 0 || c.WaitForSelectorTimeout > maxRenderingWait {
+		errs = append(errs, fmt.Errorf("%w: WaitForSelectorTimeout must be between 0 and %d ms, got %d", ErrScrapeConfig, maxRenderingWait, c.WaitForSelectorTimeout))
+	}
+	if len(c.ScreenshotFlags) > 0 && len(c.Screenshots) == 0 {
+		errs = append(errs, fmt.Errorf("%w: screenshot_flags require Screenshots", ErrScrapeConfig))
 	}
 
 	if c.RenderJS {
 
 		if len(c.JSScenario) > 0 {
 			if _, err := json.Marshal(c.JSScenario); err != nil {
-				return fmt.Errorf("failed to marshal js_scenario: %w", err)
+				errs = append(errs, fmt.Errorf("failed to marshal js_scenario: %w", err))
 			}
 		}
 		if len(c.Screenshots) > 0 {
 			for name, value := range c.Screenshots {
 				if value == "" {
-					return fmt.Errorf("%w: screenshots[%s] require either a selector or fullpage", ErrScrapeConfig, name)
+					errs = append(errs, fmt.Errorf("%w: screenshots[%s] require either a selector or fullpage", ErrScrapeConfig, name))
 				}
 			}
 		}
@@ -226,25 +444,35 @@ func (c *ScrapeConfig) validateConfig() error {
 	if c.ExtractionEphemeralTemplate != nil {
 		_, err := json.Marshal(c.ExtractionEphemeralTemplate)
 		if err != nil {
-			return fmt.Errorf("failed to marshal extraction_ephemeral_template: %w", err)
+			errs = append(errs, fmt.Errorf("failed to marshal extraction_ephemeral_template: %w", err))
 		}
 	}
 
-	for key, value := range c.Headers {
-		if key == "" || value == "" {
-			return fmt.Errorf("%w: headers key and value cannot be empty, found key: %s, value: %s", ErrScrapeConfig, key, value)
+	if c.Profile != nil {
+		if err := c.Profile.validate(); err != nil {
+			errs = append(errs, err)
 		}
 	}
-
-	if len(c.Cookies) > 0 {
-		for name, value := range c.Cookies {
-			if name == "" || value == "" {
-				return fmt.Errorf("%w: cookies name and value cannot be empty, found name: %s, value: %s", ErrScrapeConfig, name, value)
-			}
+	if c.Device != nil {
+		if c.Profile != nil {
+			errs = append(errs, fmt.Errorf("%w: Device cannot be combined with Profile", ErrScrapeConfig))
+		}
+		if err := c.Device.validate(); err != nil {
+			errs = append(errs, err)
 		}
 	}
 
-	return nil
+	if _, err := EncodeHeaderParams(c.Headers, c.Cookies); err != nil {
+		errs = append(errs, err)
+	}
+	if c.IfNoneMatch != "" && hasHeader(c.Headers, "if-none-match") {
+		errs = append(errs, fmt.Errorf("%w: IfNoneMatch cannot be combined with an If-None-Match header", ErrScrapeConfig))
+	}
+	if !c.IfModifiedSince.IsZero() && hasHeader(c.Headers, "if-modified-since") {
+		errs = append(errs, fmt.Errorf("%w: IfModifiedSince cannot be combined with an If-Modified-Since header", ErrScrapeConfig))
+	}
+
+	return joinConfigErrors(ErrScrapeConfig, errs)
 }
 
 // toAPIParamsWithValidation converts the ScrapeConfig into URL parameters for the Scrapfly API.
@@ -263,6 +491,8 @@ func (c *ScrapeConfig) toAPIParamsWithValidation() (url.Values, error) {
 	if c.Country != "" {
 		country := strings.ToLower(c.Country)
 		params.Set("country", country)
+	} else if c.Device != nil && c.Device.Country != "" {
+		params.Set("country", strings.ToLower(c.Device.Country))
 	}
 
 	if c.ProxyPool != "" {
@@ -271,8 +501,21 @@ func (c *ScrapeConfig) toAPIParamsWithValidation() (url.Values, error) {
 
 	if c.RenderJS {
 		params.Set("render_js", "true")
+		scenario := c.JSScenario
 		if c.WaitForSelector != "" {
-			params.Set("wait_for_selector", c.WaitForSelector)
+			if c.WaitForSelectorState == "" && c.WaitForSelectorTimeout == 0 {
+				params.Set("wait_for_selector", c.WaitForSelector)
+			} else {
+				var opts []js_scenario.WaitForSelectorOption
+				if c.WaitForSelectorState != "" {
+					opts = append(opts, js_scenario.WithSelectorState(c.WaitForSelectorState))
+				}
+				if c.WaitForSelectorTimeout > 0 {
+					opts = append(opts, js_scenario.WithSelectorTimeout(c.WaitForSelectorTimeout))
+				}
+				wait := js_scenario.New().WaitForSelector(c.WaitForSelector, opts...).Steps()
+				scenario = append(wait, c.JSScenario...)
+			}
 		}
 		if c.RenderingWait > 0 {
 			params.Set("rendering_wait", fmt.Sprint(c.RenderingWait))
@@ -283,8 +526,8 @@ func (c *ScrapeConfig) toAPIParamsWithValidation() (url.Values, error) {
 		if c.JS != "" {
 			params.Set("js", urlSafeB64Encode(c.JS))
 		}
-		if len(c.JSScenario) > 0 {
-			scenarioJSON, _ := json.Marshal(c.JSScenario)
+		if len(scenario) > 0 {
+			scenarioJSON, _ := json.Marshal(scenario)
 			params.Set("js_scenario", urlSafeB64Encode(string(scenarioJSON)))
 		}
 		if len(c.Screenshots) > 0 {
@@ -299,6 +542,9 @@ func (c *ScrapeConfig) toAPIParamsWithValidation() (url.Values, error) {
 			}
 			params.Set("screenshot_flags", strings.Join(flags, ","))
 		}
+		if c.RenderingStage != "" && c.RenderingStage != RenderingStageComplete {
+			params.Set("rendering_stage", string(c.RenderingStage))
+		}
 	}
 
 	if c.ASP {
@@ -346,14 +592,21 @@ func (c *ScrapeConfig) toAPIParamsWithValidation() (url.Values, error) {
 		}
 	}
 
+	profile := c.browserProfile()
 	if c.OS != "" {
-		params.Set("os", c.OS)
+		params.Set("os", string(c.OS))
+	} else if profile != nil && profile.OS != "" {
+		params.Set("os", string(profile.OS))
 	}
 	if len(c.Lang) > 0 {
 		params.Set("lang", strings.Join(c.Lang, ","))
+	} else if c.Device != nil && len(c.Device.Lang) > 0 {
+		params.Set("lang", strings.Join(c.Device.Lang, ","))
 	}
 	if c.BrowserBrand != "" {
 		params.Set("browser_brand", c.BrowserBrand)
+	} else if profile != nil && profile.Brand != "" {
+		params.Set("browser_brand", string(profile.Brand))
 	}
 	if c.ProxifiedResponse {
 		params.Set("proxified_response", "true")
@@ -361,12 +614,16 @@ func (c *ScrapeConfig) toAPIParamsWithValidation() (url.Values, error) {
 	if c.CostBudget > 0 {
 		params.Set("cost_budget", fmt.Sprint(c.CostBudget))
 	}
+	if c.GeolocationTarget != nil {
+		country, geolocation, _ := c.GeolocationTarget.resolve()
+		params.Set("country", country)
+		if geolocation != "" {
+			params.Set("geolocation", geolocation)
+		}
+	}
 	if c.Geolocation != "" {
 		params.Set("geolocation", c.Geolocation)
 	}
-	if c.RenderingStage != "" && c.RenderingStage != "complete" {
-		params.Set("rendering_stage", c.RenderingStage)
-	}
 
 	if c.Format != "" {
 		formatVal := c.Format.String()
@@ -391,29 +648,24 @@ func (c *ScrapeConfig) toAPIParamsWithValidation() (url.Values, error) {
 		params.Set("extraction_model", string(c.ExtractionModel))
 	}
 
-	for key, value := range c.Headers {
-		params.Set(fmt.Sprintf("headers[%s]", strings.ToLower(key)), value)
+	headerParams, err := EncodeHeaderParams(c.Headers, c.Cookies)
+	if err != nil {
+		return nil, err
 	}
-
-	if len(c.Cookies) > 0 {
-		var cookieParts []string
-		for name, value := range c.Cookies {
-			cookieParts = append(cookieParts, fmt.Sprintf("%s=%s", name, value))
-		}
-		cookieHeader := strings.Join(cookieParts, "; ")
-
-		existingCookie := ""
-		for k, v := range c.Headers {
-			if strings.ToLower(k) == "cookie" {
-				existingCookie = v
-			}
-		}
-		if existingCookie != "" {
-			params.Set("headers[cookie]", existingCookie+"; "+cookieHeader)
-		} else {
-			params.Set("headers[cookie]", cookieHeader)
+	for key, values := range headerParams {
+		params[key] = values
+	}
+	if profile != nil {
+		for key, value := range profile.headers(c.Headers) {
+			params.Set(fmt.Sprintf("headers[%s]", key), value)
 		}
 	}
+	if c.IfNoneMatch != "" {
+		params.Set("headers[if-none-match]", c.IfNoneMatch)
+	}
+	if !c.IfModifiedSince.IsZero() {
+		params.Set("headers[if-modified-since]", c.IfModifiedSince.UTC().Format(http.TimeFormat))
+	}
 
 	return params, nil
 }
@@ -0,0 +1,198 @@
+package scrapfly
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	js_scenario "github.com/scrapfly/go-scrapfly/scenario"
+)
+
+func TestScrapeConfig_RenderingParamsSerialize(t *testing.T) {
+	cfg := &ScrapeConfig{
+		URL:            "https://example.com",
+		RenderJS:       true,
+		RenderingWait:  2000,
+		AutoScroll:     true,
+		RenderingStage: RenderingStageDOMContentLoaded,
+		OS:             OSMacOS,
+		Lang:           []string{"fr-FR", "en"},
+	}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"rendering_wait":  "2000",
+		"auto_scroll":     "true",
+		"rendering_stage": "domcontentloaded",
+		"os":              "mac",
+		"lang":            "fr-FR,en",
+	}
+	for k, v := range want {
+		if got := params.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestScrapeConfig_DefaultRenderingStageOmitted(t *testing.T) {
+	cfg := &ScrapeConfig{URL: "https://example.com", RenderJS: true, RenderingStage: RenderingStageComplete}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Has("rendering_stage") {
+		t.Errorf("rendering_stage should be omitted for the API default, got %q", params.Get("rendering_stage"))
+	}
+}
+
+func TestScrapeConfig_RenderingParamsValidation(t *testing.T) {
+	cases := map[string]*ScrapeConfig{
+		"invalid os":              {URL: "https://example.com", OS: "beos"},
+		"invalid stage":           {URL: "https://example.com", RenderJS: true, RenderingStage: "networkidle"},
+		"invalid lang":            {URL: "https://example.com", Lang: []string{"en_US"}},
+		"rendering_wait too long": {URL: "https://example.com", RenderJS: true, RenderingWait: 30000},
+		"rendering_wait no js":    {URL: "https://example.com", RenderingWait: 1000},
+		"auto_scroll no js":       {URL: "https://example.com", AutoScroll: true},
+		"stage no js":             {URL: "https://example.com", RenderingStage: RenderingStageDOMContentLoaded},
+		"screenshots no js":       {URL: "https://example.com", Screenshots: map[string]string{"page": "fullpage"}},
+		"selector no js":          {URL: "https://example.com", WaitForSelector: "#x"},
+		"selector state no sel":   {URL: "https://example.com", RenderJS: true, WaitForSelectorState: js_scenario.SelectorStateHidden},
+		"selector bad state":      {URL: "https://example.com", RenderJS: true, WaitForSelector: "#x", WaitForSelectorState: "attached"},
+		"selector timeout range":  {URL: "https://example.com", RenderJS: true, WaitForSelector: "#x", WaitForSelectorTimeout: 60000},
+		"js no js":                {URL: "https://example.com", JS: "return 1"},
+		"flags no screenshots":    {URL: "https://example.com", RenderJS: true, ScreenshotFlags: []ScreenshotFlag{DarkMode}},
+	}
+	for name, cfg := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := cfg.toAPIParamsWithValidation(); err == nil {
+				t.Fatal("expected validation error")
+			}
+		})
+	}
+	// browser-only checks wrap ErrScrapeConfig so callers can errors.Is them.
+	_, err := (&ScrapeConfig{URL: "https://example.com", AutoScroll: true}).toAPIParamsWithValidation()
+	if !errors.Is(err, ErrScrapeConfig) {
+		t.Errorf("expected ErrScrapeConfig, got %v", err)
+	}
+}
+
+func TestScrapeConfig_RawJSIsBase64Encoded(t *testing.T) {
+	js := "return document.title + '?&=/'"
+	params, err := (&ScrapeConfig{URL: "https://example.com", RenderJS: true, JS: js}).toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(params.Get("js"))
+	if err != nil || string(decoded) != js {
+		t.Errorf("js = %q, decoded %q (%v)", params.Get("js"), decoded, err)
+	}
+}
+
+func TestScrapeConfig_WaitForSelectorStateBecomesScenarioStep(t *testing.T) {
+	bare, err := (&ScrapeConfig{URL: "https://example.com", RenderJS: true, WaitForSelector: "#x"}).toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bare.Get("wait_for_selector") != "#x" || bare.Has("js_scenario") {
+		t.Errorf("a bare selector must use the wait_for_selector parameter, got %v", bare)
+	}
+
+	params, err := (&ScrapeConfig{
+		URL:                    "https://example.com",
+		RenderJS:               true,
+		WaitForSelector:        ".spinner",
+		WaitForSelectorState:   js_scenario.SelectorStateHidden,
+		WaitForSelectorTimeout: 5000,
+		JSScenario:             []js_scenario.JSScenarioStep{{"click": map[string]interface{}{"selector": "#go"}}},
+	}).toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Has("wait_for_selector") {
+		t.Error("wait_for_selector must not be sent alongside the scenario step")
+	}
+	decoded, _ := base64.RawURLEncoding.DecodeString(params.Get("js_scenario"))
+	var steps []map[string]map[string]interface{}
+	if err := json.Unmarshal(decoded, &steps); err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || steps[0]["wait_for_selector"]["state"] != "hidden" || steps[0]["wait_for_selector"]["timeout"] != 5000.0 || steps[1]["click"] == nil {
+		t.Errorf("unexpected scenario %s", decoded)
+	}
+}
+
+func TestScrapeConfig_ValidateReportsEveryProblem(t *testing.T) {
+	cfg := &ScrapeConfig{
+		URL:             "example.com/no-scheme",
+		WaitForSelector: ".item",
+		AutoScroll:      true,
+		Cache:           true,
+		Session:         "s1",
+		Lang:            []string{"not a tag"},
+	}
+	err := cfg.Validate()
+	if !errors.Is(err, ErrScrapeConfig) {
+		t.Fatalf("got %v, want ErrScrapeConfig", err)
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("expected joined errors, got %T", err)
+	}
+	if got := len(joined.Unwrap()); got != 5 {
+		t.Errorf("got %d problems, want 5:\n%v", got, err)
+	}
+	for _, problem := range joined.Unwrap() {
+		if !errors.Is(problem, ErrScrapeConfig) {
+			t.Errorf("problem does not wrap ErrScrapeConfig: %v", problem)
+		}
+	}
+}
+
+func TestScrapeConfig_ValidateValidConfig(t *testing.T) {
+	cfg := &ScrapeConfig{URL: "https://example.com", RenderJS: true, WaitForSelector: ".item"}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestScrapeConfig_MethodValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ScrapeConfig
+		wantErr bool
+	}{
+		{"default GET", ScrapeConfig{URL: "https://example.com"}, false},
+		{"lowercase head", ScrapeConfig{URL: "https://example.com", Method: "head"}, false},
+		{"options", ScrapeConfig{URL: "https://example.com", Method: HttpMethodOptions}, false},
+		{"patch with body", ScrapeConfig{URL: "https://example.com", Method: HttpMethodPatch, Body: "{}"}, false},
+		{"put without body", ScrapeConfig{URL: "https://example.com", Method: HttpMethodPut}, false},
+		{"delete unsupported", ScrapeConfig{URL: "https://example.com", Method: "DELETE"}, true},
+		{"unknown method", ScrapeConfig{URL: "https://example.com", Method: "FETCH"}, true},
+		{"GET with body", ScrapeConfig{URL: "https://example.com", Body: "x"}, true},
+		{"HEAD with data", ScrapeConfig{URL: "https://example.com", Method: HttpMethodHead, Data: map[string]interface{}{"a": 1}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrScrapeConfig) {
+				t.Errorf("error does not wrap ErrScrapeConfig: %v", err)
+			}
+		})
+	}
+}
+
+func TestScrapeConfig_LowercaseMethodEncodesBody(t *testing.T) {
+	cfg := &ScrapeConfig{URL: "https://example.com", Method: "patch", Data: map[string]interface{}{"a": "b"}}
+	if err := cfg.processBody(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Body != "a=b" {
+		t.Errorf("Body = %q, want a=b", cfg.Body)
+	}
+}
@@ -1,8 +1,10 @@
 package scrapfly
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
+	"slices"
 	"strings"
 )
 
@@ -30,12 +32,42 @@ const (
 	OptionLoadImages ScreenshotOption = "load_images"
 	// OptionDarkMode enables dark mode rendering.
 	OptionDarkMode ScreenshotOption = "dark_mode"
-	// OptionBlockBanners blocks cookie banners and similar overlays.
+	// OptionBlockBanners blocks cookie banners, ads and similar overlays.
+	// It is the only blocking option of the API: hide the rest (chat
+	// widgets...) with ScreenshotConfig.HideSelectors.
 	OptionBlockBanners ScreenshotOption = "block_banners"
 	// OptionPrintMediaFormat uses print media CSS for rendering.
 	OptionPrintMediaFormat ScreenshotOption = "print_media_format"
 )
 
+// ScreenshotColorScheme is the prefers-color-scheme the page is rendered
+// with.
+type ScreenshotColorScheme string
+
+// Available color schemes.
+const (
+	// ColorSchemeLight renders the light theme, the default.
+	ColorSchemeLight ScreenshotColorScheme = "light"
+	// ColorSchemeDark renders the dark theme, like OptionDarkMode.
+	ColorSchemeDark ScreenshotColorScheme = "dark"
+)
+
+// ChatWidgetSelectors match the launchers and frames of common chat widgets
+// (Intercom, Drift, Zendesk, HubSpot, Crisp, Tawk.to, LiveChat), for
+// ScreenshotConfig.HideSelectors.
+var ChatWidgetSelectors = []string{
+	"#intercom-container",
+	".intercom-lightweight-app",
+	"#drift-widget-container",
+	"#drift-frame-controller",
+	"#launcher",
+	"iframe#webWidget",
+	"#hubspot-messages-iframe-container",
+	".crisp-client",
+	"iframe[title*=\"chat widget\" i]",
+	"#chat-widget-container",
+}
+
 // ScreenshotConfig configures a screenshot capture request to the Scrapfly API.
 //
 // This struct contains all available options for customizing screenshot behavior,
@@ -53,26 +85,59 @@ const (
 type ScreenshotConfig struct {
 	// URL is the target URL to capture (required).
 	URL string
-	// Format specifies the image format (jpg, png, webp, gif).
+	// Format specifies the image format (jpg, png, webp, gif), jpg by
+	// default.
 	Format ScreenshotFormat
+	// Quality is the JPEG quality, from 1 to 100, of jpg screenshots. The
+	// API has no quality parameter: the image is encoded again once
+	// downloaded, so Quality only lowers the size. Zero keeps the image as
+	// captured.
+	Quality int
 	// Capture defines what to capture: "fullpage" for entire page, or a CSS selector for specific element.
 	Capture string
+	// Selector captures only the element matching the CSS selector (e.g.
+	// "#pricing"), like Capture set to it. It can't be combined with a
+	// different Capture.
+	Selector string
+	// Clip crops the capture to a region, in pixels from the top left
+	// corner of the captured page or element. The API has no clip
+	// parameter: the image is cropped once downloaded, which requires the
+	// jpg, png or gif format.
+	Clip *ScreenshotClip
 	// Resolution sets the viewport size (e.g., "1920x1080").
 	Resolution string
+	// Profile, when set and Resolution is empty, uses the profile viewport
+	// as Resolution. The Screenshot API has no other emulation parameter.
+	Profile *BrowserProfile
+	// Device, like Profile, uses the viewport of the device browser as
+	// Resolution, e.g. DeviceIPhone14() or DevicePixel7(), and its country
+	// when Country is empty. It can't be combined with Profile. The page is
+	// rendered at a scale factor of 1, whatever the device ScaleFactor.
+	Device *DeviceProfile
+	// ColorScheme sets prefers-color-scheme: ColorSchemeDark adds
+	// OptionDarkMode. Empty renders the light theme.
+	ColorScheme ScreenshotColorScheme
 	// Country specifies the proxy country code (e.g., "us", "uk", "de").
 	Country string
 	// Timeout sets the maximum time in milliseconds to wait for the request.
 	Timeout int
-	// RenderingWait is additional wait time in milliseconds after page load.
+	// RenderingWait is additional wait time in milliseconds after page load,
+	// at most 25000.
 	RenderingWait int
 	// WaitForSelector waits for a CSS selector to appear before capturing.
 	WaitForSelector string
 	// Options are additional screenshot options (dark mode, block banners, etc.).
 	Options []ScreenshotOption
-	// AutoScroll automatically scrolls the page to load lazy content.
+	// AutoScroll automatically scrolls the page to load lazy content,
+	// usually needed for a meaningful "fullpage" capture.
 	AutoScroll bool
-	// JS is custom JavaScript code to execute before capturing.
+	// JS is custom JavaScript code to execute before capturing, once the
+	// page is loaded (to trigger lazy loading, close a modal...).
 	JS string
+	// HideSelectors hides the elements matching the CSS selectors before
+	// capturing, with a style injected ahead of JS (see
+	// ChatWidgetSelectors). An invalid selector only affects itself.
+	HideSelectors []string
 	// Cache enables response caching.
 	Cache bool
 	// CacheTTL sets the cache time-to-live in seconds.
@@ -86,6 +151,14 @@ type ScreenshotConfig struct {
 	VisionDeficiencyType VisionDeficiencyType
 }
 
+// ScreenshotClip is a region of a screenshot, in pixels.
+type ScreenshotClip struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
 // toAPIParams converts the ScreenshotConfig into URL parameters for the Scrapfly API.
 // This is an internal method used by the Client to prepare API requests.
 func (c *ScreenshotConfig) toAPIParams() (url.Values, error) {
@@ -96,21 +169,64 @@ func (c *ScreenshotConfig) toAPIParams() (url.Values, error) {
 	}
 	params.Set("url", c.URL)
 
-	if c.Format != "" {
+	switch c.Format {
+	case "":
+	case FormatJPG, FormatPNG, FormatWEBP, FormatGIF:
 		params.Set("format", string(c.Format))
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", ErrScreenshotConfig, c.Format)
+	}
+	if c.Quality != 0 {
+		if c.Quality < 1 || c.Quality > 100 {
+			return nil, fmt.Errorf("%w: Quality %d is out of the 1-100 range", ErrScreenshotConfig, c.Quality)
+		}
+		if c.Format != "" && c.Format != FormatJPG {
+			return nil, fmt.Errorf("%w: Quality only applies to jpg screenshots, not %s", ErrScreenshotConfig, c.Format)
+		}
 	}
-	if c.Capture != "" {
+	switch {
+	case c.Selector != "" && c.Capture != "" && c.Capture != c.Selector:
+		return nil, fmt.Errorf("%w: Selector %q conflicts with Capture %q", ErrScreenshotConfig, c.Selector, c.Capture)
+	case c.Selector != "":
+		params.Set("capture", c.Selector)
+	case c.Capture != "":
 		params.Set("capture", c.Capture)
 	}
-	if c.Resolution != "" {
+	if clip := c.Clip; clip != nil {
+		if clip.X < 0 || clip.Y < 0 || clip.Width <= 0 || clip.Height <= 0 {
+			return nil, fmt.Errorf("%w: invalid Clip %+v", ErrScreenshotConfig, *clip)
+		}
+		if c.Format == FormatWEBP {
+			return nil, fmt.Errorf("%w: Clip can't crop webp screenshots", ErrScreenshotConfig)
+		}
+	}
+	profile := c.Profile
+	if c.Device != nil {
+		if c.Profile != nil {
+			return nil, fmt.Errorf("%w: Device cannot be combined with Profile", ErrScreenshotConfig)
+		}
+		profile = &c.Device.Browser
+	}
+	switch {
+	case c.Resolution != "":
 		params.Set("resolution", c.Resolution)
+	case profile != nil && profile.Viewport != "":
+		if !viewportRegex.MatchString(profile.Viewport) {
+			return nil, fmt.Errorf("%w: invalid profile viewport %q, expected WIDTHxHEIGHT", ErrScreenshotConfig, profile.Viewport)
+		}
+		params.Set("resolution", profile.Viewport)
 	}
 	if c.Country != "" {
 		params.Set("country", c.Country)
+	} else if c.Device != nil && c.Device.Country != "" {
+		params.Set("country", strings.ToLower(c.Device.Country))
 	}
 	if c.Timeout > 0 {
 		params.Set("timeout", fmt.Sprint(c.Timeout))
 	}
+	if c.RenderingWait < 0 || c.RenderingWait > maxRenderingWait {
+		return nil, fmt.Errorf("%w: rendering_wait must be between 0 and %d ms, got %d", ErrScreenshotConfig, maxRenderingWait, c.RenderingWait)
+	}
 	if c.RenderingWait > 0 {
 		params.Set("rendering_wait", fmt.Sprint(c.RenderingWait))
 	}
@@ -120,13 +236,26 @@ func (c *ScreenshotConfig) toAPIParams() (url.Values, error) {
 	if c.AutoScroll {
 		params.Set("auto_scroll", "true")
 	}
-	if c.JS != "" {
-		params.Set("js", urlSafeB64Encode(c.JS))
+	if js := hideSelectorsJS(c.HideSelectors) + c.JS; js != "" {
+		params.Set("js", urlSafeB64Encode(js))
 	}
 
-	if len(c.Options) > 0 {
+	options := c.Options
+	switch c.ColorScheme {
+	case "", ColorSchemeLight:
+		if c.ColorScheme == ColorSchemeLight && slices.Contains(options, OptionDarkMode) {
+			return nil, fmt.Errorf("%w: ColorScheme light conflicts with OptionDarkMode", ErrScreenshotConfig)
+		}
+	case ColorSchemeDark:
+		if !slices.Contains(options, OptionDarkMode) {
+			options = append(slices.Clip(options), OptionDarkMode)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown color scheme %q", ErrScreenshotConfig, c.ColorScheme)
+	}
+	if len(options) > 0 {
 		var opts []string
-		for _, opt := range c.Options {
+		for _, opt := range options {
 			opts = append(opts, string(opt))
 		}
 		params.Set("options", strings.Join(opts, ","))
@@ -152,3 +281,20 @@ func (c *ScreenshotConfig) toAPIParams() (url.Values, error) {
 
 	return params, nil
 }
+
+// hideSelectorsJS returns the script adding a style hiding selectors, one
+// rule each so an invalid selector doesn't drop the others; empty without
+// selectors.
+func hideSelectorsJS(selectors []string) string {
+	var css strings.Builder
+	for _, selector := range selectors {
+		if selector = strings.TrimSpace(selector); selector != "" {
+			css.WriteString(selector + "{display:none!important;visibility:hidden!important}\n")
+		}
+	}
+	if css.Len() == 0 {
+		return ""
+	}
+	text, _ := json.Marshal(css.String())
+	return fmt.Sprintf("(function(){var s=document.createElement('style');s.textContent=%s;(document.head||document.documentElement).appendChild(s);})();\n", text)
+}
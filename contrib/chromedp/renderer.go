@@ -0,0 +1,125 @@
+// Package chromedprender is a chromedp-backed scrapfly.HTMLRenderer.
+//
+// It renders stored scrape results to images with a local headless Chrome,
+// for archival thumbnails that don't need a second (billable) Screenshot API
+// call. It lives in its own module so the core SDK does not depend on
+// chromedp.
+//
+//	go get github.com/scrapfly/go-scrapfly/contrib/chromedp
+//
+// Example:
+//
+//	renderer := chromedprender.New(chromedprender.WithViewport(1280, 800))
+//	img, err := result.RenderImage(ctx, renderer)
+package chromedprender
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+	"github.com/scrapfly/go-scrapfly"
+)
+
+var _ scrapfly.HTMLRenderer = (*Renderer)(nil)
+
+// Renderer renders HTML documents with a local headless Chrome through chromedp.
+type Renderer struct {
+	width     int64
+	height    int64
+	fullPage  bool
+	quality   int
+	allocOpts []chromedp.ExecAllocatorOption
+}
+
+// Option configures a Renderer.
+type Option func(*Renderer)
+
+// WithViewport sets the browser viewport size in CSS pixels (default 1280x800).
+func WithViewport(width, height int64) Option {
+	return func(r *Renderer) {
+		r.width = width
+		r.height = height
+	}
+}
+
+// WithFullPage captures the whole document instead of the viewport only.
+func WithFullPage(fullPage bool) Option {
+	return func(r *Renderer) {
+		r.fullPage = fullPage
+	}
+}
+
+// WithJPEGQuality encodes captures as JPEG with the given quality (1-99).
+// The default (100) produces lossless PNG.
+func WithJPEGQuality(quality int) Option {
+	return func(r *Renderer) {
+		r.quality = quality
+	}
+}
+
+// WithAllocatorOptions overrides the Chrome process options (binary path,
+// flags, ...). Defaults to chromedp.DefaultExecAllocatorOptions.
+func WithAllocatorOptions(opts ...chromedp.ExecAllocatorOption) Option {
+	return func(r *Renderer) {
+		r.allocOpts = opts
+	}
+}
+
+// New creates a Renderer. Each RenderHTML call starts a short-lived browser
+// tab bound to the call's context.
+func New(opts ...Option) *Renderer {
+	r := &Renderer{width: 1280, height: 800, quality: 100}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// RenderHTML loads document into a blank page and captures it.
+func (r *Renderer) RenderHTML(ctx context.Context, document string) ([]byte, error) {
+	allocOpts := r.allocOpts
+	if len(allocOpts) == 0 {
+		allocOpts = chromedp.DefaultExecAllocatorOptions[:]
+	}
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, allocOpts...)
+	defer cancelAlloc()
+	tabCtx, cancelTab := chromedp.NewContext(allocCtx)
+	defer cancelTab()
+
+	var img []byte
+	var capture chromedp.Action
+	switch {
+	case r.fullPage:
+		capture = chromedp.FullScreenshot(&img, r.quality)
+	case r.quality < 100:
+		capture = chromedp.ActionFunc(func(ctx context.Context) (err error) {
+			img, err = page.CaptureScreenshot().
+				WithFormat(page.CaptureScreenshotFormatJpeg).
+				WithQuality(int64(r.quality)).
+				Do(ctx)
+			return err
+		})
+	default:
+		capture = chromedp.CaptureScreenshot(&img)
+	}
+
+	err := chromedp.Run(tabCtx,
+		chromedp.EmulateViewport(r.width, r.height),
+		chromedp.Navigate("about:blank"),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			tree, err := page.GetFrameTree().Do(ctx)
+			if err != nil {
+				return err
+			}
+			return page.SetDocumentContent(tree.Frame.ID, document).Do(ctx)
+		}),
+		chromedp.WaitReady("body", chromedp.ByQuery),
+		capture,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("chromedprender: render html: %w", err)
+	}
+	return img, nil
+}
@@ -0,0 +1,20 @@
+module github.com/scrapfly/go-scrapfly/contrib/zstd
+
+go 1.24.0
+
+require (
+	github.com/klauspost/compress v1.18.0
+	github.com/scrapfly/go-scrapfly v0.0.0
+)
+
+require (
+	github.com/PuerkitoBio/goquery v1.10.3 // indirect
+	github.com/andybalholm/cascadia v1.3.3 // indirect
+	github.com/google/jsonschema-go v0.3.0 // indirect
+	github.com/vmihailenco/msgpack/v5 v5.4.1 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	golang.org/x/net v0.46.0 // indirect
+	golang.org/x/text v0.30.0 // indirect
+)
+
+replace github.com/scrapfly/go-scrapfly => ../..
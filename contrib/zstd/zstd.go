@@ -0,0 +1,36 @@
+// Package zstd registers Zstandard compression for Extraction API uploads.
+//
+// Importing it makes ExtractionConfig.AutoCompress prefer zstd over gzip.
+// It lives in its own module so the core SDK does not depend on a zstd
+// implementation.
+//
+//	go get github.com/scrapfly/go-scrapfly/contrib/zstd
+//
+// Example:
+//
+//	import _ "github.com/scrapfly/go-scrapfly/contrib/zstd"
+//
+//	result, err := client.Extract(&scrapfly.ExtractionConfig{
+//	    Body:             document,
+//	    ContentType:      "text/html",
+//	    ExtractionPrompt: "extract the table rows",
+//	    AutoCompress:     true,
+//	})
+package zstd
+
+import (
+	"github.com/klauspost/compress/zstd"
+	"github.com/scrapfly/go-scrapfly"
+)
+
+// encoder is shared: EncodeAll is safe for concurrent use.
+var encoder, _ = zstd.NewWriter(nil)
+
+func init() {
+	scrapfly.RegisterCompressor(scrapfly.ZSTD, Compress)
+}
+
+// Compress encodes data as a single zstd frame.
+func Compress(data []byte) ([]byte, error) {
+	return encoder.EncodeAll(data, make([]byte, 0, len(data)/2)), nil
+}
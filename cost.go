@@ -0,0 +1,85 @@
+package scrapfly
+
+import "strings"
+
+// CostBreakdown is the API cost of a scrape split by feature, in credits.
+// Each detail of the API cost report is attributed from its code; codes
+// this SDK does not know are counted in Other.
+type CostBreakdown struct {
+	// Base is the base scrape cost, datacenter proxy included.
+	Base int
+	// ASP is the Anti Scraping Protection cost.
+	ASP int
+	// Render is the browser rendering cost (RenderJS, screenshots).
+	Render int
+	// Proxy is the premium proxy pool cost, such as residential proxies.
+	Proxy int
+	// Bandwidth is the cost of the transferred data.
+	Bandwidth int
+	// Extraction is the cost of the extraction features.
+	Extraction int
+	// Other is the cost of the details not attributed to a feature.
+	Other int
+	// Total is the total cost reported by the API.
+	Total int
+}
+
+// Add returns the sum of two breakdowns, to aggregate the spend of several
+// scrapes, for example per target domain.
+func (b CostBreakdown) Add(other CostBreakdown) CostBreakdown {
+	return CostBreakdown{
+		Base:       b.Base + other.Base,
+		ASP:        b.ASP + other.ASP,
+		Render:     b.Render + other.Render,
+		Proxy:      b.Proxy + other.Proxy,
+		Bandwidth:  b.Bandwidth + other.Bandwidth,
+		Extraction: b.Extraction + other.Extraction,
+		Other:      b.Other + other.Other,
+		Total:      b.Total + other.Total,
+	}
+}
+
+// Breakdown splits the cost details by feature.
+func (c CostContext) Breakdown() CostBreakdown {
+	b := CostBreakdown{Total: c.Total}
+	for _, detail := range c.Details {
+		code := strings.ToUpper(detail.Code)
+		switch {
+		case strings.Contains(code, "BANDWIDTH"):
+			b.Bandwidth += detail.Amount
+		case strings.Contains(code, "ASP"), strings.Contains(code, "ANTI_SCRAPING"):
+			b.ASP += detail.Amount
+		case strings.Contains(code, "RESIDENTIAL"), strings.Contains(code, "MOBILE"), strings.Contains(code, "PREMIUM"):
+			b.Proxy += detail.Amount
+		case strings.Contains(code, "BROWSER"), strings.Contains(code, "RENDER"),
+			strings.Contains(code, "JAVASCRIPT"), strings.Contains(code, "SCREENSHOT"):
+			b.Render += detail.Amount
+		case strings.Contains(code, "EXTRACTION"), strings.HasPrefix(code, "AI_"), strings.Contains(code, "_AI"):
+			b.Extraction += detail.Amount
+		case strings.Contains(code, "BASE"), strings.Contains(code, "SCRAPE"),
+			strings.Contains(code, "DATACENTER"), strings.Contains(code, "PUBLIC_POOL"):
+			b.Base += detail.Amount
+		default:
+			b.Other += detail.Amount
+		}
+	}
+	return b
+}
+
+// Cost returns the total API cost of the scrape, in credits.
+func (r *ScrapeResult) Cost() int {
+	return r.Context.Cost.Total
+}
+
+// CostBreakdown returns the API cost of the scrape split by feature.
+//
+// Example — attribute spend to target domains:
+//
+//	spend := map[string]scrapfly.CostBreakdown{}
+//	for _, result := range results {
+//	    host := result.Context.URI.Host
+//	    spend[host] = spend[host].Add(result.CostBreakdown())
+//	}
+func (r *ScrapeResult) CostBreakdown() CostBreakdown {
+	return r.Context.Cost.Breakdown()
+}
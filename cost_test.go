@@ -0,0 +1,26 @@
+package scrapfly
+
+import "testing"
+
+func TestCostContext_Breakdown(t *testing.T) {
+	cost := CostContext{Total: 33, Details: []CostDetail{
+		{Amount: 1, Code: "PROXY_POOL_DATACENTER"},
+		{Amount: 5, Code: "BROWSER"},
+		{Amount: 25, Code: "RESIDENTIAL_PROXY"},
+		{Amount: 1, Code: "BANDWIDTH_RESIDENTIAL"},
+		{Amount: 0, Code: "ASP"},
+		{Amount: 1, Code: "SOMETHING_NEW"},
+	}}
+	want := CostBreakdown{Base: 1, Render: 5, Proxy: 25, Bandwidth: 1, Other: 1, Total: 33}
+	if got := cost.Breakdown(); got != want {
+		t.Errorf("Breakdown() = %+v, want %+v", got, want)
+	}
+
+	result := &ScrapeResult{Context: ContextData{Cost: cost}}
+	if result.Cost() != 33 {
+		t.Errorf("Cost() = %d", result.Cost())
+	}
+	if sum := result.CostBreakdown().Add(want); sum.Total != 66 || sum.Proxy != 50 {
+		t.Errorf("Add() = %+v", sum)
+	}
+}
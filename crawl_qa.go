@@ -0,0 +1,184 @@
+package scrapfly
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// QASampleOptions configures Crawl.PostQASample.
+type QASampleOptions struct {
+	// WebhookURL is the review endpoint the sample is POSTed to as JSON (required).
+	WebhookURL string
+	// Headers are added to the webhook request, e.g. an authorization token.
+	Headers map[string]string
+	// SampleSize is the number of visited pages to sample, at most 100.
+	// Defaults to 10.
+	SampleSize int
+	// Formats are the content formats included for each page. Defaults to
+	// markdown; add CrawlerFormatExtractedData to review extracted records.
+	Formats []CrawlerContentFormat
+	// Screenshot, when set, captures each sampled page with the Screenshot API
+	// using this config as a template (its URL is replaced). Screenshots are
+	// billed as regular Screenshot API calls.
+	Screenshot *ScreenshotConfig
+	// Seed makes the sample reproducible; zero picks a random sample.
+	Seed int64
+	// HTTPClient posts the payload. Defaults to a client with a 30s timeout.
+	HTTPClient *http.Client
+}
+
+// QASamplePayload is the JSON body posted to the review webhook.
+type QASamplePayload struct {
+	CrawlUUID    string     `json:"crawl_uuid"`
+	SampledAt    time.Time  `json:"sampled_at"`
+	VisitedPages int        `json:"visited_pages"`
+	Samples      []QASample `json:"samples"`
+}
+
+// QASample is one sampled page. Screenshot is base64 encoded in JSON.
+type QASample struct {
+	URL              string            `json:"url"`
+	Contents         map[string]string `json:"contents"`
+	Screenshot       []byte            `json:"screenshot,omitempty"`
+	ScreenshotFormat string            `json:"screenshot_format,omitempty"`
+	ScreenshotError  string            `json:"screenshot_error,omitempty"`
+}
+
+// maxQASampleSize is the batch limit of the contents endpoint.
+const maxQASampleSize = 100
+
+// PostQASample posts a random sample of the crawl's visited pages, with
+// their contents and optionally screenshots, to a review webhook, for
+// lightweight human QA of production crawls. The pages are sampled
+// uniformly over every visited URL. A failed screenshot is reported in
+// its sample rather than failing the whole post.
+//
+// Example:
+//
+//	crawl := scrapfly.NewCrawl(client, config)
+//	if err := crawl.Start(); err != nil { log.Fatal(err) }
+//	if err := crawl.Wait(nil); err != nil { log.Fatal(err) }
+//	_, err := crawl.PostQASample(scrapfly.QASampleOptions{
+//	    WebhookURL: "https://qa.example.com/review",
+//	    SampleSize: 20,
+//	    Formats:    []scrapfly.CrawlerContentFormat{scrapfly.CrawlerFormatMarkdown, scrapfly.CrawlerFormatExtractedData},
+//	    Screenshot: &scrapfly.ScreenshotConfig{Capture: "fullpage"},
+//	})
+func (c *Crawl) PostQASample(opts QASampleOptions) (*QASamplePayload, error) {
+	if err := c.requireStarted(); err != nil {
+		return nil, err
+	}
+	if opts.WebhookURL == "" {
+		return nil, fmt.Errorf("%w: QA sample webhook URL is required", ErrCrawlerConfig)
+	}
+	if opts.SampleSize <= 0 {
+		opts.SampleSize = 10
+	}
+	if opts.SampleSize > maxQASampleSize {
+		return nil, fmt.Errorf("%w: QA sample size must be at most %d", ErrCrawlerConfig, maxQASampleSize)
+	}
+	if len(opts.Formats) == 0 {
+		opts.Formats = []CrawlerContentFormat{CrawlerFormatMarkdown}
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	urls, visited, err := c.sampleVisitedURLs(opts.SampleSize, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		return nil, err
+	}
+	payload := &QASamplePayload{
+		CrawlUUID:    c.uuid,
+		SampledAt:    time.Now().UTC(),
+		VisitedPages: visited,
+		Samples:      make([]QASample, 0, len(urls)),
+	}
+	if len(urls) > 0 {
+		contents, err := c.ReadBatch(urls, opts.Formats)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read sampled pages: %w", err)
+		}
+		for _, u := range urls {
+			sample := QASample{URL: u, Contents: contents[u]}
+			if opts.Screenshot != nil {
+				shot := *opts.Screenshot
+				shot.URL = u
+				result, err := c.client.Screenshot(&shot)
+				if err != nil {
+					sample.ScreenshotError = err.Error()
+				} else {
+					sample.Screenshot = result.Image
+					sample.ScreenshotFormat = result.Metadata.ExtensionName
+				}
+			}
+			payload.Samples = append(payload.Samples, sample)
+		}
+	}
+
+	if err := postQAPayload(opts, payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// sampleVisitedURLs reservoir-samples n URLs over every page of visited
+// URLs and returns them with the visited count.
+func (c *Crawl) sampleVisitedURLs(n int, rng *rand.Rand) ([]string, int, error) {
+	const perPage = 1000
+	sample := make([]string, 0, n)
+	seen := 0
+	for page := 1; ; page++ {
+		urls, err := c.URLs(&CrawlURLsOptions{Status: "visited", Page: page, PerPage: perPage})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to list visited URLs: %w", err)
+		}
+		for _, entry := range urls.URLs {
+			seen++
+			if len(sample) < n {
+				sample = append(sample, entry.URL)
+			} else if j := rng.Intn(seen); j < n {
+				sample[j] = entry.URL
+			}
+		}
+		if len(urls.URLs) < perPage {
+			return sample, seen, nil
+		}
+	}
+}
+
+func postQAPayload(opts QASampleOptions, payload *QASamplePayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal QA sample: %w", err)
+	}
+	req, err := http.NewRequest("POST", opts.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", sdkUserAgent)
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWebhookFailed, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: review webhook returned %d: %s", ErrWebhookFailed, resp.StatusCode, respBody)
+	}
+	return nil
+}
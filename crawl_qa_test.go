@@ -0,0 +1,106 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newQACrawlClient(t *testing.T, visited int) *Client {
+	t.Helper()
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/urls"):
+			if r.URL.Query().Get("page") != "1" {
+				return
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			for i := 0; i < visited; i++ {
+				fmt.Fprintf(w, "https://example.com/p%d\n", i)
+			}
+		case strings.HasSuffix(r.URL.Path, "/contents/batch"):
+			body, _ := io.ReadAll(r.Body)
+			const boundary = "qa-boundary"
+			w.Header().Set("Content-Type", "multipart/related; boundary="+boundary)
+			for _, u := range strings.Split(string(body), "\n") {
+				fmt.Fprintf(w, "--%s\r\nContent-Type: text/markdown\r\nContent-Location: %s\r\n\r\n# %s\r\n", boundary, u, u)
+			}
+			fmt.Fprintf(w, "--%s--\r\n", boundary)
+		case r.URL.Path == "/screenshot":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("PNG"))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	})
+}
+
+func TestCrawl_PostQASample(t *testing.T) {
+	var received QASamplePayload
+	review := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer qa" {
+			t.Errorf("missing webhook header")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer review.Close()
+
+	crawl := NewCrawl(newQACrawlClient(t, 25), &CrawlerConfig{})
+	crawl.uuid = "crawl-1"
+	payload, err := crawl.PostQASample(QASampleOptions{
+		WebhookURL: review.URL,
+		Headers:    map[string]string{"Authorization": "Bearer qa"},
+		SampleSize: 5,
+		Screenshot: &ScreenshotConfig{},
+		Seed:       42,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if payload.VisitedPages != 25 || len(received.Samples) != 5 {
+		t.Fatalf("visited = %d, samples = %d", payload.VisitedPages, len(received.Samples))
+	}
+	seen := map[string]bool{}
+	for _, sample := range received.Samples {
+		if seen[sample.URL] {
+			t.Errorf("duplicate sample %s", sample.URL)
+		}
+		seen[sample.URL] = true
+		if sample.Contents["markdown"] != "# "+sample.URL {
+			t.Errorf("%s: contents = %v", sample.URL, sample.Contents)
+		}
+		if string(sample.Screenshot) != "PNG" || sample.ScreenshotFormat != "png" {
+			t.Errorf("%s: screenshot not attached", sample.URL)
+		}
+	}
+
+	again, err := crawl.PostQASample(QASampleOptions{WebhookURL: review.URL, Headers: map[string]string{"Authorization": "Bearer qa"}, SampleSize: 5, Seed: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range again.Samples {
+		if again.Samples[i].URL != payload.Samples[i].URL {
+			t.Errorf("same seed produced a different sample")
+		}
+	}
+}
+
+func TestCrawl_PostQASample_WebhookFailure(t *testing.T) {
+	review := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer review.Close()
+
+	crawl := NewCrawl(newQACrawlClient(t, 3), &CrawlerConfig{})
+	crawl.uuid = "crawl-1"
+	if _, err := crawl.PostQASample(QASampleOptions{WebhookURL: review.URL}); !errors.Is(err, ErrWebhookFailed) {
+		t.Errorf("got %v, want ErrWebhookFailed", err)
+	}
+}
@@ -0,0 +1,207 @@
+package scrapfly
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// CrawlerDevice is a device preset for CrawlVariants: a name for the
+// variant and the browser profile whose identity (user agent and
+// client-hint headers) each page is fetched with. The Crawler API takes no
+// fingerprint or viewport options, so the rest of the profile is unused.
+type CrawlerDevice struct {
+	// Name identifies the variant in paired results ("desktop", "mobile"...).
+	Name string
+	// Profile's UserAgent overrides CrawlerConfig.UserAgent for this variant
+	// and its Headers are merged over CrawlerConfig.Headers.
+	Profile BrowserProfile
+}
+
+// CrawlDesktop returns the crawler device preset of a desktop, for
+// CrawlVariants; the Device* presets return a DeviceProfile, for
+// ScrapeConfig.Device.
+func CrawlDesktop() CrawlerDevice {
+	return CrawlerDevice{Name: "desktop", Profile: *ProfileDesktopChrome()}
+}
+
+// CrawlMobile returns the crawler device preset of a phone.
+func CrawlMobile() CrawlerDevice {
+	return CrawlerDevice{Name: "mobile", Profile: *ProfileMobileChrome()}
+}
+
+// CrawlTablet returns the crawler device preset of a tablet.
+func CrawlTablet() CrawlerDevice {
+	return CrawlerDevice{Name: "tablet", Profile: *ProfileTabletSafari()}
+}
+
+// apply returns a copy of config carrying the device identity.
+func (d CrawlerDevice) apply(config *CrawlerConfig) *CrawlerConfig {
+	variant := *config
+	if d.Profile.UserAgent != "" {
+		variant.UserAgent = d.Profile.UserAgent
+	}
+	if len(d.Profile.Headers) > 0 {
+		variant.Headers = make(map[string]string, len(config.Headers)+len(d.Profile.Headers))
+		for k, v := range config.Headers {
+			variant.Headers[k] = v
+		}
+		for k, v := range d.Profile.Headers {
+			variant.Headers[k] = v
+		}
+	}
+	return &variant
+}
+
+// CrawlVariants runs the same crawler config once per device preset and
+// pairs the results by URL, for comparing mobile and desktop content or
+// pricing. Each variant is a regular crawler job billed as such.
+//
+// Discovery can diverge between variants (sites may link different pages
+// on mobile); for strictly comparable sets use CrawlerConfig.URLList.
+//
+//	variants, err := scrapfly.NewCrawlVariants(client, &scrapfly.CrawlerConfig{
+//	    URLList:        []string{"https://web-scraping.dev/product/1"},
+//	    ContentFormats: []scrapfly.CrawlerContentFormat{scrapfly.CrawlerFormatHTML},
+//	}, scrapfly.CrawlDesktop(), scrapfly.CrawlMobile())
+//	if err != nil { log.Fatal(err) }
+//	if err := variants.Start(); err != nil { log.Fatal(err) }
+//	if err := variants.Wait(nil); err != nil { log.Fatal(err) }
+//
+//	pages, _ := variants.Pairs(scrapfly.CrawlerFormatHTML)
+//	for _, page := range pages {
+//	    fmt.Println(page.URL, len(page.Variants["desktop"]), len(page.Variants["mobile"]))
+//	}
+type CrawlVariants struct {
+	devices []CrawlerDevice
+	crawls  map[string]*Crawl
+}
+
+// CrawlVariantPage is one URL with its content for every variant that
+// crawled it, keyed by device name.
+type CrawlVariantPage struct {
+	URL      string
+	Variants map[string]string
+}
+
+// NewCrawlVariants prepares one crawl per device. Device names must be
+// non-empty and unique. Nothing is scheduled until Start is called.
+func NewCrawlVariants(client *Client, config *CrawlerConfig, devices ...CrawlerDevice) (*CrawlVariants, error) {
+	if len(devices) == 0 {
+		return nil, fmt.Errorf("%w: at least one device preset is required", ErrCrawlerConfig)
+	}
+	v := &CrawlVariants{devices: devices, crawls: make(map[string]*Crawl, len(devices))}
+	for _, device := range devices {
+		if device.Name == "" {
+			return nil, fmt.Errorf("%w: device preset name cannot be empty", ErrCrawlerConfig)
+		}
+		if _, dup := v.crawls[device.Name]; dup {
+			return nil, fmt.Errorf("%w: duplicate device preset %q", ErrCrawlerConfig, device.Name)
+		}
+		v.crawls[device.Name] = NewCrawl(client, device.apply(config))
+	}
+	return v, nil
+}
+
+// Devices returns the device presets in their configured order.
+func (v *CrawlVariants) Devices() []CrawlerDevice { return v.devices }
+
+// Complete reports whether every variant returned content for page.
+func (v *CrawlVariants) Complete(page *CrawlVariantPage) bool {
+	return len(page.Variants) == len(v.devices)
+}
+
+// Crawl returns the underlying crawl of a variant, or nil for an unknown name.
+func (v *CrawlVariants) Crawl(device string) *Crawl { return v.crawls[device] }
+
+// Start schedules every variant. On failure the variants already started
+// are cancelled and the error is returned.
+func (v *CrawlVariants) Start() error {
+	for i, device := range v.devices {
+		if err := v.crawls[device.Name].Start(); err != nil {
+			for _, started := range v.devices[:i] {
+				_ = v.crawls[started.Name].Cancel()
+			}
+			return fmt.Errorf("start %s variant: %w", device.Name, err)
+		}
+	}
+	return nil
+}
+
+// Wait waits for every variant to reach a terminal state and returns the
+// joined errors of the variants that did not succeed.
+func (v *CrawlVariants) Wait(opts *WaitOptions) error {
+	var errs []error
+	for _, device := range v.devices {
+		if err := v.crawls[device.Name].Wait(opts); err != nil {
+			errs = append(errs, fmt.Errorf("%s variant: %w", device.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Cancel cancels every started variant.
+func (v *CrawlVariants) Cancel() error {
+	var errs []error
+	for _, device := range v.devices {
+		crawl := v.crawls[device.Name]
+		if !crawl.Started() {
+			continue
+		}
+		if err := crawl.Cancel(); err != nil {
+			errs = append(errs, fmt.Errorf("%s variant: %w", device.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Read fetches one URL from every variant. Variants that did not crawl the
+// URL are absent from the result.
+func (v *CrawlVariants) Read(targetURL string, format CrawlerContentFormat) (*CrawlVariantPage, error) {
+	page := &CrawlVariantPage{URL: targetURL, Variants: make(map[string]string, len(v.devices))}
+	for _, device := range v.devices {
+		content, err := v.crawls[device.Name].Read(targetURL, format)
+		if err != nil {
+			return nil, fmt.Errorf("%s variant: %w", device.Name, err)
+		}
+		if content != nil {
+			page.Variants[device.Name] = content.Content
+		}
+	}
+	return page, nil
+}
+
+// Pairs downloads the contents of every variant and pairs them by URL,
+// sorted by URL. Use CrawlVariants.Complete to keep only the URLs that
+// every variant crawled.
+func (v *CrawlVariants) Pairs(format CrawlerContentFormat) ([]*CrawlVariantPage, error) {
+	const pageSize = 50
+	pages := make(map[string]*CrawlVariantPage)
+	for _, device := range v.devices {
+		crawl := v.crawls[device.Name]
+		for offset := 0; ; offset += pageSize {
+			contents, err := crawl.Contents(format, &CrawlContentsOptions{Limit: pageSize, Offset: offset})
+			if err != nil {
+				return nil, fmt.Errorf("%s variant: %w", device.Name, err)
+			}
+			for pageURL, formats := range contents.Contents {
+				page, ok := pages[pageURL]
+				if !ok {
+					page = &CrawlVariantPage{URL: pageURL, Variants: make(map[string]string, len(v.devices))}
+					pages[pageURL] = page
+				}
+				page.Variants[device.Name] = formats[string(format)]
+			}
+			if contents.Links.Next == "" || len(contents.Contents) == 0 {
+				break
+			}
+		}
+	}
+
+	result := make([]*CrawlVariantPage, 0, len(pages))
+	for _, page := range pages {
+		result = append(result, page)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].URL < result[j].URL })
+	return result, nil
+}
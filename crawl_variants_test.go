@@ -0,0 +1,57 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestCrawlVariants_StartsOneCrawlPerDeviceAndPairsContents(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/crawl" {
+			var body map[string]interface{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			uuid := "desktop"
+			if strings.Contains(fmt.Sprint(body["user_agent"]), "Mobile") {
+				uuid = "mobile"
+			}
+			fmt.Fprintf(w, `{"crawler_uuid": %q, "status": "PENDING"}`, uuid)
+			return
+		}
+		uuid := strings.Split(strings.TrimPrefix(r.URL.Path, "/crawl/"), "/")[0]
+		fmt.Fprintf(w, `{"contents": {"https://example.com/p": {"html": "<p>%s</p>"}}, "links": {}}`, uuid)
+	})
+
+	variants, err := NewCrawlVariants(client, &CrawlerConfig{URL: "https://example.com/p"}, CrawlDesktop(), CrawlMobile())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := variants.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if variants.Crawl("mobile").UUID() != "mobile" || variants.Crawl("desktop").UUID() != "desktop" {
+		t.Fatalf("variants did not carry their device identity")
+	}
+
+	pages, err := variants.Pairs(CrawlerFormatHTML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 1 || !variants.Complete(pages[0]) {
+		t.Fatalf("expected one complete paired page, got %+v", pages)
+	}
+	if pages[0].Variants["mobile"] != "<p>mobile</p>" || pages[0].Variants["desktop"] != "<p>desktop</p>" {
+		t.Errorf("unexpected paired contents %+v", pages[0].Variants)
+	}
+}
+
+func TestNewCrawlVariants_RejectsDuplicateDevices(t *testing.T) {
+	client, _ := New("__API_KEY__")
+	if _, err := NewCrawlVariants(client, &CrawlerConfig{URL: "https://example.com"}, CrawlMobile(), CrawlMobile()); !errors.Is(err, ErrCrawlerConfig) {
+		t.Errorf("expected ErrCrawlerConfig, got %v", err)
+	}
+}
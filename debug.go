@@ -0,0 +1,141 @@
+package scrapfly
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// correlationIDKey carries the correlation ID of a scrape in the request
+// context, so retries and sub-requests can tag their log lines with it.
+type correlationIDKey struct{}
+
+// newCorrelationID generates the correlation ID attached to Debug scrapes
+// that don't set one.
+func newCorrelationID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return "sdk-" + hex.EncodeToString(b)
+}
+
+// correlate prepares one logical scrape of config, its retries included: a
+// Debug scrape, domain defaults included, without CorrelationID gets a
+// generated one, and ctx carries the ID for the log lines. Every attempt
+// made with the returned config sends the same ID.
+func (c *Client) correlate(ctx context.Context, config *ScrapeConfig) (context.Context, *ScrapeConfig) {
+	if config.CorrelationID == "" && c.withDomainDefaults(config).Debug {
+		config = config.Clone()
+		config.CorrelationID = newCorrelationID()
+	}
+	return withCorrelationID(ctx, config.CorrelationID), config
+}
+
+func withCorrelationID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+func correlationIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// logArgs prefixes log arguments with the correlation ID carried by ctx,
+// if any.
+func logArgs(ctx context.Context, v ...interface{}) []interface{} {
+	if id := correlationIDFrom(ctx); id != "" {
+		return append([]interface{}{"correlation_id=" + id}, v...)
+	}
+	return v
+}
+
+// CorrelationID returns the correlation ID echoed back by the API, either the
+// one set on ScrapeConfig.CorrelationID or the one generated for Debug
+// scrapes.
+func (r *ScrapeResult) CorrelationID() string {
+	if r.Config.CorrelationID == nil {
+		return ""
+	}
+	return *r.Config.CorrelationID
+}
+
+// monitoringLogURL is the dashboard URL of scrape logs, followed by the
+// scrape UUID.
+const monitoringLogURL = "https://scrapfly.io/dashboard/monitoring/log/"
+
+// ScrapeUUID returns the UUID of the scrape, the identifier of its log.
+func (r *ScrapeResult) ScrapeUUID() string {
+	if r.UUID != "" {
+		return r.UUID
+	}
+	return logURLUUID(r.Result.LogURL)
+}
+
+// MonitoringURL returns the dashboard link to the scrape log, where the
+// request, response and debug data of the scrape can be inspected, or ""
+// when the scrape UUID is unknown.
+func (r *ScrapeResult) MonitoringURL() string {
+	if r.Result.LogURL != "" {
+		return r.Result.LogURL
+	}
+	if r.UUID != "" {
+		return monitoringLogURL + r.UUID
+	}
+	return ""
+}
+
+// DebugURL returns the dashboard link to the scrape log; it is
+// MonitoringURL.
+func (r *ScrapeResult) DebugURL() string {
+	return r.MonitoringURL()
+}
+
+// ScrapeUUID returns the UUID of the failed scrape, or "" when the error
+// did not come from a scrape.
+//
+// Example — one-click link in an alert:
+//
+//	var apiErr *scrapfly.APIError
+//	if errors.As(err, &apiErr) && apiErr.ScrapeUUID() != "" {
+//	    alert(fmt.Sprintf("scrape %s failed: %s", apiErr.ScrapeUUID(), apiErr.MonitoringURL()))
+//	}
+func (e *APIError) ScrapeUUID() string {
+	if e.APIResponse != nil {
+		if uuid := e.APIResponse.ScrapeUUID(); uuid != "" {
+			return uuid
+		}
+	}
+	return logURLUUID(e.LogURL)
+}
+
+// MonitoringURL returns the dashboard link to the log of the failed
+// scrape, or "" when the API did not return one.
+func (e *APIError) MonitoringURL() string {
+	if e.APIResponse != nil {
+		if u := e.APIResponse.MonitoringURL(); u != "" {
+			return u
+		}
+	}
+	return e.LogURL
+}
+
+// DebugURL returns the dashboard link to the log of the failed scrape; it
+// is MonitoringURL.
+func (e *APIError) DebugURL() string {
+	return e.MonitoringURL()
+}
+
+// logURLUUID returns the scrape UUID of a dashboard log link, its last
+// path segment.
+func logURLUUID(logURL string) string {
+	u, err := url.Parse(logURL)
+	if err != nil || u.Path == "" {
+		return ""
+	}
+	return path.Base(strings.TrimSuffix(u.Path, "/"))
+}
@@ -0,0 +1,152 @@
+package scrapfly
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := DefaultLogger
+	DefaultLogger = &Logger{logger: log.New(&buf, "", 0), level: LevelDebug}
+	t.Cleanup(func() { DefaultLogger = previous })
+	return &buf
+}
+
+func TestClient_DebugScrapeThreadsCorrelationID(t *testing.T) {
+	logs := captureLogs(t)
+	var sent string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		sent = r.URL.Query().Get("correlation_id")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"config":{"correlation_id":"` + sent + `"},"result":{"success":false,"status":"ERR::SCRAPE::BAD_UPSTREAM_RESPONSE","status_code":200,"log_url":"https://scrapfly.io/dashboard/monitoring/log/abc"}}`))
+	})
+
+	config := &ScrapeConfig{URL: "https://example.com", Debug: true}
+	_, err := client.Scrape(config)
+	if !strings.HasPrefix(sent, "sdk-") {
+		t.Fatalf("correlation_id not generated, got %q", sent)
+	}
+	if config.CorrelationID != "" {
+		t.Error("the caller's config must not be mutated")
+	}
+	if !errors.Is(err, ErrScrapeFailed) || !strings.Contains(err.Error(), "monitoring/log/abc") {
+		t.Fatalf("error lacks the debug link: %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.DebugURL() != "https://scrapfly.io/dashboard/monitoring/log/abc" {
+		t.Errorf("APIError.DebugURL not surfaced: %v", err)
+	}
+	if apiErr != nil && apiErr.APIResponse.CorrelationID() != sent {
+		t.Errorf("CorrelationID() = %q, want %q", apiErr.APIResponse.CorrelationID(), sent)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if !strings.Contains(line, "correlation_id="+sent) {
+			t.Errorf("log line without correlation ID: %s", line)
+		}
+	}
+}
+
+func TestClient_DebugRetriesShareCorrelationID(t *testing.T) {
+	logs := captureLogs(t)
+	var mu sync.Mutex
+	var sent []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent = append(sent, r.URL.Query().Get("correlation_id"))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"result":{"success":false,"status":"ERR::PROXY::TIMEOUT","status_code":200}}`)
+	})
+	policy := &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}
+	config := &ScrapeConfig{URL: "https://example.com", Debug: true}
+
+	if _, attempts, _, err := client.scrapeRetrying(context.Background(), config, policy); attempts != 3 || !errors.Is(err, ErrProxyFailed) {
+		t.Fatalf("attempts %d, err %v", attempts, err)
+	}
+	for item := range client.ConcurrentScrapeWithOptions([]*ScrapeConfig{config}, ConcurrentScrapeOptions{Concurrency: 1, Retry: policy}) {
+		if item.Attempts != 3 {
+			t.Fatalf("concurrent: attempts %d, err %v", item.Attempts, item.Err)
+		}
+	}
+	if len(sent) != 6 || !strings.HasPrefix(sent[0], "sdk-") || sent[1] != sent[0] || sent[2] != sent[0] || sent[3] == sent[0] || sent[4] != sent[3] || sent[5] != sent[3] {
+		t.Errorf("correlation IDs sent = %v, want one per logical scrape", sent)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if strings.Contains(line, "retrying") && !strings.Contains(line, "correlation_id=sdk-") {
+			t.Errorf("retry log line without correlation ID: %s", line)
+		}
+	}
+}
+
+func TestFetchWithRetry_LogsCorrelationID(t *testing.T) {
+	logs := captureLogs(t)
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequestWithContext(withCorrelationID(context.Background(), "sdk-1"), "GET", server.URL, nil)
+	resp, err := fetchWithRetry(server.Client(), req, 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if !strings.Contains(logs.String(), "correlation_id=sdk-1 request failed with status 502") {
+		t.Errorf("retry not logged with the correlation ID: %q", logs.String())
+	}
+}
+
+func TestScrapeResult_DebugURLWithoutCorrelationID(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{LogURL: "https://scrapfly.io/dashboard/monitoring/log/x"}}
+	if result.DebugURL() != result.Result.LogURL || result.CorrelationID() != "" {
+		t.Errorf("unexpected accessors: %q %q", result.DebugURL(), result.CorrelationID())
+	}
+	if (&APIError{}).DebugURL() != "" {
+		t.Error("APIError without response must have no debug URL")
+	}
+}
+
+func TestScrapeUUIDAndMonitoringURL(t *testing.T) {
+	result := &ScrapeResult{UUID: "01HX"}
+	if result.ScrapeUUID() != "01HX" || result.MonitoringURL() != "https://scrapfly.io/dashboard/monitoring/log/01HX" {
+		t.Errorf("unexpected accessors: %q %q", result.ScrapeUUID(), result.MonitoringURL())
+	}
+	result = &ScrapeResult{Result: ResultData{LogURL: "https://scrapfly.io/dashboard/monitoring/log/01HY"}}
+	if result.ScrapeUUID() != "01HY" {
+		t.Errorf("ScrapeUUID() from log URL = %q", result.ScrapeUUID())
+	}
+	if (&ScrapeResult{}).MonitoringURL() != "" || (&APIError{}).ScrapeUUID() != "" {
+		t.Error("expected empty accessors without UUID")
+	}
+}
+
+func TestAPIError_MonitoringURL_Proxified(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Scrapfly-Reject-Code", "ERR::ASP::SHIELD_PROTECTION_FAILED")
+		w.Header().Set("X-Scrapfly-Log", "https://scrapfly.io/dashboard/monitoring/log/01HZ")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	})
+	_, err := client.ScrapeProxified(&ScrapeConfig{URL: "https://example.com"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *APIError, got %v", err)
+	}
+	if apiErr.ScrapeUUID() != "01HZ" || apiErr.MonitoringURL() != "https://scrapfly.io/dashboard/monitoring/log/01HZ" {
+		t.Errorf("unexpected accessors: %q %q", apiErr.ScrapeUUID(), apiErr.MonitoringURL())
+	}
+}
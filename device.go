@@ -0,0 +1,104 @@
+package scrapfly
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DeviceProfile describes a device in a locale: the browser emulation
+// (fingerprint brand and OS, user agent, client hints, viewport) together
+// with the accept-language and proxy country, so the options that must
+// agree are set from one value. The built-in devices (DeviceIPhone...)
+// return a new device on each call.
+//
+// Example:
+//
+//	// scrape as an iPhone in France
+//	config := &scrapfly.ScrapeConfig{
+//	    URL:    "https://example.com",
+//	    Device: scrapfly.DeviceIPhone().In("fr-FR"),
+//	}
+type DeviceProfile struct {
+	// Type is the device class.
+	Type DeviceType
+	// Browser is the browser emulation of the device.
+	Browser BrowserProfile
+	// Lang is sent as lang unless ScrapeConfig.Lang is set.
+	Lang []string
+	// Country is sent as country unless ScrapeConfig.Country or
+	// GeolocationTarget is set.
+	Country string
+}
+
+// DeviceWindowsDesktop returns the device of ProfileDesktopChrome, without
+// locale; see DeviceProfile.In.
+func DeviceWindowsDesktop() *DeviceProfile {
+	return &DeviceProfile{Type: DeviceTypeDesktop, Browser: *ProfileDesktopChrome()}
+}
+
+// DeviceMacDesktop returns the device of ProfileMacChrome.
+func DeviceMacDesktop() *DeviceProfile {
+	return &DeviceProfile{Type: DeviceTypeDesktop, Browser: *ProfileMacChrome()}
+}
+
+// DeviceAndroidPhone returns the device of ProfileMobileChrome.
+func DeviceAndroidPhone() *DeviceProfile {
+	return &DeviceProfile{Type: DeviceTypeMobile, Browser: *ProfileMobileChrome()}
+}
+
+// DeviceIPhone returns the device of ProfileMobileSafari.
+func DeviceIPhone() *DeviceProfile {
+	return &DeviceProfile{Type: DeviceTypeMobile, Browser: *ProfileMobileSafari()}
+}
+
+// DeviceAndroidTablet returns the device of ProfileTabletChrome.
+func DeviceAndroidTablet() *DeviceProfile {
+	return &DeviceProfile{Type: DeviceTypeTablet, Browser: *ProfileTabletChrome()}
+}
+
+// DeviceIPad returns the device of ProfileTabletSafari.
+func DeviceIPad() *DeviceProfile {
+	return &DeviceProfile{Type: DeviceTypeTablet, Browser: *ProfileTabletSafari()}
+}
+
+// DeviceIPhone14 returns the device of ProfileIPhone14.
+func DeviceIPhone14() *DeviceProfile {
+	return &DeviceProfile{Type: DeviceTypeMobile, Browser: *ProfileIPhone14()}
+}
+
+// DevicePixel7 returns the device of ProfilePixel7.
+func DevicePixel7() *DeviceProfile {
+	return &DeviceProfile{Type: DeviceTypeMobile, Browser: *ProfilePixel7()}
+}
+
+// In returns a copy of the device located in locale, a language tag such as
+// "fr-FR": Lang becomes the tag followed by its language ("fr-FR", "fr") and
+// Country the region, if the tag has one.
+func (d DeviceProfile) In(locale string) *DeviceProfile {
+	language, region, _ := strings.Cut(locale, "-")
+	d.Lang = []string{locale}
+	if region != "" {
+		d.Lang = append(d.Lang, language)
+		d.Country = strings.ToLower(region)
+	}
+	return &d
+}
+
+// validate checks the device type, browser profile and locale.
+func (d *DeviceProfile) validate() error {
+	if d.Type != "" && !d.Type.IsValid() {
+		return fmt.Errorf("%w: invalid device type %q", ErrScrapeConfig, string(d.Type))
+	}
+	if err := d.Browser.validate(); err != nil {
+		return err
+	}
+	for _, lang := range d.Lang {
+		if !langRegex.MatchString(lang) {
+			return fmt.Errorf("%w: invalid device lang %q, expected a language tag such as \"en-US\"", ErrScrapeConfig, lang)
+		}
+	}
+	if d.Country != "" && !countryRegex.MatchString(d.Country) {
+		return fmt.Errorf("%w: invalid device country code (ISO 3166-1 alpha-2): %s", ErrScrapeConfig, d.Country)
+	}
+	return nil
+}
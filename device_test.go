@@ -0,0 +1,68 @@
+package scrapfly
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDeviceProfile_In(t *testing.T) {
+	preset := DeviceIPhone()
+	device := preset.In("fr-FR")
+	if !slices.Equal(device.Lang, []string{"fr-FR", "fr"}) || device.Country != "fr" {
+		t.Errorf("got lang %v country %q", device.Lang, device.Country)
+	}
+	if preset.Lang != nil || preset.Country != "" {
+		t.Error("In must not modify the preset")
+	}
+	if noRegion := DeviceIPad().In("de"); noRegion.Country != "" || !slices.Equal(noRegion.Lang, []string{"de"}) {
+		t.Errorf("got lang %v country %q", noRegion.Lang, noRegion.Country)
+	}
+}
+
+func TestScrapeConfig_DeviceParams(t *testing.T) {
+	cfg := &ScrapeConfig{URL: "https://example.com", Device: DeviceAndroidPhone().In("fr-FR")}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"country":                     "fr",
+		"lang":                        "fr-FR,fr",
+		"browser_brand":               "chrome",
+		"headers[user-agent]":         ProfileMobileChrome().UserAgent,
+		"headers[sec-ch-ua-platform]": `"Android"`,
+	}
+	for k, v := range want {
+		if got := params.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+
+	cfg = &ScrapeConfig{URL: "https://example.com", Device: DeviceIPhone().In("fr-FR"), Country: "de", Lang: []string{"de"}}
+	params, err = cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Get("country") != "de" || params.Get("lang") != "de" {
+		t.Errorf("explicit options must take precedence, got country %q lang %q", params.Get("country"), params.Get("lang"))
+	}
+}
+
+func TestScrapeConfig_DeviceValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *ScrapeConfig
+	}{
+		{"with profile", &ScrapeConfig{URL: "https://example.com", Device: DeviceIPhone(), Profile: ProfileMobileSafari()}},
+		{"invalid type", &ScrapeConfig{URL: "https://example.com", Device: &DeviceProfile{Type: "watch"}}},
+		{"invalid locale", &ScrapeConfig{URL: "https://example.com", Device: DeviceIPhone().In("not a locale")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); !errors.Is(err, ErrScrapeConfig) {
+				t.Errorf("got %v, want ErrScrapeConfig", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package scrapfly
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DiffOptions configures Diff.
+type DiffOptions struct {
+	// Scope is the CSS selector of the part of the pages whose text is
+	// compared, such as "main" or "#product". Defaults to the body.
+	Scope string
+	// Selectors names the CSS selectors whose text is compared between the
+	// pages, such as {"price": ".product-price"}. Matches of a selector are
+	// joined with a newline.
+	Selectors map[string]string
+	// Exclude drops the matches of each pattern from the text before it is
+	// compared, for noise such as timestamps or view counters.
+	Exclude []*regexp.Regexp
+}
+
+// ValueChange is a selector whose text differs between two results.
+type ValueChange struct {
+	// Name is the key of the selector in DiffOptions.Selectors.
+	Name     string
+	Selector string
+	// Old and New are the texts of the selector, "" when it has no match.
+	Old string
+	New string
+}
+
+// ResultDiff is the difference between two HTML results; see Diff.
+type ResultDiff struct {
+	// Added are the text nodes of the new result missing from the old
+	// one, in document order.
+	Added []string
+	// Removed are the text nodes of the old result missing from the new
+	// one, in document order.
+	Removed []string
+	// Values are the selectors whose text changed, sorted by name.
+	Values []ValueChange
+}
+
+// Empty reports whether the results have the same text.
+func (d *ResultDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Values) == 0
+}
+
+// Value returns the change of the selector name, or nil when its text did
+// not change.
+func (d *ResultDiff) Value(name string) *ValueChange {
+	for i := range d.Values {
+		if d.Values[i].Name == name {
+			return &d.Values[i]
+		}
+	}
+	return nil
+}
+
+// Diff compares the text of two HTML results, such as two scrapes of a page
+// being monitored: the text nodes added and removed, wherever they moved,
+// and the selectors of opts whose text changed. Whitespace is collapsed, so
+// reformatting is not a change. A nil oldResult is an empty page.
+//
+// Diff needs goquery and returns an error in scrapfly_nogoquery builds.
+//
+// Example — watch a product price:
+//
+//	diff, err := scrapfly.Diff(previous, result, &scrapfly.DiffOptions{
+//	    Scope:     ".product",
+//	    Selectors: map[string]string{"price": ".product-price"},
+//	})
+//	if err == nil {
+//	    if change := diff.Value("price"); change != nil {
+//	        fmt.Printf("price changed from %s to %s\n", change.Old, change.New)
+//	    }
+//	}
+func Diff(oldResult, newResult *ScrapeResult, opts *DiffOptions) (*ResultDiff, error) {
+	if opts == nil {
+		opts = &DiffOptions{}
+	}
+	if oldResult == nil {
+		oldResult = &ScrapeResult{Result: ResultData{ContentType: "text/html"}}
+	}
+	oldNodes, err := htmlTextNodes(oldResult, opts.Scope)
+	if err != nil {
+		return nil, err
+	}
+	newNodes, err := htmlTextNodes(newResult, opts.Scope)
+	if err != nil {
+		return nil, err
+	}
+	diff := &ResultDiff{
+		Added:   missingTexts(opts.clean(newNodes), opts.clean(oldNodes)),
+		Removed: missingTexts(opts.clean(oldNodes), opts.clean(newNodes)),
+	}
+
+	names := make([]string, 0, len(opts.Selectors))
+	for name := range opts.Selectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		selector := opts.Selectors[name]
+		oldTexts, err := htmlSelectorTexts(oldResult, selector)
+		if err != nil {
+			return nil, err
+		}
+		newTexts, err := htmlSelectorTexts(newResult, selector)
+		if err != nil {
+			return nil, err
+		}
+		oldValue := strings.Join(opts.clean(oldTexts), "\n")
+		newValue := strings.Join(opts.clean(newTexts), "\n")
+		if oldValue != newValue {
+			diff.Values = append(diff.Values, ValueChange{Name: name, Selector: selector, Old: oldValue, New: newValue})
+		}
+	}
+	return diff, nil
+}
+
+// clean collapses the whitespace of texts and drops the Exclude matches,
+// then the texts left empty.
+func (o *DiffOptions) clean(texts []string) []string {
+	cleaned := make([]string, 0, len(texts))
+	for _, text := range texts {
+		for _, pattern := range o.Exclude {
+			text = pattern.ReplaceAllString(text, " ")
+		}
+		if text = strings.Join(strings.Fields(text), " "); text != "" {
+			cleaned = append(cleaned, text)
+		}
+	}
+	return cleaned
+}
+
+// missingTexts returns the texts of a not in b, in order. Repeated texts
+// count: a text present twice in a and once in b is missing once.
+func missingTexts(a, b []string) []string {
+	counts := make(map[string]int, len(b))
+	for _, text := range b {
+		counts[text]++
+	}
+	var missing []string
+	for _, text := range a {
+		if counts[text] > 0 {
+			counts[text]--
+			continue
+		}
+		missing = append(missing, text)
+	}
+	return missing
+}
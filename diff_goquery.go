@@ -0,0 +1,68 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import (
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// diffSkipped are the elements whose text is not page content.
+var diffSkipped = map[string]bool{"head": true, "script": true, "style": true, "noscript": true, "template": true}
+
+// htmlTextNodes returns the text nodes of the elements matching scope (the
+// body when empty), in document order.
+func htmlTextNodes(r *ScrapeResult, scope string) ([]string, error) {
+	doc, err := r.Selector()
+	if err != nil {
+		return nil, err
+	}
+	if scope == "" {
+		scope = "body"
+	}
+	var texts []string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		switch n.Type {
+		case html.TextNode:
+			texts = append(texts, n.Data)
+			return
+		case html.ElementNode:
+			if diffSkipped[n.Data] {
+				return
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	nodes := doc.Find(scope).Nodes
+	matched := make(map[*html.Node]bool, len(nodes))
+	for _, n := range nodes {
+		matched[n] = true
+	}
+nodes:
+	for _, n := range nodes {
+		// nested matches are read with their ancestor
+		for parent := n.Parent; parent != nil; parent = parent.Parent {
+			if matched[parent] {
+				continue nodes
+			}
+		}
+		walk(n)
+	}
+	return texts, nil
+}
+
+// htmlSelectorTexts returns the text of each element matching selector.
+func htmlSelectorTexts(r *ScrapeResult, selector string) ([]string, error) {
+	doc, err := r.Selector()
+	if err != nil {
+		return nil, err
+	}
+	var texts []string
+	doc.Find(selector).Each(func(_ int, s *goquery.Selection) {
+		texts = append(texts, s.Text())
+	})
+	return texts, nil
+}
@@ -0,0 +1,59 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import (
+	"regexp"
+	"testing"
+)
+
+func htmlResult(content string) *ScrapeResult {
+	return &ScrapeResult{Result: ResultData{ContentType: "text/html", Content: content}}
+}
+
+func TestDiff(t *testing.T) {
+	before := htmlResult(`<html><head><title>Box</title></head><body>
+		<nav>Home</nav>
+		<div class="product"><h1>Box</h1><span class="price">$10</span><p>In stock</p><p>Updated 10:00</p></div>
+		<script>var x = 1;</script></body></html>`)
+	after := htmlResult(`<html><body><nav>Home</nav>
+		<div class="product"><h1>Box</h1>
+			<span class="price">$12</span>
+			<p>Updated 11:30</p><p>Free shipping</p>
+		</div><script>var x = 2;</script></body></html>`)
+
+	diff, err := Diff(before, after, &DiffOptions{
+		Scope:     ".product",
+		Selectors: map[string]string{"price": ".price", "title": "h1"},
+		Exclude:   []*regexp.Regexp{regexp.MustCompile(`\d{1,2}:\d{2}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.Added) != 2 || diff.Added[0] != "$12" || diff.Added[1] != "Free shipping" {
+		t.Errorf("Added = %q", diff.Added)
+	}
+	if len(diff.Removed) != 2 || diff.Removed[0] != "$10" || diff.Removed[1] != "In stock" {
+		t.Errorf("Removed = %q", diff.Removed)
+	}
+	if len(diff.Values) != 1 || diff.Value("title") != nil {
+		t.Fatalf("Values = %+v", diff.Values)
+	}
+	if price := diff.Value("price"); price == nil || price.Old != "$10" || price.New != "$12" {
+		t.Errorf("price change = %+v", price)
+	}
+
+	same, err := Diff(before, htmlResult(before.Result.Content+"\n\n"), nil)
+	if err != nil || !same.Empty() {
+		t.Errorf("Diff() of the same page = %+v, %v", same, err)
+	}
+
+	first, err := Diff(nil, after, nil)
+	if err != nil || len(first.Added) != 5 || len(first.Removed) != 0 {
+		t.Errorf("Diff(nil) = %+v, %v", first, err)
+	}
+
+	if _, err := Diff(before, &ScrapeResult{Result: ResultData{ContentType: "application/json"}}, nil); err == nil {
+		t.Error("expected an error for non-HTML content")
+	}
+}
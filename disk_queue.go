@@ -0,0 +1,336 @@
+package scrapfly
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// DiskQueue is a scrape queue persisted to a journal file, so a worker
+// that crashes or restarts resumes where it left off: the configs pushed
+// and not marked done are queued again when the file is reopened. URLs
+// already queued or done are not queued twice, within a run and across
+// restarts.
+//
+// Configs are stored in their encoding/json form. A DiskQueue is safe for
+// concurrent use; a file must be opened by a single process at a time.
+//
+// Example:
+//
+//	queue, err := scrapfly.OpenDiskQueue("state/queue.jsonl")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer queue.Close()
+//	queue.Push(configs...)
+//	for item := range client.ConcurrentScrapeChan(ctx, queue.Configs(ctx), 5) {
+//	    if item.Err != nil {
+//	        queue.Release(item.Config) // scraped again later
+//	        continue
+//	    }
+//	    store(item.Result)
+//	    queue.Done(item.Config)
+//	}
+type DiskQueue struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	// pending are the keys not dispatched yet, in push order.
+	pending []string
+	configs map[string]*ScrapeConfig
+	// inFlight are the keys handed out by Next and not done or released.
+	inFlight map[string]bool
+	done     map[string]bool
+	// changed signals Configs that the queue content changed.
+	changed chan struct{}
+}
+
+// diskQueueRecord is a line of the journal of a DiskQueue.
+type diskQueueRecord struct {
+	Op     string        `json:"op"`
+	Key    string        `json:"key"`
+	Config *ScrapeConfig `json:"config,omitempty"`
+}
+
+// OpenDiskQueue opens the queue journaled at path, creating it and its
+// parent directories when missing. The journal is compacted on open.
+func OpenDiskQueue(path string) (*DiskQueue, error) {
+	q := &DiskQueue{
+		path:     path,
+		configs:  make(map[string]*ScrapeConfig),
+		inFlight: make(map[string]bool),
+		done:     make(map[string]bool),
+		changed:  make(chan struct{}, 1),
+	}
+	if err := q.load(); err != nil {
+		return nil, fmt.Errorf("failed to open queue %s: %w", path, err)
+	}
+	if err := q.compact(); err != nil {
+		return nil, fmt.Errorf("failed to open queue %s: %w", path, err)
+	}
+	return q, nil
+}
+
+// load replays the journal.
+func (q *DiskQueue) load() error {
+	f, err := os.Open(q.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var record diskQueueRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// a crash mid-write leaves a truncated last line
+			continue
+		}
+		switch record.Op {
+		case "push":
+			if record.Config != nil && !q.done[record.Key] && q.configs[record.Key] == nil {
+				q.pending = append(q.pending, record.Key)
+				q.configs[record.Key] = record.Config
+			}
+		case "done":
+			q.done[record.Key] = true
+			delete(q.configs, record.Key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	pending := q.pending[:0]
+	for _, key := range q.pending {
+		if !q.done[key] {
+			pending = append(pending, key)
+		}
+	}
+	q.pending = pending
+	return nil
+}
+
+// compact rewrites the journal with the current state and opens it for
+// appending.
+func (q *DiskQueue) compact() error {
+	if err := os.MkdirAll(filepath.Dir(q.path), 0755); err != nil {
+		return err
+	}
+	tmp := q.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for key := range q.done {
+		if err := enc.Encode(diskQueueRecord{Op: "done", Key: key}); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	for _, key := range q.pending {
+		if err := enc.Encode(diskQueueRecord{Op: "push", Key: key, Config: q.configs[key]}); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		return err
+	}
+	q.file, err = os.OpenFile(q.path, os.O_WRONLY|os.O_APPEND, 0644)
+	return err
+}
+
+// write appends records to the journal and syncs it.
+func (q *DiskQueue) write(records ...diskQueueRecord) error {
+	if q.file == nil {
+		return fmt.Errorf("queue %s is closed", q.path)
+	}
+	var data []byte
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if _, err := q.file.Write(data); err != nil {
+		return err
+	}
+	return q.file.Sync()
+}
+
+// queueKey identifies the configs that scrape the same page.
+func queueKey(config *ScrapeConfig) string {
+	method := strings.ToUpper(string(config.Method))
+	if method == "" {
+		method = "GET"
+	}
+	return method + " " + config.URL
+}
+
+// Push queues the configs whose URL (and method) is neither queued nor
+// done, and returns how many were queued.
+func (q *DiskQueue) Push(configs ...*ScrapeConfig) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var records []diskQueueRecord
+	seen := map[string]bool{}
+	for _, config := range configs {
+		key := queueKey(config)
+		if q.done[key] || q.configs[key] != nil || seen[key] {
+			continue
+		}
+		seen[key] = true
+		records = append(records, diskQueueRecord{Op: "push", Key: key, Config: config})
+	}
+	if len(records) == 0 {
+		return 0, nil
+	}
+	if err := q.write(records...); err != nil {
+		return 0, fmt.Errorf("failed to push to queue %s: %w", q.path, err)
+	}
+	for _, record := range records {
+		q.pending = append(q.pending, record.Key)
+		q.configs[record.Key] = record.Config
+	}
+	q.signal()
+	return len(records), nil
+}
+
+// Next hands out the next queued config, false when none is queued. The
+// config stays in the queue until Done; Release queues it again.
+func (q *DiskQueue) Next() (*ScrapeConfig, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return nil, false
+	}
+	key := q.pending[0]
+	q.pending = q.pending[1:]
+	q.inFlight[key] = true
+	return q.configs[key], true
+}
+
+// Done marks config as completed: it leaves the queue for good and its
+// URL is not queued again.
+func (q *DiskQueue) Done(config *ScrapeConfig) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	key := queueKey(config)
+	if q.done[key] {
+		return nil
+	}
+	if err := q.write(diskQueueRecord{Op: "done", Key: key}); err != nil {
+		return fmt.Errorf("failed to mark %s done in queue %s: %w", config.URL, q.path, err)
+	}
+	q.done[key] = true
+	delete(q.configs, key)
+	delete(q.inFlight, key)
+	q.removePending(key)
+	q.signal()
+	return nil
+}
+
+// Release queues again a config handed out by Next, at the back of the
+// queue, typically after a failed scrape.
+func (q *DiskQueue) Release(config *ScrapeConfig) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	key := queueKey(config)
+	if !q.inFlight[key] {
+		return
+	}
+	delete(q.inFlight, key)
+	q.pending = append(q.pending, key)
+	q.signal()
+}
+
+func (q *DiskQueue) removePending(key string) {
+	for i, pending := range q.pending {
+		if pending == key {
+			q.pending = append(q.pending[:i], q.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+// signal wakes Configs up; q.mu must be held.
+func (q *DiskQueue) signal() {
+	select {
+	case q.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Len returns the number of configs queued or handed out and not done.
+func (q *DiskQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending) + len(q.inFlight)
+}
+
+// Configs streams the queued configs for ConcurrentScrapeChan. The channel
+// is closed once every config is done, or once ctx is done. Released
+// configs are streamed again, so a config that always fails keeps the
+// stream open until it is marked done.
+func (q *DiskQueue) Configs(ctx context.Context) <-chan *ScrapeConfig {
+	configs := make(chan *ScrapeConfig)
+	go func() {
+		defer close(configs)
+		for {
+			config, ok := q.Next()
+			if !ok {
+				if q.Len() == 0 {
+					return
+				}
+				select {
+				case <-q.changed:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			select {
+			case configs <- config:
+			case <-ctx.Done():
+				q.Release(config)
+				return
+			}
+		}
+	}()
+	return configs
+}
+
+// Close closes the journal.
+func (q *DiskQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.file == nil {
+		return nil
+	}
+	err := q.file.Close()
+	q.file = nil
+	return err
+}
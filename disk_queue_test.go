@@ -0,0 +1,88 @@
+package scrapfly
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDiskQueue_ResumesAfterRestart(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "queue.jsonl")
+	queue, err := OpenDiskQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := queue.Push(
+		&ScrapeConfig{URL: "https://example.com/1"},
+		&ScrapeConfig{URL: "https://example.com/2", ASP: true},
+		&ScrapeConfig{URL: "https://example.com/1"},
+		&ScrapeConfig{URL: "https://example.com/3"},
+	)
+	if err != nil || n != 3 {
+		t.Fatalf("Push() = %d, %v; want 3 configs queued", n, err)
+	}
+	first, _ := queue.Next()
+	if err := queue.Done(first); err != nil {
+		t.Fatal(err)
+	}
+	// handed out but never done: queued again after the restart
+	queue.Next()
+	queue.Close()
+
+	// a crash mid-write leaves a truncated line
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	f.WriteString(`{"op":"push","key":"GET https://exa`)
+	f.Close()
+
+	queue, err = OpenDiskQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Close()
+	if queue.Len() != 2 {
+		t.Fatalf("Len() = %d after restart, want 2", queue.Len())
+	}
+	if n, _ := queue.Push(&ScrapeConfig{URL: "https://example.com/1"}); n != 0 {
+		t.Error("a done URL must not be queued again")
+	}
+	next, _ := queue.Next()
+	if next.URL != "https://example.com/2" || !next.ASP {
+		t.Errorf("Next() = %+v, want the second config with its options", next)
+	}
+}
+
+func TestDiskQueue_Configs(t *testing.T) {
+	calls := map[string]int{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		u := r.URL.Query().Get("url")
+		calls[u]++
+		if u == "https://example.com/flaky" && calls[u] == 1 {
+			fmt.Fprint(w, `{"result":{"success":false,"status":"ERR::PROXY::TIMEOUT","status_code":200}}`)
+			return
+		}
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE","status_code":200}}`)
+	})
+	queue, err := OpenDiskQueue(filepath.Join(t.TempDir(), "queue.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer queue.Close()
+	queue.Push(&ScrapeConfig{URL: "https://example.com/ok"}, &ScrapeConfig{URL: "https://example.com/flaky"})
+
+	ctx := context.Background()
+	for item := range client.ConcurrentScrapeChan(ctx, queue.Configs(ctx), 1) {
+		if item.Err != nil {
+			queue.Release(item.Config)
+			continue
+		}
+		if err := queue.Done(item.Config); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if queue.Len() != 0 || calls["https://example.com/flaky"] != 2 {
+		t.Errorf("Len() = %d, calls = %v; want the released config scraped again", queue.Len(), calls)
+	}
+}
@@ -19,6 +19,22 @@
 //
 //	go get github.com/scrapfly/go-scrapfly
 //
+// Optional dependencies can be compiled out for minimal deployments with
+// build tags, e.g. go build -tags scrapfly_nogoquery,scrapfly_nomsgpack:
+//
+//   - scrapfly_nogoquery drops goquery and golang.org/x/net: no
+//     ScrapeResult.Selector, Markdown or Article, no Diff, no charset transcoding, no
+//     microdata in StructuredData, and links and assets are found lexically
+//   - scrapfly_nomsgpack drops msgpack: BatchFormatMsgpack returns an error
+//   - scrapfly_noschema drops the JS scenario JSON schemas (js_scenario.JsScenarioSchema)
+//
+// Features with heavy dependencies live in their own modules under contrib/
+// (contrib/chromedp, contrib/zstd). The result sinks (JSONLSink), the
+// screenshot image tools and DiskQueue use the standard library only and
+// stay in the core. The SDK ships no Redis or Kafka client: stores and
+// queues on them are written by the application against SeenStore and the
+// JSON form of ScrapeResult.
+//
 // # Quick Start
 //
 // Create a client and perform a simple scrape:
@@ -97,12 +113,12 @@
 //	    {URL: "https://example.com/page3"},
 //	}
 //	resultsChan := client.ConcurrentScrape(configs, 3)
-//	for result := range resultsChan {
-//	    if result.error != nil {
-//	        log.Printf("Error: %v", result.error)
+//	for item := range resultsChan {
+//	    if item.Err != nil {
+//	        log.Printf("Error scraping page %d: %v", item.Index, item.Err)
 //	        continue
 //	    }
-//	    // Process result
+//	    // Process item.Result
 //	}
 //
 // AI Data Extraction:
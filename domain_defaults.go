@@ -0,0 +1,65 @@
+package scrapfly
+
+import (
+	"net/url"
+	"strings"
+)
+
+// SetDomainDefaults registers the default options of scrapes to domain and
+// its subdomains; the most specific registered domain applies. Defaults
+// are merged under each ScrapeConfig with ScrapeConfig.Merge, so the
+// non-zero fields of the config take precedence and maps are merged key by
+// key. A bool cannot be reset to false by the config, register narrower
+// defaults instead. A nil defaults unregisters domain.
+//
+// Example:
+//
+//	client.SetDomainDefaults("example.com", &scrapfly.ScrapeConfig{
+//	    ASP:       true,
+//	    ProxyPool: scrapfly.PublicResidentialPool,
+//	})
+//	// ASP and residential proxies are applied automatically
+//	result, err := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://shop.example.com/p/1"})
+func (c *Client) SetDomainDefaults(domain string, defaults *ScrapeConfig) {
+	domain = strings.TrimPrefix(strings.ToLower(domain), ".")
+	if defaults == nil {
+		c.domainDefaults.Delete(domain)
+		return
+	}
+	c.domainDefaults.Store(domain, defaults.Clone())
+}
+
+// DomainDefaults returns a copy of the defaults that apply to rawURL, or
+// nil.
+func (c *Client) DomainDefaults(rawURL string) *ScrapeConfig {
+	return c.lookupDomainDefaults(rawURL).Clone()
+}
+
+func (c *Client) lookupDomainDefaults(rawURL string) *ScrapeConfig {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	for host != "" {
+		if defaults, ok := c.domainDefaults.Load(host); ok {
+			return defaults.(*ScrapeConfig)
+		}
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = parent
+	}
+	return nil
+}
+
+// withDomainDefaults returns config merged over the defaults of its URL,
+// or config itself when no defaults apply.
+func (c *Client) withDomainDefaults(config *ScrapeConfig) *ScrapeConfig {
+	defaults := c.lookupDomainDefaults(config.URL)
+	if defaults == nil {
+		return config
+	}
+	return defaults.Merge(config)
+}
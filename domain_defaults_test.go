@@ -0,0 +1,75 @@
+package scrapfly
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestClient_DomainDefaultsLookup(t *testing.T) {
+	client, _ := New("__API_KEY__")
+	client.SetDomainDefaults("example.com", &ScrapeConfig{ASP: true})
+	client.SetDomainDefaults("api.example.com", &ScrapeConfig{RenderJS: true})
+
+	tests := []struct {
+		url      string
+		wantASP  bool
+		wantJS   bool
+		wantNone bool
+	}{
+		{url: "https://example.com/", wantASP: true},
+		{url: "https://shop.EXAMPLE.com/p/1", wantASP: true},
+		{url: "https://v2.api.example.com/", wantJS: true},
+		{url: "https://other.com/", wantNone: true},
+		{url: "https://notexample.com/", wantNone: true},
+	}
+	for _, tt := range tests {
+		defaults := client.DomainDefaults(tt.url)
+		if tt.wantNone {
+			if defaults != nil {
+				t.Errorf("%s: unexpected defaults %+v", tt.url, defaults)
+			}
+			continue
+		}
+		if defaults == nil || defaults.ASP != tt.wantASP || defaults.RenderJS != tt.wantJS {
+			t.Errorf("%s: got %+v", tt.url, defaults)
+		}
+	}
+
+	client.SetDomainDefaults("api.example.com", nil)
+	if defaults := client.DomainDefaults("https://api.example.com/"); defaults == nil || !defaults.ASP {
+		t.Errorf("unregistered domain must fall back to its parent, got %+v", defaults)
+	}
+}
+
+func TestClient_ScrapeAppliesDomainDefaults(t *testing.T) {
+	var query map[string][]string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result":{"success":true,"status":"DONE","status_code":200}}`))
+	})
+	client.SetDomainDefaults("example.com", &ScrapeConfig{
+		ASP:       true,
+		ProxyPool: PublicResidentialPool,
+		Headers:   map[string]string{"x-team": "default", "x-site": "example"},
+	})
+
+	config := &ScrapeConfig{URL: "https://example.com/p/1", ProxyPool: PublicDataCenterPool, Headers: map[string]string{"x-team": "mine"}}
+	if _, err := client.Scrape(config); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"asp":             "true",
+		"proxy_pool":      string(PublicDataCenterPool),
+		"headers[x-team]": "mine",
+		"headers[x-site]": "example",
+	}
+	for k, v := range want {
+		if got := query[k]; len(got) != 1 || got[0] != v {
+			t.Errorf("%s = %v, want %q", k, got, v)
+		}
+	}
+	if config.ASP || len(config.Headers) != 1 {
+		t.Error("the caller's config must not be mutated")
+	}
+}
@@ -213,6 +213,236 @@ func (f FormatOption) IsValid() bool {
 	return IsValidEnumType(f)
 }
 
+// RenderingStage defines when the headless browser considers the page loaded.
+type RenderingStage string
+
+// Available rendering stages for JavaScript rendered scrapes.
+const (
+	// RenderingStageComplete waits for the load event (all resources fetched). This is the API default.
+	RenderingStageComplete RenderingStage = "complete"
+	// RenderingStageDOMContentLoaded returns as soon as the DOM is parsed, without waiting for sub-resources.
+	RenderingStageDOMContentLoaded RenderingStage = "domcontentloaded"
+)
+
+func (f RenderingStage) Enum() []RenderingStage {
+	return []RenderingStage{RenderingStageComplete, RenderingStageDOMContentLoaded}
+}
+
+func (f RenderingStage) AnyEnum() []any {
+	return []any{RenderingStageComplete, RenderingStageDOMContentLoaded}
+}
+
+func (f RenderingStage) String() string {
+	if slices.Contains(f.Enum(), f) {
+		return string(f)
+	}
+	return "invalid_rendering_stage"
+}
+
+func (f RenderingStage) IsValid() bool {
+	return IsValidEnumType(f)
+}
+
+// OperatingSystem defines the operating system to emulate in the User-Agent and browser fingerprint.
+type OperatingSystem string
+
+// Available operating systems for the os parameter.
+const (
+	// OSWindows emulates a Windows desktop (random version).
+	OSWindows OperatingSystem = "win"
+	// OSWindows10 emulates Windows 10.
+	OSWindows10 OperatingSystem = "win10"
+	// OSWindows11 emulates Windows 11.
+	OSWindows11 OperatingSystem = "win11"
+	// OSMacOS emulates macOS.
+	OSMacOS OperatingSystem = "mac"
+	// OSLinux emulates a Linux desktop.
+	OSLinux OperatingSystem = "linux"
+	// OSChromeOS emulates ChromeOS.
+	OSChromeOS OperatingSystem = "chromeos"
+)
+
+func (f OperatingSystem) Enum() []OperatingSystem {
+	return []OperatingSystem{OSWindows, OSWindows10, OSWindows11, OSMacOS, OSLinux, OSChromeOS}
+}
+
+func (f OperatingSystem) AnyEnum() []any {
+	return []any{OSWindows, OSWindows10, OSWindows11, OSMacOS, OSLinux, OSChromeOS}
+}
+
+func (f OperatingSystem) String() string {
+	if slices.Contains(f.Enum(), f) {
+		return string(f)
+	}
+	return "invalid_operating_system"
+}
+
+func (f OperatingSystem) IsValid() bool {
+	return IsValidEnumType(f)
+}
+
+// BrowserBrandName is a Chromium-based browser the API can generate a
+// fingerprint for (the browser_brand parameter).
+type BrowserBrandName string
+
+// Available browser brands for the browser_brand parameter.
+const (
+	BrowserChrome BrowserBrandName = "chrome"
+	BrowserEdge   BrowserBrandName = "edge"
+	BrowserBrave  BrowserBrandName = "brave"
+	BrowserOpera  BrowserBrandName = "opera"
+)
+
+func (f BrowserBrandName) Enum() []BrowserBrandName {
+	return []BrowserBrandName{BrowserChrome, BrowserEdge, BrowserBrave, BrowserOpera}
+}
+
+func (f BrowserBrandName) AnyEnum() []any {
+	return []any{BrowserChrome, BrowserEdge, BrowserBrave, BrowserOpera}
+}
+
+func (f BrowserBrandName) String() string {
+	if slices.Contains(f.Enum(), f) {
+		return string(f)
+	}
+	return "invalid_browser_brand"
+}
+
+func (f BrowserBrandName) IsValid() bool {
+	return IsValidEnumType(f)
+}
+
+// ASPStage is a step of the anti-scraping protection escalation run by
+// Client.ScrapeEscalating, from the cheapest to the most expensive.
+type ASPStage string
+
+// Available ASP escalation stages.
+const (
+	// ASPStageNone scrapes without ASP.
+	ASPStageNone ASPStage = "none"
+	// ASPStageASP enables ASP.
+	ASPStageASP ASPStage = "asp"
+	// ASPStageBrowser enables ASP and browser rendering.
+	ASPStageBrowser ASPStage = "browser"
+	// ASPStageResidential enables ASP and browser rendering through the
+	// residential proxy pool.
+	ASPStageResidential ASPStage = "residential"
+)
+
+func (f ASPStage) Enum() []ASPStage {
+	return []ASPStage{ASPStageNone, ASPStageASP, ASPStageBrowser, ASPStageResidential}
+}
+
+func (f ASPStage) AnyEnum() []any {
+	return []any{ASPStageNone, ASPStageASP, ASPStageBrowser, ASPStageResidential}
+}
+
+func (f ASPStage) String() string {
+	if slices.Contains(f.Enum(), f) {
+		return string(f)
+	}
+	return "invalid_asp_stage"
+}
+
+func (f ASPStage) IsValid() bool {
+	return IsValidEnumType(f)
+}
+
+// ResourceType is a class of page resources a Cloud Browser session can
+// block from loading.
+type ResourceType string
+
+// Available resource types.
+const (
+	ResourceImages ResourceType = "images"
+	ResourceStyles ResourceType = "styles"
+	ResourceFonts  ResourceType = "fonts"
+	ResourceMedia  ResourceType = "media"
+)
+
+func (f ResourceType) Enum() []ResourceType {
+	return []ResourceType{ResourceImages, ResourceStyles, ResourceFonts, ResourceMedia}
+}
+
+func (f ResourceType) AnyEnum() []any {
+	return []any{ResourceImages, ResourceStyles, ResourceFonts, ResourceMedia}
+}
+
+func (f ResourceType) String() string {
+	if slices.Contains(f.Enum(), f) {
+		return string(f)
+	}
+	return "invalid_resource_type"
+}
+
+func (f ResourceType) IsValid() bool {
+	return IsValidEnumType(f)
+}
+
+// AssetKind is a class of page assets found by ScrapeResult.Assets.
+type AssetKind string
+
+// Available asset kinds.
+const (
+	// AssetImage are <img src> images.
+	AssetImage AssetKind = "image"
+	// AssetScript are <script src> scripts.
+	AssetScript AssetKind = "script"
+	// AssetStylesheet are <link rel="stylesheet"> stylesheets.
+	AssetStylesheet AssetKind = "stylesheet"
+	// AssetFile are <a href> links to files, such as PDFs or archives:
+	// links whose path has an extension other than a web page one.
+	AssetFile AssetKind = "file"
+)
+
+func (f AssetKind) Enum() []AssetKind {
+	return []AssetKind{AssetImage, AssetScript, AssetStylesheet, AssetFile}
+}
+
+func (f AssetKind) AnyEnum() []any {
+	return []any{AssetImage, AssetScript, AssetStylesheet, AssetFile}
+}
+
+func (f AssetKind) String() string {
+	if slices.Contains(f.Enum(), f) {
+		return string(f)
+	}
+	return "invalid_asset_kind"
+}
+
+func (f AssetKind) IsValid() bool {
+	return IsValidEnumType(f)
+}
+
+// DeviceType is the class of device a DeviceProfile describes.
+type DeviceType string
+
+// Available device types.
+const (
+	DeviceTypeDesktop DeviceType = "desktop"
+	DeviceTypeMobile  DeviceType = "mobile"
+	DeviceTypeTablet  DeviceType = "tablet"
+)
+
+func (f DeviceType) Enum() []DeviceType {
+	return []DeviceType{DeviceTypeDesktop, DeviceTypeMobile, DeviceTypeTablet}
+}
+
+func (f DeviceType) AnyEnum() []any {
+	return []any{DeviceTypeDesktop, DeviceTypeMobile, DeviceTypeTablet}
+}
+
+func (f DeviceType) String() string {
+	if slices.Contains(f.Enum(), f) {
+		return string(f)
+	}
+	return "invalid_device_type"
+}
+
+func (f DeviceType) IsValid() bool {
+	return IsValidEnumType(f)
+}
+
 type HttpMethod string
 
 const (
@@ -245,6 +475,19 @@ func (f HttpMethod) IsValid() bool {
 	return IsValidEnumType(f)
 }
 
+// normalize upper-cases the method, defaulting to GET.
+func (f HttpMethod) normalize() HttpMethod {
+	if f == "" {
+		return HttpMethodGet
+	}
+	return HttpMethod(strings.ToUpper(string(f)))
+}
+
+// allowsBody reports whether requests with the method may carry a body.
+func (f HttpMethod) allowsBody() bool {
+	return f == HttpMethodPost || f == HttpMethodPut || f == HttpMethodPatch
+}
+
 type Enumerable[T fmt.Stringer] interface {
 	Enum() []T
 	AnyEnum() []any
@@ -56,12 +56,23 @@ var (
 	// ErrASPBypassFailed indicates Anti-Scraping Protection bypass failed.
 	ErrASPBypassFailed = errors.New("ASP bypass error")
 
+	// ErrCostBudgetExceeded indicates the scrape stopped because it would
+	// exceed its cost budget (ScrapeConfig.CostBudget or ASPEscalation).
+	ErrCostBudgetExceeded = errors.New("cost budget exceeded")
+
 	// ErrScheduleFailed indicates a scheduled job error.
 	ErrScheduleFailed = errors.New("schedule error")
 
 	// ErrWebhookFailed indicates a webhook delivery error.
 	ErrWebhookFailed = errors.New("webhook error")
 
+	// ErrScrapeQueued indicates the scrape was accepted asynchronously and its
+	// result will be delivered to a webhook (see Client.ScrapeWebhook).
+	ErrScrapeQueued = errors.New("scrape queued for webhook delivery")
+
+	// ErrCloudBrowserConfig indicates invalid Cloud Browser configuration.
+	ErrCloudBrowserConfig = errors.New("invalid cloud browser config")
+
 	// ErrSessionFailed indicates a browser session error.
 	ErrSessionFailed = errors.New("session error")
 
@@ -86,6 +97,14 @@ var (
 	// ErrCrawlerCancelled indicates Crawl.Wait() observed a CANCELLED terminal state.
 	ErrCrawlerCancelled = errors.New("crawler was cancelled")
 
+	// ErrPoolClosed indicates a config was submitted to a Pool after it
+	// was shut down.
+	ErrPoolClosed = errors.New("pool is shut down")
+
+	// ErrRobotsDisallowed indicates a URL was not scraped because the
+	// robots.txt of its host disallows it (see Robots).
+	ErrRobotsDisallowed = errors.New("disallowed by robots.txt")
+
 	// ErrUnexpectedResponseFormat indicates the server returned a Content-Type the SDK didn't expect.
 	// Used for example when GET /crawl/{uuid}/urls returns JSON instead of streaming text.
 	ErrUnexpectedResponseFormat = errors.New("unexpected response format")
@@ -112,6 +131,9 @@ type APIError struct {
 	RetryAfterMs int
 	// Hint provides additional context or suggestions for resolving the error.
 	Hint string
+	// LogURL is the dashboard link to the scrape log for errors without
+	// APIResponse, such as proxified scrape errors; see MonitoringURL.
+	LogURL string
 }
 
 // Error implements the error interface.
@@ -0,0 +1,205 @@
+package scrapfly
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"unicode/utf8"
+)
+
+// DefaultExtractionChunkSize is the chunk size ExtractChunked uses when
+// ChunkedExtractionOptions.ChunkSize is not set.
+const DefaultExtractionChunkSize = 256 << 10 // 256 KiB
+
+// ChunkedExtractionOptions configures ExtractChunked.
+type ChunkedExtractionOptions struct {
+	// ChunkSize is the maximum chunk size in bytes. Defaults to
+	// DefaultExtractionChunkSize.
+	ChunkSize int
+	// Overlap is how many bytes consecutive chunks share, so a record cut by
+	// a chunk boundary is whole in at least one chunk. Defaults to a tenth
+	// of ChunkSize; must be smaller than half of it.
+	Overlap int
+	// Concurrency is the number of chunks extracted in parallel. Defaults to 1.
+	Concurrency int
+	// Key identifies a record for deduplication; records with the same key
+	// are merged into the first one. Defaults to the record JSON encoding,
+	// which only removes exact duplicates (typically the overlap).
+	Key func(record interface{}) string
+}
+
+// ChunkedExtractionResult is the merged result of ExtractChunked.
+type ChunkedExtractionResult struct {
+	// Records are the deduplicated records of every chunk, in document order.
+	Records []interface{}
+	// Chunks is the number of chunks the document was split into.
+	Chunks int
+	// Results are the raw per-chunk results; nil for failed chunks.
+	Results []*ExtractionResult
+}
+
+// Decode unmarshals the merged records into v, typically a pointer to a
+// slice of structs.
+func (r *ChunkedExtractionResult) Decode(v interface{}) error {
+	_, err := remarshal(r.Records, v)
+	return err
+}
+
+// ChunkError is the error of one chunk of ExtractChunked.
+type ChunkError struct {
+	Chunk int
+	Err   error
+}
+
+func (e *ChunkError) Error() string { return fmt.Sprintf("chunk %d: %v", e.Chunk, e.Err) }
+
+func (e *ChunkError) Unwrap() error { return e.Err }
+
+// ExtractChunked extracts records from documents too large for a single
+// Extraction API call: the body is split into overlapping chunks cut at
+// line or tag boundaries, each chunk is extracted with config, and the
+// records are merged and deduplicated.
+//
+// Each chunk result contributes its records: the elements of an array, the
+// elements of the single array field of an object ({"products": [...]}),
+// or the object itself. Extraction is billed per chunk.
+//
+// When some chunks fail, the records of the others are returned along with
+// the joined *ChunkError values.
+//
+// Example:
+//
+//	result, err := client.ExtractChunked(&scrapfly.ExtractionConfig{
+//	    Body:             catalog,
+//	    ContentType:      "text/html",
+//	    ExtractionPrompt: "extract every product as {name, price, sku}",
+//	}, scrapfly.ChunkedExtractionOptions{
+//	    Concurrency: 4,
+//	    Key:         func(r interface{}) string { return fmt.Sprint(r.(map[string]interface{})["sku"]) },
+//	})
+func (c *Client) ExtractChunked(config *ExtractionConfig, opts ChunkedExtractionOptions) (*ChunkedExtractionResult, error) {
+	if config.IsDocumentCompressed {
+		return nil, fmt.Errorf("%w: compressed documents cannot be chunked", ErrExtractionConfig)
+	}
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultExtractionChunkSize
+	}
+	if opts.Overlap == 0 {
+		opts.Overlap = opts.ChunkSize / 10
+	}
+	if opts.Overlap < 0 || opts.Overlap*2 >= opts.ChunkSize {
+		return nil, fmt.Errorf("%w: chunk overlap must be between 0 and half the chunk size", ErrExtractionConfig)
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.Key == nil {
+		opts.Key = defaultRecordKey
+	}
+
+	chunks := splitDocument(config.Body, opts.ChunkSize, opts.Overlap)
+	result := &ChunkedExtractionResult{Chunks: len(chunks), Results: make([]*ExtractionResult, len(chunks))}
+	errs := make([]error, len(chunks))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Concurrency)
+	for i, chunk := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, chunk []byte) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			chunkConfig := *config
+			chunkConfig.Body = chunk
+			res, err := c.Extract(&chunkConfig)
+			if err != nil {
+				errs[i] = &ChunkError{Chunk: i, Err: err}
+				return
+			}
+			result.Results[i] = res
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for _, res := range result.Results {
+		if res == nil {
+			continue
+		}
+		for _, record := range chunkRecords(res.Data) {
+			key := opts.Key(record)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			result.Records = append(result.Records, record)
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// splitDocument cuts body into chunks of at most size bytes, each starting
+// within overlap bytes of the end of the previous one. Chunk ends are moved
+// back to the last newline or '>' in the second half of the chunk, starts
+// forward to the first boundary of the overlap, and neither splits a UTF-8
+// sequence.
+func splitDocument(body []byte, size, overlap int) [][]byte {
+	var chunks [][]byte
+	for start := 0; start < len(body); {
+		end := start + size
+		if end >= len(body) {
+			chunks = append(chunks, body[start:])
+			break
+		}
+		window := body[start:end]
+		if cut := bytes.LastIndexAny(window[size/2:], "\n>"); cut >= 0 {
+			end = start + size/2 + cut + 1
+		}
+		for end > start && !utf8.RuneStart(body[end]) {
+			end--
+		}
+		chunks = append(chunks, body[start:end])
+		next := end - overlap
+		for next > start && !utf8.RuneStart(body[next]) {
+			next--
+		}
+		// Start the next chunk on a line, or failing that a tag, boundary.
+		if i := bytes.IndexByte(body[next:end], '\n'); i >= 0 && next+i+1 < end {
+			next += i + 1
+		} else if i := bytes.IndexByte(body[next:end], '>'); i >= 0 && next+i+1 < end {
+			next += i + 1
+		}
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+	return chunks
+}
+
+// chunkRecords returns the records of one chunk's extracted data.
+func chunkRecords(data interface{}) []interface{} {
+	switch v := data.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		if len(v) == 1 {
+			for _, field := range v {
+				if records, ok := field.([]interface{}); ok {
+					return records
+				}
+			}
+		}
+	}
+	return []interface{}{data}
+}
+
+func defaultRecordKey(record interface{}) string {
+	// encoding/json sorts map keys, so equal records encode identically.
+	key, _ := json.Marshal(record)
+	return string(key)
+}
@@ -0,0 +1,107 @@
+package scrapfly
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSplitDocument_OverlapsAndCoversBody(t *testing.T) {
+	var doc strings.Builder
+	for i := 0; i < 200; i++ {
+		doc.WriteString("<li>product é ")
+		doc.WriteString(strings.Repeat("x", i%7))
+		doc.WriteString("</li>\n")
+	}
+	body := []byte(doc.String())
+	chunks := splitDocument(body, 512, 64)
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	if !bytes.HasPrefix(body, chunks[0]) || !bytes.HasSuffix(body, chunks[len(chunks)-1]) {
+		t.Error("chunks do not cover the document ends")
+	}
+	for i, chunk := range chunks {
+		if len(chunk) > 512 {
+			t.Errorf("chunk %d is %d bytes", i, len(chunk))
+		}
+		if !bytes.Contains(body, chunk) {
+			t.Errorf("chunk %d is not a slice of the document", i)
+		}
+		if i > 0 && !bytes.HasPrefix(chunk, []byte("<li>")) && !bytes.HasPrefix(chunk, []byte("\n")) {
+			t.Errorf("chunk %d is not cut at a boundary: %q", i, chunk[:10])
+		}
+	}
+}
+
+func TestClient_ExtractChunked_MergesAndDedups(t *testing.T) {
+	itemRegex := regexp.MustCompile(`<li>(\w+)</li>`)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var products []map[string]string
+		for _, m := range itemRegex.FindAllSubmatch(body, -1) {
+			products = append(products, map[string]string{"name": string(m[1])})
+		}
+		data, _ := json.Marshal(map[string]interface{}{"data": map[string]interface{}{"products": products}})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+	})
+
+	var doc strings.Builder
+	for i := 0; i < 100; i++ {
+		doc.WriteString("<li>item" + strings.Repeat("a", i%5) + string(rune('a'+i%26)) + "</li>\n")
+	}
+	result, err := client.ExtractChunked(&ExtractionConfig{
+		Body:             []byte(doc.String()),
+		ContentType:      "text/html",
+		ExtractionPrompt: "products",
+	}, ChunkedExtractionOptions{ChunkSize: 400, Concurrency: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Chunks < 2 {
+		t.Fatalf("expected several chunks, got %d", result.Chunks)
+	}
+	unique := map[string]bool{}
+	for _, m := range itemRegex.FindAllStringSubmatch(doc.String(), -1) {
+		unique[m[1]] = true
+	}
+	var products []struct{ Name string }
+	if err := result.Decode(&products); err != nil {
+		t.Fatal(err)
+	}
+	if len(products) != len(unique) {
+		t.Errorf("got %d records, want %d unique", len(products), len(unique))
+	}
+}
+
+func TestClient_ExtractChunked_ReportsChunkErrors(t *testing.T) {
+	calls := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":"ERR::EXTRACTION::INVALID","message":"bad chunk"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[{"n":1}]}`))
+	})
+	result, err := client.ExtractChunked(&ExtractionConfig{
+		Body:             []byte(strings.Repeat("line\n", 300)),
+		ContentType:      "text/plain",
+		ExtractionPrompt: "lines",
+	}, ChunkedExtractionOptions{ChunkSize: 500})
+	var chunkErr *ChunkError
+	if !errors.As(err, &chunkErr) || chunkErr.Chunk != 1 {
+		t.Fatalf("expected chunk 1 error, got %v", err)
+	}
+	if len(result.Records) != 1 || result.Results[1] != nil {
+		t.Errorf("unexpected partial result: %+v", result)
+	}
+}
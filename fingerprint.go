@@ -0,0 +1,73 @@
+package scrapfly
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+)
+
+// FingerprintOptions normalizes the content hashed by
+// ScrapeResult.Fingerprint. Whitespace is always collapsed, and dropped
+// between tags.
+type FingerprintOptions struct {
+	// TextOnly hashes the text of HTML content only: tags, comments,
+	// scripts and styles are dropped, so markup changes such as rotated
+	// class names or inline nonces don't change the fingerprint.
+	TextOnly bool
+	// IgnoreTimestamps drops dates, times and Unix timestamps, such as
+	// "generated at" footers or cache-busting query strings.
+	IgnoreTimestamps bool
+	// Exclude drops the matches of each pattern, for page-specific noise
+	// such as CSRF tokens or session IDs.
+	Exclude []*regexp.Regexp
+}
+
+var (
+	fingerprintBlockRegex = regexp.MustCompile(`(?is)<(script|style|noscript|template)\b.*?</(?:script|style|noscript|template)\s*>|<!--.*?-->`)
+	fingerprintTagRegex   = regexp.MustCompile(`(?s)<[^>]*>`)
+	// fingerprintTimeRegex matches ISO and common dates, clock times and
+	// 10 or 13 digit Unix timestamps.
+	fingerprintTimeRegex = regexp.MustCompile(`(?i)\b\d{4}-\d{2}-\d{2}(?:[T ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:Z|[+-]\d{2}:?\d{2})?)?\b|\b\d{1,2}[/.]\d{1,2}[/.]\d{2,4}\b|\b\d{1,2}:\d{2}(?::\d{2})?(?:\s?[ap]m)?\b|\b\d{10}(?:\d{3})?\b`)
+)
+
+// Fingerprint returns a hex SHA-256 hash of the normalized content, for
+// deduplicating pages: two results with the same fingerprint have the same
+// content under opts (nil only collapses whitespace). Binary content is
+// hashed as is.
+//
+// Example — skip storing duplicate pages:
+//
+//	seen := map[string]bool{}
+//	opts := &scrapfly.FingerprintOptions{TextOnly: true, IgnoreTimestamps: true}
+//	if fp := result.Fingerprint(opts); !seen[fp] {
+//	    seen[fp] = true
+//	    store(result)
+//	}
+func (r *ScrapeResult) Fingerprint(opts *FingerprintOptions) string {
+	if r.rawContent != nil || r.Result.Format == "binary" {
+		data, err := r.Bytes()
+		if err == nil {
+			sum := sha256.Sum256(data)
+			return hex.EncodeToString(sum[:])
+		}
+	}
+	if opts == nil {
+		opts = &FingerprintOptions{}
+	}
+	content := r.Result.Content
+	if opts.TextOnly && strings.Contains(strings.ToLower(r.Result.ContentType), "html") {
+		content = fingerprintBlockRegex.ReplaceAllString(content, " ")
+		content = fingerprintTagRegex.ReplaceAllString(content, " ")
+	}
+	for _, pattern := range opts.Exclude {
+		content = pattern.ReplaceAllString(content, " ")
+	}
+	if opts.IgnoreTimestamps {
+		content = fingerprintTimeRegex.ReplaceAllString(content, " ")
+	}
+	// whitespace between tags is not significant either
+	content = strings.NewReplacer("> ", ">", " <", "<").Replace(strings.Join(strings.Fields(content), " "))
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
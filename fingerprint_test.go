@@ -0,0 +1,43 @@
+package scrapfly
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestScrapeResult_Fingerprint(t *testing.T) {
+	page := func(content string) *ScrapeResult {
+		return &ScrapeResult{Result: ResultData{ContentType: "text/html", Content: content}}
+	}
+	base := page("<html><body>\n  <p>Hello   world</p>\n</body></html>")
+
+	if base.Fingerprint(nil) != page("<html><body><p>Hello world</p></body></html>").Fingerprint(nil) {
+		t.Error("whitespace changes should not change the fingerprint")
+	}
+	if base.Fingerprint(nil) == page("<html><body><p>Hello there</p></body></html>").Fingerprint(nil) {
+		t.Error("content changes should change the fingerprint")
+	}
+
+	textOnly := &FingerprintOptions{TextOnly: true}
+	restyled := page(`<html><body><p class="x1">Hello world</p><script nonce="abc">track()</script></body></html>`)
+	if base.Fingerprint(textOnly) != restyled.Fingerprint(textOnly) {
+		t.Error("TextOnly should ignore markup and scripts")
+	}
+
+	timestamps := &FingerprintOptions{IgnoreTimestamps: true}
+	a := page("<p>Hello</p><footer>Generated 2024-03-01T10:00:00Z, 12:30 pm, cb=1709287200</footer>")
+	b := page("<p>Hello</p><footer>Generated 2024-03-02T11:15:42Z, 9:05 am, cb=1709373600</footer>")
+	if a.Fingerprint(timestamps) != b.Fingerprint(timestamps) || a.Fingerprint(nil) == b.Fingerprint(nil) {
+		t.Error("IgnoreTimestamps should ignore dates and times only when set")
+	}
+
+	csrf := &FingerprintOptions{Exclude: []*regexp.Regexp{regexp.MustCompile(`csrf=\w+`)}}
+	if page("<p>csrf=abc</p>").Fingerprint(csrf) != page("<p>csrf=xyz</p>").Fingerprint(csrf) {
+		t.Error("Exclude patterns should be dropped")
+	}
+
+	blob := &ScrapeResult{rawContent: []byte{0x00, 0x01}}
+	if len(blob.Fingerprint(nil)) != 64 {
+		t.Errorf("unexpected binary fingerprint %q", blob.Fingerprint(nil))
+	}
+}
@@ -0,0 +1,140 @@
+package scrapfly
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// GeolocationTarget spoofs the browser geolocation at a US state or city
+// center. It does not select proxies in that region: the API selects them
+// by country only, so a GeolocationTarget resolves to the country parameter
+// plus the geolocation. Sites that localize from the navigator geolocation
+// (store pickers, local pricing) see the target location, sites that
+// localize from the IP see anywhere in the country.
+//
+// State and city targeting require RenderJS, since only a rendered page
+// has a navigator geolocation. Only US states are supported.
+//
+// Example:
+//
+//	config := &scrapfly.ScrapeConfig{
+//	    URL:               "https://web-scraping.dev/product/1",
+//	    RenderJS:          true,
+//	    GeolocationTarget: &scrapfly.GeolocationTarget{State: "TX", City: "Austin"},
+//	}
+type GeolocationTarget struct {
+	// Country is the ISO 3166-1 alpha-2 code; defaults to "us" when State is set.
+	Country string
+	// State is a two-letter USPS state code such as "CA" or "NY" (DC included).
+	State string
+	// City narrows the geolocation to a major city of State; see GeoCities.
+	City string
+}
+
+type geoPoint struct{ lat, lon float64 }
+
+// usStateCenters are the geographic centers of the US states.
+var usStateCenters = map[string]geoPoint{
+	"AL": {32.806671, -86.791130}, "AK": {61.370716, -152.404419}, "AZ": {33.729759, -111.431221},
+	"AR": {34.969704, -92.373123}, "CA": {36.116203, -119.681564}, "CO": {39.059811, -105.311104},
+	"CT": {41.597782, -72.755371}, "DE": {39.318523, -75.507141}, "DC": {38.897438, -77.026817},
+	"FL": {27.766279, -81.686783}, "GA": {33.040619, -83.643074}, "HI": {21.094318, -157.498337},
+	"ID": {44.240459, -114.478828}, "IL": {40.349457, -88.986137}, "IN": {39.849426, -86.258278},
+	"IA": {42.011539, -93.210526}, "KS": {38.526600, -96.726486}, "KY": {37.668140, -84.670067},
+	"LA": {31.169546, -91.867805}, "ME": {44.693947, -69.381927}, "MD": {39.063946, -76.802101},
+	"MA": {42.230171, -71.530106}, "MI": {43.326618, -84.536095}, "MN": {45.694454, -93.900192},
+	"MS": {32.741646, -89.678696}, "MO": {38.456085, -92.288368}, "MT": {46.921925, -110.454353},
+	"NE": {41.125370, -98.268082}, "NV": {38.313515, -117.055374}, "NH": {43.452492, -71.563896},
+	"NJ": {40.298904, -74.521011}, "NM": {34.840515, -106.248482}, "NY": {42.165726, -74.948051},
+	"NC": {35.630066, -79.806419}, "ND": {47.528912, -99.784012}, "OH": {40.388783, -82.764915},
+	"OK": {35.565342, -96.928917}, "OR": {44.572021, -122.070938}, "PA": {40.590752, -77.209755},
+	"RI": {41.680893, -71.511780}, "SC": {33.856892, -80.945007}, "SD": {44.299782, -99.438828},
+	"TN": {35.747845, -86.692345}, "TX": {31.054487, -97.563461}, "UT": {40.150032, -111.862434},
+	"VT": {44.045876, -72.710686}, "VA": {37.769337, -78.169968}, "WA": {47.400902, -121.490494},
+	"WV": {38.491226, -80.954453}, "WI": {44.268543, -89.616508}, "WY": {42.755966, -107.302490},
+}
+
+// usCities are the centers of major US cities, keyed by state then lower-cased name.
+var usCities = map[string]map[string]geoPoint{
+	"AZ": {"phoenix": {33.448376, -112.074036}},
+	"CA": {"los angeles": {34.052235, -118.243683}, "san diego": {32.715736, -117.161087}, "san francisco": {37.774929, -122.419418}, "san jose": {37.338207, -121.886330}},
+	"CO": {"denver": {39.739235, -104.990250}},
+	"DC": {"washington": {38.907192, -77.036873}},
+	"FL": {"jacksonville": {30.332184, -81.655647}, "miami": {25.761681, -80.191788}},
+	"GA": {"atlanta": {33.748997, -84.387985}},
+	"IL": {"chicago": {41.878113, -87.629799}},
+	"MA": {"boston": {42.360081, -71.058884}},
+	"MI": {"detroit": {42.331429, -83.045753}},
+	"NV": {"las vegas": {36.169941, -115.139832}},
+	"NY": {"new york": {40.712776, -74.005974}},
+	"NC": {"charlotte": {35.227085, -80.843124}},
+	"OH": {"columbus": {39.961178, -82.998795}},
+	"OR": {"portland": {45.515232, -122.678385}},
+	"PA": {"philadelphia": {39.952583, -75.165222}},
+	"TN": {"nashville": {36.162663, -86.781601}},
+	"TX": {"austin": {30.267153, -97.743057}, "dallas": {32.776665, -96.796989}, "houston": {29.760427, -95.369804}, "san antonio": {29.424122, -98.493629}},
+	"WA": {"seattle": {47.606209, -122.332069}},
+}
+
+// GeoCities returns the cities of state GeolocationTarget.City accepts, sorted.
+func GeoCities(state string) []string {
+	cities := make([]string, 0, len(usCities[strings.ToUpper(state)]))
+	for city := range usCities[strings.ToUpper(state)] {
+		cities = append(cities, city)
+	}
+	sort.Strings(cities)
+	return cities
+}
+
+// resolve validates the target and returns its country code and the
+// geolocation ("" for a country-only target).
+func (g *GeolocationTarget) resolve() (country string, geolocation string, err error) {
+	country = strings.ToLower(g.Country)
+	state := strings.ToUpper(g.State)
+	if state == "" {
+		if g.City != "" {
+			return "", "", fmt.Errorf("%w: GeolocationTarget.City requires State", ErrScrapeConfig)
+		}
+		if country == "" || !countryRegex.MatchString(country) {
+			return "", "", fmt.Errorf("%w: invalid GeolocationTarget country code (ISO 3166-1 alpha-2): %q", ErrScrapeConfig, g.Country)
+		}
+		return country, "", nil
+	}
+	if country == "" {
+		country = "us"
+	}
+	if country != "us" {
+		return "", "", fmt.Errorf("%w: GeolocationTarget.State is only supported for the us country, got %q", ErrScrapeConfig, g.Country)
+	}
+	point, ok := usStateCenters[state]
+	if !ok {
+		return "", "", fmt.Errorf("%w: invalid GeolocationTarget US state code %q", ErrScrapeConfig, g.State)
+	}
+	if g.City != "" {
+		point, ok = usCities[state][strings.ToLower(strings.TrimSpace(g.City))]
+		if !ok {
+			return "", "", fmt.Errorf("%w: unknown GeolocationTarget city %q in %s, set Geolocation coordinates instead", ErrScrapeConfig, g.City, state)
+		}
+	}
+	return country, formatGeolocation(point), nil
+}
+
+func formatGeolocation(p geoPoint) string {
+	return strconv.FormatFloat(p.lat, 'f', -1, 64) + "," + strconv.FormatFloat(p.lon, 'f', -1, 64)
+}
+
+// validateGeolocation checks a "latitude,longitude" pair.
+func validateGeolocation(value string) error {
+	lat, lon, ok := strings.Cut(value, ",")
+	if !ok {
+		return fmt.Errorf("%w: invalid geolocation %q, expected \"latitude,longitude\"", ErrScrapeConfig, value)
+	}
+	latitude, err1 := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	longitude, err2 := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err1 != nil || err2 != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return fmt.Errorf("%w: invalid geolocation %q, expected \"latitude,longitude\"", ErrScrapeConfig, value)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package scrapfly
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScrapeConfig_GeolocationTargetParams(t *testing.T) {
+	tests := []struct {
+		target      GeolocationTarget
+		country     string
+		geolocation string
+	}{
+		{GeolocationTarget{Country: "FR"}, "fr", ""},
+		{GeolocationTarget{State: "tx"}, "us", "31.054487,-97.563461"},
+		{GeolocationTarget{Country: "us", State: "TX", City: "Austin"}, "us", "30.267153,-97.743057"},
+	}
+	for _, tt := range tests {
+		cfg := &ScrapeConfig{URL: "https://example.com", RenderJS: true, GeolocationTarget: &tt.target}
+		params, err := cfg.toAPIParamsWithValidation()
+		if err != nil {
+			t.Fatalf("%+v: %v", tt.target, err)
+		}
+		if got := params.Get("country"); got != tt.country {
+			t.Errorf("%+v: country = %q, want %q", tt.target, got, tt.country)
+		}
+		if got := params.Get("geolocation"); got != tt.geolocation {
+			t.Errorf("%+v: geolocation = %q, want %q", tt.target, got, tt.geolocation)
+		}
+	}
+}
+
+func TestScrapeConfig_GeolocationTargetValidation(t *testing.T) {
+	tests := map[string]*ScrapeConfig{
+		"unknown state":      {GeolocationTarget: &GeolocationTarget{State: "XX"}},
+		"non-us state":       {GeolocationTarget: &GeolocationTarget{Country: "ca", State: "QC"}},
+		"city without state": {GeolocationTarget: &GeolocationTarget{Country: "us", City: "Austin"}},
+		"unknown city":       {GeolocationTarget: &GeolocationTarget{State: "TX", City: "Nowhere"}},
+		"with country":       {GeolocationTarget: &GeolocationTarget{State: "TX"}, Country: "us"},
+		"without rendering":  {GeolocationTarget: &GeolocationTarget{State: "TX"}},
+		"bad geolocation":    {Geolocation: "north"},
+		"out of range":       {Geolocation: "91,0"},
+	}
+	for name, cfg := range tests {
+		cfg.URL = "https://example.com"
+		cfg.RenderJS = name != "without rendering"
+		if _, err := cfg.toAPIParamsWithValidation(); !errors.Is(err, ErrScrapeConfig) {
+			t.Errorf("%s: got %v, want ErrScrapeConfig", name, err)
+		}
+	}
+}
+
+func TestGeoCities(t *testing.T) {
+	cities := GeoCities("tx")
+	if len(cities) != 4 || cities[0] != "austin" {
+		t.Errorf("GeoCities(tx) = %v", cities)
+	}
+}
@@ -0,0 +1,151 @@
+package scrapfly
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Link is an <a href> link of a page, resolved to an absolute URL.
+type Link struct {
+	// URL is the absolute URL, without fragment.
+	URL string
+	// Text is the anchor text, whitespace-collapsed. It is empty when built
+	// with the scrapfly_nogoquery tag.
+	Text string
+	// Rel is the rel attribute ("nofollow", "next"...).
+	Rel string
+}
+
+// Nofollow reports whether the link is marked rel="nofollow".
+func (l Link) Nofollow() bool {
+	for _, rel := range strings.Fields(strings.ToLower(l.Rel)) {
+		if rel == "nofollow" {
+			return true
+		}
+	}
+	return false
+}
+
+// LinkOptions filters the links returned by ScrapeResult.Links. The zero
+// value keeps every http(s) link.
+type LinkOptions struct {
+	// SameDomain keeps only the links to the host of the page.
+	SameDomain bool
+	// IncludeSubdomains also keeps subdomains of the page host with
+	// SameDomain, "shop.example.com" for a page on "example.com" or
+	// "www.example.com".
+	IncludeSubdomains bool
+	// Include keeps only the links whose URL matches one of the patterns.
+	Include []*regexp.Regexp
+	// Exclude drops the links whose URL matches one of the patterns.
+	Exclude []*regexp.Regexp
+	// SkipNofollow drops the rel="nofollow" links.
+	SkipNofollow bool
+}
+
+// Links are the links returned by ScrapeResult.Links.
+type Links []Link
+
+// URLs returns the link URLs.
+func (l Links) URLs() []string {
+	urls := make([]string, len(l))
+	for i, link := range l {
+		urls[i] = link.URL
+	}
+	return urls
+}
+
+// ScrapeConfigs returns a copy of base for each link, with URL set, ready
+// for ScrapeBatch or ConcurrentScrape. A nil base gives plain configs.
+func (l Links) ScrapeConfigs(base *ScrapeConfig) []*ScrapeConfig {
+	configs := make([]*ScrapeConfig, 0, len(l))
+	for _, link := range l {
+		config := base.Clone()
+		if config == nil {
+			config = &ScrapeConfig{}
+		}
+		config.URL = link.URL
+		configs = append(configs, config)
+	}
+	return configs
+}
+
+// Links returns the <a href> links of an HTML result, resolved against the
+// final URL of the page and filtered by opts (nil keeps all). Fragments are
+// dropped, non-http(s) links (mailto:, javascript:...) skipped and
+// duplicates removed, keeping the first occurrence. Non-HTML results have
+// no links.
+//
+// Example — follow the product links of a listing page:
+//
+//	links := result.Links(&scrapfly.LinkOptions{
+//	    SameDomain: true,
+//	    Include:    []*regexp.Regexp{regexp.MustCompile(`/product/\d+`)},
+//	})
+//	results, err := client.ScrapeBatch(links.ScrapeConfigs(&scrapfly.ScrapeConfig{ASP: true}))
+func (r *ScrapeResult) Links(opts *LinkOptions) Links {
+	if opts == nil {
+		opts = &LinkOptions{}
+	}
+	pageURL := r.Result.URL
+	if pageURL == "" {
+		pageURL = r.Config.URL
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+	host := strings.TrimPrefix(strings.ToLower(base.Hostname()), "www.")
+	seen := make(map[string]bool)
+	var links Links
+	for _, a := range htmlAnchors(r) {
+		ref, err := base.Parse(strings.TrimSpace(a.href))
+		if err != nil || (ref.Scheme != "http" && ref.Scheme != "https") {
+			continue
+		}
+		ref.Fragment = ""
+		ref.RawFragment = ""
+		link := Link{URL: ref.String(), Text: a.text, Rel: a.rel}
+		if seen[link.URL] || !opts.keep(link, ref, host) {
+			continue
+		}
+		seen[link.URL] = true
+		links = append(links, link)
+	}
+	return links
+}
+
+// keep applies the options to a resolved link; host is the page host
+// without "www.".
+func (o *LinkOptions) keep(link Link, ref *url.URL, host string) bool {
+	if o.SameDomain {
+		linkHost := strings.TrimPrefix(strings.ToLower(ref.Hostname()), "www.")
+		if linkHost != host && !(o.IncludeSubdomains && strings.HasSuffix(linkHost, "."+host)) {
+			return false
+		}
+	}
+	if o.SkipNofollow && link.Nofollow() {
+		return false
+	}
+	if len(o.Include) > 0 && !matchesAny(o.Include, link.URL) {
+		return false
+	}
+	return !matchesAny(o.Exclude, link.URL)
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// htmlAnchor is an <a href> element as found by htmlAnchors.
+type htmlAnchor struct {
+	href string
+	rel  string
+	text string
+}
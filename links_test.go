@@ -0,0 +1,63 @@
+package scrapfly
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestScrapeResult_Links(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{
+		URL:         "https://www.example.com/shop/list?page=1",
+		ContentType: "text/html",
+		Content: `<html><body>
+<a href="/product/1">One</a>
+<a href="product/2#reviews">Two</a>
+<a href="https://example.com/product/1">One again</a>
+<a href="https://shop.example.com/product/3">Three</a>
+<a href="https://other.com/product/4">Four</a>
+<a href="/login" rel="nofollow">Login</a>
+<a href="mailto:hi@example.com">Mail</a>
+</body></html>`,
+	}}
+
+	all := result.Links(nil)
+	want := []string{
+		"https://www.example.com/product/1",
+		"https://www.example.com/shop/product/2",
+		"https://example.com/product/1",
+		"https://shop.example.com/product/3",
+		"https://other.com/product/4",
+		"https://www.example.com/login",
+	}
+	if got := all.URLs(); len(got) != len(want) {
+		t.Fatalf("Links(nil) = %v, want %v", got, want)
+	} else {
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("link %d = %q, want %q", i, got[i], want[i])
+			}
+		}
+	}
+
+	cases := []struct {
+		name string
+		opts LinkOptions
+		want int
+	}{
+		{"same domain", LinkOptions{SameDomain: true}, 4},
+		{"subdomains", LinkOptions{SameDomain: true, IncludeSubdomains: true}, 5},
+		{"nofollow", LinkOptions{SkipNofollow: true}, 5},
+		{"include", LinkOptions{Include: []*regexp.Regexp{regexp.MustCompile(`/product/\d+$`)}}, 5},
+		{"exclude", LinkOptions{Exclude: []*regexp.Regexp{regexp.MustCompile(`other\.com`)}}, 5},
+	}
+	for _, tc := range cases {
+		if got := result.Links(&tc.opts); len(got) != tc.want {
+			t.Errorf("%s: got %d links %v, want %d", tc.name, len(got), got.URLs(), tc.want)
+		}
+	}
+
+	configs := result.Links(&LinkOptions{SameDomain: true, SkipNofollow: true}).ScrapeConfigs(&ScrapeConfig{ASP: true})
+	if len(configs) != 3 || !configs[0].ASP || configs[0].URL != want[0] {
+		t.Errorf("unexpected configs %+v", configs)
+	}
+}
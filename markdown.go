@@ -0,0 +1,31 @@
+package scrapfly
+
+import "errors"
+
+// errGoqueryDisabled is returned by the HTML conversions that need an HTML
+// parser in builds with the scrapfly_nogoquery tag.
+var errGoqueryDisabled = errors.New("HTML parsing is disabled by the scrapfly_nogoquery build tag")
+
+// Markdown converts the HTML content into Markdown locally: headings,
+// paragraphs, lists, tables, code blocks, emphasis, links and images, with
+// scripts, styles and form controls dropped. Links and images are resolved
+// against the final URL. Content already returned as Markdown (see
+// ScrapeConfig.Format) is returned as is.
+//
+// Unlike FormatMarkdown it costs no API call and works on results scraped
+// in the raw format, or reloaded with LoadResult. It needs goquery and
+// returns an error in builds with the scrapfly_nogoquery tag.
+//
+// Example:
+//
+//	md, err := result.Markdown()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	prompt := "Summarize this page:\n\n" + md
+func (r *ScrapeResult) Markdown() (string, error) {
+	if r.ContentFormat() == FormatMarkdown {
+		return r.Result.Content, nil
+	}
+	return htmlMarkdown(r)
+}
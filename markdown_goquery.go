@@ -0,0 +1,284 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// markdownSkipped are the elements dropped from the Markdown output.
+var markdownSkipped = map[string]bool{
+	"head": true, "script": true, "style": true, "noscript": true, "template": true,
+	"svg": true, "canvas": true, "iframe": true, "object": true, "embed": true,
+	"select": true, "button": true, "input": true, "textarea": true,
+}
+
+// markdownBlocks are the block-level elements, rendered as paragraphs.
+var markdownBlocks = map[string]bool{
+	"address": true, "article": true, "aside": true, "blockquote": true, "body": true,
+	"dd": true, "details": true, "dialog": true, "div": true, "dl": true, "dt": true,
+	"fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "html": true, "li": true, "main": true, "nav": true,
+	"ol": true, "p": true, "pre": true, "section": true, "summary": true,
+	"table": true, "ul": true,
+}
+
+// htmlMarkdown converts the HTML content of a result into Markdown.
+func htmlMarkdown(r *ScrapeResult) (string, error) {
+	doc, err := r.Selector()
+	if err != nil {
+		return "", err
+	}
+	c := &markdownConverter{}
+	if r.Result.URL != "" {
+		c.base, _ = url.Parse(r.Result.URL)
+	}
+	root := doc.Find("body").First()
+	if root.Length() == 0 {
+		root = doc.Selection
+	}
+	return c.block(root), nil
+}
+
+// markdownConverter renders goquery selections as Markdown, resolving links
+// against base when set.
+type markdownConverter struct {
+	base *url.URL
+}
+
+// block renders the children of s: runs of inline content become
+// paragraphs and block children are rendered on their own, separated by
+// blank lines.
+func (c *markdownConverter) block(s *goquery.Selection) string {
+	var parts []string
+	var inline strings.Builder
+	flush := func() {
+		if text := cleanMarkdownInline(inline.String()); text != "" {
+			parts = append(parts, text)
+		}
+		inline.Reset()
+	}
+	s.Contents().Each(func(_ int, child *goquery.Selection) {
+		name := goquery.NodeName(child)
+		switch {
+		case markdownSkipped[name]:
+		case markdownBlocks[name]:
+			flush()
+			if text := c.blockElement(name, child); text != "" {
+				parts = append(parts, text)
+			}
+		default:
+			inline.WriteString(c.inline(name, child))
+		}
+	})
+	flush()
+	return strings.Join(parts, "\n\n")
+}
+
+func (c *markdownConverter) blockElement(name string, s *goquery.Selection) string {
+	switch name {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := cleanMarkdownInline(c.inlineChildren(s))
+		if text == "" {
+			return ""
+		}
+		return strings.Repeat("#", int(name[1]-'0')) + " " + strings.ReplaceAll(text, "  \n", " ")
+	case "hr":
+		return "---"
+	case "pre":
+		lang := ""
+		for _, class := range strings.Fields(s.Find("code").AttrOr("class", "")) {
+			if l, ok := strings.CutPrefix(class, "language-"); ok {
+				lang = l
+			}
+		}
+		return "```" + lang + "\n" + strings.Trim(s.Text(), "\n") + "\n```"
+	case "blockquote":
+		text := c.block(s)
+		if text == "" {
+			return ""
+		}
+		return prefixLines(text, "> ", "> ")
+	case "ul", "ol":
+		return c.list(name == "ol", s)
+	case "table":
+		return c.table(s)
+	}
+	return c.block(s)
+}
+
+func (c *markdownConverter) list(ordered bool, s *goquery.Selection) string {
+	var items []string
+	s.ChildrenFiltered("li").Each(func(i int, li *goquery.Selection) {
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", i+1)
+		}
+		text := strings.ReplaceAll(c.block(li), "\n\n", "\n")
+		items = append(items, prefixLines(text, marker, strings.Repeat(" ", len(marker))))
+	})
+	return strings.Join(items, "\n")
+}
+
+func (c *markdownConverter) table(s *goquery.Selection) string {
+	var rows [][]string
+	columns := 0
+	s.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		var row []string
+		tr.ChildrenFiltered("th, td").Each(func(_ int, cell *goquery.Selection) {
+			text := cleanMarkdownInline(c.inlineChildren(cell))
+			text = strings.ReplaceAll(strings.ReplaceAll(text, "  \n", " "), "|", `\|`)
+			row = append(row, text)
+		})
+		if len(row) > 0 {
+			rows = append(rows, row)
+			columns = max(columns, len(row))
+		}
+	})
+	if len(rows) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	writeRow := func(row []string) {
+		for len(row) < columns {
+			row = append(row, "")
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+	writeRow(rows[0])
+	writeRow(slices.Repeat([]string{"---"}, columns))
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// inline renders an inline node; line breaks are "\n", cleaned up by
+// cleanMarkdownInline.
+func (c *markdownConverter) inline(name string, s *goquery.Selection) string {
+	switch name {
+	case "#text":
+		return collapseSpaces(s.Text())
+	case "#comment":
+		return ""
+	case "br":
+		return "\n"
+	case "img":
+		src := s.AttrOr("src", "")
+		if src == "" {
+			return ""
+		}
+		return "![" + s.AttrOr("alt", "") + "](" + c.resolve(src) + ")"
+	case "a":
+		text := strings.TrimSpace(c.inlineChildren(s))
+		href, ok := s.Attr("href")
+		if !ok || text == "" || strings.HasPrefix(strings.TrimSpace(href), "javascript:") {
+			return text
+		}
+		return "[" + text + "](" + c.resolve(href) + ")"
+	case "strong", "b":
+		return wrapMarkdown(c.inlineChildren(s), "**")
+	case "em", "i":
+		return wrapMarkdown(c.inlineChildren(s), "_")
+	case "del", "s", "strike":
+		return wrapMarkdown(c.inlineChildren(s), "~~")
+	case "code", "kbd", "samp":
+		return wrapMarkdown(s.Text(), "`")
+	}
+	if markdownSkipped[name] {
+		return ""
+	}
+	if markdownBlocks[name] {
+		// block inside an inline element, such as a <div> in a link
+		return " " + c.inlineChildren(s) + " "
+	}
+	return c.inlineChildren(s)
+}
+
+func (c *markdownConverter) inlineChildren(s *goquery.Selection) string {
+	var b strings.Builder
+	s.Contents().Each(func(_ int, child *goquery.Selection) {
+		b.WriteString(c.inline(goquery.NodeName(child), child))
+	})
+	return b.String()
+}
+
+func (c *markdownConverter) resolve(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if c.base == nil {
+		return ref
+	}
+	u, err := c.base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return u.String()
+}
+
+// collapseSpaces replaces each run of whitespace, line breaks included,
+// with a single space.
+func collapseSpaces(text string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range text {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' {
+			space = true
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+	if space {
+		b.WriteByte(' ')
+	}
+	return b.String()
+}
+
+// cleanMarkdownInline collapses the spaces of rendered inline content and
+// turns line breaks into Markdown hard breaks.
+func cleanMarkdownInline(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "  \n")
+}
+
+// wrapMarkdown wraps text in marker, keeping surrounding spaces outside.
+func wrapMarkdown(text, marker string) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		return text
+	}
+	lead := text[:strings.Index(text, trimmed)]
+	trail := text[len(lead)+len(trimmed):]
+	return lead + marker + trimmed + marker + trail
+}
+
+// prefixLines prefixes the first line of text with first and the others
+// with rest.
+func prefixLines(text, first, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		switch {
+		case i == 0:
+			lines[i] = first + line
+		case line == "":
+			lines[i] = strings.TrimRight(rest, " ")
+		default:
+			lines[i] = rest + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,46 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import "testing"
+
+func TestScrapeResult_Markdown(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{
+		URL:         "https://example.com/blog/post",
+		ContentType: "text/html",
+		Content: `<html><head><title>T</title><style>p{}</style></head><body>
+<h1>Hello  <em>World</em></h1>
+<p>Some <strong>bold</strong> text and a <a href="/about">link</a>.<br>Next line.</p>
+<script>var x = 1;</script>
+<ul><li>One</li><li>Two<ol><li>Nested</li></ol></li></ul>
+<pre><code class="language-go">fmt.Println("hi")
+</code></pre>
+<blockquote><p>Quote</p></blockquote>
+<table><tr><th>Name</th><th>Price</th></tr><tr><td>Box | Big</td><td>9.99</td></tr></table>
+<img src="img/a.png" alt="A">
+</body></html>`,
+	}}
+	md, err := result.Markdown()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Hello _World_\n\n" +
+		"Some **bold** text and a [link](https://example.com/about).  \nNext line.\n\n" +
+		"- One\n- Two\n  1. Nested\n\n" +
+		"```go\nfmt.Println(\"hi\")\n```\n\n" +
+		"> Quote\n\n" +
+		"| Name | Price |\n| --- | --- |\n| Box \\| Big | 9.99 |\n\n" +
+		"![A](https://example.com/blog/img/a.png)"
+	if md != want {
+		t.Errorf("Markdown() =\n%s\nwant\n%s", md, want)
+	}
+
+	markdown := "markdown"
+	converted := &ScrapeResult{Config: ConfigData{Format: &markdown}, Result: ResultData{Content: "# Already"}}
+	if md, err := converted.Markdown(); err != nil || md != "# Already" {
+		t.Errorf("Markdown() of markdown content = %q, %v", md, err)
+	}
+	if _, err := (&ScrapeResult{Result: ResultData{ContentType: "application/pdf"}}).Markdown(); err == nil {
+		t.Error("expected an error for non-HTML content")
+	}
+}
@@ -241,7 +241,7 @@ func (c *Client) doMonitoringRequest(requestURL string) (map[string]any, error)
 	req.Header.Set("User-Agent", sdkUserAgent)
 	req.Header.Set("Accept", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.doAuxiliary(req)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,123 @@
+package next
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"slices"
+	"sync/atomic"
+
+	"github.com/scrapfly/go-scrapfly"
+)
+
+// Request is one scrape of a batch. Its options are applied after the
+// batch options.
+type Request struct {
+	URL     string
+	Options []Option
+}
+
+// URLs returns a request sequence over urls, with no per-request options.
+func URLs(urls ...string) iter.Seq[Request] {
+	return func(yield func(Request) bool) {
+		for _, u := range urls {
+			if !yield(Request{URL: u}) {
+				return
+			}
+		}
+	}
+}
+
+// ScrapeError is the error of one request of a batch.
+type ScrapeError struct {
+	URL string
+	Err error
+}
+
+func (e *ScrapeError) Error() string { return fmt.Sprintf("scrape %s: %v", e.URL, e.Err) }
+
+func (e *ScrapeError) Unwrap() error { return e.Err }
+
+// ScrapeAll scrapes every request of requests with up to concurrency
+// scrapes in flight and yields the results in completion order. Errors are
+// *ScrapeError values carrying the request URL. requests is consumed
+// lazily, so it can be fed by a crawler or a database cursor.
+//
+// Breaking out of the loop, or cancelling ctx, stops consuming requests and
+// cancels the scrapes in flight; ScrapeAll returns once they have exited.
+// When ctx ends the run before every request is reported, ctx's error is
+// yielded last, with a nil Result, so a truncated run can be told from a
+// complete one.
+func (c *Client) ScrapeAll(ctx context.Context, requests iter.Seq[Request], concurrency int, opts ...Option) iter.Seq2[Result, error] {
+	return c.ScrapeAllWithOptions(ctx, requests, scrapfly.ConcurrentScrapeOptions{Concurrency: max(concurrency, 1)}, opts...)
+}
+
+// ScrapeAllWithOptions is ScrapeAll on the v1 engine options: per-host
+// limits, retries, Adaptive, CostBudget, progress (see
+// scrapfly.Client.ConcurrentScrapeChanWithOptions). Concurrency <= 0 uses
+// the account's concurrent limit. Run-level errors, such as
+// scrapfly.ErrCostBudgetExceeded, are yielded as they are, with a nil
+// Result.
+//
+// Example:
+//
+//	run := scrapfly.ConcurrentScrapeOptions{Concurrency: 10, MaxPerHostConcurrency: 2, Retry: &scrapfly.RetryPolicy{MaxAttempts: 3}}
+//	for result, err := range client.ScrapeAllWithOptions(ctx, requests, run, next.WithASP()) {
+//	    ...
+//	}
+func (c *Client) ScrapeAllWithOptions(ctx context.Context, requests iter.Seq[Request], run scrapfly.ConcurrentScrapeOptions, opts ...Option) iter.Seq2[Result, error] {
+	return func(yield func(Result, error) bool) {
+		parent := ctx
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		// truncated is set when a request is not read or not dispatched
+		// because ctx is done
+		var truncated atomic.Bool
+		onComplete := run.OnComplete
+		run.OnComplete = func(summary scrapfly.ConcurrentScrapeSummary) {
+			if summary.Canceled {
+				truncated.Store(true)
+			}
+			if onComplete != nil {
+				onComplete(summary)
+			}
+		}
+
+		configs := make(chan *scrapfly.ScrapeConfig)
+		go func() {
+			defer close(configs)
+			for req := range requests {
+				// invalid options fail the scrape of the request
+				config := &scrapfly.ScrapeConfig{URL: req.URL}
+				Options(slices.Concat(opts, req.Options)...)(config)
+				select {
+				case configs <- config:
+				case <-ctx.Done():
+					truncated.Store(true)
+					return
+				}
+			}
+		}()
+
+		results := c.client.ConcurrentScrapeChanWithOptions(ctx, configs, run)
+		for item := range results {
+			var result Result
+			err := item.Err
+			if err != nil && item.Config != nil {
+				err = &ScrapeError{URL: item.Config.URL, Err: err}
+			} else if err == nil {
+				result = &scrapeResult{raw: item.Result}
+			}
+			if !yield(result, err) {
+				cancel()
+				// Drain so the scrapes in flight can exit before returning.
+				for range results {
+				}
+				return
+			}
+		}
+		if err := parent.Err(); err != nil && truncated.Load() {
+			yield(nil, err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Package next is the experimental context-first surface of the SDK, the
+// candidate shape for a v2 major version:
+//
+//   - every call takes a context.Context
+//   - configs are immutable functional options, so a template is a plain
+//     []Option that can be shared across goroutines
+//   - results are interfaces with lazy accessors
+//   - batch operations are iterators (iter.Seq2) whose early break cancels
+//     the remaining work
+//
+// It wraps a regular scrapfly.Client, so both surfaces can be mixed while
+// migrating, and it may change between minor versions until it is promoted.
+//
+// Example:
+//
+//	client, err := next.New(os.Getenv("SCRAPFLY_KEY"))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	base := []next.Option{next.WithASP(), next.WithCountry("us")}
+//
+//	result, err := client.Scrape(ctx, "https://web-scraping.dev/product/1", base...)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(result.StatusCode(), len(result.Content()))
+//
+//	for result, err := range client.ScrapeAll(ctx, next.URLs(urls...), 5, base...) {
+//	    if err != nil {
+//	        log.Println(err)
+//	        continue
+//	    }
+//	    fmt.Println(result.URL())
+//	}
+package next
+
+import (
+	"context"
+
+	"github.com/scrapfly/go-scrapfly"
+)
+
+// Client is the context-first Scrapfly client. It is safe for concurrent use.
+type Client struct {
+	client *scrapfly.Client
+}
+
+// New creates a client for apiKey against the production API.
+func New(apiKey string) (*Client, error) {
+	client, err := scrapfly.New(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	return Wrap(client), nil
+}
+
+// Wrap exposes an existing scrapfly.Client through the context-first API.
+func Wrap(client *scrapfly.Client) *Client {
+	return &Client{client: client}
+}
+
+// Unwrap returns the underlying scrapfly.Client.
+func (c *Client) Unwrap() *scrapfly.Client { return c.client }
+
+// Scrape scrapes targetURL with opts.
+func (c *Client) Scrape(ctx context.Context, targetURL string, opts ...Option) (Result, error) {
+	config, err := NewScrapeConfig(targetURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	result, err := c.client.ScrapeContext(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &scrapeResult{raw: result}, nil
+}
+
+// Screenshot captures targetURL with opts.
+func (c *Client) Screenshot(ctx context.Context, targetURL string, opts ...ScreenshotOption) (Screenshot, error) {
+	config := &scrapfly.ScreenshotConfig{URL: targetURL}
+	for _, opt := range opts {
+		opt(config)
+	}
+	result, err := c.client.ScreenshotContext(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &screenshotResult{raw: result}, nil
+}
+
+// Extract extracts structured data from document, of the given content type.
+func (c *Client) Extract(ctx context.Context, document []byte, contentType string, opts ...ExtractionOption) (Extraction, error) {
+	config := &scrapfly.ExtractionConfig{Body: document, ContentType: contentType}
+	for _, opt := range opts {
+		opt(config)
+	}
+	result, err := c.client.ExtractContext(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &extractionResult{raw: result}, nil
+}
@@ -0,0 +1,197 @@
+package next_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scrapfly/go-scrapfly"
+	"github.com/scrapfly/go-scrapfly/next"
+	"github.com/scrapfly/go-scrapfly/scrapflytest"
+)
+
+func TestClient_Scrape(t *testing.T) {
+	server := scrapflytest.NewServer()
+	defer server.Close()
+	client := next.Wrap(server.Client())
+
+	result, err := client.Scrape(context.Background(), "https://example.com/a", next.WithASP(), next.WithHeader("x-a", "1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.URL() != "https://example.com/a" || result.StatusCode() != 200 || result.Content() == "" {
+		t.Errorf("unexpected result %s %d", result.URL(), result.StatusCode())
+	}
+}
+
+func TestClient_ScrapeValidatesOptions(t *testing.T) {
+	client := next.Wrap(scrapflytest.NewServer().Client())
+	_, err := client.Scrape(context.Background(), "https://example.com", next.WithWaitForSelector(".x"))
+	if !errors.Is(err, scrapfly.ErrScrapeConfig) {
+		t.Errorf("got %v, want ErrScrapeConfig", err)
+	}
+}
+
+func TestClient_ScrapeHonorsContext(t *testing.T) {
+	server := scrapflytest.NewServer()
+	defer server.Close()
+	server.SetScenario(scrapflytest.Scenario{Latency: time.Second})
+	client := next.Wrap(server.Client())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.Scrape(ctx, "https://example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want context.DeadlineExceeded", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Errorf("cancellation took %s", time.Since(start))
+	}
+}
+
+func TestClient_ScrapeAll(t *testing.T) {
+	server := scrapflytest.NewServer()
+	defer server.Close()
+	client := next.Wrap(server.Client())
+
+	urls := make([]string, 20)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://example.com/%d", i)
+	}
+	seen := map[string]bool{}
+	for result, err := range client.ScrapeAll(context.Background(), next.URLs(urls...), 4, next.WithASP()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[result.URL()] = true
+	}
+	if len(seen) != len(urls) {
+		t.Errorf("got %d results, want %d", len(seen), len(urls))
+	}
+}
+
+func TestClient_ScrapeAllBreakStopsWork(t *testing.T) {
+	server := scrapflytest.NewServer()
+	defer server.Close()
+	var served int32
+	server.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&served, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"result":{"success":true,"status":"DONE","status_code":200,"url":%q,"content":"ok"}}`, r.URL.Query().Get("url"))
+	}))
+	client := next.Wrap(server.Client())
+
+	requests := func(yield func(next.Request) bool) {
+		for i := 0; ; i++ {
+			if !yield(next.Request{URL: fmt.Sprintf("https://example.com/%d", i)}) {
+				return
+			}
+		}
+	}
+	count := 0
+	for _, err := range client.ScrapeAll(context.Background(), requests, 2) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+		if count == 5 {
+			break
+		}
+	}
+	if got := atomic.LoadInt32(&served); got > 10 {
+		t.Errorf("break did not stop the batch: %d requests served", got)
+	}
+}
+
+func TestClient_ScrapeAllReportsCancel(t *testing.T) {
+	server := scrapflytest.NewServer()
+	defer server.Close()
+	server.SetScenario(scrapflytest.Scenario{Latency: 20 * time.Millisecond})
+	client := next.Wrap(server.Client())
+
+	urls := make([]string, 50)
+	for i := range urls {
+		urls[i] = fmt.Sprintf("https://example.com/%d", i)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := 0
+	var last error
+	for result, err := range client.ScrapeAll(ctx, next.URLs(urls...), 2) {
+		if result != nil {
+			results++
+			if results == 3 {
+				cancel()
+			}
+		}
+		last = err
+	}
+	if results >= len(urls) {
+		t.Fatalf("every request scraped despite the cancel")
+	}
+	if !errors.Is(last, context.Canceled) {
+		t.Errorf("last error = %v, want context.Canceled", last)
+	}
+}
+
+func TestClient_ScrapeAllWithOptionsRetries(t *testing.T) {
+	server := scrapflytest.NewServer()
+	defer server.Close()
+	var mu sync.Mutex
+	calls := map[string]int{}
+	server.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		u := r.URL.Query().Get("url")
+		mu.Lock()
+		calls[u]++
+		n := calls[u]
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if n == 1 {
+			fmt.Fprint(w, `{"result":{"success":false,"status":"ERR::PROXY::TIMEOUT","status_code":200}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"result":{"success":true,"status":"DONE","status_code":200,"url":%q,"content":"ok"}}`, u)
+	}))
+	client := next.Wrap(server.Client())
+
+	run := scrapfly.ConcurrentScrapeOptions{
+		Concurrency: 2,
+		Retry:       &scrapfly.RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond},
+	}
+	results := 0
+	for _, err := range client.ScrapeAllWithOptions(context.Background(), next.URLs("https://example.com/a", "https://example.com/b"), run) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		results++
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if results != 2 || calls["https://example.com/a"] != 2 || calls["https://example.com/b"] != 2 {
+		t.Errorf("got %d results for calls %v, want the failures retried", results, calls)
+	}
+}
+
+func TestResult_DecodeJSONChecksContentType(t *testing.T) {
+	server := scrapflytest.NewServer()
+	defer server.Close()
+	server.Handle(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE","status_code":200,"content_type":"text/html","content":"{}"}}`)
+	}))
+	client := next.Wrap(server.Client())
+
+	result, err := client.Scrape(context.Background(), "https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var v map[string]any
+	if err := result.DecodeJSON(&v); !errors.Is(err, scrapfly.ErrContentType) {
+		t.Errorf("got %v, want ErrContentType", err)
+	}
+}
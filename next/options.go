@@ -0,0 +1,146 @@
+package next
+
+import (
+	"time"
+
+	"github.com/scrapfly/go-scrapfly"
+)
+
+// Option configures a scrape. Options are applied in order to a fresh
+// config for every call, so option slices are immutable templates.
+type Option func(*scrapfly.ScrapeConfig)
+
+// ScreenshotOption configures a screenshot.
+type ScreenshotOption func(*scrapfly.ScreenshotConfig)
+
+// ExtractionOption configures an extraction.
+type ExtractionOption func(*scrapfly.ExtractionConfig)
+
+// NewScrapeConfig builds and validates the scrapfly.ScrapeConfig for
+// targetURL and opts, for use with the v1 API.
+func NewScrapeConfig(targetURL string, opts ...Option) (*scrapfly.ScrapeConfig, error) {
+	config := &scrapfly.ScrapeConfig{URL: targetURL}
+	for _, opt := range opts {
+		opt(config)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// Options groups several options into one.
+func Options(opts ...Option) Option {
+	return func(config *scrapfly.ScrapeConfig) {
+		for _, opt := range opts {
+			opt(config)
+		}
+	}
+}
+
+// WithConfig applies fn to the config, for settings without a dedicated option.
+func WithConfig(fn func(config *scrapfly.ScrapeConfig)) Option { return fn }
+
+// WithRenderJS enables the headless browser.
+func WithRenderJS() Option {
+	return func(config *scrapfly.ScrapeConfig) { config.RenderJS = true }
+}
+
+// WithASP enables Anti Scraping Protection bypass.
+func WithASP() Option {
+	return func(config *scrapfly.ScrapeConfig) { config.ASP = true }
+}
+
+// WithCountry sets the proxy country code.
+func WithCountry(country string) Option {
+	return func(config *scrapfly.ScrapeConfig) { config.Country = country }
+}
+
+// WithProxyPool selects the proxy pool.
+func WithProxyPool(pool scrapfly.ProxyPool) Option {
+	return func(config *scrapfly.ScrapeConfig) { config.ProxyPool = pool }
+}
+
+// WithHeader sets a request header.
+func WithHeader(name, value string) Option {
+	return func(config *scrapfly.ScrapeConfig) {
+		headers := make(map[string]string, len(config.Headers)+1)
+		for k, v := range config.Headers {
+			headers[k] = v
+		}
+		headers[name] = value
+		config.Headers = headers
+	}
+}
+
+// WithFormat sets the response content format.
+func WithFormat(format scrapfly.Format, options ...scrapfly.FormatOption) Option {
+	return func(config *scrapfly.ScrapeConfig) {
+		config.Format = format
+		config.FormatOptions = options
+	}
+}
+
+// WithTimeout sets the API timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(config *scrapfly.ScrapeConfig) { config.Timeout = int(timeout / time.Millisecond) }
+}
+
+// WithSession sets the session name.
+func WithSession(name string) Option {
+	return func(config *scrapfly.ScrapeConfig) { config.Session = name }
+}
+
+// WithCache enables the response cache with a time-to-live (zero for the
+// API default).
+func WithCache(ttl time.Duration) Option {
+	return func(config *scrapfly.ScrapeConfig) {
+		config.Cache = true
+		config.CacheTTL = int(ttl / time.Second)
+	}
+}
+
+// WithWaitForSelector waits for a selector before returning (requires WithRenderJS).
+func WithWaitForSelector(selector string) Option {
+	return func(config *scrapfly.ScrapeConfig) { config.WaitForSelector = selector }
+}
+
+// WithExtractionPrompt extracts data from the page with an AI prompt.
+func WithExtractionPrompt(prompt string) Option {
+	return func(config *scrapfly.ScrapeConfig) { config.ExtractionPrompt = prompt }
+}
+
+// WithExtractionModel extracts data from the page with a predefined model.
+func WithExtractionModel(model scrapfly.ExtractionModel) Option {
+	return func(config *scrapfly.ScrapeConfig) { config.ExtractionModel = model }
+}
+
+// WithScreenshotFormat sets the image format.
+func WithScreenshotFormat(format scrapfly.ScreenshotFormat) ScreenshotOption {
+	return func(config *scrapfly.ScreenshotConfig) { config.Format = format }
+}
+
+// WithCapture sets what to capture: "fullpage" or a CSS selector.
+func WithCapture(capture string) ScreenshotOption {
+	return func(config *scrapfly.ScreenshotConfig) { config.Capture = capture }
+}
+
+// WithResolution sets the viewport size, e.g. "1920x1080".
+func WithResolution(resolution string) ScreenshotOption {
+	return func(config *scrapfly.ScreenshotConfig) { config.Resolution = resolution }
+}
+
+// WithPrompt extracts data with an AI prompt.
+func WithPrompt(prompt string) ExtractionOption {
+	return func(config *scrapfly.ExtractionConfig) { config.ExtractionPrompt = prompt }
+}
+
+// WithTemplate extracts data with a saved extraction template.
+func WithTemplate(name string) ExtractionOption {
+	return func(config *scrapfly.ExtractionConfig) { config.ExtractionTemplate = name }
+}
+
+// WithModel extracts data with a predefined model.
+func WithModel(model scrapfly.ExtractionModel) ExtractionOption {
+	return func(config *scrapfly.ExtractionConfig) { config.ExtractionModel = model }
+}
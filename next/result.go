@@ -0,0 +1,90 @@
+package next
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/scrapfly/go-scrapfly"
+)
+
+// Result is a scrape result. Accessors are computed on first use.
+type Result interface {
+	// URL is the final URL after redirects.
+	URL() string
+	// StatusCode is the upstream status code.
+	StatusCode() int
+	// Headers are the upstream response headers.
+	Headers() http.Header
+	// Content is the page content in the requested format.
+	Content() string
+	// Format is the format the content was returned in.
+	Format() scrapfly.Format
+	// DecodeJSON unmarshals a JSON content into v, like
+	// scrapfly.ScrapeResult.DecodeJSON: it fails with scrapfly.ErrContentType
+	// when the content is not JSON.
+	DecodeJSON(v any) error
+	// DecodeExtracted unmarshals the extracted data into v.
+	DecodeExtracted(v any) error
+	// LogURL is the dashboard URL of the scrape log.
+	LogURL() string
+	// Raw is the v1 result.
+	Raw() *scrapfly.ScrapeResult
+}
+
+type scrapeResult struct {
+	raw         *scrapfly.ScrapeResult
+	headersOnce sync.Once
+	headers     http.Header
+}
+
+func (r *scrapeResult) URL() string                 { return r.raw.Result.URL }
+func (r *scrapeResult) StatusCode() int             { return r.raw.Result.StatusCode }
+func (r *scrapeResult) Content() string             { return r.raw.Result.Content }
+func (r *scrapeResult) Format() scrapfly.Format     { return r.raw.ContentFormat() }
+func (r *scrapeResult) LogURL() string              { return r.raw.Result.LogURL }
+func (r *scrapeResult) Raw() *scrapfly.ScrapeResult { return r.raw }
+
+func (r *scrapeResult) DecodeJSON(v any) error {
+	return r.raw.DecodeJSON(v)
+}
+
+func (r *scrapeResult) DecodeExtracted(v any) error {
+	return r.raw.DecodeExtractedData(v)
+}
+
+func (r *scrapeResult) Headers() http.Header {
+	r.headersOnce.Do(func() { r.headers = r.raw.Headers() })
+	return r.headers
+}
+
+// Screenshot is a screenshot result.
+type Screenshot interface {
+	// Image is the encoded image.
+	Image() []byte
+	// Extension is the image file extension.
+	Extension() string
+	// Raw is the v1 result.
+	Raw() *scrapfly.ScreenshotResult
+}
+
+type screenshotResult struct{ raw *scrapfly.ScreenshotResult }
+
+func (s *screenshotResult) Image() []byte                   { return s.raw.Image }
+func (s *screenshotResult) Extension() string               { return s.raw.Metadata.ExtensionName }
+func (s *screenshotResult) Raw() *scrapfly.ScreenshotResult { return s.raw }
+
+// Extraction is an extraction result.
+type Extraction interface {
+	// Decode unmarshals the extracted data into v.
+	Decode(v any) error
+	// ContentType is the content type of the extracted data.
+	ContentType() string
+	// Raw is the v1 result.
+	Raw() *scrapfly.ExtractionResult
+}
+
+type extractionResult struct{ raw *scrapfly.ExtractionResult }
+
+func (e *extractionResult) Decode(v any) error              { return e.raw.Decode(v) }
+func (e *extractionResult) ContentType() string             { return e.raw.ContentType }
+func (e *extractionResult) Raw() *scrapfly.ExtractionResult { return e.raw }
@@ -0,0 +1,152 @@
+package scrapfly
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PaginationOptions configures ScrapeAllPages.
+type PaginationOptions struct {
+	// Selector is the CSS selector of the next-page link, such as
+	// "a.pagination-next" or "li.next > a"; its href is followed. When
+	// empty, the next page is the Link response header with rel="next",
+	// then the first <link> or <a> element with rel="next".
+	Selector string
+	// NextURL, when set, replaces the built-in detection: it returns the
+	// next page URL, relative or absolute, or "" on the last page.
+	NextURL func(result *ScrapeResult) (string, error)
+	// MaxPages caps the number of pages scraped, the first one included.
+	// Zero means no cap.
+	MaxPages int
+	// Delay is the pause between two page scrapes.
+	Delay time.Duration
+}
+
+// ScrapeAllPages scrapes config, then follows the next-page link of each
+// page and scrapes it with a copy of config, yielding each result in page
+// order. It stops after the last page (no next link, or a link to a page
+// already scraped), at MaxPages, on the first error, which is yielded, or
+// when ctx is done. Breaking out of the loop stops it too.
+//
+// Example:
+//
+//	pages := client.ScrapeAllPages(ctx, &scrapfly.ScrapeConfig{URL: "https://web-scraping.dev/products"},
+//	    scrapfly.PaginationOptions{Selector: ".paging a:last-child", MaxPages: 10})
+//	for result, err := range pages {
+//	    if err != nil {
+//	        log.Fatal(err)
+//	    }
+//	    fmt.Println(result.Result.URL)
+//	}
+func (c *Client) ScrapeAllPages(ctx context.Context, config *ScrapeConfig, opts PaginationOptions) iter.Seq2[*ScrapeResult, error] {
+	return func(yield func(*ScrapeResult, error) bool) {
+		seen := make(map[string]bool)
+		page := config.Clone()
+		for n := 1; ; n++ {
+			seen[paginationKey(page.URL)] = true
+			result, err := c.ScrapeContext(ctx, page)
+			if err != nil {
+				yield(result, err)
+				return
+			}
+			if result.Result.URL != "" {
+				seen[paginationKey(result.Result.URL)] = true
+			}
+			if !yield(result, nil) || (opts.MaxPages > 0 && n >= opts.MaxPages) {
+				return
+			}
+
+			next, err := opts.next(result)
+			if err != nil {
+				yield(nil, fmt.Errorf("failed to find the next page of %s: %w", result.Result.URL, err))
+				return
+			}
+			if next == "" || seen[paginationKey(next)] {
+				return
+			}
+			if opts.Delay > 0 {
+				select {
+				case <-ctx.Done():
+					yield(nil, ctx.Err())
+					return
+				case <-time.After(opts.Delay):
+				}
+			}
+			page = config.Clone()
+			page.URL = next
+		}
+	}
+}
+
+// next returns the absolute URL of the page after result, or "".
+func (o *PaginationOptions) next(result *ScrapeResult) (string, error) {
+	pageURL := result.Result.URL
+	if pageURL == "" {
+		pageURL = result.Config.URL
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return "", err
+	}
+
+	var href string
+	switch {
+	case o.NextURL != nil:
+		href, err = o.NextURL(result)
+	case o.Selector != "":
+		href, err = htmlNextLink(result, o.Selector)
+	default:
+		if href = linkHeaderURL(result.HeaderValues("Link"), "next"); href == "" {
+			href, err = htmlNextLink(result, "")
+		}
+	}
+	if err != nil || href == "" {
+		return "", err
+	}
+	next, err := base.Parse(href)
+	if err != nil {
+		return "", err
+	}
+	if next.Scheme != "http" && next.Scheme != "https" {
+		return "", nil
+	}
+	return next.String(), nil
+}
+
+// linkHeaderURL returns the target of the first RFC 8288 Link header value
+// with the relation rel, such as `<https://example.com/?page=2>; rel="next"`.
+func linkHeaderURL(values []string, rel string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range strings.Split(params, ";") {
+				name, relations, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(name, "rel") {
+					continue
+				}
+				for _, r := range strings.Fields(strings.Trim(relations, `"`)) {
+					if strings.EqualFold(r, rel) {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// paginationKey normalizes a page URL for loop detection.
+func paginationKey(pageURL string) string {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return pageURL
+	}
+	return refererKey(u)
+}
@@ -0,0 +1,87 @@
+package scrapfly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// paginatedHandler serves pages of a listing whose HTML links each page to
+// the next one, the last page linking back to the first.
+func paginatedHandler(pages int, link func(page int) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("url")
+		page := 1
+		fmt.Sscanf(target, "https://example.com/list?page=%d", &page)
+		next := page%pages + 1
+		payload := map[string]interface{}{"result": map[string]interface{}{
+			"success": true, "status": "DONE", "status_code": 200,
+			"url": target, "content_type": "text/html",
+			"content": fmt.Sprintf("<html><body><p>page %d</p>%s</body></html>", page, link(next)),
+		}}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(payload)
+	}
+}
+
+func TestClient_ScrapeAllPages(t *testing.T) {
+	cases := []struct {
+		name string
+		opts PaginationOptions
+		link func(int) string
+		want int
+	}{
+		{"rel next", PaginationOptions{}, func(n int) string { return fmt.Sprintf(`<a rel="next" href="?page=%d">Next</a>`, n) }, 3},
+		{"selector", PaginationOptions{Selector: ".paging a.next"}, func(n int) string {
+			return fmt.Sprintf(`<div class="paging"><a href="?page=1">1</a><a class="next" href="/list?page=%d">›</a></div>`, n)
+		}, 3},
+		{"max pages", PaginationOptions{MaxPages: 2}, func(n int) string { return fmt.Sprintf(`<a rel="next" href="?page=%d">Next</a>`, n) }, 2},
+		{"no next", PaginationOptions{}, func(int) string { return "" }, 1},
+	}
+cases:
+	for _, tc := range cases {
+		client := newTestClient(t, paginatedHandler(3, tc.link))
+		var urls []string
+		for result, err := range client.ScrapeAllPages(context.Background(), &ScrapeConfig{URL: "https://example.com/list?page=1"}, tc.opts) {
+			switch {
+			case errors.Is(err, errGoqueryDisabled):
+				// CSS selectors need goquery
+				continue cases
+			case err != nil:
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			urls = append(urls, result.Result.URL)
+		}
+		if len(urls) != tc.want {
+			t.Errorf("%s: scraped %v, want %d pages", tc.name, urls, tc.want)
+		}
+	}
+}
+
+func TestClient_ScrapeAllPages_Break(t *testing.T) {
+	calls := 0
+	handler := paginatedHandler(5, func(n int) string { return fmt.Sprintf(`<a rel="next" href="?page=%d">Next</a>`, n) })
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		handler(w, r)
+	})
+	for range client.ScrapeAllPages(context.Background(), &ScrapeConfig{URL: "https://example.com/list?page=1"}, PaginationOptions{}) {
+		break
+	}
+	if calls != 1 {
+		t.Errorf("breaking out should stop pagination, got %d scrapes", calls)
+	}
+}
+
+func TestLinkHeaderURL(t *testing.T) {
+	values := []string{`<https://api.example.com/items?page=1>; rel="prev", <https://api.example.com/items?page=3>; rel="next last"`}
+	if got := linkHeaderURL(values, "next"); got != "https://api.example.com/items?page=3" {
+		t.Errorf("linkHeaderURL(next) = %q", got)
+	}
+	if got := linkHeaderURL(values, "first"); got != "" {
+		t.Errorf("linkHeaderURL(first) = %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+package scrapfly
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// EncodeHeaderParams encodes request headers and cookies into the
+// headers[<name>] parameters of the Scrape API, exactly as ScrapeConfig
+// sends them, so callers can verify what the target will receive.
+//
+// Encoding rules:
+//   - header names are trimmed and lower-cased; two names that differ only
+//     by case are an error rather than one silently overwriting the other
+//   - header values are sent verbatim (UTF-8, commas and semicolons
+//     included) and escaped only by the query string encoding; CR, LF and
+//     NUL are rejected as they would split the upstream header
+//   - a header with several values must be given as one comma-joined value
+//   - cookies are appended to any Cookie header as "name=value" pairs
+//     sorted by name and joined with "; ". Cookie names must be HTTP
+//     tokens. Values are sent verbatim, as sites set them (spaces, commas
+//     and UTF-8 included); only a semicolon, which would start another
+//     cookie, and CR, LF or NUL are rejected
+func EncodeHeaderParams(headers, cookies map[string]string) (url.Values, error) {
+	params := url.Values{}
+	names := make(map[string]string, len(headers))
+	for key, value := range headers {
+		name := strings.ToLower(strings.TrimSpace(key))
+		if name == "" || value == "" {
+			return nil, fmt.Errorf("%w: headers key and value cannot be empty, found key: %s, value: %s", ErrScrapeConfig, key, value)
+		}
+		if !isHTTPToken(name) {
+			return nil, fmt.Errorf("%w: invalid header name %q", ErrScrapeConfig, key)
+		}
+		if strings.ContainsAny(value, "\r\n\x00") {
+			return nil, fmt.Errorf("%w: header %q value contains a line break or NUL", ErrScrapeConfig, key)
+		}
+		if previous, dup := names[name]; dup {
+			return nil, fmt.Errorf("%w: headers %q and %q differ only by case", ErrScrapeConfig, previous, key)
+		}
+		names[name] = key
+		params.Set("headers["+name+"]", value)
+	}
+
+	if len(cookies) > 0 {
+		cookieNames := make([]string, 0, len(cookies))
+		for name, value := range cookies {
+			if name == "" || value == "" {
+				return nil, fmt.Errorf("%w: cookies name and value cannot be empty, found name: %s, value: %s", ErrScrapeConfig, name, value)
+			}
+			if !isHTTPToken(name) {
+				return nil, fmt.Errorf("%w: invalid cookie name %q", ErrScrapeConfig, name)
+			}
+			if strings.ContainsAny(value, ";\r\n\x00") {
+				return nil, fmt.Errorf("%w: cookie %q value contains a semicolon, line break or NUL", ErrScrapeConfig, name)
+			}
+			cookieNames = append(cookieNames, name)
+		}
+		sort.Strings(cookieNames)
+		parts := make([]string, 0, len(cookieNames)+1)
+		if existing := params.Get("headers[cookie]"); existing != "" {
+			parts = append(parts, strings.TrimRight(strings.TrimSpace(existing), ";"))
+		}
+		for _, name := range cookieNames {
+			parts = append(parts, name+"="+cookies[name])
+		}
+		params.Set("headers[cookie]", strings.Join(parts, "; "))
+	}
+	return params, nil
+}
+
+// isHTTPToken reports whether s is an RFC 9110 token.
+func isHTTPToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// headerValue looks a header up case-insensitively.
+func headerValue(headers map[string]string, name string) (string, bool) {
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// APIParams returns the Scrape API parameters of the config, without the
+// API key, for inspecting or logging what a scrape sends. config is not
+// modified.
+//
+// They are the parameters of the config alone: Client.Scrape also applies
+// the domain defaults of the client (SetDomainDefaults), the Referer of
+// AutoReferer, and, in Debug mode without CorrelationID, a generated
+// correlation ID.
+func (c *ScrapeConfig) APIParams() (url.Values, error) {
+	config := c.Clone()
+	if err := config.processBody(); err != nil {
+		return nil, err
+	}
+	return config.toAPIParamsWithValidation()
+}
@@ -0,0 +1,102 @@
+package scrapfly
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestEncodeHeaderParams_RoundTrip(t *testing.T) {
+	headers := map[string]string{
+		"Accept":          "text/html, application/xhtml+xml;q=0.9, */*;q=0.8",
+		"X-Unicode":       "café 日本 ✓",
+		"X-Reserved":      "a=b&c=d+e%20f#frag?q",
+		"  X-Trimmed  ":   "value",
+		"Cookie":          "existing=1;",
+		"Accept-Language": "fr-CH, fr;q=0.9, en;q=0.8",
+	}
+	cookies := map[string]string{"b": "2", "a": "x%3By", "quoted": `"v"`, "raw": "a b,c café"}
+
+	params, err := EncodeHeaderParams(headers, cookies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := url.ParseQuery(params.Encode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"headers[accept]":          headers["Accept"],
+		"headers[x-unicode]":       headers["X-Unicode"],
+		"headers[x-reserved]":      headers["X-Reserved"],
+		"headers[x-trimmed]":       "value",
+		"headers[accept-language]": headers["Accept-Language"],
+		"headers[cookie]":          `existing=1; a=x%3By; b=2; quoted="v"; raw=a b,c café`,
+	}
+	if len(decoded) != len(want) {
+		t.Errorf("got %d params, want %d: %v", len(decoded), len(want), decoded)
+	}
+	for key, value := range want {
+		if got := decoded.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestEncodeHeaderParams_Rejects(t *testing.T) {
+	tests := map[string]struct {
+		headers map[string]string
+		cookies map[string]string
+	}{
+		"case duplicate":      {headers: map[string]string{"X-A": "1", "x-a": "2"}},
+		"empty value":         {headers: map[string]string{"X-A": ""}},
+		"line break":          {headers: map[string]string{"X-A": "1\r\nX-Injected: 2"}},
+		"invalid name":        {headers: map[string]string{"X A": "1"}},
+		"cookie semicolon":    {cookies: map[string]string{"a": "1; b=2"}},
+		"cookie line break":   {cookies: map[string]string{"a": "1\r\nX-Injected: 2"}},
+		"cookie NUL":          {cookies: map[string]string{"a": "1\x00"}},
+		"cookie invalid name": {cookies: map[string]string{"a=b": "1"}},
+		"cookie empty value":  {cookies: map[string]string{"a": ""}},
+	}
+	for name, tt := range tests {
+		if _, err := EncodeHeaderParams(tt.headers, tt.cookies); !errors.Is(err, ErrScrapeConfig) {
+			t.Errorf("%s: got %v, want ErrScrapeConfig", name, err)
+		}
+	}
+}
+
+func TestEncodeHeaderParams_CookieOrderIsStable(t *testing.T) {
+	cookies := map[string]string{"z": "1", "m": "2", "a": "3", "k": "4"}
+	first, _ := EncodeHeaderParams(nil, cookies)
+	for i := 0; i < 20; i++ {
+		again, _ := EncodeHeaderParams(nil, cookies)
+		if again.Encode() != first.Encode() {
+			t.Fatal("cookie header is not deterministic")
+		}
+	}
+	if got := first.Get("headers[cookie]"); got != "a=3; k=4; m=2; z=1" {
+		t.Errorf("cookie header = %q", got)
+	}
+}
+
+func TestScrapeConfig_APIParamsMatchesSentContentType(t *testing.T) {
+	config := &ScrapeConfig{
+		URL:     "https://example.com",
+		Method:  HttpMethodPost,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Data:    map[string]interface{}{"a": 1},
+	}
+	params, err := config.APIParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params["headers[content-type]"]; len(got) != 1 || got[0] != "application/json" {
+		t.Errorf("headers[content-type] = %v, want [application/json]", got)
+	}
+	if params.Has("key") {
+		t.Error("APIParams must not include the API key")
+	}
+	if config.Body != "" {
+		t.Error("APIParams modified the config")
+	}
+}
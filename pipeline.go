@@ -0,0 +1,221 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// PipelineItem is a page going through the stages of a Pipeline. Stages
+// read what the previous ones set and fill the rest.
+type PipelineItem struct {
+	// Config is the scrape config of the page.
+	Config *ScrapeConfig
+	// Result is the scrape result, set by the scrape stage.
+	Result *ScrapeResult
+	// Extraction is the result of ExtractStage.
+	Extraction *ExtractionResult
+	// Data is the data of the page, set by the transform and extract
+	// stages.
+	Data interface{}
+
+	// cost is the API credits spent by the scrape.
+	cost int
+}
+
+// PipelineStage is a step of a Pipeline, applied to each page once
+// scraped.
+type PipelineStage struct {
+	// Name identifies the stage in errors and PipelineMetrics.
+	Name string
+	// Run processes item. An error fails the item: the next stages are
+	// skipped.
+	Run func(ctx context.Context, item *PipelineItem) error
+	// Retryable stages are retried with the RetryPolicy of the run, like
+	// the scrape, when they fail with a retryable error.
+	Retryable bool
+}
+
+// TransformStage returns a stage running fn, to parse the scraped page,
+// filter it or reshape Data.
+func TransformStage(name string, fn func(ctx context.Context, item *PipelineItem) error) PipelineStage {
+	return PipelineStage{Name: name, Run: fn}
+}
+
+// ExtractStage returns a stage extracting data from the scraped page with
+// the Extraction API: template (prompt, model or extraction template) is
+// cloned with the page content, content type and URL. It sets Extraction
+// and Data.
+func (c *Client) ExtractStage(template *ExtractionConfig) PipelineStage {
+	return PipelineStage{Name: "extract", Retryable: true, Run: func(ctx context.Context, item *PipelineItem) error {
+		body, err := item.Result.Bytes()
+		if err != nil {
+			return err
+		}
+		config := template.Clone()
+		if config == nil {
+			config = &ExtractionConfig{}
+		}
+		config.Body = body
+		config.ContentType = firstNonEmpty(item.Result.Result.ContentType, "text/html")
+		config.URL = item.Result.FinalURL()
+		extraction, err := c.ExtractContext(ctx, config)
+		if err != nil {
+			return err
+		}
+		item.Extraction, item.Data = extraction, extraction.Data
+		return nil
+	}}
+}
+
+// SinkStage returns a stage writing the scrape result to sink.
+func SinkStage(sink Sink) PipelineStage {
+	return PipelineStage{Name: "sink", Run: func(_ context.Context, item *PipelineItem) error {
+		return sink.Write(item.Result)
+	}}
+}
+
+// Pipeline scrapes pages and runs them through stages (transform,
+// extract, store...) on the engine of ConcurrentScrapeWithOptions: a page
+// holds its worker from the scrape to the last stage, so the concurrency
+// limit and per-host limits bound the whole flow. It is safe to Run
+// several times, concurrently.
+//
+// Example — scrape, extract with AI, store as JSON lines:
+//
+//	sink, _ := scrapfly.CreateJSONLSink("out/products.jsonl")
+//	defer sink.Close()
+//	pipeline := client.NewPipeline(
+//	    client.ExtractStage(&scrapfly.ExtractionConfig{ExtractionModel: scrapfly.ExtractionModelProduct}),
+//	    scrapfly.TransformStage("validate", func(ctx context.Context, item *scrapfly.PipelineItem) error {
+//	        if item.Data == nil {
+//	            return errors.New("no product")
+//	        }
+//	        return nil
+//	    }),
+//	    scrapfly.SinkStage(sink),
+//	)
+//	metrics, err := pipeline.Run(ctx, configs, scrapfly.ConcurrentScrapeOptions{
+//	    Concurrency: 10,
+//	    Retry:       &scrapfly.RetryPolicy{MaxAttempts: 3},
+//	})
+type Pipeline struct {
+	client *Client
+	stages []PipelineStage
+}
+
+// PipelineMetrics reports how a Pipeline run ended.
+type PipelineMetrics struct {
+	// Summary is the summary of the run, where Succeeded counts the pages
+	// that went through every stage.
+	Summary ConcurrentScrapeSummary
+	// Stages are the metrics of the scrape stage, then of each stage in
+	// pipeline order.
+	Stages []PipelineStageMetrics
+}
+
+// PipelineStageMetrics reports the runs of a stage.
+type PipelineStageMetrics struct {
+	Name string
+	// Succeeded and Failed count the items the stage processed.
+	Succeeded int
+	Failed    int
+	// Duration is the time spent in the stage, all items and retries.
+	Duration time.Duration
+}
+
+// NewPipeline returns a pipeline running stages, in order, on the pages
+// it scrapes.
+func (c *Client) NewPipeline(stages ...PipelineStage) *Pipeline {
+	return &Pipeline{client: c, stages: stages}
+}
+
+// Run scrapes configs and runs each page through the stages, with the
+// concurrency, per-host limits, budget and progress of opts. opts.Retry is
+// applied to the scrape and to each Retryable stage on its own, so a
+// failed extraction doesn't scrape the page again.
+//
+// It returns the metrics and the failures joined (see errors.Join), each
+// carrying its URL and, past the scrape, its stage.
+func (p *Pipeline) Run(ctx context.Context, configs []*ScrapeConfig, opts ConcurrentScrapeOptions) (PipelineMetrics, error) {
+	policy := opts.Retry
+	opts.Retry = nil
+	var metrics PipelineMetrics
+	metrics.Stages = make([]PipelineStageMetrics, len(p.stages)+1)
+	metrics.Stages[0].Name = "scrape"
+	for i, stage := range p.stages {
+		metrics.Stages[i+1].Name = stage.Name
+	}
+	var mu sync.Mutex
+	record := func(stage int, started time.Time, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		m := &metrics.Stages[stage]
+		m.Duration += time.Since(started)
+		if err != nil {
+			m.Failed++
+		} else {
+			m.Succeeded++
+		}
+	}
+	onComplete := opts.OnComplete
+	opts.OnComplete = func(s ConcurrentScrapeSummary) {
+		metrics.Summary = s
+		if onComplete != nil {
+			onComplete(s)
+		}
+	}
+
+	type output struct {
+		config *ScrapeConfig
+		err    error
+	}
+	results := runConcurrent(p.client, ctx, configs, nil, opts, nil, concurrentTask[*ScrapeConfig, *PipelineItem, output]{
+		url:      func(config *ScrapeConfig) string { return config.URL },
+		priority: func(config *ScrapeConfig) int { return config.Priority },
+		run: func(ctx context.Context, config *ScrapeConfig) (*PipelineItem, error) {
+			// the scrape and stage retries log with one correlation ID
+			ctx, config = p.client.correlate(ctx, config)
+			item := &PipelineItem{Config: config}
+			started := time.Now()
+			result, _, cost, err := p.client.scrapeRetrying(ctx, config, policy)
+			item.Result, item.cost = result, cost
+			record(0, started, err)
+			if err != nil {
+				return item, err
+			}
+			for i, stage := range p.stages {
+				started := time.Now()
+				if stage.Retryable {
+					_, _, _, err = retrying(ctx, policy, config.URL, func(ctx context.Context) (struct{}, error) {
+						return struct{}{}, stage.Run(ctx, item)
+					}, func(struct{}, error) int { return 0 })
+				} else {
+					err = stage.Run(ctx, item)
+				}
+				record(i+1, started, err)
+				if err != nil {
+					return item, fmt.Errorf("%s stage failed: %w", stage.Name, err)
+				}
+			}
+			return item, nil
+		},
+		cost: func(item *PipelineItem, _ error) int { return item.cost },
+		item: func(_ *PipelineItem, config *ScrapeConfig, _, _ int, err error) output {
+			return output{config: config, err: err}
+		},
+	})
+
+	var errs []error
+	for out := range results {
+		switch {
+		case out.err != nil && out.config != nil:
+			errs = append(errs, fmt.Errorf("failed to process %s: %w", out.config.URL, out.err))
+		case out.err != nil:
+			errs = append(errs, out.err)
+		}
+	}
+	return metrics, errors.Join(errs...)
+}
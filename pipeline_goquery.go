@@ -0,0 +1,27 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import "context"
+
+// SelectStage returns a stage reading fields from the scraped HTML with
+// CSS selectors: Data is set to a map of each field name to the text of
+// the first element its selector matches, "" when none does. Non-HTML
+// pages fail the stage.
+//
+// Example:
+//
+//	scrapfly.SelectStage(map[string]string{"name": "h1", "price": ".product-price"})
+func SelectStage(fields map[string]string) PipelineStage {
+	return PipelineStage{Name: "select", Run: func(_ context.Context, item *PipelineItem) error {
+		if _, err := item.Result.Selector(); err != nil {
+			return err
+		}
+		data := make(map[string]string, len(fields))
+		for name, selector := range fields {
+			data[name] = item.Result.FindText(selector)
+		}
+		item.Data = data
+		return nil
+	}}
+}
@@ -0,0 +1,28 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import (
+	"context"
+	"testing"
+)
+
+func TestSelectStage(t *testing.T) {
+	item := &PipelineItem{Result: &ScrapeResult{Result: ResultData{
+		ContentType: "text/html",
+		Content:     `<html><body><h1> Box </h1><span class="price">$10</span></body></html>`,
+	}}}
+	stage := SelectStage(map[string]string{"name": "h1", "price": ".price", "sku": ".sku"})
+	if err := stage.Run(context.Background(), item); err != nil {
+		t.Fatal(err)
+	}
+	data := item.Data.(map[string]string)
+	if data["name"] != "Box" || data["price"] != "$10" || data["sku"] != "" {
+		t.Errorf("data = %v", data)
+	}
+
+	item = &PipelineItem{Result: &ScrapeResult{Result: ResultData{ContentType: "application/json", Content: `{}`}}}
+	if err := stage.Run(context.Background(), item); err == nil {
+		t.Error("SelectStage succeeded on a JSON page")
+	}
+}
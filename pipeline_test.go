@@ -0,0 +1,85 @@
+package scrapfly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPipeline_Run(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/extraction" {
+			body, _ := io.ReadAll(r.Body)
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"page": string(body)}, "content_type": "application/json"})
+			return
+		}
+		target := r.URL.Query().Get("url")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"context": map[string]interface{}{"cost": map[string]interface{}{"total": 2}},
+			"result":  map[string]interface{}{"success": true, "status": "DONE", "status_code": 200, "url": target, "content_type": "text/html", "content": "page " + target},
+		})
+	})
+
+	var flaky atomic.Int32
+	var mu sync.Mutex
+	var stored []string
+	pipeline := client.NewPipeline(
+		client.ExtractStage(&ExtractionConfig{ExtractionPrompt: "the page"}),
+		// the first run fails, then is retried
+		PipelineStage{Name: "flaky", Retryable: true, Run: func(_ context.Context, item *PipelineItem) error {
+			if flaky.Add(1) == 1 {
+				return ErrProxyFailed
+			}
+			if data, _ := item.Data.(map[string]interface{}); data["page"] != "page "+item.Config.URL {
+				return fmt.Errorf("extracted %v", item.Data)
+			}
+			return nil
+		}},
+		TransformStage("validate", func(_ context.Context, item *PipelineItem) error {
+			if strings.HasSuffix(item.Config.URL, "/bad") {
+				return errors.New("invalid page")
+			}
+			return nil
+		}),
+		SinkStage(SinkFunc(func(result *ScrapeResult) error {
+			mu.Lock()
+			defer mu.Unlock()
+			stored = append(stored, result.Result.URL)
+			return nil
+		})),
+	)
+	configs := []*ScrapeConfig{{URL: "https://example.com/1"}, {URL: "https://example.com/2"}, {URL: "https://example.com/bad"}}
+	metrics, err := pipeline.Run(context.Background(), configs, ConcurrentScrapeOptions{
+		Concurrency: 1,
+		Retry:       &RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond},
+	})
+	if err == nil || !strings.Contains(err.Error(), "https://example.com/bad") || !strings.Contains(err.Error(), "validate stage failed: invalid page") {
+		t.Errorf("err = %v", err)
+	}
+	if len(stored) != 2 {
+		t.Errorf("stored %v", stored)
+	}
+	if metrics.Summary.Succeeded != 2 || metrics.Summary.Failed != 1 || metrics.Summary.Cost != 6 {
+		t.Errorf("summary = %+v", metrics.Summary)
+	}
+	want := []PipelineStageMetrics{{Name: "scrape", Succeeded: 3}, {Name: "extract", Succeeded: 3}, {Name: "flaky", Succeeded: 3}, {Name: "validate", Succeeded: 2, Failed: 1}, {Name: "sink", Succeeded: 2}}
+	if len(metrics.Stages) != len(want) {
+		t.Fatalf("stages = %+v", metrics.Stages)
+	}
+	for i, m := range metrics.Stages {
+		if m.Name != want[i].Name || m.Succeeded != want[i].Succeeded || m.Failed != want[i].Failed {
+			t.Errorf("stage %d = %+v, want %+v", i, m, want[i])
+		}
+	}
+	if flaky.Load() != 4 {
+		t.Errorf("flaky stage ran %d times, want 4 with the retry", flaky.Load())
+	}
+}
@@ -0,0 +1,166 @@
+package scrapfly
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// PoolOptions configures a Pool.
+type PoolOptions struct {
+	// Workers is the number of scrapes the pool runs at once. When <= 0
+	// the account's concurrent limit is used.
+	Workers int
+	// Retry, when set, retries the transient failures of each config; see
+	// RetryPolicy.
+	Retry *RetryPolicy
+}
+
+// Pool is a long-lived set of scrape workers, created once and shared by
+// the scrapes and batches of a process, so that they all stay within the
+// same concurrency limit. A Pool is safe for concurrent use.
+type Pool struct {
+	client *Client
+	opts   PoolOptions
+	tasks  chan poolTask
+	// ctx aborts the scrapes in flight when Shutdown gives up waiting.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// poolTask is a config submitted to a Pool.
+type poolTask struct {
+	ctx    context.Context
+	config *ScrapeConfig
+	index  int
+	out    chan<- ConcurrentScrapeResult
+}
+
+// NewPool starts a pool of workers scraping with c. It fails only when
+// Workers is unset and the account lookup fails.
+//
+// Example:
+//
+//	pool, err := client.NewPool(scrapfly.PoolOptions{Workers: 10})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer pool.Shutdown(context.Background())
+//
+//	// from any goroutine, for the life of the process
+//	result, err := pool.Submit(ctx, config)
+func (c *Client) NewPool(opts PoolOptions) (*Pool, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		account, err := c.Account()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get account for concurrency limit: %w", err)
+		}
+		workers = max(account.Subscription.Usage.Scrape.ConcurrentLimit, 1)
+		DefaultLogger.Info("pool workers not provided - setting it to", workers, "from account info")
+	}
+	p := &Pool{client: c, opts: opts, tasks: make(chan poolTask)}
+	p.ctx, p.cancel = context.WithCancel(context.Background())
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	return p, nil
+}
+
+func (p *Pool) work() {
+	defer p.wg.Done()
+	for task := range p.tasks {
+		ctx, cancel := context.WithCancel(task.ctx)
+		stop := context.AfterFunc(p.ctx, cancel)
+		result, attempts, _, err := p.client.scrapeRetrying(ctx, task.config, p.opts.Retry)
+		stop()
+		cancel()
+		task.out <- ConcurrentScrapeResult{Result: result, Config: task.config, Index: task.index, Attempts: attempts, Err: err, Error: err}
+	}
+}
+
+// submit hands task to a worker, waiting for one to be free.
+func (p *Pool) submit(task poolTask) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPoolClosed
+	}
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-task.ctx.Done():
+		return task.ctx.Err()
+	}
+}
+
+// Submit scrapes config on a worker of the pool, waiting for one to be
+// free, and returns its result. It fails with ErrPoolClosed once the pool
+// is shut down.
+func (p *Pool) Submit(ctx context.Context, config *ScrapeConfig) (*ScrapeResult, error) {
+	out := make(chan ConcurrentScrapeResult, 1)
+	if err := p.submit(poolTask{ctx: ctx, config: config, out: out}); err != nil {
+		return nil, err
+	}
+	item := <-out
+	return item.Result, item.Err
+}
+
+// SubmitBatch scrapes configs on the workers of the pool, alongside the
+// other submissions, and streams the results in completion order; Index
+// is the position of the config in configs. The channel is closed once
+// every config is done. Configs not handed to a worker when ctx is done,
+// or the pool is shut down, are reported with that error.
+func (p *Pool) SubmitBatch(ctx context.Context, configs []*ScrapeConfig) <-chan ConcurrentScrapeResult {
+	out := make(chan ConcurrentScrapeResult, len(configs))
+	go func() {
+		var wg sync.WaitGroup
+		results := make(chan ConcurrentScrapeResult)
+		for i, config := range configs {
+			if err := p.submit(poolTask{ctx: ctx, config: config, index: i, out: results}); err != nil {
+				out <- ConcurrentScrapeResult{Config: config, Index: i, Err: err, Error: err}
+				continue
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				out <- <-results
+			}()
+		}
+		wg.Wait()
+		close(out)
+	}()
+	return out
+}
+
+// Shutdown stops accepting configs and waits for the submitted ones to
+// be scraped. When ctx is done first, the scrapes in flight are aborted
+// and Shutdown returns once the workers have exited, with ctx's error.
+func (p *Pool) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		p.cancel()
+		return nil
+	case <-ctx.Done():
+		p.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,96 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPool_SharesWorkersAcrossSubmissions(t *testing.T) {
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		maxInFlight = max(maxInFlight, inFlight)
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE","status_code":200}}`)
+	})
+	pool, err := client.NewPool(PoolOptions{Workers: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configs := make([]*ScrapeConfig, 6)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for b := 0; b < 2; b++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			seen := map[int]bool{}
+			for item := range pool.SubmitBatch(ctx, configs) {
+				if item.Err != nil {
+					t.Error(item.Err)
+				}
+				seen[item.Index] = true
+			}
+			if len(seen) != len(configs) {
+				t.Errorf("batch got results for %v", seen)
+			}
+		}()
+	}
+	if _, err := pool.Submit(ctx, &ScrapeConfig{URL: "https://example.com/single"}); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	if maxInFlight > 3 {
+		t.Errorf("%d scrapes in flight, want at most the 3 workers", maxInFlight)
+	}
+
+	if err := pool.Shutdown(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Submit(ctx, configs[0]); !errors.Is(err, ErrPoolClosed) {
+		t.Errorf("Submit after Shutdown: err = %v, want ErrPoolClosed", err)
+	}
+}
+
+func TestPool_ShutdownAbortsOnTimeout(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+	pool, err := client.NewPool(PoolOptions{Workers: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errc := make(chan error, 1)
+	go func() {
+		_, err := pool.Submit(context.Background(), &ScrapeConfig{URL: "https://example.com/slow"})
+		errc <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := pool.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() = %v, want the deadline error", err)
+	}
+	if err := <-errc; err == nil {
+		t.Error("the aborted scrape should fail")
+	}
+}
@@ -0,0 +1,193 @@
+package scrapfly
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var viewportRegex = regexp.MustCompile(`^[0-9]+x[0-9]+$`)
+
+// BrowserProfile bundles the emulation options of a device: the
+// fingerprint brand and OS the API supports, plus the user agent and
+// client-hint headers that describe the device to the target.
+//
+// The API generates Chromium fingerprints only, so profiles of other
+// browsers (ProfileMobileSafari) emulate the device through headers and
+// leave Brand empty. With ASP enabled the API may pick its own user agent.
+//
+// The built-in profiles (ProfileDesktopChrome...) return a new profile on
+// each call, so one can be modified without affecting the other configs.
+type BrowserProfile struct {
+	// Name identifies the profile ("desktop-chrome", "mobile-safari"...).
+	Name string
+	// Brand is sent as browser_brand unless ScrapeConfig.BrowserBrand is set.
+	Brand BrowserBrandName
+	// OS is sent as os unless ScrapeConfig.OS is set.
+	OS OperatingSystem
+	// UserAgent is sent as the user-agent header unless the config sets one.
+	UserAgent string
+	// Headers are sent unless the config sets the same header.
+	Headers map[string]string
+	// Viewport is the window size, e.g. "390x844". The Scrape API has no
+	// viewport parameter, so it is only applied by ScreenshotConfig, as
+	// its Resolution.
+	Viewport string
+	// ScaleFactor is the device pixel ratio of the device, 3 for an iPhone.
+	// The APIs render at a scale factor of 1, so captures are in CSS
+	// pixels: compare them with device screenshots downscaled by it.
+	ScaleFactor float64
+	// Mobile reports whether the profile describes a mobile device.
+	Mobile bool
+}
+
+// ProfileDesktopChrome returns the profile of Chrome on a Windows desktop.
+func ProfileDesktopChrome() *BrowserProfile {
+	return &BrowserProfile{
+		Name:      "desktop-chrome",
+		Brand:     BrowserChrome,
+		OS:        OSWindows,
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Headers: map[string]string{
+			"sec-ch-ua-mobile":   "?0",
+			"sec-ch-ua-platform": `"Windows"`,
+		},
+		Viewport:    "1920x1080",
+		ScaleFactor: 1,
+	}
+}
+
+// ProfileDesktopEdge returns the profile of Edge on a Windows desktop.
+func ProfileDesktopEdge() *BrowserProfile {
+	return &BrowserProfile{
+		Name:      "desktop-edge",
+		Brand:     BrowserEdge,
+		OS:        OSWindows,
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0",
+		Headers: map[string]string{
+			"sec-ch-ua-mobile":   "?0",
+			"sec-ch-ua-platform": `"Windows"`,
+		},
+		Viewport:    "1920x1080",
+		ScaleFactor: 1,
+	}
+}
+
+// ProfileMacChrome returns the profile of Chrome on a Mac.
+func ProfileMacChrome() *BrowserProfile {
+	return &BrowserProfile{
+		Name:      "mac-chrome",
+		Brand:     BrowserChrome,
+		OS:        OSMacOS,
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Headers: map[string]string{
+			"sec-ch-ua-mobile":   "?0",
+			"sec-ch-ua-platform": `"macOS"`,
+		},
+		Viewport:    "1440x900",
+		ScaleFactor: 2,
+	}
+}
+
+// ProfileMobileChrome returns the profile of Chrome on an Android phone
+// (Pixel 8).
+func ProfileMobileChrome() *BrowserProfile {
+	return &BrowserProfile{
+		Name:      "mobile-chrome",
+		Brand:     BrowserChrome,
+		UserAgent: "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36",
+		Headers: map[string]string{
+			"sec-ch-ua-mobile":   "?1",
+			"sec-ch-ua-platform": `"Android"`,
+		},
+		Viewport:    "412x915",
+		ScaleFactor: 2.625,
+		Mobile:      true,
+	}
+}
+
+// ProfileMobileSafari returns the profile of Safari on an iPhone (iPhone
+// 14), emulated through headers.
+func ProfileMobileSafari() *BrowserProfile {
+	return &BrowserProfile{
+		Name:        "mobile-safari",
+		UserAgent:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+		Viewport:    "390x844",
+		ScaleFactor: 3,
+		Mobile:      true,
+	}
+}
+
+// ProfileTabletChrome returns the profile of Chrome on an Android tablet.
+func ProfileTabletChrome() *BrowserProfile {
+	return &BrowserProfile{
+		Name:      "tablet-chrome",
+		Brand:     BrowserChrome,
+		UserAgent: "Mozilla/5.0 (Linux; Android 14; SM-X710) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		Headers: map[string]string{
+			"sec-ch-ua-mobile":   "?0",
+			"sec-ch-ua-platform": `"Android"`,
+		},
+		Viewport:    "800x1280",
+		ScaleFactor: 2,
+		Mobile:      true,
+	}
+}
+
+// ProfileTabletSafari returns the profile of Safari on an iPad, emulated
+// through headers.
+func ProfileTabletSafari() *BrowserProfile {
+	return &BrowserProfile{
+		Name:        "tablet-safari",
+		UserAgent:   "Mozilla/5.0 (iPad; CPU OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+		Viewport:    "820x1180",
+		ScaleFactor: 2,
+		Mobile:      true,
+	}
+}
+
+// ProfileIPhone14 is ProfileMobileSafari, which emulates an iPhone 14,
+// named after the device.
+func ProfileIPhone14() *BrowserProfile {
+	profile := ProfileMobileSafari()
+	profile.Name = "iphone-14"
+	return profile
+}
+
+// ProfilePixel7 is ProfileMobileChrome with the user agent of a Pixel 7,
+// which shares the Pixel 8 screen.
+func ProfilePixel7() *BrowserProfile {
+	profile := ProfileMobileChrome()
+	profile.Name = "pixel-7"
+	profile.UserAgent = "Mozilla/5.0 (Linux; Android 14; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36"
+	return profile
+}
+
+// validate checks the profile enum values.
+func (p *BrowserProfile) validate() error {
+	if p.Brand != "" && !p.Brand.IsValid() {
+		return fmt.Errorf("%w: invalid profile browser brand %q", ErrScrapeConfig, string(p.Brand))
+	}
+	if p.OS != "" && !p.OS.IsValid() {
+		return fmt.Errorf("%w: invalid profile OS %q", ErrScrapeConfig, string(p.OS))
+	}
+	if p.Viewport != "" && !viewportRegex.MatchString(p.Viewport) {
+		return fmt.Errorf("%w: invalid profile viewport %q, expected WIDTHxHEIGHT", ErrScrapeConfig, p.Viewport)
+	}
+	return nil
+}
+
+// headers returns the profile headers, user agent included, that are not
+// already in headers.
+func (p *BrowserProfile) headers(headers map[string]string) map[string]string {
+	out := make(map[string]string, len(p.Headers)+1)
+	if p.UserAgent != "" && !hasHeader(headers, "user-agent") {
+		out["user-agent"] = p.UserAgent
+	}
+	for k, v := range p.Headers {
+		if !hasHeader(headers, k) {
+			out[strings.ToLower(k)] = v
+		}
+	}
+	return out
+}
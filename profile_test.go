@@ -0,0 +1,143 @@
+package scrapfly
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestScrapeConfig_ProfileParams(t *testing.T) {
+	cfg := &ScrapeConfig{URL: "https://example.com", Profile: ProfileDesktopChrome()}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"browser_brand":               "chrome",
+		"os":                          "win",
+		"headers[user-agent]":         ProfileDesktopChrome().UserAgent,
+		"headers[sec-ch-ua-mobile]":   "?0",
+		"headers[sec-ch-ua-platform]": `"Windows"`,
+	}
+	for k, v := range want {
+		if got := params.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+}
+
+func TestBrowserProfile_PresetsAreCopies(t *testing.T) {
+	profile := ProfileDesktopChrome()
+	profile.Headers["sec-ch-ua-platform"] = `"Linux"`
+	if got := ProfileDesktopChrome().Headers["sec-ch-ua-platform"]; got != `"Windows"` {
+		t.Errorf("modifying a preset changed the next one: %s", got)
+	}
+	device := DeviceAndroidPhone()
+	device.Browser.Headers["sec-ch-ua-mobile"] = "?0"
+	if got := ProfileMobileChrome().Headers["sec-ch-ua-mobile"]; got != "?1" {
+		t.Errorf("modifying a device changed its profile preset: %s", got)
+	}
+	if name := ProfileIPhone14().Name; name != "iphone-14" || ProfileMobileSafari().Name != "mobile-safari" {
+		t.Errorf("iPhone 14 preset named %q", name)
+	}
+}
+
+func TestScrapeConfig_ExplicitOptionsOverrideProfile(t *testing.T) {
+	cfg := &ScrapeConfig{
+		URL:          "https://example.com",
+		Profile:      ProfileDesktopChrome(),
+		OS:           OSLinux,
+		BrowserBrand: "brave",
+		Headers:      map[string]string{"User-Agent": "custom"},
+	}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params.Get("os"); got != "linux" {
+		t.Errorf("os = %q, want linux", got)
+	}
+	if got := params.Get("browser_brand"); got != "brave" {
+		t.Errorf("browser_brand = %q, want brave", got)
+	}
+	if got := params["headers[user-agent]"]; len(got) != 1 || got[0] != "custom" {
+		t.Errorf("headers[user-agent] = %v, want [custom]", got)
+	}
+}
+
+func TestScrapeConfig_MobileSafariProfileSendsNoBrand(t *testing.T) {
+	cfg := &ScrapeConfig{URL: "https://example.com", Profile: ProfileMobileSafari()}
+	params, err := cfg.toAPIParamsWithValidation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.Has("browser_brand") || params.Has("os") {
+		t.Errorf("safari profile should not send a Chromium brand or OS, got %v", params)
+	}
+	if params.Get("headers[user-agent]") != ProfileMobileSafari().UserAgent {
+		t.Errorf("user agent = %q", params.Get("headers[user-agent]"))
+	}
+}
+
+func TestScrapeConfig_InvalidProfile(t *testing.T) {
+	for _, profile := range []BrowserProfile{
+		{Brand: "firefox"},
+		{OS: "ios"},
+		{Viewport: "large"},
+	} {
+		cfg := &ScrapeConfig{URL: "https://example.com", Profile: &profile}
+		if _, err := cfg.toAPIParamsWithValidation(); !errors.Is(err, ErrScrapeConfig) {
+			t.Errorf("profile %+v: got %v, want ErrScrapeConfig", profile, err)
+		}
+	}
+}
+
+func TestScreenshotConfig_ProfileViewport(t *testing.T) {
+	cfg := &ScreenshotConfig{URL: "https://example.com", Profile: ProfileMobileSafari()}
+	params, err := cfg.toAPIParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params.Get("resolution"); got != "390x844" {
+		t.Errorf("resolution = %q, want 390x844", got)
+	}
+	cfg.Resolution = "800x600"
+	params, _ = cfg.toAPIParams()
+	if got := params.Get("resolution"); got != "800x600" {
+		t.Errorf("resolution = %q, want explicit 800x600", got)
+	}
+}
+
+func TestScreenshotConfig_DeviceAndColorScheme(t *testing.T) {
+	cfg := &ScreenshotConfig{URL: "https://example.com", Device: DevicePixel7().In("de-DE"), ColorScheme: ColorSchemeDark, Options: []ScreenshotOption{OptionBlockBanners}}
+	params, err := cfg.toAPIParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"resolution": "412x915", "country": "de", "options": "block_banners,dark_mode"}
+	for k, v := range want {
+		if got := params.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+	if len(cfg.Options) != 1 {
+		t.Errorf("config options modified: %v", cfg.Options)
+	}
+	if DeviceIPhone14().Browser.Viewport != "390x844" || DeviceIPhone14().Browser.ScaleFactor != 3 {
+		t.Errorf("iPhone 14 preset = %+v", DeviceIPhone14().Browser)
+	}
+
+	params, _ = (&ScreenshotConfig{URL: "https://example.com", ColorScheme: ColorSchemeDark, Options: []ScreenshotOption{OptionDarkMode}}).toAPIParams()
+	if got := params.Get("options"); got != "dark_mode" {
+		t.Errorf("options = %q, want dark_mode once", got)
+	}
+
+	for _, bad := range []*ScreenshotConfig{
+		{URL: "https://example.com", Device: DeviceIPhone14(), Profile: ProfileIPhone14()},
+		{URL: "https://example.com", ColorScheme: "sepia"},
+		{URL: "https://example.com", ColorScheme: ColorSchemeLight, Options: []ScreenshotOption{OptionDarkMode}},
+	} {
+		if _, err := bad.toAPIParams(); !errors.Is(err, ErrScreenshotConfig) {
+			t.Errorf("%+v: got %v, want ErrScreenshotConfig", bad, err)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package scrapfly
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// refererIdleTTL is how long a session's RefererTracker is kept by the
+// client after its last use; idle trackers are evicted at most once per
+// refererIdleTTL, on the next AutoReferer scrape.
+const refererIdleTTL = 30 * time.Minute
+
+// refererMaxLinks is the number of links a RefererTracker keeps the
+// referer of; the links discovered first are forgotten first.
+const refererMaxLinks = 10000
+
+// RefererTracker keeps a navigation graph of visited pages and the links
+// discovered on them, so follow-up requests can carry the Referer a real
+// browser would send: the page the target was linked from, falling back to
+// the last page visited.
+//
+// A tracker is used automatically by Client.Scrape for sessions with
+// ScrapeConfig.AutoReferer; it can also be used directly when driving
+// navigation by hand. It is safe for concurrent use.
+//
+// The tracker keeps the referers of the last 10000 links discovered, so a
+// long session doesn't grow it without bound; a forgotten link gets the
+// last page visited.
+type RefererTracker struct {
+	mu         sync.Mutex
+	linkedFrom map[string]string
+	// order holds the keys of linkedFrom in discovery order, for eviction.
+	order []string
+	last  string
+	// used is the last time, in Unix nanoseconds, the client handed the
+	// tracker out for its session.
+	used atomic.Int64
+}
+
+// NewRefererTracker creates an empty RefererTracker.
+func NewRefererTracker() *RefererTracker {
+	return &RefererTracker{linkedFrom: make(map[string]string)}
+}
+
+// Observe records a visit to pageURL and the links found on it. Relative
+// links are resolved against pageURL. The first page a link is discovered
+// on is kept as its referer.
+func (t *RefererTracker) Observe(pageURL string, links []string) {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last = refererKey(base)
+	for _, link := range links {
+		ref, err := base.Parse(strings.TrimSpace(link))
+		if err != nil || (ref.Scheme != "http" && ref.Scheme != "https") {
+			continue
+		}
+		key := refererKey(ref)
+		if _, seen := t.linkedFrom[key]; !seen && key != t.last {
+			if len(t.order) >= refererMaxLinks {
+				delete(t.linkedFrom, t.order[0])
+				t.order = t.order[1:]
+			}
+			t.linkedFrom[key] = t.last
+			t.order = append(t.order, key)
+		}
+	}
+}
+
+// ObserveResult records a scrape result: its final URL and the <a href>
+// links of its HTML content. Non-HTML results only update the last visited
+// page.
+func (t *RefererTracker) ObserveResult(result *ScrapeResult) {
+	pageURL := result.Result.URL
+	if pageURL == "" {
+		pageURL = result.Config.URL
+	}
+	t.Observe(pageURL, htmlLinks(result))
+}
+
+// Referer returns the Referer to send when navigating to target, or "" when
+// nothing has been observed yet.
+func (t *RefererTracker) Referer(target string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if u, err := url.Parse(target); err == nil {
+		if from, ok := t.linkedFrom[refererKey(u)]; ok {
+			return from
+		}
+	}
+	return t.last
+}
+
+// refererKey normalizes a URL for the navigation graph. Fragments are never
+// sent in a Referer header, so they are dropped.
+func refererKey(u *url.URL) string {
+	clean := *u
+	clean.Fragment = ""
+	clean.RawFragment = ""
+	return clean.String()
+}
+
+// RefererTracker returns the navigation graph the client keeps for session
+// when ScrapeConfig.AutoReferer is used, creating it on first use. It can be
+// seeded with Observe, for example with the entry page visitors come from.
+//
+// Trackers left unused for 30 minutes are dropped, and a session scraped
+// again afterwards starts from an empty graph; ReleaseRefererTracker drops
+// one as soon as its session is done.
+func (c *Client) RefererTracker(session string) *RefererTracker {
+	now := time.Now().UnixNano()
+	c.evictIdleReferers(now)
+	value, ok := c.referers.Load(session)
+	if !ok {
+		value, _ = c.referers.LoadOrStore(session, NewRefererTracker())
+	}
+	tracker := value.(*RefererTracker)
+	tracker.used.Store(now)
+	return tracker
+}
+
+// ReleaseRefererTracker drops the navigation graph kept for session, if any.
+func (c *Client) ReleaseRefererTracker(session string) {
+	c.referers.Delete(session)
+}
+
+// evictIdleReferers drops the trackers unused for refererIdleTTL, sweeping
+// at most once per refererIdleTTL.
+func (c *Client) evictIdleReferers(now int64) {
+	last := c.referersSwept.Load()
+	if now-last < int64(refererIdleTTL) || !c.referersSwept.CompareAndSwap(last, now) {
+		return
+	}
+	c.referers.Range(func(session, value any) bool {
+		if now-value.(*RefererTracker).used.Load() > int64(refererIdleTTL) {
+			c.referers.CompareAndDelete(session, value)
+		}
+		return true
+	})
+}
+
+// hasHeader reports whether headers contain name, case-insensitively.
+func hasHeader(headers map[string]string, name string) bool {
+	for key := range headers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package scrapfly
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRefererTracker_PrefersDiscoveringPage(t *testing.T) {
+	tracker := NewRefererTracker()
+	if got := tracker.Referer("https://example.com/"); got != "" {
+		t.Errorf("empty tracker returned %q", got)
+	}
+
+	tracker.Observe("https://example.com/", []string{"/products", "https://example.com/about#team", "mailto:x@example.com"})
+	tracker.Observe("https://example.com/products", []string{"/products/1", "/about"})
+
+	cases := map[string]string{
+		"https://example.com/products/1": "https://example.com/products",
+		"https://example.com/about":      "https://example.com/",
+		"https://example.com/unknown":    "https://example.com/products",
+	}
+	for target, want := range cases {
+		if got := tracker.Referer(target); got != want {
+			t.Errorf("Referer(%q) = %q, want %q", target, got, want)
+		}
+	}
+}
+
+func TestRefererTracker_BoundsLinks(t *testing.T) {
+	tracker := NewRefererTracker()
+	links := make([]string, refererMaxLinks+10)
+	for i := range links {
+		links[i] = fmt.Sprintf("/p/%d", i)
+	}
+	tracker.Observe("https://example.com/", links)
+	tracker.Observe("https://example.com/other", nil)
+
+	if n := len(tracker.linkedFrom); n != refererMaxLinks {
+		t.Errorf("tracker keeps %d links, want %d", n, refererMaxLinks)
+	}
+	if got := tracker.Referer("https://example.com/p/0"); got != "https://example.com/other" {
+		t.Errorf("evicted link referer = %q, want the last page", got)
+	}
+	if got := tracker.Referer(fmt.Sprintf("https://example.com/p/%d", len(links)-1)); got != "https://example.com/" {
+		t.Errorf("recent link referer = %q, want the discovering page", got)
+	}
+}
+
+func TestClient_Scrape_AutoRefererFollowsSessionGraph(t *testing.T) {
+	referers := make(chan string, 2)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		referers <- r.URL.Query().Get("headers[referer]")
+		content := `<html><body><a href=\"/next\">next</a></body></html>`
+		fmt.Fprintf(w, `{"result":{"success":true,"status":"DONE","url":%q,"content_type":"text/html","content":"%s"}}`, r.URL.Query().Get("url"), content)
+	})
+
+	for _, target := range []string{"https://example.com/start", "https://example.com/next"} {
+		if _, err := client.Scrape(&ScrapeConfig{URL: target, Session: "s1", AutoReferer: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := <-referers; got != "" {
+		t.Errorf("first request of a session must not carry a referer, got %q", got)
+	}
+	if got := <-referers; got != "https://example.com/start" {
+		t.Errorf("expected referer from the discovering page, got %q", got)
+	}
+
+	if _, err := (&ScrapeConfig{URL: "https://example.com", AutoReferer: true}).toAPIParamsWithValidation(); err == nil {
+		t.Error("AutoReferer without Session must be rejected")
+	}
+}
+
+func TestClient_RefererTrackerEviction(t *testing.T) {
+	client := &Client{}
+	idle := client.RefererTracker("idle")
+	idle.Observe("https://example.com/", nil)
+	active := client.RefererTracker("active")
+
+	stale := time.Now().Add(-2 * refererIdleTTL).UnixNano()
+	idle.used.Store(stale)
+	client.referersSwept.Store(stale)
+	if client.RefererTracker("active") != active {
+		t.Error("active tracker evicted")
+	}
+	if got := client.RefererTracker("idle"); got == idle || got.Referer("https://example.com/a") != "" {
+		t.Error("idle tracker kept")
+	}
+
+	client.ReleaseRefererTracker("active")
+	if client.RefererTracker("active") == active {
+		t.Error("released tracker kept")
+	}
+}
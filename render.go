@@ -0,0 +1,60 @@
+package scrapfly
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// HTMLRenderer renders an HTML document to an image locally, without a
+// Screenshot API call. Implementations typically drive a local headless
+// browser; see the contrib/chromedp module for a chromedp-based adapter.
+//
+// RenderHTML receives the full document (with a <base> element pointing at
+// the scraped URL so relative assets resolve) and returns the encoded image
+// bytes (PNG or JPEG, at the implementation's discretion).
+type HTMLRenderer interface {
+	RenderHTML(ctx context.Context, document string) ([]byte, error)
+}
+
+var headOpenRegex = regexp.MustCompile(`(?i)<head(\s[^>]*)?>`)
+var baseTagRegex = regexp.MustCompile(`(?i)<base\s`)
+
+// RenderImage renders the scraped HTML to an image using renderer, for
+// archival thumbnails of stored results. The rendering happens locally so
+// it is not billed; it reflects the HTML as returned by the API (post-JS
+// when RenderJS was used), although external assets are fetched again by
+// the local renderer.
+//
+// Example:
+//
+//	img, err := result.RenderImage(ctx, chromedprender.New())
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	os.WriteFile("thumbnail.png", img, 0644)
+func (r *ScrapeResult) RenderImage(ctx context.Context, renderer HTMLRenderer) ([]byte, error) {
+	if renderer == nil {
+		return nil, fmt.Errorf("scrapfly: renderer is nil")
+	}
+	if !strings.Contains(r.Result.ContentType, "text/html") {
+		return nil, fmt.Errorf("%w: cannot render non-html content-type, got %s", ErrContentType, r.Result.ContentType)
+	}
+	return renderer.RenderHTML(ctx, withBaseURL(r.Result.Content, r.Result.URL))
+}
+
+// withBaseURL injects <base href="baseURL"> right after <head> so that a
+// document loaded outside its origin still resolves relative URLs. Documents
+// that already declare a base, or without a base URL, are returned as-is.
+func withBaseURL(document, baseURL string) string {
+	if baseURL == "" || baseTagRegex.MatchString(document) {
+		return document
+	}
+	baseTag := `<base href="` + html.EscapeString(baseURL) + `">`
+	if loc := headOpenRegex.FindStringIndex(document); loc != nil {
+		return document[:loc[1]] + baseTag + document[loc[1]:]
+	}
+	return baseTag + document
+}
@@ -0,0 +1,65 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type recordingRenderer struct {
+	document string
+}
+
+func (r *recordingRenderer) RenderHTML(ctx context.Context, document string) ([]byte, error) {
+	r.document = document
+	return []byte("png"), nil
+}
+
+func TestScrapeResult_RenderImageInjectsBaseURL(t *testing.T) {
+	renderer := &recordingRenderer{}
+	result := &ScrapeResult{}
+	result.Result.ContentType = "text/html; charset=utf-8"
+	result.Result.URL = "https://example.com/a/b"
+	result.Result.Content = "<html><head><title>x</title></head><body></body></html>"
+
+	img, err := result.RenderImage(context.Background(), renderer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(img) != "png" {
+		t.Errorf("unexpected image bytes %q", img)
+	}
+	if !strings.HasPrefix(renderer.document, `<html><head><base href="https://example.com/a/b">`) {
+		t.Errorf("base element not injected after <head>: %s", renderer.document)
+	}
+
+	result.Result.Content = `<html><head><base href="/"></head></html>`
+	if _, err := result.RenderImage(context.Background(), renderer); err != nil {
+		t.Fatal(err)
+	}
+	if renderer.document != result.Result.Content {
+		t.Errorf("existing base element must be preserved, got %s", renderer.document)
+	}
+}
+
+func TestScrapeResult_RenderImageRejectsNonHTML(t *testing.T) {
+	result := &ScrapeResult{}
+	result.Result.ContentType = "application/json"
+	if _, err := result.RenderImage(context.Background(), &recordingRenderer{}); !errors.Is(err, ErrContentType) {
+		t.Errorf("expected ErrContentType, got %v", err)
+	}
+}
+
+func TestWithBaseURL_SkipsHeaderElement(t *testing.T) {
+	const base = `<base href="https://example.com/">`
+	for document, want := range map[string]string{
+		`<body><header>nav</header></body>`:             base + `<body><header>nav</header></body>`,
+		`<html><head lang="en"><title>t</title></head>`: `<html><head lang="en">` + base + `<title>t</title></head>`,
+		`<HEAD><header></header>`:                       `<HEAD>` + base + `<header></header>`,
+	} {
+		if got := withBaseURL(document, "https://example.com/"); got != want {
+			t.Errorf("withBaseURL(%q) = %q, want %q", document, got, want)
+		}
+	}
+}
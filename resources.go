@@ -0,0 +1,68 @@
+package scrapfly
+
+import "fmt"
+
+// BlockAllMedia returns the resource types of images, audio and video, a
+// preset for CloudBrowserConfig.BlockResources.
+func BlockAllMedia() []ResourceType {
+	return []ResourceType{ResourceImages, ResourceMedia}
+}
+
+// BlockHeavyAssets returns every resource type that is not needed to read
+// the page content: images, media, stylesheets and fonts. It is a preset
+// for CloudBrowserConfig.BlockResources.
+func BlockHeavyAssets() []ResourceType {
+	return []ResourceType{ResourceImages, ResourceMedia, ResourceStyles, ResourceFonts}
+}
+
+// BlockResources blocks the given resource types from loading in the
+// session, cutting page load time and bandwidth. It sets the matching
+// Block* fields and fails on unknown types.
+//
+// There is no ScrapeConfig counterpart: the Scrape API has no parameter
+// to block resource types when it renders a page (the blocking fields are
+// options of the Cloud Browser websocket URL), so this applies to Cloud
+// Browser sessions only. Unknown types fail with ErrCloudBrowserConfig.
+//
+// Example:
+//
+//	config := &scrapfly.CloudBrowserConfig{ProxyPool: "public_datacenter_pool"}
+//	if err := config.BlockResources(scrapfly.BlockAllMedia()...); err != nil {
+//	    log.Fatal(err)
+//	}
+func (c *CloudBrowserConfig) BlockResources(types ...ResourceType) error {
+	for _, t := range types {
+		switch t {
+		case ResourceImages:
+			c.BlockImages = true
+		case ResourceStyles:
+			c.BlockStyles = true
+		case ResourceFonts:
+			c.BlockFonts = true
+		case ResourceMedia:
+			c.BlockMedia = true
+		default:
+			return fmt.Errorf("%w: invalid resource type %q, expected one of %v", ErrCloudBrowserConfig, string(t), t.Enum())
+		}
+	}
+	return nil
+}
+
+// BlockedResources returns the resource types the session blocks.
+func (c *CloudBrowserConfig) BlockedResources() []ResourceType {
+	var types []ResourceType
+	for _, blocked := range []struct {
+		on bool
+		t  ResourceType
+	}{
+		{c.BlockImages, ResourceImages},
+		{c.BlockStyles, ResourceStyles},
+		{c.BlockFonts, ResourceFonts},
+		{c.BlockMedia, ResourceMedia},
+	} {
+		if blocked.on {
+			types = append(types, blocked.t)
+		}
+	}
+	return types
+}
@@ -0,0 +1,33 @@
+package scrapfly
+
+import (
+	"errors"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCloudBrowserConfig_BlockResources(t *testing.T) {
+	config := &CloudBrowserConfig{}
+	if err := config.BlockResources(BlockAllMedia()...); err != nil {
+		t.Fatal(err)
+	}
+	if got := config.BlockedResources(); !slices.Equal(got, []ResourceType{ResourceImages, ResourceMedia}) {
+		t.Errorf("BlockedResources() = %v", got)
+	}
+
+	client, _ := New("__API_KEY__")
+	wsURL, err := url.Parse(client.CloudBrowser(config))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := wsURL.Query()
+	if q.Get("block_images") != "true" || q.Get("block_media") != "true" || q.Has("block_fonts") {
+		t.Errorf("unexpected blocking params %s", wsURL.RawQuery)
+	}
+
+	if err := config.BlockResources("scripts"); !errors.Is(err, ErrCloudBrowserConfig) || !strings.Contains(err.Error(), "scripts") {
+		t.Errorf("got %v, want an invalid resource type error", err)
+	}
+}
@@ -0,0 +1,122 @@
+package scrapfly
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// resultArchiveVersion is the version of the file format written by
+// ScrapeResult.Save.
+const resultArchiveVersion = 1
+
+// resultArchive is the archive metadata ScrapeResult.Save adds to the JSON
+// form of the result.
+type resultArchive struct {
+	Version int       `json:"scrapfly_result_version"`
+	SavedAt time.Time `json:"saved_at"`
+}
+
+// Save writes the full result, metadata and content, as JSON to path,
+// gzip-compressed when path ends with ".gz". Parent directories are
+// created. Reload it with LoadResult to run parsers offline.
+//
+// Example:
+//
+//	result, _ := client.Scrape(config)
+//	if err := result.Save("archive/product-1.json.gz"); err != nil {
+//	    log.Fatal(err)
+//	}
+func (r *ScrapeResult) Save(path string) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("failed to write result to %s: %w", path, err)
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return fmt.Errorf("failed to write result to %s: %w", path, err)
+	}
+	data, err = marshalWithExtra(resultArchive{Version: resultArchiveVersion, SavedAt: time.Now().UTC()}, members)
+	if err != nil {
+		return fmt.Errorf("failed to write result to %s: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	var w io.Writer = f
+	var zw *gzip.Writer
+	if strings.HasSuffix(path, ".gz") {
+		zw = gzip.NewWriter(f)
+		w = zw
+	}
+	if _, err := w.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write result to %s: %w", path, err)
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// LoadResult reads a result written by ScrapeResult.Save. Files ending
+// with ".gz" are decompressed.
+//
+// Example:
+//
+//	result, err := scrapfly.LoadResult("archive/product-1.json.gz")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	doc, _ := result.Selector()
+func LoadResult(path string) (*ScrapeResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		zr, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read result from %s: %w", path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read result from %s: %w", path, err)
+	}
+	var archive resultArchive
+	if err := json.Unmarshal(data, &archive); err != nil {
+		return nil, fmt.Errorf("failed to read result from %s: %w", path, err)
+	}
+	if archive.Version > resultArchiveVersion {
+		return nil, fmt.Errorf("result file %s has version %d, this SDK reads up to %d", path, archive.Version, resultArchiveVersion)
+	}
+	var result ScrapeResult
+	if err := result.UnmarshalJSON(data); err != nil {
+		return nil, fmt.Errorf("failed to read result from %s: %w", path, err)
+	}
+	delete(result.extra, "scrapfly_result_version")
+	delete(result.extra, "saved_at")
+	if len(result.extra) == 0 {
+		result.extra = nil
+	}
+	return &result, nil
+}
@@ -0,0 +1,59 @@
+package scrapfly
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScrapeResult_SaveLoadResult(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"result.json", "nested/result.json.gz"} {
+		result := &ScrapeResult{UUID: "uuid-1"}
+		result.Result.URL = "https://example.com"
+		result.Result.Content = "<html>hello</html>"
+		result.Result.StatusCode = 200
+		result.Context.Cost.Total = 3
+
+		path := filepath.Join(dir, name)
+		if err := result.Save(path); err != nil {
+			t.Fatalf("%s: Save() error: %v", name, err)
+		}
+		loaded, err := LoadResult(path)
+		if err != nil {
+			t.Fatalf("%s: LoadResult() error: %v", name, err)
+		}
+		if loaded.UUID != "uuid-1" || loaded.Result.Content != result.Result.Content ||
+			loaded.Result.StatusCode != 200 || loaded.Context.Cost.Total != 3 {
+			t.Errorf("%s: unexpected loaded result %+v", name, loaded)
+		}
+	}
+}
+
+func TestScrapeResult_SaveBlob(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "blob.json")
+	result := &ScrapeResult{rawContent: []byte{0x25, 0x50, 0x44, 0x46, 0x00, 0xff}}
+	if err := result.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadResult(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := loaded.Bytes()
+	if err != nil || string(data) != string(result.rawContent) {
+		t.Errorf("Bytes() = %v, %v", data, err)
+	}
+}
+
+func TestLoadResult_Errors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadResult(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+	future := filepath.Join(dir, "future.json")
+	os.WriteFile(future, []byte(`{"scrapfly_result_version": 99}`), 0644)
+	if _, err := LoadResult(future); err == nil {
+		t.Error("expected an error for an unknown version")
+	}
+}
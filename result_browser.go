@@ -0,0 +1,295 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// XHRCall is a background XHR or fetch call captured while rendering the
+// page (ScrapeConfig.RenderJS), often the quickest way to find the hidden
+// API behind a page.
+type XHRCall struct {
+	// URL is the requested URL.
+	URL string
+	// Method is the HTTP method.
+	Method string
+	// Type is the resource type, "xhr" or "fetch".
+	Type string
+	// Headers are the request headers.
+	Headers map[string]string
+	// Body is the request body.
+	Body string
+	// Response is the captured response.
+	Response XHRResponse
+}
+
+// XHRResponse is the response of a captured XHR call.
+type XHRResponse struct {
+	// Status is the HTTP status code.
+	Status int
+	// Headers are the response headers.
+	Headers map[string]string
+	// Body is the response body.
+	Body string
+}
+
+// UnmarshalJSON decodes a captured call, accepting header values and
+// bodies of any JSON type.
+func (x *XHRCall) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		URL          string                 `json:"url"`
+		Method       string                 `json:"method"`
+		Type         string                 `json:"type"`
+		ResourceType string                 `json:"resource_type"`
+		Headers      map[string]interface{} `json:"headers"`
+		Body         interface{}            `json:"body"`
+		Response     struct {
+			Status  int                    `json:"status"`
+			Headers map[string]interface{} `json:"headers"`
+			Body    interface{}            `json:"body"`
+		} `json:"response"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*x = XHRCall{
+		URL:     raw.URL,
+		Method:  strings.ToUpper(raw.Method),
+		Type:    raw.Type,
+		Headers: stringifyMap(raw.Headers),
+		Body:    stringifyValue(raw.Body),
+		Response: XHRResponse{
+			Status:  raw.Response.Status,
+			Headers: stringifyMap(raw.Response.Headers),
+			Body:    stringifyValue(raw.Response.Body),
+		},
+	}
+	if x.Type == "" {
+		x.Type = raw.ResourceType
+	}
+	return nil
+}
+
+// DecodeJSON unmarshals the JSON response body into v.
+func (x *XHRCall) DecodeJSON(v interface{}) error {
+	if err := json.Unmarshal([]byte(x.Response.Body), v); err != nil {
+		return fmt.Errorf("failed to decode XHR response of %s: %w", x.URL, err)
+	}
+	return nil
+}
+
+// XHRCalls returns the XHR and fetch calls captured while rendering, or nil
+// when none were captured.
+//
+// Example:
+//
+//	calls, _ := result.XHRCalls()
+//	for _, call := range calls {
+//	    if strings.Contains(call.URL, "/api/reviews") {
+//	        var reviews []Review
+//	        if err := call.DecodeJSON(&reviews); err == nil {
+//	            fmt.Println(len(reviews), "reviews")
+//	        }
+//	    }
+//	}
+func (r *ScrapeResult) XHRCalls() ([]XHRCall, error) {
+	var calls []XHRCall
+	if _, err := remarshal(r.Result.BrowserData.XHRCall, &calls); err != nil {
+		return nil, err
+	}
+	return calls, nil
+}
+
+// WebSocketDirection tells whether a frame was sent or received by the page.
+type WebSocketDirection string
+
+// Available WebSocket frame directions.
+const (
+	WebSocketSent     WebSocketDirection = "sent"
+	WebSocketReceived WebSocketDirection = "received"
+)
+
+// WebSocket is a WebSocket connection opened by the page while rendering,
+// with the frames exchanged on it.
+type WebSocket struct {
+	// URL is the WebSocket URL.
+	URL string
+	// Frames are the recorded frames, in the order they were exchanged.
+	Frames []WebSocketFrame
+}
+
+// WebSocketFrame is a recorded WebSocket message.
+type WebSocketFrame struct {
+	// Direction is WebSocketSent or WebSocketReceived.
+	Direction WebSocketDirection
+	// Timestamp is when the frame was exchanged; zero when not recorded.
+	Timestamp time.Time
+	// Payload is the frame payload.
+	Payload string
+}
+
+// UnmarshalJSON decodes a recorded connection, accepting "messages" or
+// "frames" lists.
+func (w *WebSocket) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		URL      string           `json:"url"`
+		Messages []WebSocketFrame `json:"messages"`
+		Frames   []WebSocketFrame `json:"frames"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	w.URL = raw.URL
+	w.Frames = append(raw.Messages, raw.Frames...)
+	return nil
+}
+
+// UnmarshalJSON decodes a frame. The timestamp may be a date string or a
+// Unix time in seconds or milliseconds.
+func (f *WebSocketFrame) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Type      string      `json:"type"`
+		Direction string      `json:"direction"`
+		Timestamp interface{} `json:"timestamp"`
+		Payload   interface{} `json:"payload"`
+		Data      interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	direction := raw.Direction
+	if direction == "" {
+		direction = raw.Type
+	}
+	switch strings.ToLower(direction) {
+	case "sent", "send", "request":
+		f.Direction = WebSocketSent
+	case "received", "receive", "response":
+		f.Direction = WebSocketReceived
+	default:
+		f.Direction = WebSocketDirection(direction)
+	}
+	payload := raw.Payload
+	if payload == nil {
+		payload = raw.Data
+	}
+	f.Payload = stringifyValue(payload)
+	f.Timestamp = parseTimestamp(raw.Timestamp)
+	return nil
+}
+
+// DecodeJSON unmarshals the JSON payload into v.
+func (f *WebSocketFrame) DecodeJSON(v interface{}) error {
+	return json.Unmarshal([]byte(f.Payload), v)
+}
+
+// WebSockets returns the WebSocket connections recorded while rendering,
+// or nil when none were recorded.
+//
+// Example:
+//
+//	sockets, _ := result.WebSockets()
+//	for _, ws := range sockets {
+//	    for _, frame := range ws.Frames {
+//	        if frame.Direction == scrapfly.WebSocketReceived {
+//	            fmt.Println(frame.Timestamp, frame.Payload)
+//	        }
+//	    }
+//	}
+func (r *ScrapeResult) WebSockets() ([]WebSocket, error) {
+	var sockets []WebSocket
+	if _, err := remarshal(r.Result.BrowserData.Websockets, &sockets); err != nil {
+		return nil, err
+	}
+	return sockets, nil
+}
+
+// LocalStorage returns the localStorage snapshot of the rendered page, or
+// nil when none was captured.
+func (r *ScrapeResult) LocalStorage() map[string]string {
+	return stringifyMap(r.Result.BrowserData.LocalStorageData)
+}
+
+// SessionStorage returns the sessionStorage snapshot of the rendered page,
+// or nil when none was captured.
+func (r *ScrapeResult) SessionStorage() map[string]string {
+	return stringifyMap(r.Result.BrowserData.SessionStorageData)
+}
+
+// DecodeLocalStorage unmarshals the JSON value of the localStorage key
+// into v.
+//
+// Example:
+//
+//	var cart struct{ Items []string }
+//	if err := result.DecodeLocalStorage("cart", &cart); err == nil {
+//	    fmt.Println(cart.Items)
+//	}
+func (r *ScrapeResult) DecodeLocalStorage(key string, v interface{}) error {
+	return decodeStorageValue("localStorage", r.Result.BrowserData.LocalStorageData, key, v)
+}
+
+// DecodeSessionStorage unmarshals the JSON value of the sessionStorage key
+// into v.
+func (r *ScrapeResult) DecodeSessionStorage(key string, v interface{}) error {
+	return decodeStorageValue("sessionStorage", r.Result.BrowserData.SessionStorageData, key, v)
+}
+
+func decodeStorageValue(storage string, data map[string]interface{}, key string, v interface{}) error {
+	value, ok := data[key]
+	if !ok {
+		return fmt.Errorf("%s has no key %q", storage, key)
+	}
+	if err := json.Unmarshal([]byte(stringifyValue(value)), v); err != nil {
+		return fmt.Errorf("failed to decode %s key %q: %w", storage, key, err)
+	}
+	return nil
+}
+
+// parseTimestamp converts a generically decoded timestamp: an RFC 3339
+// string or a Unix time in seconds (milliseconds above 1e12).
+func parseTimestamp(v interface{}) time.Time {
+	switch ts := v.(type) {
+	case float64:
+		if ts > 1e12 {
+			ts /= 1000
+		}
+		sec, frac := math.Modf(ts)
+		return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+	case string:
+		t, _ := time.Parse(time.RFC3339Nano, ts)
+		return t
+	}
+	return time.Time{}
+}
+
+// stringifyMap converts generically decoded JSON values to strings.
+func stringifyMap(m map[string]interface{}) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = stringifyValue(v)
+	}
+	return out
+}
+
+// stringifyValue returns strings as is and encodes other values as JSON;
+// null becomes "".
+func stringifyValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
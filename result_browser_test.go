@@ -0,0 +1,108 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decodeResult(t *testing.T, payload string) *ScrapeResult {
+	t.Helper()
+	var result ScrapeResult
+	if err := json.Unmarshal([]byte(payload), &result); err != nil {
+		t.Fatal(err)
+	}
+	return &result
+}
+
+func TestScrapeResult_XHRCalls(t *testing.T) {
+	result := decodeResult(t, `{"result":{"browser_data":{"xhr_call":[
+		{"url":"https://example.com/api/reviews?page=1","method":"get","type":"fetch",
+		 "headers":{"accept":"application/json","x-retry":1},"body":null,
+		 "response":{"status":200,"headers":{"content-type":"application/json"},"body":"[{\"stars\":5}]"}},
+		{"url":"https://example.com/api/track","method":"POST","resource_type":"xhr",
+		 "body":{"event":"view"},"response":{"status":204}}
+	]}}}`)
+
+	calls, err := result.XHRCalls()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 {
+		t.Fatalf("got %d calls", len(calls))
+	}
+	first := calls[0]
+	if first.Method != "GET" || first.Type != "fetch" || first.Response.Status != 200 || first.Headers["x-retry"] != "1" {
+		t.Errorf("unexpected call %+v", first)
+	}
+	var reviews []struct{ Stars int }
+	if err := first.DecodeJSON(&reviews); err != nil || len(reviews) != 1 || reviews[0].Stars != 5 {
+		t.Errorf("DecodeJSON() = %v, %+v", err, reviews)
+	}
+	if calls[1].Type != "xhr" || calls[1].Body != `{"event":"view"}` {
+		t.Errorf("unexpected call %+v", calls[1])
+	}
+
+	if calls, err := (&ScrapeResult{}).XHRCalls(); calls != nil || err != nil {
+		t.Errorf("expected no calls, got %v, %v", calls, err)
+	}
+}
+
+func TestScrapeResult_WebSockets(t *testing.T) {
+	result := decodeResult(t, `{"result":{"browser_data":{"websockets":[
+		{"url":"wss://example.com/live","messages":[
+			{"type":"sent","timestamp":1714550400.5,"payload":"{\"subscribe\":\"prices\"}"},
+			{"direction":"received","timestamp":1714550401000,"data":{"price":42}},
+			{"type":"received","timestamp":"2024-05-01T08:00:02Z","payload":"pong"}
+		]}
+	]}}}`)
+
+	sockets, err := result.WebSockets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sockets) != 1 || sockets[0].URL != "wss://example.com/live" || len(sockets[0].Frames) != 3 {
+		t.Fatalf("unexpected sockets %+v", sockets)
+	}
+	frames := sockets[0].Frames
+	if frames[0].Direction != WebSocketSent || frames[0].Timestamp.UnixMilli() != 1714550400500 {
+		t.Errorf("unexpected frame %+v", frames[0])
+	}
+	var tick struct{ Price int }
+	if frames[1].Direction != WebSocketReceived || frames[1].DecodeJSON(&tick) != nil || tick.Price != 42 {
+		t.Errorf("unexpected frame %+v", frames[1])
+	}
+	if frames[1].Timestamp.Unix() != 1714550401 || frames[2].Timestamp.Unix() != 1714550402 {
+		t.Errorf("unexpected timestamps %v %v", frames[1].Timestamp, frames[2].Timestamp)
+	}
+}
+
+func TestScrapeResult_Storage(t *testing.T) {
+	result := decodeResult(t, `{"result":{"browser_data":{
+		"local_storage_data":{"cart":"{\"items\":[\"a\",\"b\"]}","visits":3},
+		"session_storage_data":{"token":"abc","prefs":{"theme":"dark"}}
+	}}}`)
+
+	local := result.LocalStorage()
+	if local["visits"] != "3" || local["cart"] != `{"items":["a","b"]}` {
+		t.Errorf("LocalStorage() = %v", local)
+	}
+	var cart struct{ Items []string }
+	if err := result.DecodeLocalStorage("cart", &cart); err != nil || len(cart.Items) != 2 {
+		t.Errorf("DecodeLocalStorage() = %v, %+v", err, cart)
+	}
+	if err := result.DecodeLocalStorage("missing", &cart); err == nil {
+		t.Error("expected an error for a missing key")
+	}
+
+	if got := result.SessionStorage()["token"]; got != "abc" {
+		t.Errorf("SessionStorage()[token] = %q", got)
+	}
+	var prefs struct{ Theme string }
+	if err := result.DecodeSessionStorage("prefs", &prefs); err != nil || prefs.Theme != "dark" {
+		t.Errorf("DecodeSessionStorage() = %v, %+v", err, prefs)
+	}
+
+	if (&ScrapeResult{}).LocalStorage() != nil {
+		t.Error("expected nil without a snapshot")
+	}
+}
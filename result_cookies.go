@@ -0,0 +1,97 @@
+package scrapfly
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// HTTPCookie converts the cookie into an *http.Cookie. Expires is parsed
+// as an HTTP date or RFC 3339 and left zero when empty or invalid.
+func (c Cookie) HTTPCookie() *http.Cookie {
+	cookie := &http.Cookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Path:     c.Path,
+		Domain:   c.Domain,
+		MaxAge:   c.MaxAge,
+		Secure:   c.Secure,
+		HttpOnly: c.HTTPOnly,
+	}
+	if c.Expires != "" {
+		if t, err := http.ParseTime(c.Expires); err == nil {
+			cookie.Expires = t
+		} else if t, err := time.Parse(time.RFC3339, c.Expires); err == nil {
+			cookie.Expires = t
+		}
+	}
+	return cookie
+}
+
+// HTTPCookies returns the cookies set by the upstream response, from the
+// parsed cookies of the result or, when the API sent none, from the
+// Set-Cookie headers.
+//
+// Example — continue the session in the next scrape:
+//
+//	next := &scrapfly.ScrapeConfig{URL: "https://example.com/account"}
+//	next.AddCookies(result.HTTPCookies()...)
+func (r *ScrapeResult) HTTPCookies() []*http.Cookie {
+	if len(r.Result.Cookies) > 0 {
+		cookies := make([]*http.Cookie, 0, len(r.Result.Cookies))
+		for _, c := range r.Result.Cookies {
+			cookies = append(cookies, c.HTTPCookie())
+		}
+		return cookies
+	}
+	var cookies []*http.Cookie
+	for _, line := range r.HeaderValues("Set-Cookie") {
+		if cookie, err := http.ParseSetCookie(line); err == nil {
+			cookies = append(cookies, cookie)
+		}
+	}
+	return cookies
+}
+
+// AddCookies adds cookies to Cookies, skipping the expired ones and, when
+// URL is set, the ones whose domain or path do not match it. Later cookies
+// override earlier ones of the same name.
+func (c *ScrapeConfig) AddCookies(cookies ...*http.Cookie) {
+	target, _ := url.Parse(c.URL)
+	now := time.Now()
+	for _, cookie := range cookies {
+		if cookie == nil || cookie.MaxAge < 0 || (!cookie.Expires.IsZero() && cookie.Expires.Before(now)) {
+			continue
+		}
+		if target != nil && target.Host != "" && !cookieMatches(cookie, target) {
+			continue
+		}
+		if c.Cookies == nil {
+			c.Cookies = make(map[string]string)
+		}
+		c.Cookies[cookie.Name] = cookie.Value
+	}
+}
+
+// cookieMatches reports whether cookie would be sent to target, per the
+// RFC 6265 domain and path matching rules.
+func cookieMatches(cookie *http.Cookie, target *url.URL) bool {
+	if cookie.Domain != "" {
+		host := strings.ToLower(target.Hostname())
+		domain := strings.TrimPrefix(strings.ToLower(cookie.Domain), ".")
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			return false
+		}
+	}
+	if cookie.Path != "" && cookie.Path != "/" {
+		path := target.Path
+		if path == "" {
+			path = "/"
+		}
+		if path != cookie.Path && !strings.HasPrefix(path, strings.TrimSuffix(cookie.Path, "/")+"/") {
+			return false
+		}
+	}
+	return true
+}
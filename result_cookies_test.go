@@ -0,0 +1,49 @@
+package scrapfly
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestScrapeResult_HTTPCookies(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{Cookies: []Cookie{
+		{Name: "sid", Value: "abc", Domain: ".example.com", Path: "/", Expires: "Wed, 01 May 2030 08:00:00 GMT", Secure: true, HTTPOnly: true},
+	}}}
+	cookies := result.HTTPCookies()
+	if len(cookies) != 1 {
+		t.Fatalf("got %d cookies", len(cookies))
+	}
+	c := cookies[0]
+	if c.Name != "sid" || c.Value != "abc" || !c.Secure || !c.HttpOnly || c.Expires.Year() != 2030 {
+		t.Errorf("unexpected cookie %+v", c)
+	}
+
+	fromHeaders := &ScrapeResult{Result: ResultData{ResponseHeaders: map[string]interface{}{
+		"set-cookie": []interface{}{"a=1; Path=/", "b=2; Domain=example.com; HttpOnly"},
+	}}}
+	if got := fromHeaders.HTTPCookies(); len(got) != 2 || got[1].Domain != "example.com" || !got[1].HttpOnly {
+		t.Errorf("cookies not parsed from Set-Cookie headers: %+v", got)
+	}
+}
+
+func TestScrapeConfig_AddCookies(t *testing.T) {
+	config := &ScrapeConfig{URL: "https://shop.example.com/account/orders"}
+	config.AddCookies(
+		&http.Cookie{Name: "sid", Value: "abc", Domain: ".example.com"},
+		&http.Cookie{Name: "expired", Value: "x", Expires: time.Now().Add(-time.Hour)},
+		&http.Cookie{Name: "deleted", Value: "x", MaxAge: -1},
+		&http.Cookie{Name: "other", Value: "x", Domain: "other.com"},
+		&http.Cookie{Name: "scoped", Value: "y", Path: "/account"},
+		&http.Cookie{Name: "admin", Value: "z", Path: "/admin"},
+	)
+	want := map[string]string{"sid": "abc", "scoped": "y"}
+	if len(config.Cookies) != len(want) {
+		t.Fatalf("Cookies = %v, want %v", config.Cookies, want)
+	}
+	for k, v := range want {
+		if config.Cookies[k] != v {
+			t.Errorf("Cookies[%s] = %q, want %q", k, config.Cookies[k], v)
+		}
+	}
+}
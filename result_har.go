@@ -0,0 +1,185 @@
+package scrapfly
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// HAR assembles the page request and the XHR and fetch calls captured
+// while rendering into a HAR 1.2 archive, which browser devtools and HAR
+// viewers can open. Timings are not captured by the API: the page entry
+// carries the scrape duration and the calls have none. Calls are started
+// at the scrape creation time.
+//
+// Example:
+//
+//	archive, err := result.HAR()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	data, _ := json.Marshal(archive)
+//	os.WriteFile("page.har", data, 0644)
+func (r *ScrapeResult) HAR() (*HarArchive, error) {
+	calls, err := r.XHRCalls()
+	if err != nil {
+		return nil, err
+	}
+	started := time.Now()
+	for _, layout := range cacheEntryTimeLayouts {
+		if t, err := time.Parse(layout, r.Context.CreatedAt); err == nil {
+			started = t
+			break
+		}
+	}
+	startedDateTime := started.UTC().Format(time.RFC3339Nano)
+
+	method := strings.ToUpper(r.Config.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	pageURL := r.FinalURL()
+	body := ""
+	if r.Config.Body != nil {
+		body = *r.Config.Body
+	}
+	page := harEntry(startedDateTime, method, firstNonEmpty(r.Config.URL, pageURL), r.Result.RequestHeaders, body)
+	page["time"] = r.Result.Duration * 1000
+	page["timings"] = map[string]interface{}{"send": 0, "wait": r.Result.Duration * 1000, "receive": 0}
+	page["_resourceType"] = "document"
+	content := map[string]interface{}{"size": len(r.Result.Content), "mimeType": r.Result.ContentType, "text": r.Result.Content}
+	if r.rawContent != nil || r.Result.Format == "binary" {
+		if data, err := r.Bytes(); err == nil {
+			content = map[string]interface{}{"size": len(data), "mimeType": r.Result.ContentType, "text": base64.StdEncoding.EncodeToString(data), "encoding": "base64"}
+		}
+	}
+	headers := r.Headers()
+	page["response"] = harResponse(r.Result.StatusCode, harHeaders(headers), content, headers.Get("Location"))
+	entries := []map[string]interface{}{page}
+
+	for _, call := range calls {
+		entry := harEntry(startedDateTime, call.Method, call.URL, call.Headers, call.Body)
+		entry["_resourceType"] = call.Type
+		responseHeaders := make(http.Header, len(call.Response.Headers))
+		for name, value := range call.Response.Headers {
+			responseHeaders.Set(name, value)
+		}
+		content := map[string]interface{}{"size": len(call.Response.Body), "mimeType": responseHeaders.Get("Content-Type"), "text": call.Response.Body}
+		entry["response"] = harResponse(call.Response.Status, harHeaders(responseHeaders), content, responseHeaders.Get("Location"))
+		entries = append(entries, entry)
+	}
+
+	return &HarArchive{
+		logMap: map[string]interface{}{
+			"version": "1.2",
+			"creator": map[string]interface{}{"name": sdkUserAgent, "version": BuildInfo().Version},
+			"pages": []interface{}{map[string]interface{}{
+				"startedDateTime": startedDateTime,
+				"id":              "page_1",
+				"title":           pageURL,
+				"pageTimings":     map[string]interface{}{},
+			}},
+		},
+		entries: entries,
+	}, nil
+}
+
+// MarshalJSON encodes the archive as a standard HAR file, with the
+// entries under log.entries.
+func (a *HarArchive) MarshalJSON() ([]byte, error) {
+	log := make(map[string]interface{}, len(a.logMap)+1)
+	for key, value := range a.logMap {
+		log[key] = value
+	}
+	entries := a.entries
+	if entries == nil {
+		entries = []map[string]interface{}{}
+	}
+	log["entries"] = entries
+	return json.Marshal(map[string]interface{}{"log": log})
+}
+
+// harEntry returns a HAR entry with its request, the response is set by
+// the caller.
+func harEntry(startedDateTime, method, rawURL string, headers map[string]string, body string) map[string]interface{} {
+	requestHeaders := make(http.Header, len(headers))
+	for name, value := range headers {
+		requestHeaders.Set(name, value)
+	}
+	queryString := []interface{}{}
+	if u, err := url.Parse(rawURL); err == nil {
+		query := u.Query()
+		names := make([]string, 0, len(query))
+		for name := range query {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, value := range query[name] {
+				queryString = append(queryString, map[string]interface{}{"name": name, "value": value})
+			}
+		}
+	}
+	request := map[string]interface{}{
+		"method":      method,
+		"url":         rawURL,
+		"httpVersion": "HTTP/1.1",
+		"headers":     harHeaders(requestHeaders),
+		"queryString": queryString,
+		"cookies":     []interface{}{},
+		"headersSize": -1,
+		"bodySize":    len(body),
+	}
+	if body != "" {
+		request["postData"] = map[string]interface{}{"mimeType": requestHeaders.Get("Content-Type"), "text": body}
+	}
+	return map[string]interface{}{
+		"startedDateTime": startedDateTime,
+		"time":            0,
+		"request":         request,
+		"cache":           map[string]interface{}{},
+		"timings":         map[string]interface{}{"send": 0, "wait": 0, "receive": 0},
+		"pageref":         "page_1",
+	}
+}
+
+func harResponse(status int, headers []interface{}, content map[string]interface{}, redirectURL string) map[string]interface{} {
+	// HAR text is a JSON string, invalid UTF-8 would be mangled
+	if text, _ := content["text"].(string); content["encoding"] == nil && !utf8.ValidString(text) {
+		content["text"] = base64.StdEncoding.EncodeToString([]byte(text))
+		content["encoding"] = "base64"
+	}
+	return map[string]interface{}{
+		"status":      status,
+		"statusText":  http.StatusText(status),
+		"httpVersion": "HTTP/1.1",
+		"headers":     headers,
+		"cookies":     []interface{}{},
+		"content":     content,
+		"redirectURL": redirectURL,
+		"headersSize": -1,
+		"bodySize":    content["size"],
+	}
+}
+
+// harHeaders converts headers to the HAR `[{name, value}, ...]` form,
+// sorted by name.
+func harHeaders(headers http.Header) []interface{} {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	out := []interface{}{}
+	for _, name := range names {
+		for _, value := range headers[name] {
+			out = append(out, map[string]interface{}{"name": name, "value": value})
+		}
+	}
+	return out
+}
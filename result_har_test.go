@@ -0,0 +1,55 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestScrapeResult_HAR(t *testing.T) {
+	result := decodeResult(t, `{
+		"config":{"url":"https://example.com/products?page=2","method":"GET"},
+		"context":{"created_at":"2026-01-02 03:04:05"},
+		"result":{"url":"https://example.com/products?page=2","status_code":200,"duration":1.5,
+			"content_type":"text/html","content":"<html>products</html>",
+			"request_headers":{"user-agent":"Mozilla/5.0"},
+			"response_headers":{"content-type":"text/html","set-cookie":["a=1","b=2"]},
+			"browser_data":{"xhr_call":[{"url":"https://example.com/api/reviews","method":"post","type":"fetch",
+				"headers":{"content-type":"application/json"},"body":"{\"id\":1}",
+				"response":{"status":201,"headers":{"content-type":"application/json"},"body":"[] "}}]}}}`)
+
+	archive, err := result.HAR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseHAR(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Version() != "1.2" || parsed.Len() != 2 || len(parsed.Pages()) != 1 {
+		t.Fatalf("version %q, %d entries, %d pages", parsed.Version(), parsed.Len(), len(parsed.Pages()))
+	}
+
+	page := parsed.Entries()[0]
+	if page.URL() != "https://example.com/products?page=2" || page.Method() != "GET" || page.StatusCode() != 200 {
+		t.Errorf("page entry %s", page)
+	}
+	if string(page.Content()) != "<html>products</html>" || page.Time() != 1500 {
+		t.Errorf("page content %q, time %v", page.Content(), page.Time())
+	}
+	if page.StartedDateTime() != "2026-01-02T03:04:05Z" || page.RequestHeaders()["User-Agent"] != "Mozilla/5.0" {
+		t.Errorf("started %q, request headers %v", page.StartedDateTime(), page.RequestHeaders())
+	}
+
+	call := parsed.Entries()[1]
+	if call.URL() != "https://example.com/api/reviews" || call.Method() != "POST" || call.StatusCode() != 201 || call.ContentType() != "application/json" {
+		t.Errorf("call entry %s, content type %q", call, call.ContentType())
+	}
+	request := call.data["request"].(map[string]interface{})
+	if postData, _ := request["postData"].(map[string]interface{}); postData["text"] != `{"id":1}` {
+		t.Errorf("postData = %v", request["postData"])
+	}
+}
@@ -0,0 +1,58 @@
+package scrapfly
+
+import "net/http"
+
+// Headers returns the upstream response headers with canonical keys. The
+// API sends repeated headers (Set-Cookie...) as lists, kept as multiple
+// values. The returned header is a copy.
+//
+// Example:
+//
+//	for _, cookie := range result.Headers().Values("Set-Cookie") {
+//	    fmt.Println(cookie)
+//	}
+func (r *ScrapeResult) Headers() http.Header {
+	headers := make(http.Header, len(r.Result.ResponseHeaders))
+	for name, value := range r.Result.ResponseHeaders {
+		switch v := value.(type) {
+		case string:
+			headers.Add(name, v)
+		case []string:
+			for _, item := range v {
+				headers.Add(name, item)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					headers.Add(name, s)
+				}
+			}
+		}
+	}
+	return headers
+}
+
+// Header returns the first value of the upstream response header name,
+// matched case-insensitively, or "".
+func (r *ScrapeResult) Header(name string) string {
+	values := r.HeaderValues(name)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// HeaderValues returns every value of the upstream response header name,
+// matched case-insensitively.
+func (r *ScrapeResult) HeaderValues(name string) []string {
+	return r.Headers().Values(name)
+}
+
+// ContentType returns the upstream content type, from the result or the
+// Content-Type header.
+func (r *ScrapeResult) ContentType() string {
+	if r.Result.ContentType != "" {
+		return r.Result.ContentType
+	}
+	return r.Header("Content-Type")
+}
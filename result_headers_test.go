@@ -0,0 +1,38 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestScrapeResult_Headers(t *testing.T) {
+	var result ScrapeResult
+	err := json.Unmarshal([]byte(`{"result":{"response_headers":{
+		"content-type": "text/html; charset=utf-8",
+		"set-cookie": ["a=1; Path=/", "b=2; HttpOnly"],
+		"x-request-id": "abc"
+	}}}`), &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	headers := result.Headers()
+	if got := headers["Set-Cookie"]; !slices.Equal(got, []string{"a=1; Path=/", "b=2; HttpOnly"}) {
+		t.Errorf("Set-Cookie = %v", got)
+	}
+	if got := result.Header("X-Request-Id"); got != "abc" {
+		t.Errorf("Header(X-Request-Id) = %q", got)
+	}
+	if got := result.HeaderValues("set-cookie"); len(got) != 2 {
+		t.Errorf("HeaderValues(set-cookie) = %v", got)
+	}
+	if got := result.ContentType(); got != "text/html; charset=utf-8" {
+		t.Errorf("ContentType() = %q", got)
+	}
+
+	headers.Set("X-Request-Id", "changed")
+	if result.Header("X-Request-Id") != "abc" {
+		t.Error("Headers must return a copy")
+	}
+}
@@ -0,0 +1,333 @@
+package scrapfly
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// Results round-trip through JSON without loss, so they can be queued
+// (Kafka, SQS...) and rebuilt by consumers: members of the result, config,
+// context and result data objects this SDK version doesn't know are kept
+// and encoded back, blob content is inlined as binary content and the
+// detected charset is kept.
+//
+// Unknown members are collected by scanning the member boundaries of the
+// objects (objectMembers), not by decoding them again: results are decoded
+// on every API call and their content can weigh megabytes.
+
+var (
+	scrapeResultFields = jsonFieldNames(reflect.TypeOf(scrapeResultJSON{}))
+	configDataFields   = jsonFieldNames(reflect.TypeOf(ConfigData{}))
+	contextDataFields  = jsonFieldNames(reflect.TypeOf(ContextData{}))
+	resultDataFields   = jsonFieldNames(reflect.TypeOf(ResultData{}))
+)
+
+// scrapeResultJSON is the JSON form of a ScrapeResult.
+type scrapeResultJSON struct {
+	Config  ConfigData  `json:"config"`
+	Context ContextData `json:"context"`
+	Result  ResultData  `json:"result"`
+	UUID    string      `json:"uuid"`
+	Charset string      `json:"charset,omitempty"`
+}
+
+// scrapeResultDecode is scrapeResultJSON with the nested objects decoded
+// as their plain types, so their members are decoded once; UnmarshalJSON
+// collects their unknown members.
+type scrapeResultDecode struct {
+	Config  plainConfigData  `json:"config"`
+	Context plainContextData `json:"context"`
+	Result  plainResultData  `json:"result"`
+	UUID    string           `json:"uuid"`
+	Charset string           `json:"charset,omitempty"`
+}
+
+type (
+	plainConfigData  ConfigData
+	plainContextData ContextData
+	plainResultData  ResultData
+)
+
+// MarshalJSON encodes the result with the unknown members it was decoded
+// with. Blob content is encoded as base64 with the "binary" format.
+func (r ScrapeResult) MarshalJSON() ([]byte, error) {
+	v := scrapeResultJSON{Config: r.Config, Context: r.Context, Result: r.Result, UUID: r.UUID, Charset: r.charset}
+	if r.rawContent != nil {
+		v.Result.Content = base64.StdEncoding.EncodeToString(r.rawContent)
+		v.Result.Format = "binary"
+	}
+	return marshalWithExtra(v, r.extra)
+}
+
+// UnmarshalJSON decodes the result, keeping the members it doesn't know.
+// Called directly rather than through json.Unmarshal, which validates and
+// skips over data before calling it, it scans large results fewer times.
+func (r *ScrapeResult) UnmarshalJSON(data []byte) error {
+	var v scrapeResultDecode
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*r = ScrapeResult{Config: ConfigData(v.Config), Context: ContextData(v.Context), Result: ResultData(v.Result), UUID: v.UUID, charset: v.Charset}
+	return objectMembers(data, func(name string, value []byte) error {
+		var err error
+		switch key := strings.ToLower(name); key {
+		case "config":
+			r.Config.extra, err = extraMembers(value, configDataFields)
+		case "context":
+			r.Context.extra, err = extraMembers(value, contextDataFields)
+		case "result":
+			r.Result.extra, err = extraMembers(value, resultDataFields)
+		default:
+			if !scrapeResultFields[key] {
+				r.extra, err = addExtra(r.extra, name, value)
+			}
+		}
+		return err
+	})
+}
+
+// MarshalJSON encodes the config with the unknown members it was decoded
+// with.
+func (c ConfigData) MarshalJSON() ([]byte, error) {
+	type plain ConfigData
+	return marshalWithExtra(plain(c), c.extra)
+}
+
+// UnmarshalJSON decodes the config, keeping the members it doesn't know.
+func (c *ConfigData) UnmarshalJSON(data []byte) error {
+	type plain ConfigData
+	var v plain
+	extra, err := unmarshalWithExtra(data, &v, configDataFields)
+	if err != nil {
+		return err
+	}
+	*c = ConfigData(v)
+	c.extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the context with the unknown members it was decoded
+// with.
+func (c ContextData) MarshalJSON() ([]byte, error) {
+	type plain ContextData
+	return marshalWithExtra(plain(c), c.extra)
+}
+
+// UnmarshalJSON decodes the context, keeping the members it doesn't know.
+func (c *ContextData) UnmarshalJSON(data []byte) error {
+	type plain ContextData
+	var v plain
+	extra, err := unmarshalWithExtra(data, &v, contextDataFields)
+	if err != nil {
+		return err
+	}
+	*c = ContextData(v)
+	c.extra = extra
+	return nil
+}
+
+// MarshalJSON encodes the result data with the unknown members it was
+// decoded with.
+func (d ResultData) MarshalJSON() ([]byte, error) {
+	type plain ResultData
+	return marshalWithExtra(plain(d), d.extra)
+}
+
+// UnmarshalJSON decodes the result data, keeping the members it doesn't
+// know.
+func (d *ResultData) UnmarshalJSON(data []byte) error {
+	type plain ResultData
+	var v plain
+	extra, err := unmarshalWithExtra(data, &v, resultDataFields)
+	if err != nil {
+		return err
+	}
+	*d = ResultData(v)
+	d.extra = extra
+	return nil
+}
+
+// unmarshalWithExtra decodes data into v and returns the members of the
+// object whose name is not in known, nil when there are none.
+func unmarshalWithExtra(data []byte, v interface{}, known map[string]bool) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	return extraMembers(data, known)
+}
+
+// extraMembers returns the members of the object data whose name is not in
+// known, nil when there are none or data is not an object.
+func extraMembers(data []byte, known map[string]bool) (map[string]json.RawMessage, error) {
+	var extra map[string]json.RawMessage
+	err := objectMembers(data, func(name string, value []byte) error {
+		// encoding/json matches names case-insensitively
+		if known[strings.ToLower(name)] {
+			return nil
+		}
+		var err error
+		extra, err = addExtra(extra, name, value)
+		return err
+	})
+	return extra, err
+}
+
+// addExtra adds the member name to extra, allocated on first use, with its
+// value compacted as encoding/json would, so encoding is stable.
+func addExtra(extra map[string]json.RawMessage, name string, value []byte) (map[string]json.RawMessage, error) {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, value); err != nil {
+		return extra, err
+	}
+	if extra == nil {
+		extra = make(map[string]json.RawMessage)
+	}
+	extra[name] = compact.Bytes()
+	return extra, nil
+}
+
+// objectMembers calls fn with the name and raw value of each member of the
+// object data, valid JSON as accepted by json.Unmarshal. Values are only
+// skipped over, not decoded. Data other than an object has no members.
+func objectMembers(data []byte, fn func(name string, value []byte) error) error {
+	i := skipJSONSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return nil
+	}
+	for i = skipJSONSpace(data, i+1); i < len(data) && data[i] != '}'; {
+		end := skipJSONValue(data, i)
+		key := data[i:end]
+		name := string(key[1 : len(key)-1])
+		if bytes.IndexByte(key, '\\') >= 0 {
+			if err := json.Unmarshal(key, &name); err != nil {
+				return err
+			}
+		}
+		i = skipJSONSpace(data, end)
+		if i >= len(data) || data[i] != ':' {
+			return errInvalidJSONObject
+		}
+		i = skipJSONSpace(data, i+1)
+		end = skipJSONValue(data, i)
+		if err := fn(name, data[i:end]); err != nil {
+			return err
+		}
+		if i = skipJSONSpace(data, end); i < len(data) && data[i] == ',' {
+			i = skipJSONSpace(data, i+1)
+		}
+	}
+	if i >= len(data) {
+		return errInvalidJSONObject
+	}
+	return nil
+}
+
+var errInvalidJSONObject = errors.New("invalid JSON object")
+
+func skipJSONSpace(data []byte, i int) int {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t' || data[i] == '\n' || data[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipJSONValue returns the end of the value starting at i, len(data) when
+// it is truncated.
+func skipJSONValue(data []byte, i int) int {
+	if i >= len(data) {
+		return i
+	}
+	switch data[i] {
+	case '"':
+		return skipJSONString(data, i)
+	case '{', '[':
+		depth := 0
+		for i < len(data) {
+			switch data[i] {
+			case '"':
+				i = skipJSONString(data, i)
+				continue
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			}
+			i++
+		}
+		return i
+	default:
+		// number, true, false or null
+		for i < len(data) && strings.IndexByte(",}] \t\n\r", data[i]) < 0 {
+			i++
+		}
+		return i
+	}
+}
+
+// skipJSONString returns the end of the string starting at i, after its
+// closing quote.
+func skipJSONString(data []byte, i int) int {
+	for i++; i < len(data); {
+		j := bytes.IndexByte(data[i:], '"')
+		if j < 0 {
+			return len(data)
+		}
+		i += j
+		// the quote is escaped when preceded by an odd number of backslashes
+		backslashes := 0
+		for k := i - 1; k >= 0 && data[k] == '\\'; k-- {
+			backslashes++
+		}
+		i++
+		if backslashes%2 == 0 {
+			return i
+		}
+	}
+	return len(data)
+}
+
+// marshalWithExtra encodes v, an object, with the members of extra it
+// doesn't have.
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		if _, ok := members[name]; !ok {
+			members[name] = value
+		}
+	}
+	return json.Marshal(members)
+}
+
+// jsonFieldNames returns the lowercased JSON names of the fields of the
+// struct type t.
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+	return names
+}
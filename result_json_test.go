@@ -0,0 +1,87 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestScrapeResult_JSONRoundTrip(t *testing.T) {
+	payload := `{
+		"uuid": "uuid-1",
+		"future_top": {"a": [1, 2]},
+		"config": {"url": "https://example.com", "method": "GET", "future_config": "x"},
+		"context": {"asp": false, "future_context": 3},
+		"result": {"content": "<p>hi</p>", "status_code": 200, "success": true, "future_result": {"b": true}}
+	}`
+	result := decodeResult(t, payload)
+	if result.Result.Content != "<p>hi</p>" || result.Config.URL != "https://example.com" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ScrapeResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&decoded, result) {
+		t.Errorf("round trip changed the result:\n%+v\n%+v", decoded, *result)
+	}
+
+	var raw map[string]json.RawMessage
+	json.Unmarshal(data, &raw)
+	if string(raw["future_top"]) != `{"a":[1,2]}` {
+		t.Errorf("future_top = %s", raw["future_top"])
+	}
+	members := map[string]map[string]interface{}{}
+	for _, name := range []string{"config", "context", "result"} {
+		var m map[string]interface{}
+		json.Unmarshal(raw[name], &m)
+		members[name] = m
+	}
+	if members["config"]["future_config"] != "x" || members["context"]["future_context"] != 3.0 || members["result"]["future_result"] == nil {
+		t.Errorf("unknown members not kept: %s", data)
+	}
+}
+
+func TestScrapeResult_JSONBlobAndCharset(t *testing.T) {
+	result := &ScrapeResult{rawContent: []byte{0x00, 0xff, 0x10}, charset: "windows-1252"}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded ScrapeResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if body, err := decoded.Bytes(); err != nil || string(body) != string(result.rawContent) {
+		t.Errorf("Bytes() = %v, %v", body, err)
+	}
+	if decoded.Charset() != "windows-1252" {
+		t.Errorf("Charset() = %q", decoded.Charset())
+	}
+}
+
+func TestScrapeResult_JSONUnknownMembersScan(t *testing.T) {
+	payload := `{"result":{"content":"a \"}\" \\","future\"key":["]",{"x":"\\\""}],"n":-1.5e3,"t":true},
+		"future_null":null, "config":null}`
+	var result ScrapeResult
+	if err := result.UnmarshalJSON([]byte(payload)); err != nil {
+		t.Fatal(err)
+	}
+	if result.Result.Content != `a "}" \` {
+		t.Errorf("content = %q", result.Result.Content)
+	}
+	if got := string(result.Result.extra[`future"key`]); got != `["]",{"x":"\\\""}]` {
+		t.Errorf("future\"key = %s", got)
+	}
+	if string(result.Result.extra["n"]) != "-1.5e3" || string(result.Result.extra["t"]) != "true" || string(result.extra["future_null"]) != "null" {
+		t.Errorf("extra = %v, result extra = %v", result.extra, result.Result.extra)
+	}
+	if result.Config.extra != nil {
+		t.Errorf("null config has members: %v", result.Config.extra)
+	}
+}
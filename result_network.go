@@ -0,0 +1,269 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SSLInfo contains the TLS certificate chain of the target, captured when
+// ScrapeConfig.SSL is enabled. Certificates are ordered from the leaf
+// certificate to the root.
+type SSLInfo struct {
+	Certificates []SSLCertificate `json:"certs"`
+}
+
+// UnmarshalJSON accepts both {"certs": [...]} and a bare certificate list.
+func (s *SSLInfo) UnmarshalJSON(data []byte) error {
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		return json.Unmarshal(data, &s.Certificates)
+	}
+	type plain SSLInfo
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// Leaf returns the certificate of the target itself, or nil.
+func (s *SSLInfo) Leaf() *SSLCertificate {
+	if s == nil || len(s.Certificates) == 0 {
+		return nil
+	}
+	return &s.Certificates[0]
+}
+
+// SSLCertificate is a single certificate of the chain.
+type SSLCertificate struct {
+	Subject      DistinguishedName `json:"subject"`
+	Issuer       DistinguishedName `json:"issuer"`
+	SerialNumber string            `json:"serial_number"`
+	NotBefore    string            `json:"not_before"`
+	NotAfter     string            `json:"not_after"`
+	// SubjectAltNames are the subject alternative names ("DNS:example.com"
+	// entries are reported without their prefix).
+	SubjectAltNames []string `json:"subject_alt_names,omitempty"`
+}
+
+// UnmarshalJSON decodes a certificate, accepting the alternative names
+// as a list or a comma-separated "DNS:a, DNS:b" string, under
+// subject_alt_names, subjectAltName or san.
+func (c *SSLCertificate) UnmarshalJSON(data []byte) error {
+	type plain SSLCertificate
+	var raw struct {
+		plain
+		SubjectAltNames interface{} `json:"subject_alt_names"`
+		SubjectAltName  interface{} `json:"subjectAltName"`
+		SAN             interface{} `json:"san"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = SSLCertificate(raw.plain)
+	for _, names := range []interface{}{raw.SubjectAltNames, raw.SubjectAltName, raw.SAN} {
+		if names != nil {
+			c.SubjectAltNames = parseAltNames(names)
+			break
+		}
+	}
+	return nil
+}
+
+// NotAfterTime parses NotAfter.
+func (c SSLCertificate) NotAfterTime() (time.Time, error) {
+	return parseCertificateTime(c.NotAfter)
+}
+
+// NotBeforeTime parses NotBefore.
+func (c SSLCertificate) NotBeforeTime() (time.Time, error) {
+	return parseCertificateTime(c.NotBefore)
+}
+
+// certificateTimeLayouts are the date formats certificates are reported in.
+var certificateTimeLayouts = []string{time.RFC3339, "20060102150405Z", "Jan _2 15:04:05 2006 MST", "2006-01-02 15:04:05"}
+
+func parseCertificateTime(value string) (time.Time, error) {
+	for _, layout := range certificateTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized certificate date %q", value)
+}
+
+func parseAltNames(v interface{}) []string {
+	var entries []string
+	switch names := v.(type) {
+	case string:
+		entries = strings.Split(names, ",")
+	case []interface{}:
+		for _, name := range names {
+			switch n := name.(type) {
+			case string:
+				entries = append(entries, n)
+			case []interface{}:
+				// [["DNS", "example.com"], ...]
+				if len(n) == 2 {
+					entries = append(entries, fmt.Sprint(n[1]))
+				}
+			}
+		}
+	}
+	out := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		entry = strings.TrimPrefix(entry, "DNS:")
+		if entry != "" {
+			out = append(out, entry)
+		}
+	}
+	return out
+}
+
+// DistinguishedName holds the attributes of a certificate subject or issuer
+// (commonName, organizationName, ...). It decodes both the object form and
+// a plain "CN=..., O=..." string, which is kept in Raw.
+type DistinguishedName struct {
+	Attributes map[string]string
+	Raw        string
+}
+
+// CommonName returns the commonName (CN) attribute.
+func (d DistinguishedName) CommonName() string {
+	for _, key := range []string{"commonName", "common_name", "CN"} {
+		if v, ok := d.Attributes[key]; ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// UnmarshalJSON decodes a distinguished name from a string or an object.
+func (d *DistinguishedName) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		d.Raw = raw
+		d.Attributes = make(map[string]string)
+		for _, part := range strings.Split(raw, ",") {
+			if key, value, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+				d.Attributes[key] = value
+			}
+		}
+		return nil
+	}
+	var attrs map[string]interface{}
+	if err := json.Unmarshal(data, &attrs); err != nil {
+		return err
+	}
+	d.Attributes = make(map[string]string, len(attrs))
+	for key, value := range attrs {
+		d.Attributes[key] = fmt.Sprint(value)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the distinguished name back to its original form.
+func (d DistinguishedName) MarshalJSON() ([]byte, error) {
+	if d.Raw != "" {
+		return json.Marshal(d.Raw)
+	}
+	return json.Marshal(d.Attributes)
+}
+
+// DNSInfo contains the DNS records of the target host, captured when
+// ScrapeConfig.DNS is enabled, keyed by record type ("A", "AAAA", "MX"...).
+type DNSInfo struct {
+	Records map[string][]DNSRecord
+}
+
+// UnmarshalJSON decodes the record type map.
+func (d *DNSInfo) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &d.Records)
+}
+
+// MarshalJSON encodes the record type map.
+func (d DNSInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Records)
+}
+
+// DNSRecord is a single DNS record. Records reported as plain strings only
+// carry a Value.
+type DNSRecord struct {
+	Value string `json:"value"`
+	TTL   int    `json:"ttl,omitempty"`
+}
+
+// UnmarshalJSON decodes a record from a string or an object.
+func (r *DNSRecord) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &r.Value); err == nil {
+		return nil
+	}
+	type plain DNSRecord
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+// Get returns the values of the records of the given type.
+func (d *DNSInfo) Get(recordType string) []string {
+	records := d.Records[strings.ToUpper(recordType)]
+	values := make([]string, 0, len(records))
+	for _, record := range records {
+		values = append(values, record.Value)
+	}
+	return values
+}
+
+// A returns the IPv4 addresses of the host.
+func (d *DNSInfo) A() []string { return d.Get("A") }
+
+// AAAA returns the IPv6 addresses of the host.
+func (d *DNSInfo) AAAA() []string { return d.Get("AAAA") }
+
+// CNAME returns the canonical names of the host.
+func (d *DNSInfo) CNAME() []string { return d.Get("CNAME") }
+
+// SSLInfo returns the typed certificate details of the request, or nil
+// when the request did not set ScrapeConfig.SSL.
+//
+// Example:
+//
+//	result, _ := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://example.com", SSL: true})
+//	info, err := result.SSLInfo()
+//	if err == nil && info != nil && len(info.Certificates) > 0 {
+//	    fmt.Println(info.Certificates[0].Issuer.CommonName())
+//	}
+func (r *ScrapeResult) SSLInfo() (*SSLInfo, error) {
+	var info SSLInfo
+	ok, err := remarshal(r.Result.SSL, &info)
+	if !ok || err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// DNSInfo returns the typed DNS records of the request, or nil when the
+// request did not set ScrapeConfig.DNS.
+func (r *ScrapeResult) DNSInfo() (*DNSInfo, error) {
+	var info DNSInfo
+	ok, err := remarshal(r.Result.DNS, &info)
+	if !ok || err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// remarshal converts generically decoded JSON into a typed value. It
+// reports false when src holds no data.
+func remarshal(src interface{}, dst interface{}) (bool, error) {
+	if src == nil {
+		return false, nil
+	}
+	if b, isBool := src.(bool); isBool && !b {
+		return false, nil
+	}
+	data, err := json.Marshal(src)
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return false, fmt.Errorf("failed to decode %T: %w", dst, err)
+	}
+	return true, nil
+}
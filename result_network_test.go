@@ -0,0 +1,75 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestScrapeResult_SSLAndDNSInfo(t *testing.T) {
+	var result ScrapeResult
+	payload := `{"result":{
+		"ssl":{"certs":[{"subject":{"commonName":"example.com"},"issuer":"CN=R3, O=Let's Encrypt","not_after":"2030-01-01T00:00:00Z"}]},
+		"dns":{"A":["93.184.216.34"],"MX":[{"value":"mail.example.com","ttl":300}]}
+	}}`
+	if err := json.Unmarshal([]byte(payload), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	ssl, err := result.SSLInfo()
+	if err != nil || ssl == nil {
+		t.Fatalf("SSLInfo() = %v, %v", ssl, err)
+	}
+	if len(ssl.Certificates) != 1 || ssl.Certificates[0].Subject.CommonName() != "example.com" || ssl.Certificates[0].Issuer.CommonName() != "R3" {
+		t.Errorf("unexpected certificates %+v", ssl.Certificates)
+	}
+
+	dns, err := result.DNSInfo()
+	if err != nil || dns == nil {
+		t.Fatalf("DNSInfo() = %v, %v", dns, err)
+	}
+	if got := dns.Get("a"); len(got) != 1 || got[0] != "93.184.216.34" {
+		t.Errorf("unexpected A records %v", got)
+	}
+	if mx := dns.Records["MX"]; len(mx) != 1 || mx[0].TTL != 300 {
+		t.Errorf("unexpected MX records %+v", mx)
+	}
+
+	if info, err := (&ScrapeResult{}).SSLInfo(); info != nil || err != nil {
+		t.Errorf("expected no SSL info without capture, got %v, %v", info, err)
+	}
+}
+
+func TestScrapeResult_SSLCertificateDetails(t *testing.T) {
+	var result ScrapeResult
+	payload := `{"result":{
+		"ssl":[{"subject":"CN=example.com","issuer":{"commonName":"R3"},"not_before":"20240101000000Z","not_after":"2030-01-01T00:00:00Z",
+			"subjectAltName":"DNS:example.com, DNS:www.example.com"}],
+		"dns":{"A":["93.184.216.34"],"AAAA":["2606:2800:220:1::"],"CNAME":[{"value":"edge.example.net","ttl":60}]}
+	}}`
+	if err := json.Unmarshal([]byte(payload), &result); err != nil {
+		t.Fatal(err)
+	}
+
+	ssl, err := result.SSLInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	leaf := ssl.Leaf()
+	if leaf == nil || len(leaf.SubjectAltNames) != 2 || leaf.SubjectAltNames[1] != "www.example.com" {
+		t.Fatalf("unexpected leaf %+v", leaf)
+	}
+	if notAfter, err := leaf.NotAfterTime(); err != nil || notAfter.Year() != 2030 {
+		t.Errorf("NotAfterTime() = %v, %v", notAfter, err)
+	}
+	if notBefore, err := leaf.NotBeforeTime(); err != nil || notBefore.Year() != 2024 {
+		t.Errorf("NotBeforeTime() = %v, %v", notBefore, err)
+	}
+
+	dns, err := result.DNSInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dns.A()) != 1 || len(dns.AAAA()) != 1 || len(dns.CNAME()) != 1 || dns.CNAME()[0] != "edge.example.net" {
+		t.Errorf("unexpected records A=%v AAAA=%v CNAME=%v", dns.A(), dns.AAAA(), dns.CNAME())
+	}
+}
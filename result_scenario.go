@@ -0,0 +1,122 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JSScenarioReport is the execution report of ScrapeConfig.JSScenario.
+type JSScenarioReport struct {
+	// Duration is the total execution time of the scenario.
+	Duration time.Duration
+	// Executed is the number of executed steps.
+	Executed int
+	// Steps are the per-step reports, in scenario order.
+	Steps []JSScenarioStepReport
+}
+
+// JSScenarioStepReport is the execution report of a single scenario step.
+type JSScenarioStepReport struct {
+	// Index is the position of the step in the scenario.
+	Index int
+	// Action is the step type ("click", "fill", "wait_for_selector"...).
+	Action string
+	// Config is the step configuration as run.
+	Config map[string]interface{}
+	// Duration is the execution time of the step.
+	Duration time.Duration
+	// Executed reports whether the step ran; steps after a failure don't.
+	Executed bool
+	// Success reports whether the step succeeded.
+	Success bool
+	// Error describes the failure of the step, if any.
+	Error string
+	// Result is the value returned by the step, such as the return value of
+	// an "execute" step script.
+	Result interface{}
+}
+
+// UnmarshalJSON decodes the report, with durations in seconds.
+func (s *JSScenarioReport) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Duration float64                `json:"duration"`
+		Executed int                    `json:"executed"`
+		Steps    []JSScenarioStepReport `json:"steps"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	s.Duration = secondsToDuration(raw.Duration)
+	s.Executed = raw.Executed
+	s.Steps = raw.Steps
+	for i := range s.Steps {
+		s.Steps[i].Index = i
+	}
+	return nil
+}
+
+// UnmarshalJSON decodes a step report, accepting "action" or "type" for the
+// step type and an error message or object.
+func (s *JSScenarioStepReport) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Action   string                 `json:"action"`
+		Type     string                 `json:"type"`
+		Config   map[string]interface{} `json:"config"`
+		Duration float64                `json:"duration"`
+		Executed bool                   `json:"executed"`
+		Success  bool                   `json:"success"`
+		Error    interface{}            `json:"error"`
+		Result   interface{}            `json:"result"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*s = JSScenarioStepReport{
+		Action:   raw.Action,
+		Config:   raw.Config,
+		Duration: secondsToDuration(raw.Duration),
+		Executed: raw.Executed,
+		Success:  raw.Success,
+		Error:    stringifyValue(raw.Error),
+		Result:   raw.Result,
+	}
+	if s.Action == "" {
+		s.Action = raw.Type
+	}
+	return nil
+}
+
+// FailedStep returns the first executed step that did not succeed, or nil.
+//
+// Example:
+//
+//	report, _ := result.JSScenarioReport()
+//	if step := report.FailedStep(); step != nil {
+//	    log.Printf("login step %d (%s) failed: %s", step.Index, step.Action, step.Error)
+//	}
+func (s *JSScenarioReport) FailedStep() *JSScenarioStepReport {
+	if s == nil {
+		return nil
+	}
+	for i := range s.Steps {
+		if s.Steps[i].Executed && !s.Steps[i].Success {
+			return &s.Steps[i]
+		}
+	}
+	return nil
+}
+
+// JSScenarioReport returns the execution report of the scenario, or nil
+// when the scrape ran none.
+func (r *ScrapeResult) JSScenarioReport() (*JSScenarioReport, error) {
+	var report JSScenarioReport
+	ok, err := remarshal(r.Result.BrowserData.JSScenario, &report)
+	if !ok || err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second))
+}
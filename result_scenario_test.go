@@ -0,0 +1,38 @@
+package scrapfly
+
+import (
+	"testing"
+	"time"
+)
+
+func TestScrapeResult_JSScenarioReport(t *testing.T) {
+	result := decodeResult(t, `{"result":{"browser_data":{"js_scenario":{
+		"duration":2.5,"executed":3,"steps":[
+			{"action":"fill","config":{"selector":"#user","value":"me"},"duration":0.3,"executed":true,"success":true},
+			{"action":"execute","config":{"script":"return 1"},"duration":0.1,"executed":true,"success":true,"result":1},
+			{"type":"click","config":{"selector":"#login"},"duration":2,"executed":true,"success":false,"error":{"message":"element not found"}},
+			{"action":"wait_for_navigation","executed":false,"success":false}
+		]}}}}`)
+
+	report, err := result.JSScenarioReport()
+	if err != nil || report == nil {
+		t.Fatalf("JSScenarioReport() = %v, %v", report, err)
+	}
+	if report.Duration != 2500*time.Millisecond || report.Executed != 3 || len(report.Steps) != 4 {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if got := report.Steps[1].Result; got != float64(1) {
+		t.Errorf("execute step result = %v", got)
+	}
+	failed := report.FailedStep()
+	if failed == nil || failed.Index != 2 || failed.Action != "click" || failed.Error != `{"message":"element not found"}` {
+		t.Errorf("FailedStep() = %+v", failed)
+	}
+
+	if report, err := (&ScrapeResult{}).JSScenarioReport(); report != nil || err != nil {
+		t.Errorf("expected no report, got %v, %v", report, err)
+	}
+	if (*JSScenarioReport)(nil).FailedStep() != nil {
+		t.Error("nil report must have no failed step")
+	}
+}
@@ -1,15 +1,19 @@
 package scrapfly
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
-
-	"github.com/PuerkitoBio/goquery"
 )
 
 // VerifyAPIKeyResult represents the result of an API key verification.
@@ -33,54 +37,170 @@ type ScrapeResult struct {
 	// UUID is the unique identifier for this scrape request.
 	UUID string `json:"uuid"`
 
-	selectorOnce sync.Once
-	selector     *goquery.Document
-	selectorErr  error
+	// rawContent is the content of blob results, fetched unencoded; see Bytes.
+	rawContent []byte
+	// charset is the charset the content was served in; see Charset.
+	charset string
+
+	// document caches the parsed content; see selector_goquery.go.
+	document *documentCache
+	// extra holds the members of the API response this SDK doesn't know;
+	// see result_json.go.
+	extra map[string]json.RawMessage
 }
 
-// Selector provides a goquery document for parsing HTML content.
-//
-// The selector is lazy-loaded and cached using sync.Once, making it safe
-// for concurrent use. It can only be used with HTML content.
+// documentCache is the document parsed from a result content. It is held
+// by pointer so results can be copied: copies share the parsed document
+// until their content changes.
+type documentCache struct {
+	once    sync.Once
+	content string
+	doc     interface{} // *goquery.Document
+	err     error
+}
+
+// documentCacheMu guards the document field of every result; it is only
+// held to swap the cache pointer, never while parsing.
+var documentCacheMu sync.Mutex
+
+// documentCache returns the parse cache of the current content.
+func (r *ScrapeResult) documentCache() *documentCache {
+	documentCacheMu.Lock()
+	defer documentCacheMu.Unlock()
+	if r.document == nil || r.document.content != r.Result.Content {
+		r.document = &documentCache{content: r.Result.Content}
+	}
+	return r.document
+}
+
+// Charset returns the charset the page was served in, as detected from
+// its content type and <meta> tags ("gbk", "shift_jis", "utf-8"...), or ""
+// when unknown. Content in another charset is transcoded to UTF-8 when the
+// result is received, so Content and Selector always see UTF-8.
+func (r *ScrapeResult) Charset() string {
+	return r.charset
+}
+
+// ContentFormat reports the format the content was returned in, so callers
+// can tell pre-converted markdown or text from raw HTML. It uses the format
+// echoed back by the API and falls back to the content type.
+func (r *ScrapeResult) ContentFormat() Format {
+	if r.Config.Format != nil && *r.Config.Format != "" {
+		name, _, _ := strings.Cut(*r.Config.Format, ":")
+		if format := Format(name); format.IsValid() {
+			return format
+		}
+	}
+	contentType := strings.ToLower(r.Result.ContentType)
+	switch {
+	case strings.Contains(contentType, "markdown"):
+		return FormatMarkdown
+	case strings.Contains(contentType, "application/json"):
+		return FormatJSON
+	case strings.Contains(contentType, "text/plain"):
+		return FormatText
+	}
+	return FormatRaw
+}
+
+// ExtractionResult represents the result of a data extraction request.
+type ExtractionResult struct {
+	// Data contains the extracted structured data.
+	Data interface{} `json:"data"`
+	// ContentType is the content type of the extracted data.
+	ContentType string `json:"content_type"`
+	// DataQuality indicates the quality/confidence of the extraction (if available).
+	DataQuality interface{} `json:"data_quality,omitempty"`
+}
+
+// Decode unmarshals the extracted data into v, typically a pointer to a
+// struct with json tags matching the extraction template or prompt output.
+func (e *ExtractionResult) Decode(v interface{}) error {
+	if e == nil || e.Data == nil {
+		return fmt.Errorf("no extracted data to decode")
+	}
+	_, err := remarshal(e.Data, v)
+	return err
+}
+
+// DecodeExtractedData unmarshals the data extracted during the scrape (with
+// ExtractionPrompt, ExtractionTemplate, ExtractionEphemeralTemplate or
+// ExtractionModel) into v, so structured data comes back in one call.
 //
 // Example:
 //
-//	result, err := client.Scrape(config)
-//	if err != nil {
+//	var product struct {
+//	    Name  string  `json:"name"`
+//	    Price float64 `json:"price"`
+//	}
+//	result, _ := client.Scrape(&scrapfly.ScrapeConfig{
+//	    URL:             "https://web-scraping.dev/product/1",
+//	    ExtractionModel: scrapfly.ExtractionModelProduct,
+//	})
+//	if err := result.DecodeExtractedData(&product); err != nil {
 //	    log.Fatal(err)
 //	}
+func (r *ScrapeResult) DecodeExtractedData(v interface{}) error {
+	if r.Result.ExtractedData == nil {
+		return fmt.Errorf("no extracted_data in result, set an extraction option on ScrapeConfig")
+	}
+	return r.Result.ExtractedData.Decode(v)
+}
+
+// DecodeJSON unmarshals a JSON response body, or content converted with
+// FormatJSON, into v. It fails with ErrContentType when the content type is
+// not JSON (application/json, text/json or a +json type), whatever its
+// charset; a leading byte order mark is ignored.
 //
-//	doc, err := result.Selector()
-//	if err != nil {
+// Example:
+//
+//	var products []Product
+//	result, _ := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://web-scraping.dev/api/products"})
+//	if err := result.DecodeJSON(&products); err != nil {
 //	    log.Fatal(err)
 //	}
+func (r *ScrapeResult) DecodeJSON(v interface{}) error {
+	if !isJSONContentType(r.ContentType()) && r.ContentFormat() != FormatJSON {
+		return fmt.Errorf("%w: cannot decode non-json content-type as JSON, got %s", ErrContentType, r.ContentType())
+	}
+	content := strings.TrimPrefix(r.Result.Content, "\ufeff")
+	if err := json.Unmarshal([]byte(content), v); err != nil {
+		return fmt.Errorf("failed to decode JSON content of %s: %w", r.Result.URL, err)
+	}
+	return nil
+}
+
+// Bytes returns the raw response body: the base64 content of binary
+// results (images, PDFs...) decoded, the content of text results as is.
 //
-//	title := doc.Find("title").First().Text()
-//	fmt.Println(title)
-func (r *ScrapeResult) Selector() (*goquery.Document, error) {
-	r.selectorOnce.Do(func() {
-		if !strings.Contains(r.Result.ContentType, "text/html") {
-			r.selectorErr = fmt.Errorf("%w: cannot use selector on non-html content-type, got %s", ErrContentType, r.Result.ContentType)
-			return
-		}
-		doc, err := goquery.NewDocumentFromReader(strings.NewReader(r.Result.Content))
-		if err != nil {
-			r.selectorErr = err
-			return
+// Example:
+//
+//	result, _ := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://web-scraping.dev/assets/pdf/eula.pdf"})
+//	body, err := result.Bytes()
+//	if err == nil {
+//	    os.WriteFile("eula.pdf", body, 0644)
+//	}
+func (r *ScrapeResult) Bytes() ([]byte, error) {
+	if r.rawContent != nil {
+		return r.rawContent, nil
+	}
+	if r.Result.Format != "binary" {
+		return []byte(r.Result.Content), nil
+	}
+	for _, encoding := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if data, err := encoding.DecodeString(r.Result.Content); err == nil {
+			return data, nil
 		}
-		r.selector = doc
-	})
-	return r.selector, r.selectorErr
+	}
+	return nil, fmt.Errorf("failed to decode binary content of %s: invalid base64", r.Result.URL)
 }
 
-// ExtractionResult represents the result of a data extraction request.
-type ExtractionResult struct {
-	// Data contains the extracted structured data.
-	Data interface{} `json:"data"`
-	// ContentType is the content type of the extracted data.
-	ContentType string `json:"content_type"`
-	// DataQuality indicates the quality/confidence of the extraction (if available).
-	DataQuality interface{} `json:"data_quality,omitempty"`
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || mediaType == "text/json" || strings.HasSuffix(mediaType, "+json")
 }
 
 // errorResponse is used to unmarshal generic API errors.
@@ -142,11 +262,17 @@ type ConfigData struct {
 	AutoScroll      bool                `json:"auto_scroll"`
 	CostBudget      *int                `json:"cost_budget"`
 	RenderingStage  string              `json:"rendering_stage"`
+	// Format echoes back the format parameter, including its options
+	// (e.g. "markdown:no_links"). Nullable when the raw content was requested.
+	Format          *string             `json:"format,omitempty"`
 	Env             string              `json:"env"`
 	Origin          string              `json:"origin"`
 	Project         string              `json:"project"`
 	UserUUID        string              `json:"user_uuid"`
 	UUID            string              `json:"uuid"`
+
+	// extra holds the members this SDK doesn't know; see result_json.go.
+	extra map[string]json.RawMessage
 }
 
 // ContextData contains metadata about the scrape request execution.
@@ -178,6 +304,9 @@ type ContextData struct {
 	URI              URIContext        `json:"uri"`
 	URL              string            `json:"url"`
 	Webhook          interface{}       `json:"webhook"`
+
+	// extra holds the members this SDK doesn't know; see result_json.go.
+	extra map[string]json.RawMessage
 }
 
 // ResultData contains the scraped content and response information.
@@ -207,6 +336,9 @@ type ResultData struct {
 	Success         bool                   `json:"success"`
 	URL             string                 `json:"url"`
 	ExtractedData   *ExtractionResult      `json:"extracted_data"`
+
+	// extra holds the members this SDK doesn't know; see result_json.go.
+	extra map[string]json.RawMessage
 }
 
 // --- Nested Structures for Context and Result ---
@@ -313,13 +445,49 @@ type APIErrorDetails struct {
 	DocURL    string            `json:"doc_url"`
 }
 
-// IFrame represents an iframe found in the page.
+// IFrame represents an iframe found in the page, with the content it
+// rendered. Widgets such as reviews or pricing often live in iframes; use
+// Result to apply the result helpers (Selector, Links, StructuredData...) to
+// the iframe content.
 type IFrame struct {
 	URL     string     `json:"url"`
 	URI     URIContext `json:"uri"`
 	Content string     `json:"content"`
 }
 
+// Result returns the iframe as a standalone HTML result, with the iframe
+// URL as final URL. Each call returns a new result, keep it to reuse its
+// parsed Selector.
+//
+// Example:
+//
+//	for _, iframe := range result.Result.IFrames {
+//	    if strings.Contains(iframe.URL, "reviews") {
+//	        doc, _ := iframe.Result().Selector()
+//	        fmt.Println(doc.Find(".review").Length(), "reviews")
+//	    }
+//	}
+func (f IFrame) Result() *ScrapeResult {
+	return &ScrapeResult{Result: ResultData{
+		URL:         f.URL,
+		Content:     f.Content,
+		ContentType: "text/html",
+		Format:      "text",
+		Status:      "DONE",
+		Success:     true,
+	}}
+}
+
+// IFrame returns the first iframe whose URL contains substr, or nil.
+func (r *ScrapeResult) IFrame(substr string) *IFrame {
+	for i := range r.Result.IFrames {
+		if strings.Contains(r.Result.IFrames[i].URL, substr) {
+			return &r.Result.IFrames[i]
+		}
+	}
+	return nil
+}
+
 // Screenshot represents a screenshot captured during rendering.
 type Screenshot struct {
 	// CSSSelector is the CSS selector of the element to capture. If Format == fullpage, this will be nil
@@ -341,14 +509,28 @@ type Screenshot struct {
 
 // Image returns the screenshot data as a byte slice.
 func (s *Screenshot) Image() ([]byte, error) {
+	return s.Download(context.Background())
+}
+
+// Download fetches the screenshot data, like Image, with ctx bounding the
+// request. The data is kept on s, so later calls and Save don't fetch it
+// again.
+func (s *Screenshot) Download(ctx context.Context) ([]byte, error) {
 	if s.image != nil {
 		return s.image, nil
 	}
-	resp, err := http.Get(s.URL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download screenshot %s: status %d", s.Name, resp.StatusCode)
+	}
 	s.image, err = io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
@@ -443,6 +625,117 @@ func (s *Screenshot) Save(savePath ...string) (string, error) {
 	return filePath, err
 }
 
+// ScreenshotNames lists the names of the screenshots captured during the
+// scrape (the keys of ScrapeConfig.Screenshots), sorted.
+func (r *ScrapeResult) ScreenshotNames() []string {
+	names := make([]string, 0, len(r.Result.Screenshots))
+	for name := range r.Result.Screenshots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DownloadScreenshot downloads the screenshot captured under name. The image
+// is kept on the result, so later calls (and Save) don't download it again.
+//
+// Example:
+//
+//	result, _ := client.Scrape(&scrapfly.ScrapeConfig{
+//	    URL:         "https://web-scraping.dev/product/1",
+//	    RenderJS:    true,
+//	    Screenshots: map[string]string{"page": "fullpage", "reviews": "#reviews"},
+//	})
+//	for _, name := range result.ScreenshotNames() {
+//	    img, err := result.DownloadScreenshot(name)
+//	    ...
+//	}
+func (r *ScrapeResult) DownloadScreenshot(name string) ([]byte, error) {
+	screenshot, ok := r.Result.Screenshots[name]
+	if !ok {
+		return nil, fmt.Errorf("screenshot %q not found in result", name)
+	}
+	image, err := screenshot.Image()
+	if err != nil {
+		return nil, err
+	}
+	r.Result.Screenshots[name] = screenshot
+	return image, nil
+}
+
+// Screenshots is a list of screenshots; see ScrapeResult.Screenshots.
+type Screenshots []*Screenshot
+
+// Screenshots returns the screenshots captured during the scrape, sorted by
+// name. Their URLs carry the API key when the result comes from
+// Client.Scrape; use WithKey for results decoded from a webhook or loaded
+// from disk.
+//
+// Example:
+//
+//	paths, err := result.Screenshots().Download(ctx, "./screenshots")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+func (r *ScrapeResult) Screenshots() Screenshots {
+	screenshots := make(Screenshots, 0, len(r.Result.Screenshots))
+	for _, name := range r.ScreenshotNames() {
+		screenshot := r.Result.Screenshots[name]
+		if screenshot.Name == "" {
+			screenshot.Name = name
+		}
+		screenshots = append(screenshots, &screenshot)
+	}
+	return screenshots
+}
+
+// WithKey authenticates the screenshot URLs without an API key with key,
+// in place, and returns s.
+func (s Screenshots) WithKey(key string) Screenshots {
+	for _, screenshot := range s {
+		screenshot.URL = withAPIKey(screenshot.URL, key)
+	}
+	return s
+}
+
+// Download fetches each screenshot and saves it to disk as <name>.<extension>,
+// stopping at the first error or when ctx is done.
+//
+// Parameters:
+//   - savePath: Optional directory path where to save the files (defaults to current directory)
+//     (if savePath does not exists, it will be created in a best effort basis)
+//
+// Returns the full paths to the saved files, in the order of s.
+func (s Screenshots) Download(ctx context.Context, savePath ...string) ([]string, error) {
+	paths := make([]string, 0, len(s))
+	for _, screenshot := range s {
+		if _, err := screenshot.Download(ctx); err != nil {
+			return paths, err
+		}
+		filePath, err := screenshot.Save(savePath...)
+		if err != nil {
+			return paths, err
+		}
+		paths = append(paths, filePath)
+	}
+	return paths, nil
+}
+
+// withAPIKey adds the key query parameter to rawURL unless it has one.
+func withAPIKey(rawURL, key string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || key == "" {
+		return rawURL
+	}
+	query := u.Query()
+	if query.Has("key") {
+		return rawURL
+	}
+	query.Set("key", key)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
 // SaveScreenshots is a shortcut to save all screenshots to disk
 //
 // Parameters:
@@ -0,0 +1,205 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestScrapeResult_ContentFormat(t *testing.T) {
+	echoed := "markdown:no_links,no_images"
+	cases := []struct {
+		name   string
+		result *ScrapeResult
+		want   Format
+	}{
+		{"echoed with options", &ScrapeResult{Config: ConfigData{Format: &echoed}}, FormatMarkdown},
+		{"text content type", &ScrapeResult{Result: ResultData{ContentType: "text/plain; charset=utf-8"}}, FormatText},
+		{"html", &ScrapeResult{Result: ResultData{ContentType: "text/html"}}, FormatRaw},
+	}
+	for _, tc := range cases {
+		if got := tc.result.ContentFormat(); got != tc.want {
+			t.Errorf("%s: ContentFormat() = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestScrapeResult_DownloadScreenshot(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/page" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("JPEG"))
+	}))
+	defer server.Close()
+
+	result := &ScrapeResult{}
+	result.Result.Screenshots = map[string]Screenshot{
+		"page":    {URL: server.URL + "/page", Extension: "jpg", Name: "page"},
+		"missing": {URL: server.URL + "/gone", Extension: "jpg", Name: "missing"},
+	}
+	if names := result.ScreenshotNames(); len(names) != 2 || names[0] != "missing" || names[1] != "page" {
+		t.Errorf("unexpected names %v", names)
+	}
+	img, err := result.DownloadScreenshot("page")
+	if err != nil || string(img) != "JPEG" {
+		t.Fatalf("DownloadScreenshot() = %q, %v", img, err)
+	}
+	if result.Result.Screenshots["page"].image == nil {
+		t.Error("downloaded image should be kept on the result")
+	}
+	if _, err := result.DownloadScreenshot("missing"); err == nil {
+		t.Error("expected an error for a non-200 download")
+	}
+	if _, err := result.DownloadScreenshot("unknown"); err == nil {
+		t.Error("expected an error for an unknown screenshot")
+	}
+}
+
+func TestClient_Scrape_DecodesExtractedData(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("extraction_model") != "product" {
+			t.Errorf("extraction_model not sent: %s", r.URL.RawQuery)
+		}
+		w.Write([]byte(`{"result":{"success":true,"status":"DONE","extracted_data":{"content_type":"application/json","data":{"name":"Box","price":9.5}}}}`))
+	})
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com", ExtractionModel: ExtractionModelProduct})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var product struct {
+		Name  string  `json:"name"`
+		Price float64 `json:"price"`
+	}
+	if err := result.DecodeExtractedData(&product); err != nil {
+		t.Fatal(err)
+	}
+	if product.Name != "Box" || product.Price != 9.5 {
+		t.Errorf("unexpected product %+v", product)
+	}
+	if err := (&ScrapeResult{}).DecodeExtractedData(&product); err == nil {
+		t.Error("expected an error without extracted data")
+	}
+}
+
+func TestScrapeResult_DecodeJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		content     string
+		wantErr     error
+	}{
+		{"json with charset", "application/json; charset=utf-8", `{"name":"box"}`, nil},
+		{"vendor json", "application/vnd.api+json", `{"name":"box"}`, nil},
+		{"byte order mark", "application/json", "\ufeff" + `{"name":"box"}`, nil},
+		{"html", "text/html; charset=utf-8", "<html></html>", ErrContentType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := &ScrapeResult{Result: ResultData{ContentType: tt.contentType, Content: tt.content}}
+			var v struct{ Name string }
+			err := result.DecodeJSON(&v)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || v.Name != "box" {
+				t.Errorf("DecodeJSON() = %v, %+v", err, v)
+			}
+		})
+	}
+}
+
+func TestScrapeResult_Bytes(t *testing.T) {
+	binary := &ScrapeResult{Result: ResultData{Format: "binary", Content: "JVBERi0xLjQK"}}
+	if got, err := binary.Bytes(); err != nil || string(got) != "%PDF-1.4\n" {
+		t.Errorf("Bytes() = %q, %v", got, err)
+	}
+	text := &ScrapeResult{Result: ResultData{Format: "text", Content: "<html></html>"}}
+	if got, err := text.Bytes(); err != nil || string(got) != "<html></html>" {
+		t.Errorf("Bytes() = %q, %v", got, err)
+	}
+	invalid := &ScrapeResult{Result: ResultData{Format: "binary", Content: "not base64!"}}
+	if _, err := invalid.Bytes(); err == nil {
+		t.Error("expected an error for invalid base64")
+	}
+}
+
+func TestClient_Scrape_BlobBytes(t *testing.T) {
+	var blobURL string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/blob" {
+			w.Write([]byte{0x89, 'P', 'N', 'G'})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result":{"success":true,"status":"DONE","status_code":200,"format":"blob","content":"` + blobURL + `"}}`))
+	})
+	blobURL = client.host + "/blob"
+
+	result, err := client.Scrape(&ScrapeConfig{URL: "https://example.com/image.png"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := result.Bytes(); err != nil || string(got) != "\x89PNG" {
+		t.Errorf("Bytes() = %q, %v", got, err)
+	}
+}
+
+func TestScrapeResult_Screenshots(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("IMG" + r.URL.Path))
+	}))
+	defer server.Close()
+
+	result := &ScrapeResult{}
+	result.Result.Screenshots = map[string]Screenshot{
+		"reviews": {URL: server.URL + "/reviews", Extension: "jpg"},
+		"page":    {URL: server.URL + "/page?key=secret", Extension: "png"},
+	}
+	if _, err := result.Screenshots().Download(context.Background(), t.TempDir()); err == nil {
+		t.Error("expected an error for an unauthenticated download")
+	}
+
+	dir := t.TempDir()
+	paths, err := result.Screenshots().WithKey("secret").Download(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 || paths[0] != filepath.Join(dir, "page.png") || paths[1] != filepath.Join(dir, "reviews.jpg") {
+		t.Fatalf("unexpected paths %v", paths)
+	}
+	if data, _ := os.ReadFile(paths[1]); string(data) != "IMG/reviews" {
+		t.Errorf("saved %q", data)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := result.Screenshots().WithKey("secret").Download(ctx, t.TempDir()); !errors.Is(err, context.Canceled) {
+		t.Errorf("Download() with a canceled context = %v", err)
+	}
+}
+
+func TestWithAPIKey(t *testing.T) {
+	cases := map[string]string{
+		"https://api.scrapfly.io/shot/1":          "https://api.scrapfly.io/shot/1?key=k",
+		"https://api.scrapfly.io/shot/1?v=2":      "https://api.scrapfly.io/shot/1?key=k&v=2",
+		"https://api.scrapfly.io/shot/1?key=mine": "https://api.scrapfly.io/shot/1?key=mine",
+	}
+	for in, want := range cases {
+		if got := withAPIKey(in, "k"); got != want {
+			t.Errorf("withAPIKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
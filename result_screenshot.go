@@ -1,12 +1,22 @@
 package scrapfly
 
 import (
+	"bytes"
 	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // ScreenshotResult represents a screenshot captured by the API.
@@ -25,40 +35,192 @@ type ScreenshotMetadata struct {
 	UpstreamStatusCode int
 	// UpstreamURL is the final URL after any redirects.
 	UpstreamURL string
+	// Format is the image format, from the content type.
+	Format ScreenshotFormat
+	// Width and Height are the image size in pixels, zero when the format
+	// has no registered decoder (webp, see Decode).
+	Width  int
+	Height int
+	// Size is the image size in bytes.
+	Size int
+	// Resolution is the viewport requested, empty when the API picked it.
+	Resolution string
+	// CapturedAt is the time the screenshot was received.
+	CapturedAt time.Time
+}
+
+// screenshotExtensions are the file extensions of the image content types.
+var screenshotExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+	"image/gif":  "gif",
 }
 
 // newScreenshotResult creates a ScreenshotResult from an HTTP response.
 func newScreenshotResult(resp *http.Response, data []byte) (*ScreenshotResult, error) {
 	contentType := resp.Header.Get("Content-Type")
-	ext := "bin"
-	if parts := strings.Split(contentType, "/"); len(parts) == 2 {
-		ext = strings.Split(parts[1], ";")[0]
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = http.DetectContentType(data)
 	}
 
 	statusCodeStr := resp.Header.Get("x-scrapfly-upstream-http-code")
 	statusCode, _ := strconv.Atoi(statusCodeStr)
 
-	return &ScreenshotResult{
+	ext := screenshotExtension(contentType)
+	result := &ScreenshotResult{
 		Image: data,
 		Metadata: ScreenshotMetadata{
 			ExtensionName:      ext,
 			UpstreamStatusCode: statusCode,
 			UpstreamURL:        resp.Header.Get("x-scrapfly-upstream-url"),
+			Format:             ScreenshotFormat(ext),
+			CapturedAt:         time.Now(),
 		},
-	}, nil
+	}
+	result.measure()
+	return result, nil
+}
+
+// measure sets the size metadata of the image.
+func (s *ScreenshotResult) measure() {
+	s.Metadata.Size = len(s.Image)
+	s.Metadata.Width, s.Metadata.Height = 0, 0
+	if config, _, err := image.DecodeConfig(bytes.NewReader(s.Image)); err == nil {
+		s.Metadata.Width, s.Metadata.Height = config.Width, config.Height
+	}
+}
+
+// Decode decodes the screenshot, for post-processing (thumbnails, OCR...)
+// without a temporary file. jpg, png and gif are supported; import a webp
+// decoder to decode webp screenshots:
+//
+//	import _ "golang.org/x/image/webp"
+func (s *ScreenshotResult) Decode() (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(s.Image))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s screenshot: %w", s.Metadata.ExtensionName, err)
+	}
+	return img, nil
+}
+
+// screenshotExtension returns the file extension of contentType: the
+// known one, else its subtype ("svg+xml"), else "bin".
+func screenshotExtension(contentType string) string {
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	mediaType = strings.TrimSpace(mediaType)
+	if ext, ok := screenshotExtensions[mediaType]; ok {
+		return ext
+	}
+	if _, subtype, ok := strings.Cut(mediaType, "/"); ok && subtype != "" {
+		return subtype
+	}
+	return "bin"
 }
 
+// reencode crops the image to clip, when set, clamped to the image bounds,
+// and encodes it again in its format, JPEGs with quality (see
+// ScreenshotConfig.Quality; 0 keeps the default).
+func (s *ScreenshotResult) reencode(clip *ScreenshotClip, quality int) error {
+	img, format, err := image.Decode(bytes.NewReader(s.Image))
+	if err != nil {
+		return fmt.Errorf("failed to decode %s screenshot: %w", s.Metadata.ExtensionName, err)
+	}
+	if clip != nil {
+		rect := image.Rect(clip.X, clip.Y, clip.X+clip.Width, clip.Y+clip.Height).Add(img.Bounds().Min).Intersect(img.Bounds())
+		if rect.Empty() {
+			return fmt.Errorf("%w: Clip %+v is outside the %dx%d screenshot", ErrScreenshotConfig, *clip, img.Bounds().Dx(), img.Bounds().Dy())
+		}
+		cropped := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+		draw.Draw(cropped, cropped.Bounds(), img, rect.Min, draw.Src)
+		img = cropped
+	}
+	if quality == 0 {
+		quality = 90
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "png":
+		err = png.Encode(&buf, img)
+	case "jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	case "gif":
+		err = gif.Encode(&buf, img, nil)
+	default:
+		err = fmt.Errorf("unsupported format %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode %s screenshot: %w", s.Metadata.ExtensionName, err)
+	}
+	s.Image = buf.Bytes()
+	s.measure()
+	return nil
+}
+
+// WriteTo writes the image to w, to stream it to storage (an S3 upload, an
+// HTTP response...) without a file. It implements io.WriterTo.
+func (s *ScreenshotResult) WriteTo(w io.Writer) (int64, error) {
+	n, err := w.Write(s.Image)
+	return int64(n), err
+}
+
+// screenshotPlaceholderRe matches the placeholders of a Save name.
+var screenshotPlaceholderRe = regexp.MustCompile(`\{(host|timestamp|date|resolution|format)\}`)
+
+// Filename expands the placeholders of template, a file name without
+// extension:
+//   - {host}: the host of UpstreamURL
+//   - {timestamp}: CapturedAt in UTC, 20060102T150405Z
+//   - {date}: CapturedAt in UTC, 2006-01-02
+//   - {resolution}: Resolution, "default" when the API picked it
+//   - {format}: ExtensionName
+//
+// Values are made safe for file names; other text is kept as is.
+func (s *ScreenshotResult) Filename(template string) string {
+	return screenshotPlaceholderRe.ReplaceAllStringFunc(template, func(placeholder string) string {
+		var value string
+		switch placeholder {
+		case "{host}":
+			if u, err := url.Parse(s.Metadata.UpstreamURL); err == nil {
+				value = u.Host
+			}
+		case "{timestamp}":
+			value = s.Metadata.CapturedAt.UTC().Format("20060102T150405Z")
+		case "{date}":
+			value = s.Metadata.CapturedAt.UTC().Format("2006-01-02")
+		case "{resolution}":
+			value = s.Metadata.Resolution
+			if value == "" {
+				value = "default"
+			}
+		case "{format}":
+			value = s.Metadata.ExtensionName
+		}
+		if value = safeFilenameRe.ReplaceAllString(value, "_"); value == "" {
+			value = "unknown"
+		}
+		return value
+	})
+}
+
+// safeFilenameRe matches the characters replaced in file name values.
+var safeFilenameRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
 // Save saves a screenshot result to disk.
 //
 // Parameters:
-//   - name: The base name for the file (without extension)
+//   - name: The base name for the file, without extension: the one of the
+//     image content type (jpg, png, webp, gif) is added. It is expanded
+//     by Filename, and may contain "/" for subdirectories.
 //   - savePath: Optional directory path where to save the file (defaults to current directory)
 //
-// Returns the full path to the saved file.
+// Missing directories are created. Returns the full path to the saved file.
 //
 // Example:
 //
-//	filePath, err := s.Save("example", "./screenshots")
+//	// ./screenshots/web-scraping.dev/2024-05-01/web-scraping.dev_20240501T101500Z_1920x1080.png
+//	filePath, err := s.Save("{host}/{date}/{host}_{timestamp}_{resolution}", "./screenshots")
 //	if err != nil {
 //	    log.Fatal(err)
 //	}
@@ -71,10 +233,19 @@ func (s *ScreenshotResult) Save(name string, savePath ...string) (string, error)
 	if len(savePath) > 0 {
 		dir = savePath[0]
 	}
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	ext := "." + s.Metadata.ExtensionName
+	filePath := filepath.Join(dir, filepath.FromSlash(strings.TrimSuffix(s.Filename(name), ext)+ext))
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
 		return "", err
 	}
-	filePath := filepath.Join(dir, fmt.Sprintf("%s.%s", name, s.Metadata.ExtensionName))
 	err := os.WriteFile(filePath, s.Image, 0644)
 	return filePath, err
 }
+
+// SaveScreenshot saves result to disk, named name, a Filename template,
+// with the extension of its image format, in savePath (the current
+// directory by default); see ScreenshotResult.Save. It returns the path of
+// the file.
+func (c *Client) SaveScreenshot(result *ScreenshotResult, name string, savePath ...string) (string, error) {
+	return result.Save(name, savePath...)
+}
@@ -0,0 +1,299 @@
+package scrapfly
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScreenshotConfig_SelectorAndClip(t *testing.T) {
+	cfg := &ScreenshotConfig{URL: "https://example.com", Selector: "#pricing"}
+	params, err := cfg.toAPIParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params.Get("capture"); got != "#pricing" {
+		t.Errorf("capture = %q, want #pricing", got)
+	}
+
+	for _, bad := range []*ScreenshotConfig{
+		{URL: "https://example.com", Selector: "#pricing", Capture: "fullpage"},
+		{URL: "https://example.com", Clip: &ScreenshotClip{Width: 0, Height: 10}},
+		{URL: "https://example.com", Clip: &ScreenshotClip{X: -1, Width: 10, Height: 10}},
+		{URL: "https://example.com", Format: FormatWEBP, Clip: &ScreenshotClip{Width: 10, Height: 10}},
+	} {
+		if _, err := bad.toAPIParams(); !errors.Is(err, ErrScreenshotConfig) {
+			t.Errorf("%+v: got %v, want ErrScreenshotConfig", bad, err)
+		}
+	}
+}
+
+func TestScreenshotConfig_HideSelectors(t *testing.T) {
+	cfg := &ScreenshotConfig{
+		URL:           "https://example.com",
+		Options:       []ScreenshotOption{OptionBlockBanners},
+		HideSelectors: append([]string{"#promo", " "}, ChatWidgetSelectors...),
+		JS:            "window.scrollTo(0, 0);",
+	}
+	params, err := cfg.toAPIParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := params.Get("options"); got != "block_banners" {
+		t.Errorf("options = %q", got)
+	}
+	decoded, _ := base64.RawURLEncoding.DecodeString(params.Get("js"))
+	js := string(decoded)
+	if !strings.Contains(js, `#promo{display:none!important`) || !strings.Contains(js, `.crisp-client{display:none!important`) {
+		t.Errorf("selectors not hidden: %s", js)
+	}
+	if strings.Contains(js, `\n{display`) {
+		t.Errorf("blank selector kept: %s", js)
+	}
+	if !strings.HasSuffix(js, "})();\nwindow.scrollTo(0, 0);") {
+		t.Errorf("custom JS must run after the style: %s", js)
+	}
+
+	params, _ = (&ScreenshotConfig{URL: "https://example.com", HideSelectors: []string{""}}).toAPIParams()
+	if params.Has("js") {
+		t.Errorf("js = %q, want none", params.Get("js"))
+	}
+}
+
+func TestClient_Screenshot_Clip(t *testing.T) {
+	// a 40x30 image, red from x=10 y=5
+	src := image.NewRGBA(image.Rect(0, 0, 40, 30))
+	for y := 5; y < 30; y++ {
+		for x := 10; x < 40; x++ {
+			src.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	png.Encode(&encoded, src)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(encoded.Bytes())
+	})
+
+	result, err := client.Screenshot(&ScreenshotConfig{URL: "https://example.com", Format: FormatPNG, Clip: &ScreenshotClip{X: 10, Y: 5, Width: 50, Height: 20}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(bytes.NewReader(result.Image))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// clamped to the image width
+	if b := img.Bounds(); b.Dx() != 30 || b.Dy() != 20 {
+		t.Errorf("clipped to %v, want 30x20", b)
+	}
+	if r, _, _, _ := img.At(0, 0).RGBA(); r != 0xffff {
+		t.Errorf("clip origin is not red: %v", img.At(0, 0))
+	}
+
+	_, err = client.Screenshot(&ScreenshotConfig{URL: "https://example.com", Clip: &ScreenshotClip{X: 100, Y: 100, Width: 10, Height: 10}})
+	if !errors.Is(err, ErrScreenshotConfig) {
+		t.Errorf("clip outside the image: got %v, want ErrScreenshotConfig", err)
+	}
+}
+
+func TestScreenshotConfig_FormatAndQuality(t *testing.T) {
+	for _, format := range []ScreenshotFormat{FormatJPG, FormatPNG, FormatWEBP, FormatGIF} {
+		params, err := (&ScreenshotConfig{URL: "https://example.com", Format: format}).toAPIParams()
+		if err != nil || params.Get("format") != string(format) {
+			t.Errorf("format %s: got %q, %v", format, params.Get("format"), err)
+		}
+	}
+	if _, err := (&ScreenshotConfig{URL: "https://example.com", Format: FormatJPG, Quality: 80}).toAPIParams(); err != nil {
+		t.Errorf("jpg quality: %v", err)
+	}
+	for _, bad := range []*ScreenshotConfig{
+		{URL: "https://example.com", Format: "bmp"},
+		{URL: "https://example.com", Quality: 101},
+		{URL: "https://example.com", Quality: -5},
+		{URL: "https://example.com", Format: FormatPNG, Quality: 80},
+	} {
+		if _, err := bad.toAPIParams(); !errors.Is(err, ErrScreenshotConfig) {
+			t.Errorf("%+v: got %v, want ErrScreenshotConfig", bad, err)
+		}
+	}
+}
+
+func TestClient_Screenshot_QualityAndSave(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for i := range src.Pix {
+		src.Pix[i] = byte(i * 7)
+	}
+	var encoded bytes.Buffer
+	jpeg.Encode(&encoded, src, &jpeg.Options{Quality: 100})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg; charset=binary")
+		w.Write(encoded.Bytes())
+	})
+
+	result, err := client.Screenshot(&ScreenshotConfig{URL: "https://example.com", Format: FormatJPG, Quality: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Image) >= encoded.Len() {
+		t.Errorf("quality 10 image is %d bytes, captured %d", len(result.Image), encoded.Len())
+	}
+	if result.Metadata.ExtensionName != "jpg" {
+		t.Errorf("extension = %q, want jpg", result.Metadata.ExtensionName)
+	}
+	dir := t.TempDir()
+	for _, name := range []string{"pricing", "pricing.jpg"} {
+		path, err := client.SaveScreenshot(result, name, filepath.Join(dir, "shots"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := filepath.Join(dir, "shots", "pricing.jpg"); path != want {
+			t.Errorf("saved %q as %s, want %s", name, path, want)
+		}
+	}
+}
+
+func TestScreenshotExtension(t *testing.T) {
+	for contentType, want := range map[string]string{
+		"image/jpeg":               "jpg",
+		"IMAGE/PNG":                "png",
+		"image/webp; q=1":          "webp",
+		"image/gif":                "gif",
+		"image/svg+xml":            "svg+xml",
+		"":                         "bin",
+		"application/octet-stream": "octet-stream",
+	} {
+		if got := screenshotExtension(contentType); got != want {
+			t.Errorf("screenshotExtension(%q) = %q, want %q", contentType, got, want)
+		}
+	}
+}
+
+func TestScreenshotConfig_RenderingParams(t *testing.T) {
+	cfg := &ScreenshotConfig{
+		URL:             "https://example.com",
+		Capture:         "fullpage",
+		WaitForSelector: ".pricing",
+		RenderingWait:   3000,
+		AutoScroll:      true,
+		JS:              "document.querySelector('.more').click()",
+	}
+	params, err := cfg.toAPIParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"wait_for_selector": ".pricing",
+		"rendering_wait":    "3000",
+		"auto_scroll":       "true",
+		"js":                base64.RawURLEncoding.EncodeToString([]byte(cfg.JS)),
+	}
+	for k, v := range want {
+		if got := params.Get(k); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+	for _, wait := range []int{-1, 25001} {
+		if _, err := (&ScreenshotConfig{URL: "https://example.com", RenderingWait: wait}).toAPIParams(); !errors.Is(err, ErrScreenshotConfig) {
+			t.Errorf("rendering wait %d: got %v, want ErrScreenshotConfig", wait, err)
+		}
+	}
+}
+
+func TestClient_Screenshot_DecodeAndMetadata(t *testing.T) {
+	var encoded bytes.Buffer
+	png.Encode(&encoded, image.NewGray(image.Rect(0, 0, 120, 80)))
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("format") == "webp" {
+			w.Header().Set("Content-Type", "image/webp")
+			w.Write([]byte("RIFF....WEBPVP8 "))
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(encoded.Bytes())
+	})
+
+	result, err := client.Screenshot(&ScreenshotConfig{URL: "https://example.com", Format: FormatPNG})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := result.Metadata; m.Format != FormatPNG || m.Width != 120 || m.Height != 80 || m.Size != encoded.Len() {
+		t.Errorf("metadata = %+v", m)
+	}
+	img, err := result.Decode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 120 || b.Dy() != 80 {
+		t.Errorf("decoded %v", b)
+	}
+
+	result, err = client.Screenshot(&ScreenshotConfig{URL: "https://example.com", Format: FormatPNG, Clip: &ScreenshotClip{Width: 30, Height: 20}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := result.Metadata; m.Width != 30 || m.Height != 20 || m.Size != len(result.Image) {
+		t.Errorf("clipped metadata = %+v", m)
+	}
+
+	// no webp decoder is registered
+	result, err = client.Screenshot(&ScreenshotConfig{URL: "https://example.com", Format: FormatWEBP})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m := result.Metadata; m.Format != FormatWEBP || m.Width != 0 || m.Size != 16 {
+		t.Errorf("webp metadata = %+v", m)
+	}
+	if _, err := result.Decode(); err == nil {
+		t.Error("decoded a webp screenshot without decoder")
+	}
+}
+
+func TestClient_Screenshot_WriteToAndTemplate(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Header().Set("x-scrapfly-upstream-url", "https://web-scraping.dev:8443/pricing")
+		w.Write([]byte("PNG"))
+	})
+	result, err := client.Screenshot(&ScreenshotConfig{URL: "https://web-scraping.dev/pricing", Resolution: "375x812"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if n, err := result.WriteTo(&buf); err != nil || n != 3 || buf.String() != "PNG" {
+		t.Errorf("WriteTo wrote %d bytes %q, %v", n, buf.String(), err)
+	}
+
+	result.Metadata.CapturedAt = time.Date(2024, 5, 1, 12, 15, 0, 0, time.FixedZone("CEST", 2*3600))
+	if got, want := result.Filename("{host}_{timestamp}_{resolution}.{format}-{other}"), "web-scraping.dev_8443_20240501T101500Z_375x812.png-{other}"; got != want {
+		t.Errorf("Filename = %q, want %q", got, want)
+	}
+
+	dir := t.TempDir()
+	path, err := client.SaveScreenshot(result, "{host}/{date}/{resolution}", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "web-scraping.dev_8443", "2024-05-01", "375x812.png"); path != want {
+		t.Errorf("saved as %s, want %s", path, want)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "PNG" {
+		t.Errorf("saved %q, %v", data, err)
+	}
+
+	result.Metadata.Resolution, result.Metadata.UpstreamURL = "", ""
+	if got := result.Filename("{host}-{resolution}"); got != "unknown-default" {
+		t.Errorf("Filename = %q, want unknown-default", got)
+	}
+}
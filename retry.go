@@ -0,0 +1,115 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultRetryOn are the errors RetryPolicy retries when RetryOn is empty:
+// the transient proxy and anti-bot bypass failures and upstream 5xx.
+var DefaultRetryOn = []error{ErrProxyFailed, ErrASPBypassFailed, ErrUpstreamServer}
+
+// RetryPolicy retries the failed scrapes of a concurrent run
+// (ConcurrentScrapeOptions.Retry) before they are reported.
+type RetryPolicy struct {
+	// MaxAttempts is the number of scrapes made per config, the first one
+	// included. Values <= 1 disable retries.
+	MaxAttempts int
+	// RetryOn are the errors that are retried, matched with errors.Is.
+	// Defaults to DefaultRetryOn. Errors the API flags as retryable
+	// (APIError.Retryable, such as throttling) are retried too.
+	RetryOn []error
+	// Backoff is the wait before the first retry, doubled on each
+	// following one. Defaults to one second. An APIError.RetryAfterMs
+	// longer than the backoff is waited instead.
+	Backoff time.Duration
+	// MaxBackoff caps the wait between attempts. Zero means no cap.
+	MaxBackoff time.Duration
+}
+
+// retryable reports whether err is retried by the policy. Context errors
+// never are.
+func (p *RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.Retryable {
+		return true
+	}
+	retryOn := p.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = DefaultRetryOn
+	}
+	for _, target := range retryOn {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the wait after the failed attempt (1-based) that ended
+// with err.
+func (p *RetryPolicy) delay(attempt int, err error) time.Duration {
+	wait := p.Backoff
+	if wait <= 0 {
+		wait = time.Second
+	}
+	for i := 1; i < attempt; i++ {
+		wait *= 2
+		if p.MaxBackoff > 0 && wait >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		wait = max(wait, time.Duration(apiErr.RetryAfterMs)*time.Millisecond)
+	}
+	return wait
+}
+
+// scrapeRetrying scrapes config, retrying its failures as policy says, and
+// returns the last outcome with the number of attempts made and the
+// credits they spent. A nil policy makes a single attempt. The attempts
+// share the correlation ID of a Debug scrape.
+func (c *Client) scrapeRetrying(ctx context.Context, config *ScrapeConfig, policy *RetryPolicy) (*ScrapeResult, int, int, error) {
+	ctx, config = c.correlate(ctx, config)
+	return retrying(ctx, policy, config.URL, func(ctx context.Context) (*ScrapeResult, error) { return c.ScrapeContext(ctx, config) }, scrapeCost)
+}
+
+// retrying runs attempt, for the page at url, retrying its failures as
+// policy says; see scrapeRetrying.
+func retrying[R any](ctx context.Context, policy *RetryPolicy, url string, attempt func(ctx context.Context) (R, error), costOf func(R, error) int) (result R, attempts, cost int, err error) {
+	for attempts = 1; ; attempts++ {
+		result, err = attempt(ctx)
+		cost += costOf(result, err)
+		if err == nil || policy == nil || attempts >= policy.MaxAttempts || !policy.retryable(err) {
+			return result, attempts, cost, err
+		}
+		wait := policy.delay(attempts, err)
+		DefaultLogger.Debug(logArgs(ctx, "request for", url, "failed:", err, "retrying in", wait)...)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return result, attempts, cost, err
+		}
+	}
+}
+
+// scrapeCost returns the credits spent by a scrape, taken from the API
+// error response when it failed.
+func scrapeCost(result *ScrapeResult, err error) int {
+	if result != nil {
+		return result.Cost()
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.APIResponse != nil {
+		return apiErr.APIResponse.Cost()
+	}
+	return 0
+}
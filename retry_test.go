@@ -0,0 +1,72 @@
+package scrapfly
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_ConcurrentScrapeWithOptions_Retry(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		u := r.URL.Query().Get("url")
+		mu.Lock()
+		calls[u]++
+		n := calls[u]
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(u, "/flaky") && n < 3:
+			fmt.Fprint(w, `{"result":{"success":false,"status":"ERR::PROXY::TIMEOUT","status_code":200}}`)
+		case strings.HasSuffix(u, "/missing"):
+			fmt.Fprint(w, `{"result":{"success":false,"status":"ERR::SCRAPE::BAD_UPSTREAM_RESPONSE","status_code":404}}`)
+		case strings.HasSuffix(u, "/blocked"):
+			w.Write([]byte(aspBlockedResult))
+		default:
+			fmt.Fprint(w, `{"result":{"success":true,"status":"DONE","status_code":200}}`)
+		}
+	})
+	configs := []*ScrapeConfig{
+		{URL: "https://example.com/flaky"},
+		{URL: "https://example.com/missing"},
+		{URL: "https://example.com/blocked"},
+	}
+	results := map[string]ConcurrentScrapeResult{}
+	for item := range client.ConcurrentScrapeWithOptions(configs, ConcurrentScrapeOptions{
+		Concurrency: 3,
+		Retry:       &RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond},
+	}) {
+		results[item.Config.URL] = item
+	}
+
+	if item := results["https://example.com/flaky"]; item.Err != nil || item.Attempts != 3 {
+		t.Errorf("flaky: attempts %d, err %v; want success on the third attempt", item.Attempts, item.Err)
+	}
+	if item := results["https://example.com/missing"]; !errors.Is(item.Err, ErrUpstreamClient) || item.Attempts != 1 {
+		t.Errorf("missing: attempts %d, err %v; upstream 4xx must not be retried", item.Attempts, item.Err)
+	}
+	if item := results["https://example.com/blocked"]; !errors.Is(item.Err, ErrASPBypassFailed) || item.Attempts != 3 {
+		t.Errorf("blocked: attempts %d, err %v; want the last ASP failure after 3 attempts", item.Attempts, item.Err)
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := &RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 300 * time.Millisecond, 10: 300 * time.Millisecond} {
+		if got := policy.delay(attempt, errors.New("failed")); got != want {
+			t.Errorf("delay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+	throttled := &APIError{Retryable: true, RetryAfterMs: 2000}
+	if got := policy.delay(1, throttled); got != 2*time.Second {
+		t.Errorf("delay with Retry-After = %v, want 2s", got)
+	}
+	if !policy.retryable(throttled) {
+		t.Error("errors flagged retryable by the API must be retried")
+	}
+}
@@ -0,0 +1,290 @@
+package scrapfly
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RobotsOptions configures a Robots.
+type RobotsOptions struct {
+	// UserAgent is the crawler name matched against the User-agent lines
+	// of robots.txt ("MyBot" for "MyBot/1.0"). The groups for all crawlers
+	// ("*") apply when no group names it, or when UserAgent is empty.
+	UserAgent string
+	// Config is the template of the robots.txt scrapes, cloned for each
+	// host with its URL set. Nil scrapes with the defaults.
+	Config *ScrapeConfig
+	// MaxCrawlDelay caps the Crawl-delay of a host, so a site asking for
+	// minutes between requests doesn't stall a run. Zero means no cap.
+	MaxCrawlDelay time.Duration
+}
+
+// Robots fetches the robots.txt of each host once, through the scrape
+// API, and answers whether URLs may be crawled. Set it on
+// ConcurrentScrapeOptions.Robots or SiteCrawlOptions.Robots to skip the
+// disallowed URLs and wait the Crawl-delay of each host between its
+// scrapes. A Robots is safe for concurrent use.
+//
+// Rules follow RFC 9309: the longest matching Allow or Disallow path wins,
+// Allow on a tie, with "*" and "$" wildcards. A robots.txt answered with a
+// 4xx status allows everything; one that can't be fetched is an error, and
+// is fetched again on the next call.
+type Robots struct {
+	client *Client
+	opts   RobotsOptions
+
+	mu    sync.Mutex
+	hosts map[string]*robotsHost
+}
+
+// robotsHost is the robots.txt of a host, fetched once.
+type robotsHost struct {
+	// ready is closed once rules or err is set.
+	ready chan struct{}
+	rules *robotsRules
+	err   error
+}
+
+// robotsRules are the rules of a robots.txt for the user agent.
+type robotsRules struct {
+	rules    []robotsRule
+	delay    time.Duration
+	sitemaps []string
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern *regexp.Regexp
+	// length is the length of the path, the rule priority.
+	length int
+}
+
+// NewRobots returns a Robots fetching robots.txt files with c.
+//
+// Example:
+//
+//	robots := client.NewRobots(scrapfly.RobotsOptions{UserAgent: "MyBot"})
+//	results := client.ConcurrentScrapeWithOptions(configs, scrapfly.ConcurrentScrapeOptions{
+//	    Concurrency: 10,
+//	    Robots:      robots,
+//	})
+//	for item := range results {
+//	    if errors.Is(item.Err, scrapfly.ErrRobotsDisallowed) {
+//	        continue // skipped, not scraped
+//	    }
+//	    ...
+//	}
+func (c *Client) NewRobots(opts RobotsOptions) *Robots {
+	return &Robots{client: c, opts: opts, hosts: make(map[string]*robotsHost)}
+}
+
+// Allowed reports whether robots.txt allows crawling rawURL.
+func (r *Robots) Allowed(ctx context.Context, rawURL string) (bool, error) {
+	rules, u, err := r.rulesFor(ctx, rawURL)
+	if err != nil {
+		return false, err
+	}
+	return rules.allowed(u), nil
+}
+
+// CrawlDelay returns the Crawl-delay robots.txt asks for between the
+// requests to the host of rawURL, capped to MaxCrawlDelay; zero when it
+// sets none.
+func (r *Robots) CrawlDelay(ctx context.Context, rawURL string) (time.Duration, error) {
+	rules, _, err := r.rulesFor(ctx, rawURL)
+	if err != nil {
+		return 0, err
+	}
+	if r.opts.MaxCrawlDelay > 0 {
+		return min(rules.delay, r.opts.MaxCrawlDelay), nil
+	}
+	return rules.delay, nil
+}
+
+// Sitemaps returns the sitemap URLs the robots.txt of the host of rawURL
+// lists, for SitemapURLs.
+func (r *Robots) Sitemaps(ctx context.Context, rawURL string) ([]string, error) {
+	rules, _, err := r.rulesFor(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return rules.sitemaps, nil
+}
+
+// check returns an error wrapping ErrRobotsDisallowed when robots.txt
+// disallows rawURL, or the error fetching it.
+func (r *Robots) check(ctx context.Context, rawURL string) error {
+	allowed, err := r.Allowed(ctx, rawURL)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s", ErrRobotsDisallowed, rawURL)
+	}
+	return nil
+}
+
+// rulesFor returns the rules of the host of rawURL, fetching its
+// robots.txt on first use, and the parsed URL.
+func (r *Robots) rulesFor(ctx context.Context, rawURL string) (*robotsRules, *url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, nil, fmt.Errorf("can't check robots.txt of %q: not an http(s) URL", rawURL)
+	}
+	origin := u.Scheme + "://" + strings.ToLower(u.Host)
+
+	r.mu.Lock()
+	host, ok := r.hosts[origin]
+	if !ok {
+		host = &robotsHost{ready: make(chan struct{})}
+		r.hosts[origin] = host
+		r.mu.Unlock()
+		host.rules, host.err = r.fetch(ctx, origin)
+		if host.err != nil {
+			// fetched again on the next call
+			r.mu.Lock()
+			delete(r.hosts, origin)
+			r.mu.Unlock()
+		}
+		close(host.ready)
+	} else {
+		r.mu.Unlock()
+		select {
+		case <-host.ready:
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+	if host.err != nil {
+		return nil, nil, host.err
+	}
+	return host.rules, u, nil
+}
+
+// fetch scrapes and parses the robots.txt of origin.
+func (r *Robots) fetch(ctx context.Context, origin string) (*robotsRules, error) {
+	config := scrapeConfigFor(r.opts.Config, origin+"/robots.txt")
+	result, err := r.client.ScrapeContext(ctx, config)
+	if errors.Is(err, ErrUpstreamClient) {
+		// no robots.txt, or no access to it: everything is allowed
+		return &robotsRules{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s: %w", config.URL, err)
+	}
+	if code := result.Result.StatusCode; code >= 400 && code < 500 {
+		return &robotsRules{}, nil
+	}
+	DefaultLogger.Debug(logArgs(ctx, "fetched", config.URL)...)
+	return parseRobots(result.Result.Content, r.opts.UserAgent), nil
+}
+
+// parseRobots returns the rules of content for userAgent: the ones of the
+// groups naming it, else the ones of the "*" groups.
+func parseRobots(content, userAgent string) *robotsRules {
+	agent := strings.ToLower(strings.TrimSpace(userAgent))
+	if i := strings.IndexAny(agent, "/ "); i >= 0 {
+		agent = agent[:i]
+	}
+	var named, all robotsRules
+	// namedSeen is set by a group naming agent, even without rules
+	namedSeen := false
+	var sitemaps []string
+	// the group being read: the user agents it applies to, and whether its
+	// rules started (a User-agent line then starts a new group)
+	var forNamed, forAll, inRules bool
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		switch key {
+		case "user-agent":
+			if inRules {
+				forNamed, forAll, inRules = false, false, false
+			}
+			name := strings.ToLower(value)
+			forAll = forAll || name == "*"
+			forNamed = forNamed || (agent != "" && name == agent)
+			namedSeen = namedSeen || forNamed
+			continue
+		case "sitemap":
+			if value != "" {
+				sitemaps = append(sitemaps, value)
+			}
+			continue
+		}
+		inRules = true
+		for _, target := range []*robotsRules{&named, &all} {
+			if (target == &named && !forNamed) || (target == &all && !forAll) {
+				continue
+			}
+			switch key {
+			case "allow", "disallow":
+				// an empty Disallow allows everything
+				if value != "" {
+					target.rules = append(target.rules, robotsRule{allow: key == "allow", pattern: robotsPattern(value), length: len(value)})
+				}
+			case "crawl-delay":
+				if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds >= 0 {
+					target.delay = time.Duration(seconds * float64(time.Second))
+				}
+			}
+		}
+	}
+
+	rules := &all
+	if namedSeen {
+		rules = &named
+	}
+	rules.sitemaps = sitemaps
+	return rules
+}
+
+// robotsPattern compiles a robots.txt path, where "*" matches any
+// sequence and a trailing "$" anchors the end.
+func robotsPattern(path string) *regexp.Regexp {
+	anchored := strings.HasSuffix(path, "$")
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(strings.TrimSuffix(path, "$")), `\*`, ".*")
+	if anchored {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// allowed applies the rules to u: the longest matching rule wins, Allow
+// on a tie, and no matching rule allows.
+func (r *robotsRules) allowed(u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	allowed, length := true, -1
+	for _, rule := range r.rules {
+		if !rule.pattern.MatchString(path) {
+			continue
+		}
+		if rule.length > length || (rule.length == length && rule.allow) {
+			allowed, length = rule.allow, rule.length
+		}
+	}
+	return allowed
+}
@@ -0,0 +1,125 @@
+package scrapfly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseRobots(t *testing.T) {
+	content := `# robots.txt
+User-agent: *
+Disallow: /private
+Allow: /private/public
+Disallow: /*.pdf$
+Crawl-delay: 2
+
+User-agent: MyBot
+User-agent: OtherBot
+Disallow: /search?q=
+Crawl-delay: 0.5
+
+Sitemap: https://example.com/sitemap.xml
+`
+	all := parseRobots(content, "")
+	cases := []struct {
+		path    string
+		allowed bool
+	}{
+		{"/", true},
+		{"/private", false},
+		{"/private/page", false},
+		{"/private/public/page", true},
+		{"/docs/file.pdf", false},
+		{"/docs/file.pdf?x=1", true},
+		{"/robots.txt", true},
+	}
+	for _, tc := range cases {
+		u, _ := url.Parse("https://example.com" + tc.path)
+		if got := all.allowed(u); got != tc.allowed {
+			t.Errorf("* %s allowed = %v, want %v", tc.path, got, tc.allowed)
+		}
+	}
+	if all.delay != 2*time.Second || len(all.sitemaps) != 1 {
+		t.Errorf("* rules = %+v", all)
+	}
+
+	named := parseRobots(content, "MyBot/1.0")
+	for path, allowed := range map[string]bool{"/private": true, "/search?q=go": false, "/search": true} {
+		u, _ := url.Parse("https://example.com" + path)
+		if got := named.allowed(u); got != allowed {
+			t.Errorf("MyBot %s allowed = %v, want %v", path, got, allowed)
+		}
+	}
+	if named.delay != 500*time.Millisecond {
+		t.Errorf("MyBot delay = %v", named.delay)
+	}
+
+	// a group naming the agent without rules allows everything
+	if rules := parseRobots("User-agent: *\nDisallow: /\n\nUser-agent: MyBot\nDisallow:\n", "MyBot"); !rules.allowed(&url.URL{Path: "/page"}) {
+		t.Error("empty MyBot group disallowed /page")
+	}
+}
+
+func TestClient_ConcurrentScrapeWithOptions_Robots(t *testing.T) {
+	var mu sync.Mutex
+	robotsFetches := map[string]int{}
+	var starts []time.Time
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		target, _ := url.Parse(r.URL.Query().Get("url"))
+		result := map[string]interface{}{"success": true, "status": "DONE", "status_code": 200, "url": target.String(), "content": "ok"}
+		mu.Lock()
+		switch {
+		case target.Path == "/robots.txt" && target.Host == "example.com":
+			robotsFetches[target.Host]++
+			result["content"] = "User-agent: *\nDisallow: /private\nCrawl-delay: 0.05\n"
+		case target.Path == "/robots.txt":
+			robotsFetches[target.Host]++
+			result["success"], result["status_code"], result["status"] = false, 404, "DONE"
+		case target.Host == "example.com":
+			starts = append(starts, time.Now())
+		}
+		mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	})
+
+	configs := []*ScrapeConfig{{URL: "https://example.com/private/1"}}
+	for i := 0; i < 3; i++ {
+		configs = append(configs, &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}, &ScrapeConfig{URL: fmt.Sprintf("https://other.com/private/%d", i)})
+	}
+	robots := client.NewRobots(RobotsOptions{})
+	var summary ConcurrentScrapeSummary
+	for item := range client.ConcurrentScrapeWithOptionsContext(context.Background(), configs, ConcurrentScrapeOptions{
+		Concurrency: 4,
+		Robots:      robots,
+		OnComplete:  func(s ConcurrentScrapeSummary) { summary = s },
+	}) {
+		if item.Index == 0 {
+			if !errors.Is(item.Err, ErrRobotsDisallowed) || item.Attempts != 0 {
+				t.Errorf("disallowed config: err %v, %d attempts", item.Err, item.Attempts)
+			}
+		} else if item.Err != nil {
+			t.Errorf("config %d: %v", item.Index, item.Err)
+		}
+	}
+	if summary.Succeeded != 6 || summary.Failed != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if robotsFetches["example.com"] != 1 || robotsFetches["other.com"] != 1 {
+		t.Errorf("robots.txt fetches = %v", robotsFetches)
+	}
+	if len(starts) != 3 {
+		t.Fatalf("example.com scraped %d times", len(starts))
+	}
+	for i := 1; i < len(starts); i++ {
+		if gap := starts[i].Sub(starts[i-1]); gap < 40*time.Millisecond {
+			t.Errorf("example.com scrapes %d and %d started %v apart, want the 50ms crawl delay", i-1, i, gap)
+		}
+	}
+}
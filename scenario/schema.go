@@ -1,3 +1,5 @@
+//go:build !scrapfly_noschema
+
 package js_scenario
 
 import (
@@ -0,0 +1,381 @@
+package scrapfly
+
+import (
+	"container/heap"
+	"math"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hostScheduler picks the order in which ConcurrentScrapeWithOptions
+// dispatches configs: configs are queued per host and the next one is the
+// first, by Priority then input order, whose host is under
+// MaxPerHostConcurrency and MinDelayPerHost, or its robots.txt
+// Crawl-delay. Without per-host limits all configs share one queue.
+type hostScheduler struct {
+	mu         sync.Mutex
+	maxPerHost int
+	minDelay   time.Duration
+	// priorities are the Priority of each config.
+	priorities []int
+	hosts      []*hostQueue
+	// hostOf is the position in hosts of the queue of each config.
+	hostOf []int
+	// byHost maps a host to its position in hosts; perHost queues configs
+	// per host without per-host limits.
+	byHost  map[string]int
+	perHost bool
+	// freed is signaled when a scrape ends, it may free a host slot.
+	freed chan struct{}
+
+	// running counts the scrapes in flight, all hosts.
+	running int
+	// cap, when > 0, caps running; see AutoConcurrency.
+	cap int
+	// adaptive, when set, caps running to limit, halved when the API
+	// throttles and raised back by successes (AIMD).
+	adaptive     *AdaptiveConcurrency
+	limit        float64
+	maxLimit     float64
+	started      []time.Time
+	throttles    []int
+	lastDecrease time.Time
+	// notBefore holds the dispatch back until the Retry-After of the last
+	// throttled scrape.
+	notBefore time.Time
+	// pace, when > 0, is the minimum time between two dispatches, all
+	// hosts; see SpreadOver.
+	pace         time.Duration
+	lastDispatch time.Time
+	// halted is set when the dispatch stopped: throttled configs are no
+	// longer queued again, nothing would dispatch them.
+	halted bool
+}
+
+type hostQueue struct {
+	// indexes are the configs not dispatched yet, by priority then input
+	// order.
+	indexes   []int
+	running   int
+	lastStart time.Time
+	// delay, when longer than minDelay, is the Crawl-delay of the host.
+	delay time.Duration
+}
+
+// newHostScheduler queues n configs, placed by their URL and priority.
+// Configs are queued per host with per-host limits, or with perHost for
+// the host delays set later (see setHostDelay).
+func newHostScheduler(n int, place func(index int) (url string, priority int), maxPerHost int, minDelay time.Duration, perHost bool) *hostScheduler {
+	s := &hostScheduler{
+		maxPerHost: maxPerHost,
+		minDelay:   minDelay,
+		priorities: make([]int, n),
+		hostOf:     make([]int, n),
+		byHost:     map[string]int{},
+		perHost:    perHost,
+		freed:      make(chan struct{}, 1),
+	}
+	for i := 0; i < n; i++ {
+		rawURL, priority := place(i)
+		s.priorities[i] = priority
+		s.hostOf[i] = s.queueOf(rawURL)
+		q := s.hosts[s.hostOf[i]]
+		q.indexes = append(q.indexes, i)
+	}
+	for _, q := range s.hosts {
+		sort.SliceStable(q.indexes, func(i, j int) bool {
+			return s.priorities[q.indexes[i]] > s.priorities[q.indexes[j]]
+		})
+	}
+	return s
+}
+
+// queueOf returns the position in hosts of the queue of rawURL, adding
+// the queue of a new host.
+func (s *hostScheduler) queueOf(rawURL string) int {
+	host := ""
+	if s.maxPerHost > 0 || s.minDelay > 0 || s.perHost {
+		if u, err := url.Parse(rawURL); err == nil {
+			host = strings.ToLower(u.Hostname())
+		}
+	}
+	q, ok := s.byHost[host]
+	if !ok {
+		q = len(s.hosts)
+		s.byHost[host] = q
+		s.hosts = append(s.hosts, &hostQueue{})
+	}
+	return q
+}
+
+// add queues a config received after the scheduler was created and
+// returns its index, the next one.
+func (s *hostScheduler) add(rawURL string, priority int) int {
+	s.mu.Lock()
+	index := len(s.priorities)
+	s.priorities = append(s.priorities, priority)
+	s.hostOf = append(s.hostOf, s.queueOf(rawURL))
+	if s.adaptive != nil {
+		s.started = append(s.started, time.Time{})
+		s.throttles = append(s.throttles, 0)
+	}
+	q := s.hosts[s.hostOf[index]]
+	at := sort.Search(len(q.indexes), func(i int) bool { return s.before(index, q.indexes[i]) })
+	q.indexes = slices.Insert(q.indexes, at, index)
+	s.mu.Unlock()
+	return index
+}
+
+// queued returns the number of configs not dispatched yet.
+func (s *hostScheduler) queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, q := range s.hosts {
+		n += len(q.indexes)
+	}
+	return n
+}
+
+// before reports whether the config at index a is dispatched before the
+// one at index b.
+func (s *hostScheduler) before(a, b int) bool {
+	if pa, pb := s.priorities[a], s.priorities[b]; pa != pb {
+		return pa > pb
+	}
+	return a < b
+}
+
+// next returns the index of the config to dispatch at now. When none can
+// start it returns -1 and how long until one can, 0 when waiting for a
+// scrape to end (see freed). ok is false when every config was dispatched.
+func (s *hostScheduler) next(now time.Time) (index int, wait time.Duration, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index = -1
+	for _, q := range s.hosts {
+		if len(q.indexes) == 0 {
+			continue
+		}
+		ok = true
+		if s.maxPerHost > 0 && q.running >= s.maxPerHost {
+			continue
+		}
+		if delay := max(s.minDelay, q.delay); delay > 0 && !q.lastStart.IsZero() {
+			if d := q.lastStart.Add(delay).Sub(now); d > 0 {
+				if wait == 0 || d < wait {
+					wait = d
+				}
+				continue
+			}
+		}
+		if index < 0 || s.before(q.indexes[0], index) {
+			index = q.indexes[0]
+		}
+	}
+	if s.adaptive != nil && s.running > 0 {
+		// a throttled scrape may be queued again
+		ok = true
+	}
+	if index >= 0 {
+		wait = 0
+		if s.pace > 0 && !s.lastDispatch.IsZero() {
+			if d := s.lastDispatch.Add(s.pace).Sub(now); d > 0 {
+				return -1, d, ok
+			}
+		}
+		if s.cap > 0 && s.running >= s.cap {
+			return -1, 0, ok
+		}
+		if s.adaptive != nil {
+			if d := s.notBefore.Sub(now); d > 0 {
+				return -1, d, ok
+			}
+			if float64(s.running) >= math.Floor(s.limit) {
+				return -1, 0, ok
+			}
+		}
+	}
+	return index, wait, ok
+}
+
+// start records the dispatch of index, returned by next. index is looked
+// up rather than taken from the front of its queue: a throttled config may
+// have been queued again before it since next.
+func (s *hostScheduler) start(index int, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := s.hosts[s.hostOf[index]]
+	if i := slices.Index(q.indexes, index); i >= 0 {
+		q.indexes = slices.Delete(q.indexes, i, i+1)
+	}
+	q.running++
+	q.lastStart = now
+	s.lastDispatch = now
+	s.running++
+	if s.adaptive != nil {
+		s.started[index] = now
+	}
+}
+
+// done records the end of the scrape of index; a success raises the
+// adaptive limit by one every limit successes.
+func (s *hostScheduler) done(index int, succeeded bool) {
+	s.mu.Lock()
+	s.hosts[s.hostOf[index]].running--
+	s.running--
+	if s.adaptive != nil && succeeded {
+		s.limit = min(s.maxLimit, s.limit+1/s.limit)
+	}
+	s.mu.Unlock()
+	s.signalFreed()
+}
+
+// throttled records the end of the scrape of index, throttled by the API:
+// the adaptive limit is halved, once for all the scrapes started before
+// the previous decrease, the dispatch waits retryAfter, and index is
+// queued again unless it was throttled MaxRequeues times already or the
+// dispatch was halted.
+func (s *hostScheduler) throttled(index int, now time.Time, retryAfter time.Duration) (requeued bool) {
+	s.mu.Lock()
+	q := s.hosts[s.hostOf[index]]
+	q.running--
+	s.running--
+	if s.lastDecrease.IsZero() || s.started[index].After(s.lastDecrease) {
+		s.limit = max(float64(s.adaptive.min()), s.limit/2)
+		s.lastDecrease = now
+		DefaultLogger.Info("API throttling, concurrency lowered to", int(s.limit))
+	}
+	if until := now.Add(retryAfter); until.After(s.notBefore) {
+		s.notBefore = until
+	}
+	if !s.halted && s.throttles[index] < s.adaptive.maxRequeues() {
+		s.throttles[index]++
+		at := sort.Search(len(q.indexes), func(i int) bool { return s.before(index, q.indexes[i]) })
+		q.indexes = append(q.indexes[:at], append([]int{index}, q.indexes[at:]...)...)
+		requeued = true
+	}
+	s.mu.Unlock()
+	s.signalFreed()
+	return requeued
+}
+
+// spread paces the dispatch so the configs queued start evenly over d.
+func (s *hostScheduler) spread(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queued := 0
+	for _, q := range s.hosts {
+		queued += len(q.indexes)
+	}
+	if queued > 0 {
+		s.pace = d / time.Duration(queued)
+	}
+}
+
+// setHostDelay raises the delay between the starts of the scrapes of the
+// host of index to delay. It reports whether the delay was raised.
+func (s *hostScheduler) setHostDelay(index int, delay time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q := s.hosts[s.hostOf[index]]
+	if delay <= q.delay {
+		return false
+	}
+	q.delay = delay
+	return true
+}
+
+func (s *hostScheduler) signalFreed() {
+	select {
+	case s.freed <- struct{}{}:
+	default:
+	}
+}
+
+// remove takes indexes out of the queues, they are never dispatched.
+func (s *hostScheduler) remove(indexes ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, index := range indexes {
+		q := s.hosts[s.hostOf[index]]
+		for i, queued := range q.indexes {
+			if queued == index {
+				q.indexes = append(q.indexes[:i], q.indexes[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// halt stops the queueing of throttled configs again and returns the
+// indexes of the configs not dispatched, in input order.
+func (s *hostScheduler) halt() []int {
+	s.mu.Lock()
+	s.halted = true
+	s.mu.Unlock()
+	return s.pending()
+}
+
+// pending returns the indexes of the configs not dispatched, in input
+// order.
+func (s *hostScheduler) pending() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	indexes := []int{}
+	for _, q := range s.hosts {
+		indexes = append(indexes, q.indexes...)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// priorityAging is the wait after which a queued config of
+// ConcurrentScrapeChan gains one priority level.
+const priorityAging = 30 * time.Second
+
+// chanQueueSize is the number of configs ConcurrentScrapeChan reads ahead
+// of its workers to order them by priority.
+const chanQueueSize = 1024
+
+// priorityQueue orders the configs of ConcurrentScrapeChan by aged
+// priority. Aging is linear in the wait, so the order between two queued
+// configs never changes and a heap keyed at enqueue time is enough.
+type priorityQueue []queuedConfig
+
+type queuedConfig struct {
+	index  int
+	config *ScrapeConfig
+	// key is the priority minus the enqueue time in priorityAging units.
+	key float64
+}
+
+func (q *priorityQueue) push(index int, config *ScrapeConfig, epoch time.Time) {
+	priority := 0
+	if config != nil {
+		priority = config.Priority
+	}
+	heap.Push(q, queuedConfig{index: index, config: config, key: float64(priority) - float64(time.Since(epoch))/float64(priorityAging)})
+}
+
+func (q *priorityQueue) pop() queuedConfig { return heap.Pop(q).(queuedConfig) }
+
+func (q priorityQueue) Len() int { return len(q) }
+func (q priorityQueue) Less(i, j int) bool {
+	if q[i].key != q[j].key {
+		return q[i].key > q[j].key
+	}
+	return q[i].index < q[j].index
+}
+func (q priorityQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *priorityQueue) Push(x interface{}) { *q = append(*q, x.(queuedConfig)) }
+func (q *priorityQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
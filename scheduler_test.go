@@ -0,0 +1,51 @@
+package scrapfly
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriorityQueue_Aging(t *testing.T) {
+	epoch := time.Now().Add(-2 * time.Minute)
+	var queue priorityQueue
+	// queued two minutes ago: aged by four levels
+	queue = append(queue, queuedConfig{index: 0, config: &ScrapeConfig{URL: "old"}, key: 0})
+	queue.push(1, &ScrapeConfig{URL: "urgent", Priority: 5}, epoch)
+	queue.push(2, &ScrapeConfig{URL: "recheck", Priority: 3}, epoch)
+
+	var order []string
+	for queue.Len() > 0 {
+		order = append(order, queue.pop().config.URL)
+	}
+	if order[0] != "urgent" || order[1] != "old" || order[2] != "recheck" {
+		t.Errorf("order = %v, want [urgent old recheck]", order)
+	}
+}
+
+func TestHostScheduler_StartAfterRequeue(t *testing.T) {
+	s := newHostScheduler(4, func(int) (string, int) { return "https://example.com", 0 }, 0, 0, false)
+	s.adaptive = &AdaptiveConcurrency{}
+	s.limit, s.maxLimit = 4, 4
+	s.started, s.throttles = make([]time.Time, 4), make([]int, 4)
+	now := time.Now()
+	for _, want := range []int{0, 1} {
+		index, _, _ := s.next(now)
+		if index != want {
+			t.Fatalf("next = %d, want %d", index, want)
+		}
+		s.start(index, now)
+	}
+
+	// 1 is throttled and queued again between next and start of 2
+	index, _, _ := s.next(now)
+	if !s.throttled(1, now, 0) {
+		t.Fatal("1 not requeued")
+	}
+	s.start(index, now)
+	if index != 2 {
+		t.Fatalf("next = %d, want 2", index)
+	}
+	if got := s.pending(); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Errorf("pending = %v, want [1 3]", got)
+	}
+}
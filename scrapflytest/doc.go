@@ -0,0 +1,14 @@
+// Package scrapflytest provides a fake Scrapfly API server for tests, with
+// failure injection to chaos-test retry and circuit-breaker configuration
+// before production.
+//
+//	server := scrapflytest.NewServer()
+//	defer server.Close()
+//	server.SetScenario(scrapflytest.RateLimitStorm)
+//
+//	client := server.Client()
+//	_, err := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://example.com"})
+//
+// The healthy path answers /scrape with a successful result echoing the
+// requested URL; override it with Server.Handle.
+package scrapflytest
@@ -0,0 +1,111 @@
+package scrapflytest
+
+import (
+	"math/rand"
+	"time"
+)
+
+// Fault is a failure the fake server can inject into a response.
+type Fault int
+
+const (
+	// FaultNone serves the healthy response.
+	FaultNone Fault = iota
+	// FaultTooManyRequests answers 429 with a Retry-After header.
+	FaultTooManyRequests
+	// FaultServerError answers 502, which the SDK retries.
+	FaultServerError
+	// FaultMalformed answers 200 with an invalid JSON payload.
+	FaultMalformed
+	// FaultTruncated answers 200 and drops the connection mid-body.
+	FaultTruncated
+)
+
+// String returns the fault name.
+func (f Fault) String() string {
+	switch f {
+	case FaultNone:
+		return "none"
+	case FaultTooManyRequests:
+		return "too_many_requests"
+	case FaultServerError:
+		return "server_error"
+	case FaultMalformed:
+		return "malformed"
+	case FaultTruncated:
+		return "truncated"
+	}
+	return "invalid_fault"
+}
+
+// Scenario describes the failures injected by the fake server. Rates are
+// probabilities between 0 and 1 evaluated per request, in the order
+// TooManyRequests, ServerError, Malformed, Truncated. The zero value
+// injects nothing.
+type Scenario struct {
+	// Name labels the scenario in test output.
+	Name string
+
+	// Latency is added to every response, plus a random 0..Jitter.
+	Latency time.Duration
+	Jitter  time.Duration
+
+	// StormStart and StormLength answer 429 to requests number StormStart
+	// to StormStart+StormLength-1 (1-based), simulating a rate-limit storm.
+	StormStart  int
+	StormLength int
+	// RetryAfter is the Retry-After sent with 429 responses (default 1s).
+	RetryAfter time.Duration
+
+	TooManyRequestsRate float64
+	ServerErrorRate     float64
+	MalformedRate       float64
+	TruncatedRate       float64
+
+	// Seed makes the random faults reproducible.
+	Seed int64
+}
+
+// Predefined scenarios.
+var (
+	// Healthy injects no failure.
+	Healthy = Scenario{Name: "healthy"}
+	// RateLimitStorm answers 429 to the first 20 requests.
+	RateLimitStorm = Scenario{Name: "rate_limit_storm", StormStart: 1, StormLength: 20, RetryAfter: time.Second}
+	// Flaky fails a third of the requests with 5xx or 429.
+	Flaky = Scenario{Name: "flaky", ServerErrorRate: 0.2, TooManyRequestsRate: 0.1, Seed: 42}
+	// Slow adds 2-3s of latency to every response.
+	Slow = Scenario{Name: "slow", Latency: 2 * time.Second, Jitter: time.Second}
+	// Corrupted returns malformed or truncated payloads for 30% of the requests.
+	Corrupted = Scenario{Name: "corrupted", MalformedRate: 0.15, TruncatedRate: 0.15, Seed: 7}
+)
+
+func (s Scenario) latency(rng *rand.Rand) time.Duration {
+	if s.Jitter <= 0 {
+		return s.Latency
+	}
+	return s.Latency + time.Duration(rng.Int63n(int64(s.Jitter)))
+}
+
+// pick selects the fault for the n-th request (1-based).
+func (s Scenario) pick(n int, rng *rand.Rand) Fault {
+	if s.StormLength > 0 && n >= s.StormStart && n < s.StormStart+s.StormLength {
+		return FaultTooManyRequests
+	}
+	roll := rng.Float64()
+	for _, candidate := range []struct {
+		rate  float64
+		fault Fault
+	}{
+		{s.TooManyRequestsRate, FaultTooManyRequests},
+		{s.ServerErrorRate, FaultServerError},
+		{s.MalformedRate, FaultMalformed},
+		{s.TruncatedRate, FaultTruncated},
+	} {
+		if roll < candidate.rate {
+			return candidate.fault
+		}
+		roll -= candidate.rate
+	}
+	return FaultNone
+}
@@ -0,0 +1,171 @@
+package scrapflytest
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/scrapfly/go-scrapfly"
+)
+
+// APIKey is the key accepted by the fake server and used by Server.Client.
+const APIKey = "scrapflytest-key"
+
+// Server is a fake Scrapfly API. It is safe for concurrent use.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	scenario Scenario
+	rng      *rand.Rand
+	requests int
+	faults   map[Fault]int
+	handler  http.Handler
+}
+
+// NewServer starts a fake Scrapfly API with no faults injected. Close it
+// when done.
+func NewServer() *Server {
+	s := &Server{rng: rand.New(rand.NewSource(1)), faults: make(map[Fault]int)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a scrapfly.Client pointed at the fake server.
+func (s *Server) Client() *scrapfly.Client {
+	client, err := scrapfly.NewWithHost(APIKey, s.URL, true)
+	if err != nil {
+		panic(err)
+	}
+	return client
+}
+
+// Handle replaces the healthy-path handler, used for requests no fault is
+// injected into.
+func (s *Server) Handle(handler http.Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = handler
+}
+
+// SetScenario installs a failure scenario and resets the request counters.
+func (s *Server) SetScenario(scenario Scenario) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scenario = scenario
+	s.rng = rand.New(rand.NewSource(scenario.Seed))
+	s.requests = 0
+	s.faults = make(map[Fault]int)
+}
+
+// Requests returns the number of requests received since the last SetScenario.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+// Faults returns how many times each fault was injected since the last SetScenario.
+func (s *Server) Faults() map[Fault]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	faults := make(map[Fault]int, len(s.faults))
+	for fault, count := range s.faults {
+		faults[fault] = count
+	}
+	return faults
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	scenario := s.scenario
+	latency := scenario.latency(s.rng)
+	fault := scenario.pick(s.requests, s.rng)
+	if fault != FaultNone {
+		s.faults[fault]++
+	}
+	handler := s.handler
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if r.URL.Query().Get("key") != APIKey {
+		writeError(w, http.StatusUnauthorized, "ERR::AUTH::INVALID_KEY", "invalid API key")
+		return
+	}
+
+	switch fault {
+	case FaultTooManyRequests:
+		retryAfter := scenario.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = time.Second
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds()+0.5)))
+		writeError(w, http.StatusTooManyRequests, "ERR::THROTTLE::MAX_REQUEST_RATE_EXCEEDED", "too many requests")
+		return
+	case FaultServerError:
+		writeError(w, http.StatusBadGateway, "ERR::API::INTERNAL_ERROR", "upstream api failure")
+		return
+	case FaultMalformed:
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"result": {"success": true, "status": "DONE", "content": `))
+		return
+	case FaultTruncated:
+		body := successBody(r)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(body[:len(body)/2])
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+		// Abort the connection mid-body so the client sees an unexpected EOF.
+		panic(http.ErrAbortHandler)
+	}
+
+	if handler != nil {
+		handler.ServeHTTP(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(successBody(r))
+}
+
+func successBody(r *http.Request) []byte {
+	target := r.URL.Query().Get("url")
+	body, _ := json.Marshal(map[string]interface{}{
+		"uuid":   "scrapflytest",
+		"config": map[string]interface{}{"url": target, "method": r.Method},
+		"result": map[string]interface{}{
+			"success":      true,
+			"status":       "DONE",
+			"status_code":  200,
+			"url":          target,
+			"content_type": "text/html; charset=utf-8",
+			"content":      "<html><head><title>scrapflytest</title></head><body></body></html>",
+			"format":       "text",
+		},
+	})
+	return body
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code":      code,
+		"message":   message,
+		"http_code": status,
+	})
+}
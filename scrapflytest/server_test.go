@@ -0,0 +1,56 @@
+package scrapflytest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/scrapfly/go-scrapfly"
+)
+
+func TestServer_HealthyByDefault(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	result, err := server.Client().Scrape(&scrapfly.ScrapeConfig{URL: "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Result.URL != "https://example.com" {
+		t.Errorf("unexpected url %q", result.Result.URL)
+	}
+}
+
+func TestServer_RateLimitStorm(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SetScenario(Scenario{StormStart: 1, StormLength: 2})
+
+	client := server.Client()
+	for i := 0; i < 2; i++ {
+		_, err := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://example.com"})
+		var apiErr *scrapfly.APIError
+		if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode != http.StatusTooManyRequests || apiErr.RetryAfterMs != 1000 {
+			t.Fatalf("request %d: expected a 429 with Retry-After, got %v", i+1, err)
+		}
+	}
+	if _, err := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://example.com"}); err != nil {
+		t.Errorf("storm should be over, got %v", err)
+	}
+	if got := server.Faults()[FaultTooManyRequests]; got != 2 {
+		t.Errorf("expected 2 injected 429, got %d", got)
+	}
+}
+
+func TestServer_CorruptedPayloads(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	for _, scenario := range []Scenario{{MalformedRate: 1}, {TruncatedRate: 1}} {
+		server.SetScenario(scenario)
+		if _, err := client.Scrape(&scrapfly.ScrapeConfig{URL: "https://example.com"}); err == nil {
+			t.Errorf("%+v: expected a decoding error", scenario)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ScreenshotSet holds the screenshots of a page by resolution, as returned
+// by ScreenshotResolutions.
+type ScreenshotSet map[string]*ScreenshotResult
+
+// Resolutions returns the resolutions of the set, narrowest first.
+func (s ScreenshotSet) Resolutions() []string {
+	resolutions := make([]string, 0, len(s))
+	for resolution := range s {
+		resolutions = append(resolutions, resolution)
+	}
+	sort.Slice(resolutions, func(i, j int) bool {
+		var wi, hi, wj, hj int
+		fmt.Sscanf(resolutions[i], "%dx%d", &wi, &hi)
+		fmt.Sscanf(resolutions[j], "%dx%d", &wj, &hj)
+		if wi != wj {
+			return wi < wj
+		}
+		return hi < hj
+	})
+	return resolutions
+}
+
+// Save saves each screenshot as name-<resolution>.<ext> in savePath (the
+// current directory by default), narrowest first, and returns the paths.
+func (s ScreenshotSet) Save(name string, savePath ...string) ([]string, error) {
+	var paths []string
+	for _, resolution := range s.Resolutions() {
+		path, err := s[resolution].Save(name+"-"+resolution, savePath...)
+		if err != nil {
+			return paths, fmt.Errorf("failed to save %s screenshot: %w", resolution, err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// ScreenshotResolutions captures the page of config at each resolution
+// ("375x812", "1920x1080"...) concurrently, for responsive-design checks.
+// Each capture is a copy of config with its Resolution set.
+//
+// It returns the screenshots taken, keyed by resolution, and the failures
+// joined (see errors.Join), so a failed viewport doesn't drop the others.
+//
+// Example:
+//
+//	set, err := client.ScreenshotResolutions(&scrapfly.ScreenshotConfig{
+//	    URL:     "https://web-scraping.dev/pricing",
+//	    Capture: "fullpage",
+//	}, []string{"375x812", "768x1024", "1920x1080"})
+//	if err != nil {
+//	    log.Print(err)
+//	}
+//	paths, err := set.Save("pricing", "./screenshots")
+func (c *Client) ScreenshotResolutions(config *ScreenshotConfig, resolutions []string) (ScreenshotSet, error) {
+	return c.ScreenshotResolutionsContext(context.Background(), config, resolutions)
+}
+
+// ScreenshotResolutionsContext is ScreenshotResolutions bound to ctx.
+func (c *Client) ScreenshotResolutionsContext(ctx context.Context, config *ScreenshotConfig, resolutions []string) (ScreenshotSet, error) {
+	if config == nil {
+		return nil, fmt.Errorf("%w: config is required", ErrScreenshotConfig)
+	}
+	var configs []*ScreenshotConfig
+	listed := make(map[string]bool)
+	for _, resolution := range resolutions {
+		resolution = strings.ToLower(strings.TrimSpace(resolution))
+		if !viewportRegex.MatchString(resolution) {
+			return nil, fmt.Errorf("%w: invalid resolution %q, want WIDTHxHEIGHT", ErrScreenshotConfig, resolution)
+		}
+		if listed[resolution] {
+			continue
+		}
+		listed[resolution] = true
+		shot := config.Clone()
+		shot.Resolution = resolution
+		configs = append(configs, shot)
+	}
+	if len(configs) == 0 {
+		return nil, fmt.Errorf("%w: no resolution", ErrScreenshotConfig)
+	}
+
+	set := make(ScreenshotSet, len(configs))
+	var errs []error
+	for item := range c.ConcurrentScreenshot(ctx, configs, ConcurrentScrapeOptions{Concurrency: len(configs)}) {
+		switch {
+		case item.Err != nil && item.Config != nil:
+			errs = append(errs, fmt.Errorf("failed to capture %s at %s: %w", item.Config.URL, item.Config.Resolution, item.Err))
+		case item.Err != nil:
+			errs = append(errs, item.Err)
+		default:
+			set[item.Config.Resolution] = item.Result
+		}
+	}
+	return set, errors.Join(errs...)
+}
@@ -0,0 +1,53 @@
+package scrapfly
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClient_ScreenshotResolutions(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		resolution := r.URL.Query().Get("resolution")
+		if resolution == "768x1024" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, `{"code":"ERR::SCREENSHOT::UNABLE_TO_TAKE_SCREENSHOT","message":"capture failed"}`)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprintf(w, "PNG %s %s", r.URL.Query().Get("capture"), resolution)
+	})
+
+	config := &ScreenshotConfig{URL: "https://example.com/pricing", Capture: "fullpage"}
+	set, err := client.ScreenshotResolutions(config, []string{"1920x1080", "375x812", "768x1024", "375x812"})
+	if err == nil || !strings.Contains(err.Error(), "768x1024") {
+		t.Errorf("err = %v, want the 768x1024 failure", err)
+	}
+	if got := fmt.Sprint(set.Resolutions()); got != "[375x812 1920x1080]" {
+		t.Fatalf("resolutions = %s", got)
+	}
+	if got := string(set["375x812"].Image); got != "PNG fullpage 375x812" {
+		t.Errorf("375x812 image = %q", got)
+	}
+	if config.Resolution != "" {
+		t.Errorf("config modified: %+v", config)
+	}
+
+	dir := t.TempDir()
+	paths, err := set.Save("pricing", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{filepath.Join(dir, "pricing-375x812.png"), filepath.Join(dir, "pricing-1920x1080.png")}; fmt.Sprint(paths) != fmt.Sprint(want) {
+		t.Errorf("paths = %v, want %v", paths, want)
+	}
+
+	for _, resolutions := range [][]string{nil, {"large"}} {
+		if _, err := client.ScreenshotResolutions(config, resolutions); !errors.Is(err, ErrScreenshotConfig) {
+			t.Errorf("%v: got %v, want ErrScreenshotConfig", resolutions, err)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package scrapfly
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SeenStore records the pages a crawl has queued, by canonical URL, so
+// each page is scraped once. The store of SiteCrawlOptions.Seen outlives
+// the crawl when it is persisted (FileSeenStore), and is shared by the
+// crawls, or the workers of different machines, using it at once.
+//
+// Implementations must be safe for concurrent use. A Redis store maps Add
+// to SADD, which adds atomically:
+//
+//	type redisSeen struct{ rdb *redis.Client }
+//
+//	func (s redisSeen) Add(ctx context.Context, key string) (bool, error) {
+//	    n, err := s.rdb.SAdd(ctx, "crawl:seen", key).Result()
+//	    return n == 1, err
+//	}
+type SeenStore interface {
+	// Add records key, reporting whether it was not recorded yet.
+	Add(ctx context.Context, key string) (bool, error)
+}
+
+// FrontierStore is a SeenStore that also keeps the crawl frontier: the
+// pages added and not done yet. CrawlSite marks a page done once OnPage
+// returned and its links were added, and first queues the pages the store
+// reports pending, so a crawl killed midway resumes its frontier.
+type FrontierStore interface {
+	SeenStore
+	// Done records that the page of key was crawled.
+	Done(ctx context.Context, key string) error
+	// Pending returns the keys added and not done, in the order they were
+	// added.
+	Pending(ctx context.Context) ([]string, error)
+}
+
+// MemorySeenStore is a SeenStore held in memory, the default of CrawlSite.
+type MemorySeenStore struct {
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// NewMemorySeenStore returns an empty MemorySeenStore.
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{seen: make(map[string]bool)}
+}
+
+// Add records key, reporting whether it was new.
+func (s *MemorySeenStore) Add(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[key] {
+		return false, nil
+	}
+	s.seen[key] = true
+	return true, nil
+}
+
+// Len returns the number of keys recorded.
+func (s *MemorySeenStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.seen)
+}
+
+// FileSeenStore is a FrontierStore persisted to a file: a JSON string per
+// line for each key added, and a {"done": key} line for each key done. A
+// crawl restarted with it, even after a crash, queues again the pages left
+// pending (queued and not done) and doesn't scrape the others again; a
+// page whose OnPage call the crash interrupted is scraped again. The keys
+// are loaded in memory on open. A FileSeenStore is safe for concurrent
+// use; a file must be opened by a single process at a time.
+//
+// Example — resume a crawl after a restart:
+//
+//	seen, err := scrapfly.OpenFileSeenStore("state/seen.jsonl")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer seen.Close()
+//	// the pending pages of the previous run are queued first; no seeds
+//	// are needed to resume, seeds are crawled even when already seen
+//	summary, err := client.CrawlSite(ctx, nil, scrapfly.SiteCrawlOptions{Seen: seen})
+type FileSeenStore struct {
+	mu   sync.Mutex
+	path string
+	file *os.File
+	seen map[string]bool
+	done map[string]bool
+	// added are the keys in the order they were added.
+	added []string
+}
+
+// seenDoneRecord is the line of a key done in a FileSeenStore.
+type seenDoneRecord struct {
+	Done string `json:"done"`
+}
+
+// OpenFileSeenStore opens the store at path, creating it and its parent
+// directories when missing.
+func OpenFileSeenStore(path string) (*FileSeenStore, error) {
+	s := &FileSeenStore{path: path, seen: make(map[string]bool), done: make(map[string]bool)}
+	if err := s.open(); err != nil {
+		return nil, fmt.Errorf("failed to open seen store %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// open loads the keys of the file and opens it for appending.
+func (s *FileSeenStore) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		// a crash mid-write leaves a truncated last line, skipped
+		var key string
+		var done seenDoneRecord
+		switch {
+		case json.Unmarshal(scanner.Bytes(), &key) == nil:
+			if !s.seen[key] {
+				s.seen[key] = true
+				s.added = append(s.added, key)
+			}
+		case json.Unmarshal(scanner.Bytes(), &done) == nil && done.Done != "":
+			s.done[done.Done] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		f.Close()
+		return err
+	}
+	// end a truncated last line, so the next key starts a line of its own
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, info.Size()-1); err != nil && !errors.Is(err, io.EOF) {
+			f.Close()
+			return err
+		}
+		if last[0] != '\n' {
+			if _, err := f.Write([]byte{'\n'}); err != nil {
+				f.Close()
+				return err
+			}
+		}
+	}
+	s.file = f
+	return nil
+}
+
+// Add records key, reporting whether it was new. New keys are written and
+// synced to the file before Add returns.
+func (s *FileSeenStore) Add(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.seen[key] {
+		return false, nil
+	}
+	if s.file == nil {
+		return false, fmt.Errorf("seen store %s is closed", s.path)
+	}
+	if err := s.write(key); err != nil {
+		return false, err
+	}
+	s.seen[key] = true
+	s.added = append(s.added, key)
+	return true, nil
+}
+
+// Done records that the page of key was crawled. The record is synced to
+// the file before Done returns.
+func (s *FileSeenStore) Done(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done[key] {
+		return nil
+	}
+	if s.file == nil {
+		return fmt.Errorf("seen store %s is closed", s.path)
+	}
+	if err := s.write(seenDoneRecord{Done: key}); err != nil {
+		return err
+	}
+	s.done[key] = true
+	return nil
+}
+
+// Pending returns the keys added and not done, in the order they were
+// added.
+func (s *FileSeenStore) Pending(context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var keys []string
+	for _, key := range s.added {
+		if !s.done[key] {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// write appends the line of record and syncs the file; s.mu must be held.
+func (s *FileSeenStore) write(record interface{}) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Len returns the number of keys recorded.
+func (s *FileSeenStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.seen)
+}
+
+// Close closes the file. Add fails once the store is closed.
+func (s *FileSeenStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
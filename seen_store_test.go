@@ -0,0 +1,62 @@
+package scrapfly
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSeenStore_Reopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state", "seen.jsonl")
+	seen, err := OpenFileSeenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"https://example.com/", "https://example.com/a", "https://example.com/"} {
+		seen.Add(ctx, key)
+	}
+	if seen.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", seen.Len())
+	}
+	seen.Close()
+	if _, err := seen.Add(ctx, "https://example.com/b"); err == nil {
+		t.Error("Add succeeded on a closed store")
+	}
+
+	// a crash mid-write leaves a truncated line
+	f, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	f.WriteString(`"https://example.com/tru`)
+	f.Close()
+
+	seen, err = OpenFileSeenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer seen.Close()
+	if added, _ := seen.Add(ctx, "https://example.com/a"); added {
+		t.Error("key recorded before the restart added again")
+	}
+	if added, err := seen.Add(ctx, "https://example.com/c"); !added || err != nil {
+		t.Errorf("Add(new) = %v, %v", added, err)
+	}
+	seen.Close()
+
+	seen, _ = OpenFileSeenStore(path)
+	defer seen.Close()
+	if seen.Len() != 3 {
+		t.Errorf("Len() after reopen = %d, want 3", seen.Len())
+	}
+	if err := seen.Done(ctx, "https://example.com/a"); err != nil {
+		t.Fatal(err)
+	}
+	seen.Close()
+
+	seen, _ = OpenFileSeenStore(path)
+	defer seen.Close()
+	if pending, _ := seen.Pending(ctx); fmt.Sprint(pending) != "[https://example.com/ https://example.com/c]" {
+		t.Errorf("Pending() after reopen = %v", pending)
+	}
+}
@@ -0,0 +1,241 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Selector provides a goquery document for parsing HTML content.
+//
+// The content is parsed on the first call and the document cached, so
+// repeated calls are free; it is safe for concurrent use. Copies of the
+// result share the document, which is re-parsed if the content changes.
+// Queries don't modify the document; clone selections before removing or
+// editing nodes. It can only be used with HTML content.
+//
+// Example:
+//
+//	result, err := client.Scrape(config)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	doc, err := result.Selector()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	title := doc.Find("title").First().Text()
+//	fmt.Println(title)
+func (r *ScrapeResult) Selector() (*goquery.Document, error) {
+	cache := r.documentCache()
+	cache.once.Do(func() {
+		if !strings.Contains(r.Result.ContentType, "text/html") {
+			cache.err = fmt.Errorf("%w: cannot use selector on non-html content-type, got %s", ErrContentType, r.Result.ContentType)
+			return
+		}
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(cache.content))
+		if err != nil {
+			cache.err = err
+			return
+		}
+		cache.doc = doc
+	})
+	doc, _ := cache.doc.(*goquery.Document)
+	return doc, cache.err
+}
+
+// Find returns the elements of the HTML content matching the CSS
+// selector, or an empty selection for non-HTML content.
+//
+// Example:
+//
+//	for _, price := range result.Find(".product .price").EachIter() {
+//	    fmt.Println(price.Text())
+//	}
+func (r *ScrapeResult) Find(selector string) *goquery.Selection {
+	doc, err := r.Selector()
+	if err != nil {
+		return &goquery.Selection{}
+	}
+	return doc.Find(selector)
+}
+
+// FindText returns the whitespace-trimmed text of the first element
+// matching the CSS selector, or "".
+func (r *ScrapeResult) FindText(selector string) string {
+	return strings.TrimSpace(r.Find(selector).First().Text())
+}
+
+// FindAttr returns the attribute attr of the first element matching the
+// CSS selector, or "".
+func (r *ScrapeResult) FindAttr(selector, attr string) string {
+	return r.Find(selector).First().AttrOr(attr, "")
+}
+
+// htmlLinks returns the href of every <a href> element of an HTML result,
+// or nil for non-HTML content.
+func htmlLinks(result *ScrapeResult) []string {
+	var links []string
+	for _, a := range htmlAnchors(result) {
+		links = append(links, a.href)
+	}
+	return links
+}
+
+// htmlAnchors returns every <a href> element of an HTML result, or nil for
+// non-HTML content.
+func htmlAnchors(result *ScrapeResult) []htmlAnchor {
+	doc, err := result.Selector()
+	if err != nil {
+		return nil
+	}
+	var anchors []htmlAnchor
+	doc.Find("a[href]").Each(func(_ int, s *goquery.Selection) {
+		anchors = append(anchors, htmlAnchor{
+			href: s.AttrOr("href", ""),
+			rel:  s.AttrOr("rel", ""),
+			text: strings.Join(strings.Fields(s.Text()), " "),
+		})
+	})
+	return anchors
+}
+
+// StructuredData extracts the JSON-LD blocks, microdata items and meta
+// tags of an HTML result.
+//
+// Example:
+//
+//	data, err := result.StructuredData()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(data.OpenGraph["title"], data.Meta["description"])
+//	for _, product := range data.JSONLDOfType("Product") {
+//	    fmt.Println(product["name"])
+//	}
+func (r *ScrapeResult) StructuredData() (*StructuredData, error) {
+	doc, err := r.Selector()
+	if err != nil {
+		return nil, err
+	}
+	data := newStructuredData()
+	data.JSONLD = parseJSONLD(doc.Find(`script[type="application/ld+json"]`).Map(func(_ int, s *goquery.Selection) string {
+		return s.Text()
+	}))
+	doc.Find("[itemscope]").Not("[itemprop]").Each(func(_ int, s *goquery.Selection) {
+		data.Microdata = append(data.Microdata, microdataItem(s))
+	})
+	doc.Find("meta[content]").Each(func(_ int, s *goquery.Selection) {
+		property, _ := s.Attr("property")
+		name, _ := s.Attr("name")
+		content, _ := s.Attr("content")
+		data.addMetaTag(property, name, content)
+	})
+	return data, nil
+}
+
+// microdataItem reads the item of an itemscope element and its properties.
+func microdataItem(s *goquery.Selection) *MicrodataItem {
+	item := &MicrodataItem{Properties: map[string][]interface{}{}}
+	item.Type = strings.Fields(s.AttrOr("itemtype", ""))
+	item.ID = s.AttrOr("itemid", "")
+	var walk func(*goquery.Selection)
+	walk = func(parent *goquery.Selection) {
+		parent.Children().Each(func(_ int, child *goquery.Selection) {
+			props := strings.Fields(child.AttrOr("itemprop", ""))
+			_, scoped := child.Attr("itemscope")
+			if len(props) > 0 {
+				var value interface{}
+				if scoped {
+					value = microdataItem(child)
+				} else {
+					value = microdataValue(child)
+				}
+				for _, prop := range props {
+					item.Properties[prop] = append(item.Properties[prop], value)
+				}
+			}
+			// properties of a nested item belong to it
+			if !scoped {
+				walk(child)
+			}
+		})
+	}
+	walk(s)
+	return item
+}
+
+// microdataValue returns the property value of an element per the HTML
+// microdata rules.
+func microdataValue(s *goquery.Selection) string {
+	switch goquery.NodeName(s) {
+	case "meta":
+		return s.AttrOr("content", "")
+	case "a", "area", "link":
+		return s.AttrOr("href", "")
+	case "img", "audio", "video", "source", "iframe", "embed", "track":
+		return s.AttrOr("src", "")
+	case "object":
+		return s.AttrOr("data", "")
+	case "data", "meter":
+		return s.AttrOr("value", "")
+	case "time":
+		if datetime, ok := s.Attr("datetime"); ok {
+			return datetime
+		}
+	}
+	if content, ok := s.Attr("content"); ok {
+		return content
+	}
+	return strings.TrimSpace(s.Text())
+}
+
+// htmlAssets returns the unresolved asset references of an HTML result:
+// <img src>, <script src>, <link rel="stylesheet" href> and, as AssetFile
+// candidates, <a href>.
+func htmlAssets(result *ScrapeResult) []Asset {
+	doc, err := result.Selector()
+	if err != nil {
+		return nil
+	}
+	var assets []Asset
+	doc.Find("img[src], script[src], link[href], a[href]").Each(func(_ int, s *goquery.Selection) {
+		switch goquery.NodeName(s) {
+		case "img":
+			assets = append(assets, Asset{URL: s.AttrOr("src", ""), Kind: AssetImage})
+		case "script":
+			assets = append(assets, Asset{URL: s.AttrOr("src", ""), Kind: AssetScript})
+		case "link":
+			if isStylesheetRel(s.AttrOr("rel", "")) {
+				assets = append(assets, Asset{URL: s.AttrOr("href", ""), Kind: AssetStylesheet})
+			}
+		case "a":
+			assets = append(assets, Asset{URL: s.AttrOr("href", ""), Kind: AssetFile})
+		}
+	})
+	return assets
+}
+
+// htmlNextLink returns the href of the first element matching selector or,
+// when selector is empty, of the first <link> or <a> with rel="next".
+func htmlNextLink(result *ScrapeResult, selector string) (string, error) {
+	doc, err := result.Selector()
+	if err != nil {
+		return "", err
+	}
+	if selector == "" {
+		selector = `link[rel~="next"][href], a[rel~="next"][href]`
+	}
+	return strings.TrimSpace(doc.Find(selector).First().AttrOr("href", "")), nil
+}
+
+// Selector parses the iframe content into a goquery document. It parses on
+// every call; use Result to keep a cached one.
+func (f IFrame) Selector() (*goquery.Document, error) {
+	return f.Result().Selector()
+}
@@ -0,0 +1,85 @@
+//go:build !scrapfly_nogoquery
+
+package scrapfly
+
+import "testing"
+
+func TestScrapeResult_StructuredData_Microdata(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{ContentType: "text/html", Content: `<div itemscope itemtype="https://schema.org/Product">
+  <h1 itemprop="name"> Chocolate </h1>
+  <img itemprop="image" src="/choco.jpg">
+  <div itemprop="offers" itemscope itemtype="https://schema.org/Offer">
+    <meta itemprop="price" content="9.99"><span itemprop="priceCurrency">USD</span>
+  </div>
+  <div><a itemprop="url" href="/product/1">link</a></div>
+</div>`}}
+	data, err := result.StructuredData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Microdata) != 1 {
+		t.Fatalf("Microdata = %v, want 1 top-level item", data.Microdata)
+	}
+	product := data.Microdata[0]
+	if !product.IsType("Product") || product.Prop("name") != "Chocolate" || product.Prop("image") != "/choco.jpg" || product.Prop("url") != "/product/1" {
+		t.Errorf("unexpected product %+v", product)
+	}
+	offer := product.Item("offers")
+	if offer == nil || offer.Prop("price") != "9.99" || offer.Prop("priceCurrency") != "USD" {
+		t.Errorf("unexpected offer %+v", offer)
+	}
+	if _, ok := product.Properties["price"]; ok {
+		t.Error("offer properties should not leak into the product")
+	}
+}
+
+func TestIFrame_Selector(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{IFrames: []IFrame{
+		{URL: "https://ads.example.com/banner", Content: "<p>ad</p>"},
+		{URL: "https://widgets.example.com/reviews?id=1", Content: `<div class="review">Great</div><div class="review">Bad</div><a href="/more">more</a>`},
+	}}}
+	iframe := result.IFrame("reviews")
+	if iframe == nil {
+		t.Fatal("IFrame(reviews) not found")
+	}
+	doc, err := iframe.Selector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := doc.Find(".review").Length(); n != 2 {
+		t.Errorf("found %d reviews, want 2", n)
+	}
+	if links := iframe.Result().Links(nil).URLs(); len(links) != 1 || links[0] != "https://widgets.example.com/more" {
+		t.Errorf("links resolved against the iframe URL: %v", links)
+	}
+	if result.IFrame("missing") != nil {
+		t.Error("expected nil for an unknown iframe")
+	}
+}
+
+func TestScrapeResult_SelectorCache(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{ContentType: "text/html", Content: `<h1 class="t">Title</h1><a href="/x">x</a>`}}
+	first, err := result.Selector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := result.Selector(); second != first {
+		t.Error("Selector() should return the cached document")
+	}
+	copied := *result
+	if doc, _ := copied.Selector(); doc != first {
+		t.Error("copies should share the parsed document")
+	}
+	copied.Result.Content = "<h1>Changed</h1>"
+	if copied.FindText("h1") != "Changed" || result.FindText("h1") != "Title" {
+		t.Errorf("content changes should re-parse: %q %q", copied.FindText("h1"), result.FindText("h1"))
+	}
+	if result.FindAttr("a", "href") != "/x" || result.Find("h1.t").Length() != 1 {
+		t.Error("unexpected Find results")
+	}
+
+	pdf := &ScrapeResult{Result: ResultData{ContentType: "application/pdf"}}
+	if pdf.Find("h1").Length() != 0 || pdf.FindText("h1") != "" || pdf.FindAttr("a", "href") != "" {
+		t.Error("expected empty selections for non-HTML content")
+	}
+}
@@ -0,0 +1,156 @@
+//go:build scrapfly_nogoquery
+
+package scrapfly
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// anchorRegex matches the opening tag of <a> elements, assetTagRegex and
+// nextTagRegex the tags htmlAssets and htmlNextLink read, and htmlAttrRegex
+// their attributes. Without goquery, links are found lexically; markup
+// inside comments or scripts is not skipped.
+var (
+	anchorRegex   = regexp.MustCompile(`(?is)<a(\s[^>]*)>`)
+	assetTagRegex = regexp.MustCompile(`(?is)<(img|script|link|a)(\s[^>]*)>`)
+	nextTagRegex  = regexp.MustCompile(`(?is)<(?:link|a)(\s[^>]*)>`)
+	htmlAttrRegex = regexp.MustCompile(`(?is)\s([a-z][a-z0-9_:-]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s>]+))`)
+)
+
+// htmlLinks returns the href of every <a href> element of an HTML result,
+// or nil for non-HTML content.
+func htmlLinks(result *ScrapeResult) []string {
+	var links []string
+	for _, a := range htmlAnchors(result) {
+		links = append(links, a.href)
+	}
+	return links
+}
+
+// htmlAnchors returns every <a href> element of an HTML result, or nil for
+// non-HTML content. The anchor text is not extracted.
+func htmlAnchors(result *ScrapeResult) []htmlAnchor {
+	if !strings.Contains(result.Result.ContentType, "text/html") {
+		return nil
+	}
+	var anchors []htmlAnchor
+	for _, m := range anchorRegex.FindAllStringSubmatch(result.Result.Content, -1) {
+		attrs := htmlAttrs(m[1])
+		if href, ok := attrs["href"]; ok {
+			anchors = append(anchors, htmlAnchor{href: href, rel: attrs["rel"]})
+		}
+	}
+	return anchors
+}
+
+// htmlAttrs parses the attributes of a tag, keyed by lowercased name.
+func htmlAttrs(tag string) map[string]string {
+	attrs := map[string]string{}
+	for _, m := range htmlAttrRegex.FindAllStringSubmatch(tag, -1) {
+		name := strings.ToLower(m[1])
+		if _, exists := attrs[name]; !exists {
+			attrs[name] = html.UnescapeString(m[2] + m[3] + m[4])
+		}
+	}
+	return attrs
+}
+
+var (
+	jsonLDRegex  = regexp.MustCompile(`(?is)<script\s[^>]*?\btype\s*=\s*["']?application/ld\+json["']?[^>]*>(.*?)</script>`)
+	metaTagRegex = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+)
+
+// StructuredData extracts the JSON-LD blocks and meta tags of an HTML
+// result. Without goquery, microdata is not extracted.
+func (r *ScrapeResult) StructuredData() (*StructuredData, error) {
+	if !strings.Contains(r.Result.ContentType, "text/html") {
+		return nil, fmt.Errorf("%w: cannot extract structured data from non-html content-type, got %s", ErrContentType, r.Result.ContentType)
+	}
+	data := newStructuredData()
+	var blocks []string
+	for _, m := range jsonLDRegex.FindAllStringSubmatch(r.Result.Content, -1) {
+		blocks = append(blocks, m[1])
+	}
+	data.JSONLD = parseJSONLD(blocks)
+	for _, tag := range metaTagRegex.FindAllString(r.Result.Content, -1) {
+		attrs := htmlAttrs(tag)
+		if content, ok := attrs["content"]; ok {
+			data.addMetaTag(attrs["property"], attrs["name"], content)
+		}
+	}
+	return data, nil
+}
+
+// htmlAssets returns the unresolved asset references of an HTML result:
+// <img src>, <script src>, <link rel="stylesheet" href> and, as AssetFile
+// candidates, <a href>.
+func htmlAssets(result *ScrapeResult) []Asset {
+	if !strings.Contains(result.Result.ContentType, "text/html") {
+		return nil
+	}
+	var assets []Asset
+	for _, m := range assetTagRegex.FindAllStringSubmatch(result.Result.Content, -1) {
+		attrs := htmlAttrs(m[2])
+		switch strings.ToLower(m[1]) {
+		case "img":
+			if src, ok := attrs["src"]; ok {
+				assets = append(assets, Asset{URL: src, Kind: AssetImage})
+			}
+		case "script":
+			if src, ok := attrs["src"]; ok {
+				assets = append(assets, Asset{URL: src, Kind: AssetScript})
+			}
+		case "link":
+			if href, ok := attrs["href"]; ok && isStylesheetRel(attrs["rel"]) {
+				assets = append(assets, Asset{URL: href, Kind: AssetStylesheet})
+			}
+		case "a":
+			if href, ok := attrs["href"]; ok {
+				assets = append(assets, Asset{URL: href, Kind: AssetFile})
+			}
+		}
+	}
+	return assets
+}
+
+func htmlMarkdown(r *ScrapeResult) (string, error) {
+	return "", errGoqueryDisabled
+}
+
+func htmlArticle(r *ScrapeResult) (*Article, error) {
+	return nil, errGoqueryDisabled
+}
+
+func htmlTextNodes(r *ScrapeResult, scope string) ([]string, error) {
+	return nil, errGoqueryDisabled
+}
+
+func htmlSelectorTexts(r *ScrapeResult, selector string) ([]string, error) {
+	return nil, errGoqueryDisabled
+}
+
+// htmlNextLink returns the href of the first <link> or <a> with rel="next".
+// CSS selectors need goquery.
+func htmlNextLink(result *ScrapeResult, selector string) (string, error) {
+	if selector != "" {
+		return "", errGoqueryDisabled
+	}
+	if !strings.Contains(result.Result.ContentType, "text/html") {
+		return "", fmt.Errorf("%w: cannot find links in non-html content-type, got %s", ErrContentType, result.Result.ContentType)
+	}
+	for _, m := range nextTagRegex.FindAllStringSubmatch(result.Result.Content, -1) {
+		attrs := htmlAttrs(m[1])
+		if href, ok := attrs["href"]; ok && slices.Contains(strings.Fields(strings.ToLower(attrs["rel"])), "next") {
+			return strings.TrimSpace(href), nil
+		}
+	}
+	return "", nil
+}
+
+// transcodeToUTF8 needs the charset tables of golang.org/x/net, content is
+// kept as received.
+func (r *ScrapeResult) transcodeToUTF8() {}
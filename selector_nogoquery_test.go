@@ -0,0 +1,22 @@
+//go:build scrapfly_nogoquery
+
+package scrapfly
+
+import "testing"
+
+func TestHTMLLinks_Lexical(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{
+		ContentType: "text/html",
+		Content:     `<a href="/a">A</a> <A class=x HREF='/b?x=1&amp;y=2'>B</A> <a href=/c>C</a> <link href="/css">`,
+	}}
+	links := htmlLinks(result)
+	want := []string{"/a", "/b?x=1&y=2", "/c"}
+	if len(links) != len(want) {
+		t.Fatalf("links = %v, want %v", links, want)
+	}
+	for i := range want {
+		if links[i] != want[i] {
+			t.Errorf("links[%d] = %q, want %q", i, links[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package scrapfly
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Sink stores the results of a pipeline; see ConcurrentScrapeToSink.
+type Sink interface {
+	Write(result *ScrapeResult) error
+}
+
+// SinkFunc adapts a function to Sink.
+type SinkFunc func(result *ScrapeResult) error
+
+// Write calls f(result).
+func (f SinkFunc) Write(result *ScrapeResult) error {
+	return f(result)
+}
+
+// JSONLSink writes results as JSON lines, one result per line in its
+// lossless JSON form (see ScrapeResult.MarshalJSON). It is safe for
+// concurrent use.
+type JSONLSink struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer []io.Closer
+}
+
+// NewJSONLSink returns a sink writing to w.
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{w: w}
+}
+
+// CreateJSONLSink creates the file at path, and its parent directories,
+// and returns a sink writing to it, gzip-compressed when path ends with
+// ".gz". Close the sink to flush the file.
+func CreateJSONLSink(path string) (*JSONLSink, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return &JSONLSink{w: f, closer: []io.Closer{f}}, nil
+	}
+	zw := gzip.NewWriter(f)
+	return &JSONLSink{w: zw, closer: []io.Closer{zw, f}}, nil
+}
+
+// Write appends result as a line.
+func (s *JSONLSink) Write(result *ScrapeResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(data, '\n'))
+	return err
+}
+
+// Close closes the file of a sink made by CreateJSONLSink; it does nothing
+// for NewJSONLSink.
+func (s *JSONLSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var errs []error
+	for _, c := range s.closer {
+		errs = append(errs, c.Close())
+	}
+	s.closer = nil
+	return errors.Join(errs...)
+}
+
+// ConcurrentScrapeToSink runs ConcurrentScrapeWithOptionsContext and
+// writes the successful results to sink as they complete, so pipelines
+// store results without draining a channel. Sink writes are serialized.
+//
+// It returns the run summary and the failures joined (see errors.Join):
+// the scrape errors, each carrying its URL, and the run-level ones. A sink
+// error aborts the run, which then fails with it.
+//
+// Example:
+//
+//	sink, err := scrapfly.CreateJSONLSink("out/products.jsonl.gz")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer sink.Close()
+//	summary, err := client.ConcurrentScrapeToSink(ctx, configs, scrapfly.ConcurrentScrapeOptions{Concurrency: 10}, sink)
+//	log.Printf("%d stored, errors: %v", summary.Succeeded, err)
+func (c *Client) ConcurrentScrapeToSink(ctx context.Context, configs []*ScrapeConfig, opts ConcurrentScrapeOptions, sink Sink) (ConcurrentScrapeSummary, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var summary ConcurrentScrapeSummary
+	onComplete := opts.OnComplete
+	opts.OnComplete = func(s ConcurrentScrapeSummary) {
+		summary = s
+		if onComplete != nil {
+			onComplete(s)
+		}
+	}
+
+	var errs []error
+	var sinkErr error
+	for item := range c.ConcurrentScrapeWithOptionsContext(ctx, configs, opts) {
+		switch {
+		case sinkErr != nil:
+			// aborted, drain the run
+		case item.Err != nil && item.Config != nil:
+			errs = append(errs, fmt.Errorf("failed to scrape %s: %w", item.Config.URL, item.Err))
+		case item.Err != nil:
+			errs = append(errs, item.Err)
+		default:
+			if err := sink.Write(item.Result); err != nil {
+				sinkErr = fmt.Errorf("failed to write result of %s: %w", item.Config.URL, err)
+				cancel()
+			}
+		}
+	}
+	if sinkErr != nil {
+		return summary, sinkErr
+	}
+	return summary, errors.Join(errs...)
+}
@@ -0,0 +1,87 @@
+package scrapfly
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestClient_ConcurrentScrapeToSink_JSONL(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		u := r.URL.Query().Get("url")
+		if strings.HasSuffix(u, "/missing") {
+			fmt.Fprint(w, `{"result":{"success":false,"status":"ERR::SCRAPE::BAD_UPSTREAM_RESPONSE","status_code":404}}`)
+			return
+		}
+		fmt.Fprintf(w, `{"result":{"success":true,"status":"DONE","status_code":200,"url":%q,"content":"<p>ok</p>"}}`, u)
+	})
+	path := filepath.Join(t.TempDir(), "out", "results.jsonl.gz")
+	sink, err := CreateJSONLSink(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	configs := []*ScrapeConfig{{URL: "https://example.com/a"}, {URL: "https://example.com/missing"}, {URL: "https://example.com/b"}}
+	summary, err := client.ConcurrentScrapeToSink(context.Background(), configs, ConcurrentScrapeOptions{Concurrency: 2}, sink)
+	if !errors.Is(err, ErrUpstreamClient) || !strings.Contains(err.Error(), "https://example.com/missing") {
+		t.Errorf("err = %v, want the failure of the missing page", err)
+	}
+	if summary.Succeeded != 2 || summary.Failed != 1 {
+		t.Errorf("summary = %+v", summary)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	urls := map[string]bool{}
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		var result ScrapeResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatal(err)
+		}
+		urls[result.Result.URL] = true
+	}
+	if len(urls) != 2 || !urls["https://example.com/a"] || !urls["https://example.com/b"] {
+		t.Errorf("stored %v", urls)
+	}
+}
+
+func TestClient_ConcurrentScrapeToSink_SinkErrorAborts(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"success":true,"status":"DONE","status_code":200}}`)
+	})
+	configs := make([]*ScrapeConfig, 20)
+	for i := range configs {
+		configs[i] = &ScrapeConfig{URL: fmt.Sprintf("https://example.com/%d", i)}
+	}
+	errDiskFull := errors.New("disk full")
+	writes := 0
+	sink := SinkFunc(func(*ScrapeResult) error {
+		writes++
+		return errDiskFull
+	})
+	summary, err := client.ConcurrentScrapeToSink(context.Background(), configs, ConcurrentScrapeOptions{Concurrency: 1}, sink)
+	if !errors.Is(err, errDiskFull) {
+		t.Errorf("err = %v, want the sink error", err)
+	}
+	if writes != 1 || len(summary.Pending) == 0 {
+		t.Errorf("%d writes, %d pending; the run should stop at the first sink error", writes, len(summary.Pending))
+	}
+}
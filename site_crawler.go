@@ -0,0 +1,402 @@
+package scrapfly
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SiteCrawlOptions configures CrawlSite.
+type SiteCrawlOptions struct {
+	// Config is the template of the page scrapes (ASP, RenderJS, proxy
+	// pool...), cloned for each page with its URL set and, on the pages of
+	// followed links, a Referer header. Nil scrapes with the defaults.
+	Config *ScrapeConfig
+	// Follow selects the links followed. SameDomain keeps the links to the
+	// host of the seed the page was reached from, so a redirect to another
+	// site doesn't widen the crawl; Include and Exclude are the allow and
+	// deny patterns of the link URLs.
+	Follow LinkOptions
+	// MaxDepth is how many links away from the seeds the crawl goes: seeds
+	// are at depth 0 and the links of a page at depth d are at depth d+1.
+	// Zero means no limit.
+	MaxDepth int
+	// MaxPages caps the pages scraped, failures included. Zero means no
+	// limit.
+	MaxPages int
+	// Concurrency is the maximum number of scrapes in flight. When <= 0 the
+	// account's concurrent limit is used.
+	Concurrency int
+	// Canonicalizer identifies the pages already queued, so each page is
+	// scraped once. Defaults to DefaultURLCanonicalizer.
+	Canonicalizer *URLCanonicalizer
+	// Seen records the pages queued, by canonical URL, and skips the links
+	// to the pages it already has. Defaults to a new MemorySeenStore. Seeds
+	// are always queued, so a crawl stopped cleanly resumes from the
+	// Summary.Pending of the previous one. A FrontierStore, such as
+	// FileSeenStore, also resumes the pages a killed crawl left queued:
+	// they are queued before the seeds, at depth 0 and scoped to their own
+	// host for Follow.SameDomain.
+	Seen SeenStore
+	// Robots, when set, skips the pages robots.txt disallows, counted in
+	// Summary.Disallowed, and waits the Crawl-delay of each host between
+	// the starts of its scrapes. Pages whose robots.txt can't be fetched
+	// fail without being scraped.
+	Robots *Robots
+	// OnPage, when set, is called with each page scraped, failed ones
+	// included. Calls are serialized, from the goroutine of CrawlSite.
+	// Trimming page.Links follows fewer links; returning an error stops
+	// the crawl, aborting the scrapes in flight, and CrawlSite returns it.
+	OnPage func(page *CrawledPage) error
+}
+
+// CrawledPage is a page scraped by CrawlSite.
+type CrawledPage struct {
+	// URL is the URL the page was queued with.
+	URL string
+	// Depth is the number of links followed from a seed to the page.
+	Depth int
+	// Referer is the final URL of the page the link was found on, "" for
+	// the seeds. It is sent as the Referer header of the page scrape,
+	// unless SiteCrawlOptions.Config sets one.
+	Referer string
+	// Result is the scrape result, nil when the scrape failed.
+	Result *ScrapeResult
+	// Err is the scrape error.
+	Err error
+	// Links are the links of the page kept by SiteCrawlOptions.Follow, the
+	// ones the crawl follows. They are empty at MaxDepth and for failed
+	// pages; links to pages already queued are skipped.
+	Links Links
+}
+
+// SiteCrawlSummary reports how a CrawlSite run ended.
+type SiteCrawlSummary struct {
+	// Succeeded and Failed count the pages scraped.
+	Succeeded int
+	Failed    int
+	// Discovered is the number of distinct pages queued, seeds included.
+	Discovered int
+	// Disallowed counts the pages skipped because robots.txt disallows
+	// them; see SiteCrawlOptions.Robots.
+	Disallowed int
+	// Pending holds the URLs queued but never scraped, because of MaxPages
+	// or the crawl being stopped, in the order they were discovered.
+	Pending []string
+	// MaxPagesReached reports whether MaxPages stopped the crawl.
+	MaxPagesReached bool
+	// Canceled reports whether the context was done before the crawl ended.
+	Canceled bool
+	// Cost is the API credits spent by the scrapes.
+	Cost int
+	// Elapsed is the wall-clock duration of the crawl.
+	Elapsed time.Duration
+}
+
+// crawlPage is a page queued by CrawlSite.
+type crawlPage struct {
+	config  *ScrapeConfig
+	depth   int
+	referer string
+	// scope is the host of the seed, without "www."
+	scope string
+	// seq is the discovery order of the page.
+	seq int
+	// allowed is set once robots.txt allowed the page.
+	allowed bool
+	// key is the canonical URL of the page, its key in the seen store.
+	key string
+}
+
+// CrawlSite crawls from seeds with this client: it scrapes each page,
+// passes it to OnPage and follows its links breadth-first, within the
+// Follow rules, MaxDepth and MaxPages, until no page is left. Unlike
+// StartCrawl, which runs the crawl on the Crawler API, the crawl is driven
+// locally and each page is a regular scrape, so the SDK options (sessions,
+// ASP, extraction...) apply page by page.
+//
+// Failed pages are reported to OnPage, not as the error: CrawlSite fails
+// with the OnPage error, the account lookup error or, when ctx is done,
+// its cause.
+//
+// Example:
+//
+//	summary, err := client.CrawlSite(ctx, []string{"https://web-scraping.dev/products"}, scrapfly.SiteCrawlOptions{
+//	    Config: &scrapfly.ScrapeConfig{ASP: true},
+//	    Follow: scrapfly.LinkOptions{
+//	        SameDomain: true,
+//	        Include:    []*regexp.Regexp{regexp.MustCompile(`/products?`)},
+//	    },
+//	    MaxDepth:    3,
+//	    MaxPages:    200,
+//	    Concurrency: 5,
+//	    OnPage: func(page *scrapfly.CrawledPage) error {
+//	        if page.Err == nil {
+//	            store(page.URL, page.Result)
+//	        }
+//	        return nil
+//	    },
+//	})
+func (c *Client) CrawlSite(ctx context.Context, seeds []string, opts SiteCrawlOptions) (SiteCrawlSummary, error) {
+	started := time.Now()
+	canonicalizer := opts.Canonicalizer
+	if canonicalizer == nil {
+		canonicalizer = DefaultURLCanonicalizer
+	}
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	seen := opts.Seen
+	if seen == nil {
+		seen = NewMemorySeenStore()
+	}
+	frontierStore, _ := seen.(FrontierStore)
+	var summary SiteCrawlSummary
+	var err error
+	frontier := &crawlFrontier{queues: make(map[string][]crawlPage), lastStart: make(map[string]time.Time)}
+	// queued holds the pages of this crawl, seeds included
+	queued := make(map[string]bool)
+	enqueue := func(rawURL string, page crawlPage, seed bool) {
+		key := canonicalizer.Canonicalize(rawURL)
+		if queued[key] || err != nil {
+			return
+		}
+		added, seenErr := seen.Add(ctx, key)
+		if seenErr != nil {
+			err = fmt.Errorf("failed to record %s as seen: %w", rawURL, seenErr)
+			cancel()
+			return
+		}
+		if !added && !seed {
+			return
+		}
+		queued[key] = true
+		page.key = key
+		page.config = scrapeConfigFor(opts.Config, rawURL)
+		if page.referer != "" && !hasHeader(page.config.Headers, "referer") {
+			if page.config.Headers == nil {
+				page.config.Headers = make(map[string]string)
+			}
+			page.config.Headers["referer"] = page.referer
+		}
+		page.seq = summary.Discovered
+		frontier.push(page)
+		summary.Discovered++
+	}
+	if frontierStore != nil {
+		resumed, pendingErr := frontierStore.Pending(ctx)
+		if pendingErr != nil {
+			return summary, fmt.Errorf("failed to load the pending pages: %w", pendingErr)
+		}
+		seeds = append(resumed, seeds...)
+	}
+	for _, seed := range seeds {
+		scope := ""
+		if u, err := url.Parse(seed); err == nil {
+			scope = strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+		}
+		enqueue(seed, crawlPage{scope: scope}, true)
+	}
+	// finish marks page done in a FrontierStore.
+	finish := func(page crawlPage) {
+		if frontierStore == nil || err != nil {
+			return
+		}
+		if doneErr := frontierStore.Done(ctx, page.key); doneErr != nil {
+			err = fmt.Errorf("failed to record %s as done: %w", page.config.URL, doneErr)
+			cancel()
+		}
+	}
+
+	// report passes a page that ended to OnPage and queues its links.
+	report := func(page crawlPage, result *ScrapeResult, scrapeErr error) {
+		if err != nil {
+			return
+		}
+		summary.Cost += scrapeCost(result, scrapeErr)
+		crawled := &CrawledPage{URL: page.config.URL, Depth: page.depth, Referer: page.referer, Result: result, Err: scrapeErr}
+		if scrapeErr != nil {
+			summary.Failed++
+		} else {
+			summary.Succeeded++
+			if opts.MaxDepth <= 0 || page.depth < opts.MaxDepth {
+				crawled.Links = crawlLinks(result, opts.Follow, page.scope)
+			}
+		}
+		if opts.OnPage != nil {
+			if err = opts.OnPage(crawled); err != nil {
+				cancel()
+				return
+			}
+		}
+		for _, link := range crawled.Links {
+			enqueue(link.URL, crawlPage{depth: page.depth + 1, referer: result.FinalURL(), scope: page.scope}, false)
+		}
+		// done once its links are queued, a crash before resumes it
+		finish(page)
+	}
+	// pick returns the host of the next page to scrape at now: the first
+	// discovered among the hosts out of their Crawl-delay. When every host
+	// left waits, it returns -1 and the shortest wait. Pages robots.txt
+	// disallows are dropped, and fail when it can't be fetched.
+	pick := func(now time.Time) (int, time.Duration) {
+		at, wait := -1, time.Duration(0)
+		for i, host := range frontier.hosts {
+			queue := frontier.queues[host]
+			for opts.Robots != nil && len(queue) > 0 && !queue[0].allowed {
+				page := queue[0]
+				checkErr := opts.Robots.check(ctx, page.config.URL)
+				if checkErr == nil {
+					queue[0].allowed = true
+					break
+				}
+				queue = queue[1:]
+				frontier.len--
+				if errors.Is(checkErr, ErrRobotsDisallowed) {
+					summary.Disallowed++
+					finish(page)
+				} else {
+					report(page, nil, checkErr)
+				}
+			}
+			frontier.queues[host] = queue
+			if len(queue) == 0 {
+				continue
+			}
+			if last := frontier.lastStart[host]; opts.Robots != nil && !last.IsZero() {
+				delay, _ := opts.Robots.CrawlDelay(ctx, queue[0].config.URL)
+				if d := last.Add(delay).Sub(now); d > 0 {
+					if wait == 0 || d < wait {
+						wait = d
+					}
+					continue
+				}
+			}
+			if at < 0 || queue[0].seq < frontier.queues[frontier.hosts[at]][0].seq {
+				at = i
+			}
+		}
+		if at >= 0 {
+			wait = 0
+		}
+		return at, wait
+	}
+
+	configs := make(chan *ScrapeConfig)
+	input := configs
+	results := c.ConcurrentScrapeChan(ctx, configs, opts.Concurrency)
+	var sent []crawlPage
+	inFlight := 0
+crawl:
+	for {
+		limited := opts.MaxPages > 0 && len(sent) >= opts.MaxPages
+		at := -1
+		var delayC <-chan time.Time
+		if input != nil && err == nil && ctx.Err() == nil && !limited {
+			var wait time.Duration
+			if at, wait = pick(time.Now()); wait > 0 {
+				delayC = time.After(wait)
+			}
+		}
+		if input != nil && (err != nil || ctx.Err() != nil || (inFlight == 0 && at < 0 && delayC == nil)) {
+			close(input)
+			input = nil
+		}
+		var send chan<- *ScrapeConfig
+		var next *ScrapeConfig
+		if input != nil && at >= 0 {
+			send, next = input, frontier.queues[frontier.hosts[at]][0].config
+		}
+
+		select {
+		case send <- next:
+			host := frontier.hosts[at]
+			sent = append(sent, frontier.queues[host][0])
+			frontier.queues[host] = frontier.queues[host][1:]
+			frontier.len--
+			frontier.lastStart[host] = time.Now()
+			inFlight++
+		case <-delayC:
+		case item, ok := <-results:
+			if !ok {
+				break crawl
+			}
+			if item.Index < 0 {
+				err = item.Err
+				continue
+			}
+			inFlight--
+			report(sent[item.Index], item.Result, item.Err)
+		}
+	}
+
+	summary.Pending = frontier.pending()
+	summary.MaxPagesReached = opts.MaxPages > 0 && len(sent) >= opts.MaxPages && frontier.len > 0
+	summary.Canceled = parent.Err() != nil
+	summary.Elapsed = time.Since(started)
+	if err == nil && summary.Canceled {
+		err = context.Cause(parent)
+	}
+	return summary, err
+}
+
+// crawlFrontier queues the pages of CrawlSite per host.
+type crawlFrontier struct {
+	queues map[string][]crawlPage
+	// hosts are the keys of queues, in discovery order.
+	hosts     []string
+	lastStart map[string]time.Time
+	// len counts the queued pages, all hosts.
+	len int
+}
+
+func (f *crawlFrontier) push(page crawlPage) {
+	host := ""
+	if u, err := url.Parse(page.config.URL); err == nil {
+		host = strings.ToLower(u.Hostname())
+	}
+	if _, ok := f.queues[host]; !ok {
+		f.hosts = append(f.hosts, host)
+	}
+	f.queues[host] = append(f.queues[host], page)
+	f.len++
+}
+
+// pending returns the URLs of the queued pages, in discovery order.
+func (f *crawlFrontier) pending() []string {
+	var pages []crawlPage
+	for _, host := range f.hosts {
+		pages = append(pages, f.queues[host]...)
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].seq < pages[j].seq })
+	var urls []string
+	for _, page := range pages {
+		urls = append(urls, page.config.URL)
+	}
+	return urls
+}
+
+// crawlLinks returns the links of result kept by opts, with SameDomain
+// checked against scope, the host of the seed, rather than the host of the
+// page.
+func crawlLinks(result *ScrapeResult, opts LinkOptions, scope string) Links {
+	sameDomain := opts.SameDomain
+	opts.SameDomain = false
+	links := result.Links(&opts)
+	if !sameDomain {
+		return links
+	}
+	scoped := LinkOptions{SameDomain: true, IncludeSubdomains: opts.IncludeSubdomains}
+	kept := links[:0]
+	for _, link := range links {
+		if ref, err := url.Parse(link.URL); err == nil && scoped.keep(link, ref, scope) {
+			kept = append(kept, link)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,274 @@
+package scrapfly
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// siteCrawlHandler serves the pages of site through the scrape API, each
+// an HTML page with links to the given hrefs.
+func siteCrawlHandler(t *testing.T, site map[string][]string) (http.HandlerFunc, func() []string) {
+	var mu sync.Mutex
+	var scraped []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("url")
+		mu.Lock()
+		scraped = append(scraped, target)
+		mu.Unlock()
+		hrefs, ok := site[target]
+		if !ok {
+			http.Error(w, `{"code":"ERR::SCRAPE::UPSTREAM_NOT_FOUND","message":"not found"}`, http.StatusNotFound)
+			return
+		}
+		content := "<html><body>"
+		for _, href := range hrefs {
+			content += `<a href="` + href + `">link</a>`
+		}
+		content += "</body></html>"
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"context": map[string]interface{}{"cost": map[string]interface{}{"total": 1}},
+			"result": map[string]interface{}{
+				"success": true, "status": "DONE", "status_code": 200, "url": target,
+				"content_type": "text/html", "content": content,
+			},
+		})
+	}
+	return handler, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		out := append([]string(nil), scraped...)
+		sort.Strings(out)
+		return out
+	}
+}
+
+func TestClient_CrawlSite(t *testing.T) {
+	handler, scraped := siteCrawlHandler(t, map[string][]string{
+		"https://example.com/":         {"/a", "/b", "https://other.com/x", "/logout"},
+		"https://example.com/a":        {"/a/1", "/"},
+		"https://example.com/b":        {"/a?utm_source=nav", "https://shop.example.com/b/1"},
+		"https://example.com/a/1":      {"/deep"},
+		"https://shop.example.com/b/1": {"/deep"},
+	})
+	client := newTestClient(t, handler)
+
+	referers := map[string]string{}
+	summary, err := client.CrawlSite(context.Background(), []string{"https://example.com/"}, SiteCrawlOptions{
+		Follow: LinkOptions{
+			SameDomain:        true,
+			IncludeSubdomains: true,
+			Exclude:           []*regexp.Regexp{regexp.MustCompile(`/logout$`)},
+		},
+		MaxDepth:    2,
+		Concurrency: 2,
+		OnPage: func(page *CrawledPage) error {
+			if page.Err != nil {
+				t.Errorf("page %s: %v", page.URL, page.Err)
+			}
+			referers[page.URL] = page.Referer
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"https://example.com/", "https://example.com/a", "https://example.com/a/1", "https://example.com/b", "https://shop.example.com/b/1"}
+	if got := scraped(); len(got) != len(want) {
+		t.Fatalf("scraped %v, want %v", got, want)
+	} else {
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("scraped %v, want %v", got, want)
+				break
+			}
+		}
+	}
+	if referers["https://example.com/a/1"] != "https://example.com/a" || referers["https://example.com/"] != "" {
+		t.Errorf("referers = %v", referers)
+	}
+	if summary.Succeeded != 5 || summary.Failed != 0 || summary.Discovered != 5 || summary.Cost != 5 || len(summary.Pending) != 0 || summary.MaxPagesReached {
+		t.Errorf("summary = %+v", summary)
+	}
+}
+
+func TestClient_CrawlSite_SendsReferer(t *testing.T) {
+	handler, _ := siteCrawlHandler(t, map[string][]string{
+		"https://example.com/":  {"/a"},
+		"https://example.com/a": {"/b"},
+		"https://example.com/b": nil,
+	})
+	var mu sync.Mutex
+	sent := map[string]string{}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sent[r.URL.Query().Get("url")] = r.URL.Query().Get("headers[referer]")
+		mu.Unlock()
+		handler(w, r)
+	})
+
+	crawl := func(config *ScrapeConfig) {
+		t.Helper()
+		if _, err := client.CrawlSite(context.Background(), []string{"https://example.com/"}, SiteCrawlOptions{Config: config, Concurrency: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	crawl(nil)
+	if sent["https://example.com/"] != "" || sent["https://example.com/a"] != "https://example.com/" || sent["https://example.com/b"] != "https://example.com/a" {
+		t.Errorf("referers sent = %v", sent)
+	}
+	crawl(&ScrapeConfig{Headers: map[string]string{"Referer": "https://www.google.com/"}})
+	for page, referer := range sent {
+		if referer != "https://www.google.com/" {
+			t.Errorf("page %s sent referer %q, want the template one", page, referer)
+		}
+	}
+}
+
+func TestClient_CrawlSite_MaxPagesAndStop(t *testing.T) {
+	site := map[string][]string{"https://example.com/": {"/1", "/2", "/3", "/4", "/5"}}
+	handler, _ := siteCrawlHandler(t, site)
+	client := newTestClient(t, handler)
+
+	summary, err := client.CrawlSite(context.Background(), []string{"https://example.com/"}, SiteCrawlOptions{MaxPages: 3, Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the linked pages are not served and fail
+	if summary.Succeeded != 1 || summary.Failed != 2 || !summary.MaxPagesReached || len(summary.Pending) != 3 || summary.Pending[0] != "https://example.com/3" {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	stop := errors.New("enough")
+	pages := 0
+	_, err = client.CrawlSite(context.Background(), []string{"https://example.com/"}, SiteCrawlOptions{
+		Concurrency: 1,
+		OnPage: func(page *CrawledPage) error {
+			pages++
+			return stop
+		},
+	})
+	if !errors.Is(err, stop) || pages != 1 {
+		t.Errorf("err = %v after %d pages, want the OnPage error after 1", err, pages)
+	}
+}
+
+func TestClient_CrawlSite_Robots(t *testing.T) {
+	handler, scraped := siteCrawlHandler(t, map[string][]string{
+		"https://example.com/robots.txt": {},
+		"https://example.com/":           {"/a", "/private/b", "/c"},
+		"https://example.com/a":          {},
+		"https://example.com/c":          {},
+	})
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("url") == "https://example.com/robots.txt" {
+			w.Write([]byte(`{"result":{"success":true,"status":"DONE","status_code":200,"content":"User-agent: *\nDisallow: /private\n"}}`))
+			return
+		}
+		handler(w, r)
+	})
+
+	summary, err := client.CrawlSite(context.Background(), []string{"https://example.com/"}, SiteCrawlOptions{
+		Robots:      client.NewRobots(RobotsOptions{}),
+		Concurrency: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := scraped(); len(got) != 3 || summary.Succeeded != 3 || summary.Disallowed != 1 {
+		t.Errorf("scraped %v, summary %+v", got, summary)
+	}
+}
+
+func TestClient_CrawlSite_Seen(t *testing.T) {
+	handler, scraped := siteCrawlHandler(t, map[string][]string{
+		"https://example.com/":  {"/a", "/b"},
+		"https://example.com/a": {},
+		"https://example.com/b": {},
+	})
+	client := newTestClient(t, handler)
+
+	// a previous crawl queued the seed and /a
+	seen := NewMemorySeenStore()
+	seen.Add(context.Background(), "https://example.com/")
+	seen.Add(context.Background(), "https://example.com/a")
+	summary, err := client.CrawlSite(context.Background(), []string{"https://example.com/"}, SiteCrawlOptions{Seen: seen, Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := scraped(); len(got) != 2 || got[0] != "https://example.com/" || got[1] != "https://example.com/b" {
+		t.Errorf("scraped %v, want the seed and /b", got)
+	}
+	if summary.Discovered != 2 || seen.Len() != 3 {
+		t.Errorf("summary %+v, %d seen", summary, seen.Len())
+	}
+}
+
+func TestClient_CrawlSite_ResumesAfterKill(t *testing.T) {
+	handler, scraped := siteCrawlHandler(t, map[string][]string{
+		"https://example.com/":  {"/a", "/b"},
+		"https://example.com/a": {"/c"},
+		"https://example.com/b": {},
+		"https://example.com/c": {},
+	})
+	client := newTestClient(t, handler)
+	dir := t.TempDir()
+	path, crashed := filepath.Join(dir, "seen.jsonl"), filepath.Join(dir, "crashed.jsonl")
+
+	// the process dies while OnPage handles /a: the store is what it was
+	// on disk at that point
+	seen, err := OpenFileSeenStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	killed := errors.New("killed")
+	_, err = client.CrawlSite(context.Background(), []string{"https://example.com/"}, SiteCrawlOptions{Seen: seen, Concurrency: 1, OnPage: func(page *CrawledPage) error {
+		if page.URL != "https://example.com/a" {
+			return nil
+		}
+		state, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(crashed, state, 0644); err != nil {
+			return err
+		}
+		return killed
+	}})
+	seen.Close()
+	if !errors.Is(err, killed) {
+		t.Fatalf("first run: %v", err)
+	}
+
+	seen, err = OpenFileSeenStore(crashed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer seen.Close()
+	summary, err := client.CrawlSite(context.Background(), nil, SiteCrawlOptions{Seen: seen, Concurrency: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the seed was done before the kill; /a (and /b, when it was scraped
+	// ahead) were not, they are scraped again
+	counts := map[string]int{}
+	for _, u := range scraped() {
+		counts[u]++
+	}
+	if counts["https://example.com/"] != 1 || counts["https://example.com/a"] != 2 || counts["https://example.com/c"] != 1 || counts["https://example.com/b"] == 0 {
+		t.Errorf("scraped %v, want the frontier resumed without the seed", scraped())
+	}
+	if summary.Discovered != 3 {
+		t.Errorf("summary %+v, want /a, /b and /c crawled", summary)
+	}
+	if pending, _ := seen.Pending(context.Background()); len(pending) != 0 {
+		t.Errorf("pending after the resumed crawl: %v", pending)
+	}
+}
@@ -0,0 +1,247 @@
+package scrapfly
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sitemapMaxSize is the size limit of an uncompressed sitemap set by the
+// sitemaps protocol.
+const sitemapMaxSize = 50 << 20
+
+// sitemapTimeLayouts are the W3C datetime forms of <lastmod>.
+var sitemapTimeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04Z07:00", "2006-01-02T15:04:05", "2006-01-02", "2006-01", "2006"}
+
+// SitemapOptions configures SitemapURLs and ScrapeSitemap.
+type SitemapOptions struct {
+	// SitemapConfig is the template of the sitemap scrapes, cloned for each
+	// sitemap with its URL set. Nil scrapes with the defaults.
+	SitemapConfig *ScrapeConfig
+	// Config is the template of the page scrapes of ScrapeSitemap, cloned
+	// for each page with its URL set. Nil scrapes with the defaults.
+	Config *ScrapeConfig
+	// Include keeps only the page URLs matching one of the patterns.
+	Include []*regexp.Regexp
+	// Exclude drops the page URLs matching one of the patterns.
+	Exclude []*regexp.Regexp
+	// ModifiedSince skips the pages, and the sitemaps of an index, whose
+	// <lastmod> is before it. Entries without <lastmod> are kept. Zero
+	// keeps every entry.
+	ModifiedSince time.Time
+	// MaxURLs caps the page URLs listed. Zero means no limit.
+	MaxURLs int
+	// Scrape configures the batch run of the pages. Its Concurrency also
+	// bounds the sitemap scrapes of an index.
+	Scrape ConcurrentScrapeOptions
+}
+
+// SitemapURL is a page listed by a sitemap.
+type SitemapURL struct {
+	// Loc is the URL of the page.
+	Loc string
+	// LastMod is the last modification time of the page, zero when the
+	// sitemap doesn't say or in an unknown format.
+	LastMod time.Time
+	// ChangeFreq is how often the page changes ("daily", "monthly"...).
+	ChangeFreq string
+	// Priority is the priority of the page relative to the other pages of
+	// the site, from 0 to 1; 0.5 when the sitemap doesn't say.
+	Priority float64
+	// Sitemap is the URL of the sitemap listing the page.
+	Sitemap string
+}
+
+// sitemapDocument is a <urlset> or a <sitemapindex>.
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapEntry `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod"`
+	ChangeFreq string `xml:"changefreq"`
+	Priority   string `xml:"priority"`
+}
+
+// SitemapURLs scrapes the sitemap at sitemapURL and returns the pages it
+// lists, filtered by opts, in sitemap order. Sitemap indexes are followed
+// down to their page sitemaps; gzipped sitemaps (.xml.gz) and text
+// sitemaps, one URL per line, are supported. It fails when a sitemap
+// can't be scraped or parsed.
+func (c *Client) SitemapURLs(ctx context.Context, sitemapURL string, opts SitemapOptions) ([]SitemapURL, error) {
+	var urls []SitemapURL
+	listed := make(map[string]bool)
+	visited := map[string]bool{sitemapURL: true}
+	for level := []string{sitemapURL}; len(level) > 0; {
+		configs := make([]*ScrapeConfig, len(level))
+		for i, u := range level {
+			configs[i] = scrapeConfigFor(opts.SitemapConfig, u)
+		}
+		results, err := c.ScrapeAllSync(ctx, configs, opts.Scrape.Concurrency)
+		if err != nil {
+			return nil, err
+		}
+		var next []string
+		for i, result := range results {
+			data, err := result.Bytes()
+			if err != nil {
+				return nil, err
+			}
+			document, err := parseSitemap(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse sitemap %s: %w", level[i], err)
+			}
+			for _, entry := range document.Sitemaps {
+				loc := strings.TrimSpace(entry.Loc)
+				if visited[loc] || !sitemapModifiedSince(entry.LastMod, opts.ModifiedSince) {
+					continue
+				}
+				visited[loc] = true
+				next = append(next, loc)
+			}
+			for _, entry := range document.URLs {
+				page, ok := opts.keep(entry)
+				if !ok || listed[page.Loc] {
+					continue
+				}
+				listed[page.Loc] = true
+				page.Sitemap = level[i]
+				urls = append(urls, page)
+				if opts.MaxURLs > 0 && len(urls) >= opts.MaxURLs {
+					return urls, nil
+				}
+			}
+		}
+		level = next
+	}
+	return urls, nil
+}
+
+// ScrapeSitemap scrapes the pages listed by the sitemap at sitemapURL (see
+// SitemapURLs) as a ConcurrentScrapeWithOptions run configured by
+// opts.Scrape; Index is the position of the page in the listing. It fails
+// before scraping any page when the sitemap can't be listed.
+//
+// Example — scrape the product pages changed this week:
+//
+//	results, err := client.ScrapeSitemap("https://web-scraping.dev/sitemap.xml", scrapfly.SitemapOptions{
+//	    Config:        &scrapfly.ScrapeConfig{ASP: true},
+//	    Include:       []*regexp.Regexp{regexp.MustCompile(`/product/\d+`)},
+//	    ModifiedSince: time.Now().AddDate(0, 0, -7),
+//	    Scrape:        scrapfly.ConcurrentScrapeOptions{Concurrency: 10},
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for item := range results {
+//	    ...
+//	}
+func (c *Client) ScrapeSitemap(sitemapURL string, opts SitemapOptions) (<-chan ConcurrentScrapeResult, error) {
+	return c.ScrapeSitemapContext(context.Background(), sitemapURL, opts)
+}
+
+// ScrapeSitemapContext is ScrapeSitemap with a context, bounding both the
+// sitemap listing and the run.
+func (c *Client) ScrapeSitemapContext(ctx context.Context, sitemapURL string, opts SitemapOptions) (<-chan ConcurrentScrapeResult, error) {
+	urls, err := c.SitemapURLs(ctx, sitemapURL, opts)
+	if err != nil {
+		return nil, err
+	}
+	configs := make([]*ScrapeConfig, len(urls))
+	for i, u := range urls {
+		configs[i] = scrapeConfigFor(opts.Config, u.Loc)
+	}
+	return c.ConcurrentScrapeWithOptionsContext(ctx, configs, opts.Scrape), nil
+}
+
+// scrapeConfigFor returns a copy of base, a plain config when nil, for
+// rawURL.
+func scrapeConfigFor(base *ScrapeConfig, rawURL string) *ScrapeConfig {
+	config := base.Clone()
+	if config == nil {
+		config = &ScrapeConfig{}
+	}
+	config.URL = rawURL
+	return config
+}
+
+// keep applies the options to a sitemap page entry.
+func (o *SitemapOptions) keep(entry sitemapEntry) (SitemapURL, bool) {
+	page := SitemapURL{Loc: strings.TrimSpace(entry.Loc), ChangeFreq: strings.TrimSpace(entry.ChangeFreq), Priority: 0.5}
+	if u, err := url.Parse(page.Loc); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return page, false
+	}
+	if priority, err := strconv.ParseFloat(strings.TrimSpace(entry.Priority), 64); err == nil {
+		page.Priority = priority
+	}
+	page.LastMod = parseSitemapTime(entry.LastMod)
+	if !sitemapModifiedSince(entry.LastMod, o.ModifiedSince) {
+		return page, false
+	}
+	if len(o.Include) > 0 && !matchesAny(o.Include, page.Loc) {
+		return page, false
+	}
+	return page, !matchesAny(o.Exclude, page.Loc)
+}
+
+// sitemapModifiedSince reports whether an entry modified at lastmod is to
+// be kept with the ModifiedSince cutoff since.
+func sitemapModifiedSince(lastmod string, since time.Time) bool {
+	modified := parseSitemapTime(lastmod)
+	return since.IsZero() || modified.IsZero() || !modified.Before(since)
+}
+
+func parseSitemapTime(value string) time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range sitemapTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseSitemap parses a sitemap, gunzipping it first when gzipped.
+func parseSitemap(data []byte) (*sitemapDocument, error) {
+	if len(data) > 1 && data[0] == 0x1f && data[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if data, err = io.ReadAll(io.LimitReader(zr, sitemapMaxSize)); err != nil {
+			return nil, err
+		}
+	}
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("\xef\xbb\xbf"))
+	if len(data) > 0 && data[0] != '<' {
+		// a text sitemap
+		var document sitemapDocument
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				document.URLs = append(document.URLs, sitemapEntry{Loc: line})
+			}
+		}
+		return &document, scanner.Err()
+	}
+	var document sitemapDocument
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	if name := document.XMLName.Local; name != "urlset" && name != "sitemapindex" {
+		return nil, fmt.Errorf("unexpected root element <%s>", name)
+	}
+	return &document, nil
+}
@@ -0,0 +1,96 @@
+package scrapfly
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestClient_ScrapeSitemap(t *testing.T) {
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/product/1</loc><lastmod>2026-03-01</lastmod><priority>0.8</priority></url>
+  <url><loc>https://example.com/product/2</loc><lastmod>2025-01-01T10:00:00+00:00</lastmod></url>
+  <url><loc>https://example.com/about</loc></url>
+  <url><loc> https://example.com/product/3 </loc></url>
+</urlset>`))
+	zw.Close()
+
+	sitemaps := map[string]map[string]interface{}{
+		"https://example.com/sitemap.xml": {"content": `<?xml version="1.0" encoding="UTF-8"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/products.xml.gz</loc><lastmod>2026-03-01T00:00:00Z</lastmod></sitemap>
+  <sitemap><loc>https://example.com/old.xml</loc><lastmod>2024-01-01</lastmod></sitemap>
+  <sitemap><loc>https://example.com/pages.txt</loc></sitemap>
+  <sitemap><loc>https://example.com/sitemap.xml</loc></sitemap>
+</sitemapindex>`},
+		"https://example.com/products.xml.gz": {"content": base64.StdEncoding.EncodeToString(gz.Bytes()), "format": "binary", "content_type": "application/gzip"},
+		"https://example.com/pages.txt":       {"content": "https://example.com/product/1\nhttps://example.com/product/4\n\n", "content_type": "text/plain"},
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		target := r.URL.Query().Get("url")
+		result := map[string]interface{}{"success": true, "status": "DONE", "status_code": 200, "url": target, "content": "page " + target}
+		for key, value := range sitemaps[target] {
+			result[key] = value
+		}
+		if target == "https://example.com/old.xml" {
+			t.Error("old sitemap scraped")
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"result": result})
+	})
+
+	opts := SitemapOptions{
+		Include:       []*regexp.Regexp{regexp.MustCompile(`/product/`)},
+		ModifiedSince: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Config:        &ScrapeConfig{ASP: true},
+		Scrape:        ConcurrentScrapeOptions{Concurrency: 2, Ordered: true},
+	}
+	urls, err := client.SitemapURLs(t.Context(), "https://example.com/sitemap.xml", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"https://example.com/product/1", "https://example.com/product/3", "https://example.com/product/4"}
+	if len(urls) != len(want) {
+		t.Fatalf("urls = %+v, want %v", urls, want)
+	}
+	for i, u := range urls {
+		if u.Loc != want[i] {
+			t.Errorf("url %d = %q, want %q", i, u.Loc, want[i])
+		}
+	}
+	if urls[0].Priority != 0.8 || urls[0].LastMod.Year() != 2026 || urls[0].Sitemap != "https://example.com/products.xml.gz" || urls[1].Priority != 0.5 {
+		t.Errorf("url = %+v", urls[0])
+	}
+
+	opts.MaxURLs = 2
+	results, err := client.ScrapeSitemap("https://example.com/sitemap.xml", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for item := range results {
+		if item.Err != nil {
+			t.Fatal(item.Err)
+		}
+		if !item.Config.ASP {
+			t.Error("page config not built from Config")
+		}
+		got = append(got, item.Result.Result.Content)
+	}
+	if len(got) != 2 || got[0] != "page "+want[0] || got[1] != "page "+want[1] {
+		t.Errorf("scraped %v", got)
+	}
+}
+
+func TestParseSitemap_NotASitemap(t *testing.T) {
+	if _, err := parseSitemap([]byte(`<html><body>not found</body></html>`)); err == nil {
+		t.Error("an HTML page parsed as a sitemap")
+	}
+}
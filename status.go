@@ -0,0 +1,110 @@
+package scrapfly
+
+import (
+	"regexp"
+	"strings"
+)
+
+// BlockDetector recognizes block pages served with a success status, such
+// as a captcha or an anti-bot challenge answered with 200 OK.
+type BlockDetector interface {
+	SoftBlocked(result *ScrapeResult) bool
+}
+
+// BlockDetectorFunc adapts a function to BlockDetector.
+type BlockDetectorFunc func(result *ScrapeResult) bool
+
+// SoftBlocked calls f(result).
+func (f BlockDetectorFunc) SoftBlocked(result *ScrapeResult) bool {
+	return f(result)
+}
+
+// PatternBlockDetector reports the text results whose content matches any
+// of its patterns as soft-blocked.
+type PatternBlockDetector []*regexp.Regexp
+
+// SoftBlocked reports whether the content of result matches a pattern.
+// Binary results are never soft-blocked.
+func (d PatternBlockDetector) SoftBlocked(result *ScrapeResult) bool {
+	if result.rawContent != nil || result.Result.Format == "binary" {
+		return false
+	}
+	for _, pattern := range d {
+		if pattern.MatchString(result.Result.Content) {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultBlockDetector is the BlockDetector of IsSuccess and of
+// IsSoftBlocked(nil). It matches the challenge pages of the common captcha
+// and anti-bot products; replace it, or wrap it with a BlockDetectorFunc,
+// to recognize the block pages of a specific site.
+var DefaultBlockDetector BlockDetector = PatternBlockDetector{
+	regexp.MustCompile(`(?i)class=["'][^"']*\b(g-recaptcha|h-captcha|cf-turnstile)\b`),
+	regexp.MustCompile(`(?i)(geo\.)?captcha-delivery\.com|px-captcha|_incapsula_resource|/cdn-cgi/challenge-platform/`),
+	regexp.MustCompile(`(?i)<title>\s*(just a moment\.\.\.|attention required!|access denied|pardon our interruption|are you a (human|robot)\??)\s*</title>`),
+}
+
+// IsSuccess reports whether the scrape succeeded with a 2xx upstream status
+// and a page that DefaultBlockDetector does not recognize as a block page.
+//
+// Example:
+//
+//	result, err := client.Scrape(config)
+//	if err == nil && !result.IsSuccess() {
+//	    config.ASP = true // retry past the captcha
+//	}
+func (r *ScrapeResult) IsSuccess() bool {
+	return r.Result.Success && r.Result.StatusCode >= 200 && r.Result.StatusCode < 300 && !r.IsSoftBlocked(nil)
+}
+
+// IsSoftBlocked reports whether detector, DefaultBlockDetector when nil,
+// recognizes the result as a block page served with a success status.
+func (r *ScrapeResult) IsSoftBlocked(detector BlockDetector) bool {
+	if detector == nil {
+		detector = DefaultBlockDetector
+	}
+	return detector != nil && detector.SoftBlocked(r)
+}
+
+// IsRedirect reports whether the scrape was redirected, or the upstream
+// answered with a redirection status (304 Not Modified aside).
+func (r *ScrapeResult) IsRedirect() bool {
+	return len(r.RedirectChain()) > 0 || (r.Result.StatusCode >= 300 && r.Result.StatusCode < 400 && r.Result.StatusCode != 304)
+}
+
+// RedirectChain returns the URLs the scrape was redirected through, in
+// the order the API reports them (the redirects of the result context).
+// It is empty when the scrape was not redirected; see FinalURL for the
+// page the content was read from.
+func (r *ScrapeResult) RedirectChain() []string {
+	var chain []string
+	add := func(u string) {
+		if u = strings.TrimSpace(u); u != "" {
+			chain = append(chain, u)
+		}
+	}
+	switch redirects := r.Context.Redirects.(type) {
+	case string:
+		add(redirects)
+	case []interface{}:
+		for _, redirect := range redirects {
+			switch redirect := redirect.(type) {
+			case string:
+				add(redirect)
+			case map[string]interface{}:
+				u, _ := redirect["url"].(string)
+				add(u)
+			}
+		}
+	}
+	return chain
+}
+
+// FinalURL returns the URL of the page the content was read from, after
+// redirects, falling back to the requested URL.
+func (r *ScrapeResult) FinalURL() string {
+	return firstNonEmpty(r.Result.URL, r.Context.URL, r.Config.URL)
+}
@@ -0,0 +1,74 @@
+package scrapfly
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestScrapeResult_IsSuccess(t *testing.T) {
+	ok := ResultData{Success: true, StatusCode: 200, Content: "<html><title>Products</title></html>"}
+	captcha := ok
+	captcha.Content = `<html><body><div class="g-recaptcha" data-sitekey="x"></div></body></html>`
+	challenge := ok
+	challenge.Content = "<html><head><title>Just a moment...</title></head></html>"
+	notFound := ok
+	notFound.StatusCode = 404
+
+	cases := map[string]struct {
+		data ResultData
+		want bool
+	}{
+		"ok":        {ok, true},
+		"captcha":   {captcha, false},
+		"challenge": {challenge, false},
+		"not found": {notFound, false},
+	}
+	for name, tc := range cases {
+		if got := (&ScrapeResult{Result: tc.data}).IsSuccess(); got != tc.want {
+			t.Errorf("%s: IsSuccess() = %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+func TestScrapeResult_IsSoftBlocked(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{Success: true, StatusCode: 200, Content: "<p>Please log in to see prices</p>"}}
+	if result.IsSoftBlocked(nil) {
+		t.Error("default detector should not match a regular page")
+	}
+	login := PatternBlockDetector{regexp.MustCompile(`log in to see prices`)}
+	if !result.IsSoftBlocked(login) {
+		t.Error("custom detector should match")
+	}
+
+	saved := DefaultBlockDetector
+	defer func() { DefaultBlockDetector = saved }()
+	DefaultBlockDetector = BlockDetectorFunc(func(r *ScrapeResult) bool {
+		return saved.SoftBlocked(r) || login.SoftBlocked(r)
+	})
+	if result.IsSuccess() {
+		t.Error("IsSuccess() should use DefaultBlockDetector")
+	}
+}
+
+func TestScrapeResult_Redirects(t *testing.T) {
+	result := &ScrapeResult{
+		Config:  ConfigData{URL: "http://example.com"},
+		Context: ContextData{Redirects: []interface{}{"http://example.com", map[string]interface{}{"url": "https://example.com"}}},
+		Result:  ResultData{StatusCode: 200, URL: "https://www.example.com/"},
+	}
+	chain := result.RedirectChain()
+	if len(chain) != 2 || chain[0] != "http://example.com" || chain[1] != "https://example.com" {
+		t.Errorf("RedirectChain() = %v", chain)
+	}
+	if !result.IsRedirect() || result.FinalURL() != "https://www.example.com/" {
+		t.Errorf("IsRedirect() = %v, FinalURL() = %q", result.IsRedirect(), result.FinalURL())
+	}
+
+	direct := &ScrapeResult{Config: ConfigData{URL: "https://example.com"}, Result: ResultData{StatusCode: 200}}
+	if direct.IsRedirect() || len(direct.RedirectChain()) != 0 || direct.FinalURL() != "https://example.com" {
+		t.Errorf("IsRedirect() = %v, FinalURL() = %q", direct.IsRedirect(), direct.FinalURL())
+	}
+	if moved := (&ScrapeResult{Result: ResultData{StatusCode: 301}}); !moved.IsRedirect() {
+		t.Error("a 301 result should be a redirect")
+	}
+}
@@ -0,0 +1,107 @@
+package scrapfly
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StreamedScrape describes a scrape whose body was streamed by ScrapeTo.
+type StreamedScrape struct {
+	// StatusCode is the upstream status code.
+	StatusCode int
+	// Header is the upstream response header, Scrapfly metadata included
+	// (X-Scrapfly-Api-Cost, X-Scrapfly-Log...).
+	Header http.Header
+	// Written is the number of body bytes copied to the writer.
+	Written int64
+}
+
+// Cost returns the API credits billed for the scrape, from the
+// X-Scrapfly-Api-Cost header.
+func (s *StreamedScrape) Cost() int {
+	cost, _ := strconv.Atoi(s.Header.Get("X-Scrapfly-Api-Cost"))
+	return cost
+}
+
+// LogURL returns the dashboard URL of the scrape log, from the
+// X-Scrapfly-Log header.
+func (s *StreamedScrape) LogURL() string {
+	return s.Header.Get("X-Scrapfly-Log")
+}
+
+// ScrapeTo scrapes config and copies the upstream body to w as it arrives,
+// without holding it in memory, for multi-hundred-MB files. It uses the
+// proxified response mode (see ScrapeProxified), so the scrape fails with
+// an *APIError like Scrape does, but no content formats, extraction or
+// screenshots apply. config is not modified.
+//
+// The timeout of the client HTTP client, extended like Scrape's by
+// ScrapeConfig.Timeout, only bounds the wait for the response headers: the
+// transfer of the body runs for as long as it takes, so bound it with ctx.
+//
+// Example:
+//
+//	f, _ := os.Create("dump.csv")
+//	defer f.Close()
+//	stream, err := client.ScrapeTo(ctx, &scrapfly.ScrapeConfig{URL: "https://example.com/dump.csv"}, f)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println(stream.Written, "bytes,", stream.Cost(), "credits")
+func (c *Client) ScrapeTo(ctx context.Context, config *ScrapeConfig, w io.Writer) (*StreamedScrape, error) {
+	resp, err := c.scrapeProxified(ctx, config.Clone(), true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	written, err := io.Copy(w, resp.Body)
+	stream := &StreamedScrape{StatusCode: resp.StatusCode, Header: resp.Header, Written: written}
+	if err != nil {
+		return stream, fmt.Errorf("failed to stream response body after %d bytes: %w", written, err)
+	}
+	return stream, nil
+}
+
+// streamingDo sends req with a copy of hc without its Timeout, which
+// covers reading the body: the timeout only bounds the wait for the
+// response headers, the body is read for as long as the context of req
+// allows.
+func streamingDo(hc *http.Client, req *http.Request) (*http.Response, error) {
+	unbounded := *hc
+	unbounded.Timeout = 0
+	if hc.Timeout <= 0 {
+		return unbounded.Do(req)
+	}
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(hc.Timeout, cancel)
+	resp, err := unbounded.Do(req.WithContext(ctx))
+	if !timer.Stop() {
+		if err == nil {
+			resp.Body.Close()
+		}
+		cancel()
+		return nil, fmt.Errorf("no response headers after %s: %w", hc.Timeout, context.DeadlineExceeded)
+	}
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose releases the context of a streamed response with its body.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,75 @@
+package scrapfly
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_ScrapeToStreamsBody(t *testing.T) {
+	body := strings.Repeat("id,name\n", 10000)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("proxified_response") != "true" {
+			t.Errorf("proxified_response not set: %s", r.URL.RawQuery)
+		}
+		w.Header().Set("X-Scrapfly-Api-Cost", "3")
+		w.Header().Set("X-Scrapfly-Log", "https://scrapfly.io/dashboard/monitoring/log/abc")
+		w.Write([]byte(body))
+	})
+
+	config := &ScrapeConfig{URL: "https://example.com/dump.csv"}
+	var buf bytes.Buffer
+	stream, err := client.ScrapeTo(context.Background(), config, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != body || stream.Written != int64(len(body)) {
+		t.Errorf("wrote %d bytes, want %d", stream.Written, len(body))
+	}
+	if stream.StatusCode != http.StatusOK || stream.Cost() != 3 || !strings.HasSuffix(stream.LogURL(), "/abc") {
+		t.Errorf("unexpected metadata %+v", stream)
+	}
+	if config.ProxifiedResponse {
+		t.Error("the caller's config must not be mutated")
+	}
+}
+
+func TestClient_ScrapeToReportsRejection(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Scrapfly-Reject-Code", "ERR::ASP::SHIELD_PROTECTION_FAILED")
+		w.WriteHeader(http.StatusForbidden)
+	})
+	var buf bytes.Buffer
+	_, err := client.ScrapeTo(context.Background(), &ScrapeConfig{URL: "https://example.com/"}, &buf)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != "ERR::ASP::SHIELD_PROTECTION_FAILED" || buf.Len() != 0 {
+		t.Errorf("got %v, want the rejection as *APIError", err)
+	}
+}
+
+func TestClient_ScrapeToOutlivesHTTPTimeout(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("first,"))
+		w.(http.Flusher).Flush()
+		time.Sleep(300 * time.Millisecond)
+		w.Write([]byte("second"))
+	})
+	client.httpClient.Timeout = 100 * time.Millisecond
+
+	var buf bytes.Buffer
+	if _, err := client.ScrapeTo(context.Background(), &ScrapeConfig{URL: "https://example.com/dump.csv"}, &buf); err != nil || buf.String() != "first,second" {
+		t.Fatalf("got %q, %v; want the body streamed past the client timeout", buf.String(), err)
+	}
+
+	slow := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+	})
+	slow.httpClient.Timeout = 100 * time.Millisecond
+	if _, err := slow.ScrapeTo(context.Background(), &ScrapeConfig{URL: "https://example.com/dump.csv"}, &buf); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want the header timeout", err)
+	}
+}
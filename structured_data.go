@@ -0,0 +1,189 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// StructuredData is the machine-readable data embedded in an HTML page:
+// schema.org JSON-LD blocks, microdata items and meta tags. Product,
+// article and organization pages very often carry their main fields there.
+type StructuredData struct {
+	// JSONLD are the JSON-LD objects of the page. Top-level arrays and
+	// @graph lists are flattened; blocks that are not valid JSON are skipped.
+	JSONLD []map[string]interface{}
+	// Microdata are the top-level microdata items (itemscope elements
+	// that are not a property of another item).
+	Microdata []*MicrodataItem
+	// OpenGraph are the og: meta properties, keyed without the "og:"
+	// prefix ("title", "image", "price:amount"...). The first value wins.
+	OpenGraph map[string]string
+	// Meta are the named meta tags ("description", "twitter:card"...),
+	// keyed by lowercased name. The first value wins.
+	Meta map[string]string
+}
+
+// MicrodataItem is a microdata item (an itemscope element).
+type MicrodataItem struct {
+	// Type are the itemtype URLs, such as "https://schema.org/Product".
+	Type []string
+	// ID is the itemid, if any.
+	ID string
+	// Properties are the item properties by name. Values are strings or,
+	// for nested items, *MicrodataItem.
+	Properties map[string][]interface{}
+}
+
+// Prop returns the first string value of the property name, or "".
+func (m *MicrodataItem) Prop(name string) string {
+	for _, v := range m.Properties[name] {
+		if s, ok := v.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
+
+// Item returns the first nested item of the property name, or nil.
+func (m *MicrodataItem) Item(name string) *MicrodataItem {
+	for _, v := range m.Properties[name] {
+		if item, ok := v.(*MicrodataItem); ok {
+			return item
+		}
+	}
+	return nil
+}
+
+// IsType reports whether the item has the schema type typ, given as a
+// full URL or as the bare type name ("Product").
+func (m *MicrodataItem) IsType(typ string) bool {
+	for _, t := range m.Type {
+		if schemaTypeMatches(t, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// JSONLDOfType returns the JSON-LD objects whose @type is or includes typ,
+// given as a bare type name ("Product") or a full URL.
+func (s *StructuredData) JSONLDOfType(typ string) []map[string]interface{} {
+	var out []map[string]interface{}
+	for _, obj := range s.JSONLD {
+		switch t := obj["@type"].(type) {
+		case string:
+			if schemaTypeMatches(t, typ) {
+				out = append(out, obj)
+			}
+		case []interface{}:
+			for _, v := range t {
+				if name, ok := v.(string); ok && schemaTypeMatches(name, typ) {
+					out = append(out, obj)
+					break
+				}
+			}
+		}
+	}
+	return out
+}
+
+// DecodeJSONLD unmarshals the first JSON-LD object of type typ into v.
+//
+// Example:
+//
+//	data, _ := result.StructuredData()
+//	var product struct {
+//	    Name   string `json:"name"`
+//	    Offers struct {
+//	        Price         json.Number `json:"price"`
+//	        PriceCurrency string      `json:"priceCurrency"`
+//	    } `json:"offers"`
+//	}
+//	if err := data.DecodeJSONLD("Product", &product); err == nil {
+//	    fmt.Println(product.Name, product.Offers.Price)
+//	}
+func (s *StructuredData) DecodeJSONLD(typ string, v interface{}) error {
+	objects := s.JSONLDOfType(typ)
+	if len(objects) == 0 {
+		return fmt.Errorf("no JSON-LD object of type %q", typ)
+	}
+	if _, err := remarshal(objects[0], v); err != nil {
+		return fmt.Errorf("failed to decode JSON-LD %q: %w", typ, err)
+	}
+	return nil
+}
+
+// schemaTypeMatches compares two schema types, given as bare names or full
+// URLs; a bare name matches the last segment of a URL.
+func schemaTypeMatches(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	if strings.ContainsAny(a, "/#:") && strings.ContainsAny(b, "/#:") {
+		return false
+	}
+	return strings.EqualFold(schemaTypeName(a), schemaTypeName(b))
+}
+
+func schemaTypeName(typ string) string {
+	return typ[strings.LastIndexAny(typ, "/#:")+1:]
+}
+
+// parseJSONLD decodes the text of JSON-LD script blocks, flattening arrays
+// and @graph lists.
+func parseJSONLD(blocks []string) []map[string]interface{} {
+	var out []map[string]interface{}
+	var flatten func(v interface{})
+	flatten = func(v interface{}) {
+		switch value := v.(type) {
+		case []interface{}:
+			for _, item := range value {
+				flatten(item)
+			}
+		case map[string]interface{}:
+			if graph, ok := value["@graph"].([]interface{}); ok {
+				flatten(graph)
+				return
+			}
+			out = append(out, value)
+		}
+	}
+	for _, block := range blocks {
+		var v interface{}
+		// pages often wrap the JSON in HTML comments or CDATA markers
+		block = strings.TrimSpace(block)
+		for _, marker := range [][2]string{{"<!--", "-->"}, {"<![CDATA[", "]]>"}, {"//<![CDATA[", "//]]>"}} {
+			block = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(block, marker[0]), marker[1]))
+		}
+		if err := json.Unmarshal([]byte(block), &v); err == nil {
+			flatten(v)
+		}
+	}
+	return out
+}
+
+// addMetaTag records a meta tag into OpenGraph or Meta.
+func (s *StructuredData) addMetaTag(property, name, content string) {
+	if property == "" && strings.HasPrefix(strings.ToLower(name), "og:") {
+		property = name
+	}
+	if key, ok := strings.CutPrefix(strings.ToLower(property), "og:"); ok {
+		if _, exists := s.OpenGraph[key]; !exists {
+			s.OpenGraph[key] = content
+		}
+		return
+	}
+	if name == "" {
+		name = property
+	}
+	if name = strings.ToLower(name); name != "" {
+		if _, exists := s.Meta[name]; !exists {
+			s.Meta[name] = content
+		}
+	}
+}
+
+func newStructuredData() *StructuredData {
+	return &StructuredData{OpenGraph: map[string]string{}, Meta: map[string]string{}}
+}
@@ -0,0 +1,53 @@
+package scrapfly
+
+import (
+	"errors"
+	"testing"
+)
+
+const structuredDataPage = `<html><head>
+<meta property="og:title" content="Box of Chocolate Candy">
+<meta property="og:price:amount" content="9.99">
+<meta name="Description" content="Delicious chocolate">
+<meta name="twitter:card" content="summary">
+<script type="application/ld+json">{"@context": "https://schema.org", "@type": "Product", "name": "Box of Chocolate Candy", "offers": {"price": "9.99", "priceCurrency": "USD"}}</script>
+<script type="application/ld+json">{"@graph": [{"@type": ["Thing", "Organization"], "name": "Scrapfly"}, {"@type": "BreadcrumbList"}]}</script>
+<script type="application/ld+json">{not json</script>
+</head><body></body></html>`
+
+func TestScrapeResult_StructuredData(t *testing.T) {
+	result := &ScrapeResult{Result: ResultData{ContentType: "text/html; charset=utf-8", Content: structuredDataPage}}
+	data, err := result.StructuredData()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.JSONLD) != 3 {
+		t.Fatalf("JSONLD = %v, want 3 objects", data.JSONLD)
+	}
+	if got := data.JSONLDOfType("organization"); len(got) != 1 || got[0]["name"] != "Scrapfly" {
+		t.Errorf("JSONLDOfType(organization) = %v", got)
+	}
+	var product struct {
+		Name   string `json:"name"`
+		Offers struct {
+			Price string `json:"price"`
+		} `json:"offers"`
+	}
+	if err := data.DecodeJSONLD("https://schema.org/Product", &product); err != nil || product.Offers.Price != "9.99" {
+		t.Errorf("DecodeJSONLD() = %+v, %v", product, err)
+	}
+	if err := data.DecodeJSONLD("Recipe", &product); err == nil {
+		t.Error("expected an error for a missing type")
+	}
+	if data.OpenGraph["title"] != "Box of Chocolate Candy" || data.OpenGraph["price:amount"] != "9.99" {
+		t.Errorf("OpenGraph = %v", data.OpenGraph)
+	}
+	if data.Meta["description"] != "Delicious chocolate" || data.Meta["twitter:card"] != "summary" {
+		t.Errorf("Meta = %v", data.Meta)
+	}
+
+	result = &ScrapeResult{Result: ResultData{ContentType: "application/json", Content: "{}"}}
+	if _, err := result.StructuredData(); !errors.Is(err, ErrContentType) {
+		t.Errorf("expected ErrContentType, got %v", err)
+	}
+}
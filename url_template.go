@@ -0,0 +1,106 @@
+package scrapfly
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var templatePlaceholderRegex = regexp.MustCompile(`\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+
+// ExpandURLTemplate replaces the {name} placeholders of template with
+// values, path-escaped before the query string and query-escaped after it.
+// A placeholder without value is an error; unused values are ignored.
+//
+// Example:
+//
+//	u, _ := scrapfly.ExpandURLTemplate("https://example.com/search?q={query}&page={page}",
+//	    map[string]string{"query": "red shoes", "page": "2"})
+//	// https://example.com/search?q=red+shoes&page=2
+func ExpandURLTemplate(template string, values map[string]string) (string, error) {
+	queryStart := strings.IndexByte(template, '?')
+	var missing []string
+	var b strings.Builder
+	last := 0
+	for _, loc := range templatePlaceholderRegex.FindAllStringSubmatchIndex(template, -1) {
+		name := template[loc[2]:loc[3]]
+		value, ok := values[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		b.WriteString(template[last:loc[0]])
+		if queryStart >= 0 && loc[0] > queryStart {
+			b.WriteString(url.QueryEscape(value))
+		} else {
+			b.WriteString(url.PathEscape(value))
+		}
+		last = loc[1]
+	}
+	if len(missing) > 0 {
+		return "", fmt.Errorf("%w: URL template %q has no value for %s", ErrScrapeConfig, template, strings.Join(missing, ", "))
+	}
+	b.WriteString(template[last:])
+	return b.String(), nil
+}
+
+// ExpandScrapeConfigs expands template with each value map and returns one
+// clone of base per URL, in the order of values. A nil base gives configs
+// with only the URL set.
+//
+// Example:
+//
+//	configs, err := scrapfly.ExpandScrapeConfigs(
+//	    &scrapfly.ScrapeConfig{ASP: true},
+//	    "https://example.com/search?q={query}&page={page}",
+//	    scrapfly.TemplateProduct(map[string][]string{
+//	        "query": {"shoes", "boots"},
+//	        "page":  {"1", "2", "3"},
+//	    }),
+//	)
+func ExpandScrapeConfigs(base *ScrapeConfig, template string, values []map[string]string) ([]*ScrapeConfig, error) {
+	configs := make([]*ScrapeConfig, 0, len(values))
+	for _, v := range values {
+		u, err := ExpandURLTemplate(template, v)
+		if err != nil {
+			return nil, err
+		}
+		config := base.Clone()
+		if config == nil {
+			config = &ScrapeConfig{}
+		}
+		config.URL = u
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// TemplateProduct returns every combination of params, for use with
+// ExpandScrapeConfigs. Combinations are ordered by parameter name, the last
+// name varying fastest, and values keep their order.
+func TemplateProduct(params map[string][]string) []map[string]string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	combinations := []map[string]string{{}}
+	for _, name := range names {
+		next := make([]map[string]string, 0, len(combinations)*len(params[name]))
+		for _, combination := range combinations {
+			for _, value := range params[name] {
+				expanded := make(map[string]string, len(combination)+1)
+				for k, v := range combination {
+					expanded[k] = v
+				}
+				expanded[name] = value
+				next = append(next, expanded)
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
@@ -0,0 +1,51 @@
+package scrapfly
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpandURLTemplate(t *testing.T) {
+	got, err := ExpandURLTemplate("https://example.com/c/{category}/search?q={query}&page={page}",
+		map[string]string{"category": "men/shoes", "query": "red & blue", "page": "2", "unused": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://example.com/c/men%2Fshoes/search?q=red+%26+blue&page=2"; got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	if _, err := ExpandURLTemplate("https://example.com/?q={query}&page={page}", map[string]string{}); !errors.Is(err, ErrScrapeConfig) {
+		t.Errorf("got %v, want ErrScrapeConfig", err)
+	}
+}
+
+func TestExpandScrapeConfigs(t *testing.T) {
+	base := &ScrapeConfig{ASP: true, Headers: map[string]string{"x-a": "b"}}
+	configs, err := ExpandScrapeConfigs(base, "https://example.com/search?q={query}&page={page}",
+		TemplateProduct(map[string][]string{"query": {"shoes", "boots"}, "page": {"1", "2"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"https://example.com/search?q=shoes&page=1",
+		"https://example.com/search?q=boots&page=1",
+		"https://example.com/search?q=shoes&page=2",
+		"https://example.com/search?q=boots&page=2",
+	}
+	if len(configs) != len(want) {
+		t.Fatalf("got %d configs, want %d", len(configs), len(want))
+	}
+	for i, config := range configs {
+		if config.URL != want[i] {
+			t.Errorf("config %d URL = %s, want %s", i, config.URL, want[i])
+		}
+		if !config.ASP {
+			t.Errorf("config %d lost the base options", i)
+		}
+	}
+	configs[0].Headers["x-a"] = "changed"
+	if base.Headers["x-a"] != "b" || configs[1].Headers["x-a"] != "b" {
+		t.Error("configs must not share maps with the base or each other")
+	}
+}
@@ -1,6 +1,7 @@
 package scrapfly
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -41,16 +42,23 @@ func fetchWithRetry(client *http.Client, req *http.Request, retries int, delay t
 		resp, err := client.Do(req)
 		if err != nil {
 			lastErr = err
-			DefaultLogger.Debug("request failed:", err, "retrying...")
-			time.Sleep(delay)
+			if ctxErr := req.Context().Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			DefaultLogger.Debug(logArgs(req.Context(), "request failed:", err, "retrying...")...)
+			if err := sleepContext(req.Context(), delay); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
 		if resp.StatusCode >= 500 && resp.StatusCode < 600 {
 			resp.Body.Close() // Close body to prevent resource leaks
 			lastErr = &APIError{Message: "server error", HTTPStatusCode: resp.StatusCode}
-			DefaultLogger.Debug("request failed with status", resp.StatusCode, "retrying...")
-			time.Sleep(delay)
+			DefaultLogger.Debug(logArgs(req.Context(), "request failed with status", resp.StatusCode, "retrying...")...)
+			if err := sleepContext(req.Context(), delay); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
@@ -59,6 +67,33 @@ func fetchWithRetry(client *http.Client, req *http.Request, retries int, delay t
 	return nil, lastErr
 }
 
+// sleepContext sleeps for d or until ctx is done, returning ctx's error in
+// the latter case.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// joinConfigErrors returns nil, the single error or the joined errors,
+// every error wrapping sentinel.
+func joinConfigErrors(sentinel error, errs []error) error {
+	for i, err := range errs {
+		if !errors.Is(err, sentinel) {
+			errs[i] = fmt.Errorf("%w: %w", sentinel, err)
+		}
+	}
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
+
 // ValidateExclusiveFields checks a struct for fields marked with the "exclusive" tag
 // and ensures that only one field per exclusive group is set.
 func ValidateExclusiveFields(s interface{}) error {
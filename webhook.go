@@ -0,0 +1,115 @@
+package scrapfly
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ScrapeJob is the acknowledgement of an asynchronous scrape: the API
+// queued the request and will POST the ScrapeResult to the webhook instead
+// of returning it inline. Match the delivery with ParseScrapeWebhook on UUID
+// or on the CorrelationID set on the config.
+type ScrapeJob struct {
+	// UUID identifies the queued scrape; the delivered result carries the same UUID.
+	UUID string
+	// WebhookName is the webhook the result will be delivered to.
+	WebhookName string
+	// CorrelationID echoes ScrapeConfig.CorrelationID.
+	CorrelationID string
+	// URL is the scraped URL.
+	URL string
+	// StatusCode is the HTTP status of the API acknowledgement (usually 201 or 202).
+	StatusCode int
+	// QueuedAt is when the acknowledgement was received.
+	QueuedAt time.Time
+	// Raw is the decoded acknowledgement body, for fields not mapped above.
+	Raw map[string]interface{}
+}
+
+// ScrapeWebhook performs an asynchronous scrape delivered to
+// config.Webhook. It returns as soon as the API acknowledged the job, with
+// nothing to parse inline; the result arrives later at the webhook endpoint.
+//
+// Example:
+//
+//	job, err := client.ScrapeWebhook(&scrapfly.ScrapeConfig{
+//	    URL:           "https://web-scraping.dev/product/1",
+//	    Webhook:       "my-webhook",
+//	    CorrelationID: "product-1",
+//	})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	fmt.Println("queued", job.UUID)
+func (c *Client) ScrapeWebhook(config *ScrapeConfig) (*ScrapeJob, error) {
+	if config.Webhook == "" {
+		return nil, fmt.Errorf("%w: ScrapeWebhook requires Webhook", ErrScrapeConfig)
+	}
+	config = c.withDomainDefaults(config)
+	req, _, err := c.newScrapeRequest(config)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := c.httpClientFor(time.Duration(config.Timeout) * time.Millisecond)
+	resp, err := fetchWithRetry(httpClient, req, defaultRetries, defaultDelay)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusAccepted:
+		return parseScrapeJob(resp, body, config), nil
+	}
+	return nil, c.handleAPIErrorResponse(resp, body)
+}
+
+// parseScrapeJob decodes a webhook acknowledgement; the config is used for
+// the fields it does not carry.
+func parseScrapeJob(resp *http.Response, body []byte, config *ScrapeConfig) *ScrapeJob {
+	job := &ScrapeJob{
+		WebhookName:   config.Webhook,
+		CorrelationID: config.CorrelationID,
+		URL:           config.URL,
+		StatusCode:    resp.StatusCode,
+		QueuedAt:      time.Now(),
+	}
+	if err := json.Unmarshal(body, &job.Raw); err != nil {
+		return job
+	}
+	job.UUID, _ = job.Raw["job_uuid"].(string)
+	if name, ok := job.Raw["webhook_name"].(string); ok && name != "" {
+		job.WebhookName = name
+	}
+	return job
+}
+
+// ParseScrapeWebhook decodes the body POSTed to a webhook endpoint for an
+// asynchronous scrape into a ScrapeResult. Failed scrapes are reported as
+// errors, like Client.Scrape does.
+//
+// Example:
+//
+//	http.HandleFunc("/scrapfly", func(w http.ResponseWriter, r *http.Request) {
+//	    body, _ := io.ReadAll(r.Body)
+//	    result, err := scrapfly.ParseScrapeWebhook(body)
+//	    ...
+//	})
+func ParseScrapeWebhook(body []byte) (*ScrapeResult, error) {
+	var result ScrapeResult
+	if err := result.UnmarshalJSON(body); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scrape webhook payload: %w", err)
+	}
+	if result.Result.Success && result.Result.Status == "DONE" {
+		return &result, nil
+	}
+	return nil, errorFromResult(&result)
+}
@@ -0,0 +1,44 @@
+package scrapfly
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClient_ScrapeWebhook_ReturnsQueuedJob(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("webhook_name") != "hook" {
+			t.Errorf("webhook_name not sent: %s", r.URL.RawQuery)
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"job_uuid":"job-1","webhook_name":"hook"}`))
+	})
+	config := &ScrapeConfig{URL: "https://example.com", Webhook: "hook", CorrelationID: "c-1"}
+
+	job, err := client.ScrapeWebhook(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.UUID != "job-1" || job.WebhookName != "hook" || job.CorrelationID != "c-1" || job.StatusCode != http.StatusAccepted {
+		t.Errorf("unexpected job %+v", job)
+	}
+
+	if _, err := client.Scrape(config); !errors.Is(err, ErrScrapeQueued) {
+		t.Errorf("Scrape must report ErrScrapeQueued for a queued job, got %v", err)
+	}
+	if _, err := client.ScrapeWebhook(&ScrapeConfig{URL: "https://example.com"}); !errors.Is(err, ErrScrapeConfig) {
+		t.Errorf("ScrapeWebhook without Webhook must fail, got %v", err)
+	}
+}
+
+func TestParseScrapeWebhook(t *testing.T) {
+	result, err := ParseScrapeWebhook([]byte(`{"uuid":"job-1","result":{"success":true,"status":"DONE","content":"ok"}}`))
+	if err != nil || result.UUID != "job-1" || result.Result.Content != "ok" {
+		t.Fatalf("ParseScrapeWebhook() = %+v, %v", result, err)
+	}
+	_, err = ParseScrapeWebhook([]byte(`{"result":{"success":false,"status":"ERR::SCRAPE::OPERATION_TIMEOUT","error":{"code":"ERR::SCRAPE::OPERATION_TIMEOUT","message":"timeout"}}}`))
+	if !errors.Is(err, ErrScrapeFailed) {
+		t.Errorf("expected ErrScrapeFailed, got %v", err)
+	}
+}